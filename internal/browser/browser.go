@@ -0,0 +1,65 @@
+// Package browser opens URLs in the user's default web browser using the
+// operating system opener (xdg-open, open, or rundll32).
+//
+// Usage:
+//
+//	if err := browser.Open(ctx, mr.WebURL); err != nil {
+//	    log.Warnf("Open it manually: %s", mr.WebURL)
+//	}
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// openTimeout bounds how long the OS opener may take to hand the URL over.
+const openTimeout = 10 * time.Second
+
+var (
+	errUnsupportedURL = errors.New("only http and https URLs can be opened")
+
+	// ErrUnsupportedURL is returned by [Open] when the URL is not an http(s) URL.
+	ErrUnsupportedURL = errUnsupportedURL
+)
+
+// Command returns the opener executable and arguments for goos.
+// Linux and the BSDs use xdg-open, macOS uses open, and Windows uses rundll32
+// (which, unlike "cmd /c start", does not interpret shell metacharacters).
+func Command(goos, rawURL string) (string, []string) {
+	switch goos {
+	case "darwin":
+		return "open", []string{rawURL}
+	case "windows":
+		return "rundll32", []string{"url.dll,FileProtocolHandler", rawURL}
+	default:
+		return "xdg-open", []string{rawURL}
+	}
+}
+
+// Open opens rawURL in the default browser.
+//
+// Returns [ErrUnsupportedURL] if rawURL is not an absolute http or https URL.
+// Returns an error if the opener is missing or fails, e.g. in a headless
+// environment; callers are expected to fall back to printing the URL.
+func Open(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: %q", errUnsupportedURL, rawURL)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, openTimeout)
+	defer cancel()
+
+	name, args := Command(runtime.GOOS, u.String())
+	// #nosec G204 - The opener is fixed per OS and the URL is validated above
+	if err := exec.CommandContext(ctx, name, args...).Run(); err != nil {
+		return fmt.Errorf("failed to run %s: %w", name, err)
+	}
+	return nil
+}
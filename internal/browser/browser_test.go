@@ -0,0 +1,52 @@
+package browser_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sgaunet/auto-mr/internal/browser"
+)
+
+func TestCommand(t *testing.T) {
+	const u = "https://gitlab.com/owner/repo/-/merge_requests/1"
+
+	tests := []struct {
+		goos     string
+		wantName string
+		wantArgs []string
+	}{
+		{"linux", "xdg-open", []string{u}},
+		{"freebsd", "xdg-open", []string{u}},
+		{"darwin", "open", []string{u}},
+		{"windows", "rundll32", []string{"url.dll,FileProtocolHandler", u}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			name, args := browser.Command(tt.goos, u)
+			if name != tt.wantName {
+				t.Errorf("Command() name = %q, want %q", name, tt.wantName)
+			}
+			if len(args) != len(tt.wantArgs) {
+				t.Fatalf("Command() args = %v, want %v", args, tt.wantArgs)
+			}
+			for i := range args {
+				if args[i] != tt.wantArgs[i] {
+					t.Errorf("Command() args[%d] = %q, want %q", i, args[i], tt.wantArgs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestOpen_RejectsNonHTTPURL(t *testing.T) {
+	for _, u := range []string{"", "file:///etc/passwd", "javascript:alert(1)", "not a url", "https://"} {
+		t.Run(u, func(t *testing.T) {
+			err := browser.Open(context.Background(), u)
+			if !errors.Is(err, browser.ErrUnsupportedURL) {
+				t.Errorf("Open(%q) error = %v, want ErrUnsupportedURL", u, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+// Package codeowners reads a CODEOWNERS file and finds the owners of a set of
+// changed paths, for the --reviewers-from-codeowners review requests.
+//
+// Patterns follow gitignore rules, as on GitHub, GitLab and Forgejo: the last
+// matching line of the file wins. GitLab sections ("[Section] @default-owner")
+// are each matched separately, so a path may have owners in several sections.
+//
+// Usage:
+//
+//	file, path, err := codeowners.Load(repoRoot)
+//	if errors.Is(err, codeowners.ErrNotFound) {
+//	    return
+//	}
+//	owners := file.Owners([]string{"docs/README.md", "pkg/git/git.go"})
+package codeowners
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// Locations are the paths, relative to the repository root, searched for the
+// CODEOWNERS file, in order. They cover the GitHub, GitLab and Forgejo conventions.
+var Locations = []string{
+	".github/CODEOWNERS",
+	".gitlab/CODEOWNERS",
+	".gitea/CODEOWNERS",
+	".forgejo/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+}
+
+var (
+	errNotFound = errors.New("no CODEOWNERS file found")
+
+	// ErrNotFound is returned by [Load] when none of the [Locations] holds a CODEOWNERS file.
+	ErrNotFound = errNotFound
+)
+
+// File is a parsed CODEOWNERS file.
+type File struct {
+	sections [][]rule
+}
+
+type rule struct {
+	pattern gitignore.Pattern
+	owners  []string
+}
+
+// Load reads the first CODEOWNERS file found at one of the [Locations] under repoRoot,
+// and returns it with its path.
+//
+// Returns [ErrNotFound] if there is none.
+func Load(repoRoot string) (*File, string, error) {
+	for _, location := range Locations {
+		path := filepath.Join(repoRoot, filepath.FromSlash(location))
+		// #nosec G304 - The path is one of the fixed locations under the repository root
+		f, err := os.Open(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to open %s: %w", location, err)
+		}
+		file, err := Parse(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read %s: %w", location, err)
+		}
+		return file, path, nil
+	}
+	return nil, "", fmt.Errorf("%w in %s", errNotFound, strings.Join(Locations, ", "))
+}
+
+// Parse parses the content of a CODEOWNERS file. Comments, blank lines and negated
+// patterns (not supported by CODEOWNERS) are skipped.
+func Parse(r io.Reader) (*File, error) {
+	file := &File{sections: [][]rule{nil}}
+	var defaults []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		if header, ok := sectionHeader(line); ok {
+			file.sections = append(file.sections, nil)
+			defaults = header
+			continue
+		}
+
+		fields := strings.Fields(line)
+		owners := fields[1:]
+		if len(owners) == 0 {
+			owners = defaults
+		}
+		last := len(file.sections) - 1
+		file.sections[last] = append(file.sections[last], rule{
+			pattern: gitignore.ParsePattern(fields[0], nil),
+			owners:  owners,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan CODEOWNERS: %w", err)
+	}
+	return file, nil
+}
+
+// sectionHeader reports whether line starts a GitLab section ("[Name]", "^[Name]" for
+// an optional one, "[Name][2]" with an approval count), and returns its default owners.
+func sectionHeader(line string) ([]string, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimPrefix(line, "^"), "[")
+	if !ok {
+		return nil, false
+	}
+	_, rest, ok = strings.Cut(rest, "]")
+	if !ok {
+		return nil, false
+	}
+	if count, ok := strings.CutPrefix(rest, "["); ok {
+		_, rest, _ = strings.Cut(count, "]")
+	}
+	return strings.Fields(rest), true
+}
+
+// Owners returns the owners of paths (slash-separated, relative to the repository
+// root), in order of first appearance and without duplicates. Users and teams lose
+// their leading "@" ("jane", "org/team"); e-mail owners are left out since reviews
+// can only be requested from accounts.
+func (f *File) Owners(paths []string) []string {
+	var owners []string
+	for _, path := range paths {
+		parts := strings.Split(path, "/")
+		for _, section := range f.sections {
+			for _, owner := range matchingOwners(section, parts) {
+				name, ok := strings.CutPrefix(owner, "@")
+				if ok && name != "" && !slices.Contains(owners, name) {
+					owners = append(owners, name)
+				}
+			}
+		}
+	}
+	return owners
+}
+
+// matchingOwners returns the owners of the last rule of section matching path.
+func matchingOwners(section []rule, path []string) []string {
+	for _, r := range slices.Backward(section) {
+		if r.pattern.Match(path, false) == gitignore.Exclude {
+			return r.owners
+		}
+	}
+	return nil
+}
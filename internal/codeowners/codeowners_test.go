@@ -0,0 +1,62 @@
+package codeowners_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sgaunet/auto-mr/internal/codeowners"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwners(t *testing.T) {
+	file, err := codeowners.Parse(strings.NewReader(`# Default owners
+*           @lead
+
+*.go        @gopher jane@example.com
+/docs/      @org/writers
+pkg/git/    @gitter @gopher
+!vendor/    @nobody
+
+[Security] @org/security
+internal/security/
+`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		paths []string
+		want  []string
+	}{
+		{"catch-all", []string{"Makefile"}, []string{"lead"}},
+		{"last match wins", []string{"main.go"}, []string{"gopher"}},
+		{"directory", []string{"docs/guide/install.md"}, []string{"org/writers"}},
+		{"anchored directory", []string{"site/docs/index.md"}, []string{"lead"}},
+		{"deduplicated", []string{"pkg/git/git.go", "main.go"}, []string{"gitter", "gopher"}},
+		{"section defaults", []string{"internal/security/sanitize.go"}, []string{"gopher", "org/security"}},
+		{"no paths", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, file.Owners(tt.paths))
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	root := t.TempDir()
+	_, _, err := codeowners.Load(root)
+	require.ErrorIs(t, err, codeowners.ErrNotFound)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "CODEOWNERS"), []byte("* @root\n"), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".github"), 0o750))
+	path := filepath.Join(root, ".github", "CODEOWNERS")
+	require.NoError(t, os.WriteFile(path, []byte("* @github\n"), 0o600))
+
+	file, found, err := codeowners.Load(root)
+	require.NoError(t, err)
+	assert.Equal(t, path, found)
+	assert.Equal(t, []string{"github"}, file.Owners([]string{"README.md"}))
+}
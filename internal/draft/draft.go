@@ -0,0 +1,22 @@
+// Package draft recognizes the title prefixes that mark a merge/pull request
+// as a draft (work in progress) on GitLab and Forgejo.
+package draft
+
+import (
+	"regexp"
+	"strings"
+)
+
+// prefixPattern matches GitLab's draft prefixes ("Draft:", "[Draft]", "(Draft)")
+// and Forgejo's default work-in-progress prefixes ("WIP:", "[WIP]"), case-insensitively.
+var prefixPattern = regexp.MustCompile(`(?i)^\s*(draft:|\[draft]|\(draft\)|wip:|\[wip])\s*`)
+
+// StripPrefix removes the draft prefix of title.
+// Returns title unchanged and false if it has no draft prefix.
+func StripPrefix(title string) (string, bool) {
+	loc := prefixPattern.FindStringIndex(title)
+	if loc == nil {
+		return title, false
+	}
+	return strings.TrimSpace(title[loc[1]:]), true
+}
@@ -0,0 +1,32 @@
+package draft_test
+
+import (
+	"testing"
+
+	"github.com/sgaunet/auto-mr/internal/draft"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripPrefix(t *testing.T) {
+	tests := []struct {
+		title   string
+		want    string
+		isDraft bool
+	}{
+		{"Draft: feat: add x", "feat: add x", true},
+		{"draft:feat: add x", "feat: add x", true},
+		{"[Draft] fix: y", "fix: y", true},
+		{"(Draft) fix: y", "fix: y", true},
+		{"WIP: chore: z", "chore: z", true},
+		{"[WIP] chore: z", "chore: z", true},
+		{"feat: drafting support", "feat: drafting support", false},
+		{"fix: Draft: in the middle", "fix: Draft: in the middle", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			got, isDraft := draft.StripPrefix(tt.title)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.isDraft, isDraft)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+// Package hook runs user-configured shell commands at fixed points of the
+// auto-mr workflow, such as the pre-merge hook run after CI passes.
+//
+// Usage:
+//
+//	env := []string{"AUTO_MR_SOURCE_BRANCH=feature"}
+//	if err := hook.Run(ctx, "make test", repoRoot, env); err != nil {
+//	    return err // wraps hook.ErrFailed and includes the command output
+//	}
+package hook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+var (
+	errFailed = errors.New("hook command failed")
+
+	// ErrFailed is returned by [Run] when the command exits non-zero or cannot be started.
+	ErrFailed = errFailed
+)
+
+// Shell returns the shell executable and arguments running command on goos:
+// "cmd /C" on Windows, "sh -c" elsewhere.
+func Shell(goos, command string) (string, []string) {
+	if goos == "windows" {
+		return "cmd", []string{"/C", command}
+	}
+	return "sh", []string{"-c", command}
+}
+
+// Run runs command through the system shell in dir, with env ("KEY=value"
+// entries) added to the current environment. The combined output is
+// returned in the error so that callers can show why the hook failed.
+//
+// Returns [ErrFailed] if the command cannot be started or exits non-zero.
+func Run(ctx context.Context, command, dir string, env []string) error {
+	name, args := Shell(runtime.GOOS, command)
+	// #nosec G204 - The command comes from the user's own configuration or flags
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		out := strings.TrimSpace(string(output))
+		if out == "" {
+			return fmt.Errorf("%w: %q: %w", errFailed, command, err)
+		}
+		return fmt.Errorf("%w: %q: %w\nOutput:\n%s", errFailed, command, err, out)
+	}
+	return nil
+}
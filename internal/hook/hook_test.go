@@ -0,0 +1,47 @@
+package hook_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/sgaunet/auto-mr/internal/hook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShell(t *testing.T) {
+	name, args := hook.Shell("linux", "make test")
+	assert.Equal(t, "sh", name)
+	assert.Equal(t, []string{"-c", "make test"}, args)
+
+	name, args = hook.Shell("windows", "make test")
+	assert.Equal(t, "cmd", name)
+	assert.Equal(t, []string{"/C", "make test"}, args)
+}
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+
+	t.Run("success runs in dir with env", func(t *testing.T) {
+		dir := t.TempDir()
+		err := hook.Run(context.Background(), `echo "$AUTO_MR_SOURCE_BRANCH" > out.txt`, dir,
+			[]string{"AUTO_MR_SOURCE_BRANCH=feature"})
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(filepath.Join(dir, "out.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "feature\n", string(data))
+	})
+
+	t.Run("failure includes output", func(t *testing.T) {
+		err := hook.Run(context.Background(), "echo lint failed; exit 3", t.TempDir(), nil)
+		require.ErrorIs(t, err, hook.ErrFailed)
+		assert.Contains(t, err.Error(), "lint failed")
+		assert.Contains(t, err.Error(), "exit status 3")
+	})
+}
@@ -1,7 +1,17 @@
 // Package labels provides automatic label selection based on conventional commit types.
 package labels
 
-import "strings"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// scopeSeparator separates the scope from the value of a GitLab scoped label.
+const scopeSeparator = "::"
+
+// ErrScopeConflict is returned by [CheckScopes] when two selected labels share a scope.
+var ErrScopeConflict = errors.New("labels of the same scope cannot be applied together")
 
 // commitTypeToLabels maps conventional commit types to candidate label names.
 var commitTypeToLabels = map[string][]string{
@@ -84,3 +94,34 @@ func AutoSelectLabels(title string, availableLabels []string) []string {
 
 	return matched
 }
+
+// Scope returns the scope of a GitLab scoped label: the text before the last "::"
+// ("priority::high" → "priority", "workflow::backend::review" → "workflow::backend").
+// Returns "" when name is not a scoped label.
+func Scope(name string) string {
+	idx := strings.LastIndex(name, scopeSeparator)
+	if idx < 1 || idx+len(scopeSeparator) == len(name) {
+		return ""
+	}
+	return name[:idx]
+}
+
+// CheckScopes ensures no two labels of selected share a scope, since only one
+// label per scope can be applied. scopes maps label names to their scope; labels
+// missing from it or mapped to "" are not scoped.
+//
+// Returns [ErrScopeConflict] naming the first two conflicting labels.
+func CheckScopes(selected []string, scopes map[string]string) error {
+	seen := make(map[string]string, len(selected))
+	for _, label := range selected {
+		scope := scopes[label]
+		if scope == "" {
+			continue
+		}
+		if previous, found := seen[scope]; found && previous != label {
+			return fmt.Errorf("%w: '%s' and '%s' (scope '%s')", ErrScopeConflict, previous, label, scope)
+		}
+		seen[scope] = label
+	}
+	return nil
+}
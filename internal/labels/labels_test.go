@@ -1,6 +1,7 @@
 package labels_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/sgaunet/auto-mr/internal/labels"
@@ -132,6 +133,59 @@ func TestAutoSelectLabels(t *testing.T) {
 	}
 }
 
+func TestScope(t *testing.T) {
+	tests := []struct {
+		name  string
+		label string
+		want  string
+	}{
+		{"scoped", "priority::high", "priority"},
+		{"nested scope", "workflow::backend::review", "workflow::backend"},
+		{"not scoped", "bug", ""},
+		{"single colon", "type:bug", ""},
+		{"empty scope", "::high", ""},
+		{"empty value", "priority::", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := labels.Scope(tt.label); got != tt.want {
+				t.Errorf("Scope(%q) = %q, want %q", tt.label, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckScopes(t *testing.T) {
+	scopes := map[string]string{
+		"priority::high": "priority",
+		"priority::low":  "priority",
+		"status::review": "status",
+		"bug":            "",
+	}
+
+	tests := []struct {
+		name     string
+		selected []string
+		wantErr  bool
+	}{
+		{"different scopes", []string{"priority::high", "status::review", "bug"}, false},
+		{"same scope", []string{"bug", "priority::high", "priority::low"}, true},
+		{"same label twice", []string{"priority::high", "priority::high"}, false},
+		{"unknown label", []string{"priority::high", "other::label"}, false},
+		{"none", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := labels.CheckScopes(tt.selected, scopes)
+			if tt.wantErr != errors.Is(err, labels.ErrScopeConflict) {
+				t.Errorf("CheckScopes(%v) = %v, wantErr %v", tt.selected, err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func stringSliceEqual(a, b []string) bool {
 	if len(a) == 0 && len(b) == 0 {
 		// Treat nil and empty as equal only if both are nil or both are empty
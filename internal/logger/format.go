@@ -0,0 +1,321 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Supported output formats.
+const (
+	// FormatText renders human-oriented bullets with colors and spinners (default).
+	FormatText = "text"
+	// FormatJSON renders one JSON object per line with time, level, msg and fields.
+	FormatJSON = "json"
+)
+
+// Supported progress modes for pipeline/workflow waits.
+const (
+	// ProgressSpinner renders running jobs as animated spinners (default).
+	ProgressSpinner = "spinner"
+	// ProgressPlain prints a one-line status per running job at every poll instead of spinners.
+	ProgressPlain = "plain"
+)
+
+var (
+	errUnknownFormat   = errors.New("unknown log format")
+	errUnknownProgress = errors.New("unknown progress mode")
+
+	// ErrUnknownFormat is returned by [SetFormat] for an unsupported format name.
+	ErrUnknownFormat = errUnknownFormat
+	// ErrUnknownProgress is returned by [Configure] for an unsupported progress mode.
+	ErrUnknownProgress = errUnknownProgress
+)
+
+var (
+	outputMu      sync.RWMutex
+	output        io.Writer = os.Stdout
+	interactive             = true
+	plainProgress bool
+)
+
+// Options controls how log output is rendered.
+type Options struct {
+	// Format is [FormatText] (default when empty) or [FormatJSON].
+	Format string
+	// NoColor strips ANSI escape codes. Spinners rely on cursor control,
+	// so they are disabled as well.
+	NoColor bool
+	// NoSpinner replaces animated spinners with static status lines.
+	NoSpinner bool
+	// Progress is [ProgressSpinner] (default when empty) or [ProgressPlain].
+	// Plain progress implies NoSpinner.
+	Progress string
+	// Quiet drops every line below the warn level, including job progress
+	// lines that bypass the logger level. It implies NoSpinner.
+	Quiet bool
+}
+
+// ansiPattern matches the CSI escape sequences emitted by bullets (colors and cursor moves).
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// levelByColor maps the color prefix bullets puts in front of the bullet symbol to a level name.
+var levelByColor = map[string]string{
+	"\x1b[2m":         "debug",
+	"\x1b[36m":        "info",
+	"\x1b[32m":        "success",
+	"\x1b[33m":        "warn",
+	"\x1b[31m":        "error",
+	"\x1b[91m\x1b[1m": "fatal",
+}
+
+// fieldsPattern matches the dimmed " (key=value, ...)" suffix bullets appends for fields.
+var fieldsPattern = regexp.MustCompile(`\x1b\[2m \(([^()]*=[^()]*)\)\x1b\[0m$`)
+
+// Configure selects how loggers created afterwards render their output.
+// It must be called before [NewLogger] and before any platform client is created.
+//
+// When stdout is not a terminal (piped or captured in CI), or the NO_COLOR
+// environment variable is set, text output falls back to plain lines without
+// ANSI codes even if opts does not ask for it.
+//
+// Returns [ErrUnknownFormat] if opts.Format is neither [FormatText] nor [FormatJSON],
+// and [ErrUnknownProgress] if opts.Progress is neither [ProgressSpinner] nor [ProgressPlain].
+func Configure(opts Options) error {
+	switch opts.Progress {
+	case ProgressSpinner, "":
+	case ProgressPlain:
+		opts.NoSpinner = true
+	default:
+		return fmt.Errorf("%w: %q (supported: %s, %s)",
+			errUnknownProgress, opts.Progress, ProgressSpinner, ProgressPlain)
+	}
+
+	if opts.Quiet {
+		opts.NoSpinner = true
+	}
+
+	isTTY := term.IsTerminal(int(os.Stdout.Fd())) //nolint:gosec // fd fits int on supported platforms
+	noColor := opts.NoColor || !isTTY || os.Getenv("NO_COLOR") != ""
+
+	outputMu.Lock()
+	defer outputMu.Unlock()
+
+	plainProgress = opts.Progress == ProgressPlain
+	switch opts.Format {
+	case FormatText, "":
+		switch {
+		case noColor:
+			output = NewPlainWriter(os.Stdout)
+		case opts.NoSpinner:
+			// Hiding the *os.File makes bullets render spinners as static lines
+			// while keeping colors.
+			output = struct{ io.Writer }{os.Stdout}
+		default:
+			output = os.Stdout
+		}
+		interactive = !noColor && !opts.NoSpinner
+	case FormatJSON:
+		output = NewJSONWriter(os.Stdout)
+		interactive = false
+	default:
+		return fmt.Errorf("%w: %q (supported: %s, %s)", errUnknownFormat, opts.Format, FormatText, FormatJSON)
+	}
+	if opts.Quiet {
+		output = NewQuietWriter(output)
+	}
+	return nil
+}
+
+// Output returns the writer loggers and updatable loggers should write to.
+// Unless spinners are enabled it is not a terminal, so bullets renders them as static lines.
+func Output() io.Writer {
+	outputMu.RLock()
+	defer outputMu.RUnlock()
+	return output
+}
+
+// Interactive reports whether the output is meant for a human watching spinners.
+// Wait loops use it to decide whether to emit periodic status lines instead.
+func Interactive() bool {
+	outputMu.RLock()
+	defer outputMu.RUnlock()
+	return interactive
+}
+
+// PlainProgress reports whether trackers should print a status line per running job
+// at every poll instead of creating spinners.
+func PlainProgress() bool {
+	outputMu.RLock()
+	defer outputMu.RUnlock()
+	return plainProgress
+}
+
+// plainWriter strips ANSI escape codes before writing to out.
+type plainWriter struct {
+	out io.Writer
+}
+
+// NewPlainWriter returns a writer that removes ANSI colors and cursor
+// movements from bullets output before writing it to out.
+func NewPlainWriter(out io.Writer) io.Writer {
+	return &plainWriter{out: out}
+}
+
+// Write strips escape codes from p and writes the remaining text.
+func (w *plainWriter) Write(p []byte) (int, error) {
+	if _, err := w.out.Write(ansiPattern.ReplaceAll(p, nil)); err != nil {
+		return 0, fmt.Errorf("failed to write log output: %w", err)
+	}
+	return len(p), nil
+}
+
+// quietWriter forwards only warning and error lines.
+type quietWriter struct {
+	mu  sync.Mutex
+	out io.Writer
+	buf []byte
+}
+
+// NewQuietWriter returns a writer that drops bullets lines below the warn level
+// (debug, info, success and spinner lines) and writes the others to out.
+func NewQuietWriter(out io.Writer) io.Writer {
+	return &quietWriter{out: out}
+}
+
+// Write buffers p and forwards each complete warn, error or fatal line.
+func (w *quietWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		idx := bytes.IndexByte(w.buf, '\n')
+		if idx < 0 {
+			break
+		}
+		line := w.buf[:idx+1]
+		w.buf = w.buf[idx+1:]
+
+		switch lineLevel(strings.TrimLeft(string(line), " \r")) {
+		case "warn", "error", "fatal":
+			if _, err := w.out.Write(line); err != nil {
+				return len(p), fmt.Errorf("failed to write log output: %w", err)
+			}
+		}
+	}
+	return len(p), nil
+}
+
+// lineLevel returns the level of a bullets line from its color prefix,
+// "info" when it has none.
+func lineLevel(line string) string {
+	for prefix, level := range levelByColor {
+		if strings.HasPrefix(line, prefix) {
+			return level
+		}
+	}
+	return "info"
+}
+
+// jsonWriter converts the lines written by bullets into JSON objects.
+type jsonWriter struct {
+	mu  sync.Mutex
+	out io.Writer
+	buf []byte
+	now func() time.Time
+}
+
+// jsonEntry is the shape of a structured log line.
+type jsonEntry struct {
+	Time   string         `json:"time"`
+	Level  string         `json:"level"`
+	Msg    string         `json:"msg"`
+	Fields map[string]any `json:"fields,omitempty"`
+}
+
+// NewJSONWriter returns a writer that turns bullets output into JSON lines on out.
+// Each line carries time (RFC 3339, UTC), level, msg and, when present, fields.
+// The writer is not a terminal, so bullets falls back to static spinner output.
+func NewJSONWriter(out io.Writer) io.Writer {
+	return &jsonWriter{out: out, now: time.Now}
+}
+
+// Write buffers p and emits one JSON object per complete line.
+func (w *jsonWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		idx := bytes.IndexByte(w.buf, '\n')
+		if idx < 0 {
+			break
+		}
+		line := string(w.buf[:idx])
+		w.buf = w.buf[idx+1:]
+
+		if err := w.writeLine(line); err != nil {
+			return len(p), err
+		}
+	}
+	return len(p), nil
+}
+
+// writeLine parses a single bullets line and writes it as JSON.
+// Blank lines (from Ln) are dropped.
+func (w *jsonWriter) writeLine(line string) error {
+	line = strings.TrimLeft(line, " \r")
+	if strings.TrimSpace(ansiPattern.ReplaceAllString(line, "")) == "" {
+		return nil
+	}
+
+	entry := jsonEntry{
+		Time:  w.now().UTC().Format(time.RFC3339),
+		Level: lineLevel(line),
+	}
+
+	if m := fieldsPattern.FindStringSubmatch(line); m != nil {
+		entry.Fields = parseFields(m[1])
+		line = line[:len(line)-len(m[0])]
+	}
+
+	msg := ansiPattern.ReplaceAllString(line, "")
+	// Drop the bullet symbol (or spinner frame) that precedes the message.
+	if _, rest, ok := strings.Cut(msg, " "); ok {
+		msg = rest
+	}
+	entry.Msg = strings.TrimSpace(msg)
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode log entry: %w", err)
+	}
+	data = append(data, '\n')
+	if _, err := w.out.Write(data); err != nil {
+		return fmt.Errorf("failed to write log entry: %w", err)
+	}
+	return nil
+}
+
+// parseFields splits "key=value, key2=value2" into a map.
+func parseFields(s string) map[string]any {
+	fields := make(map[string]any)
+	for part := range strings.SplitSeq(s, ", ") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		fields[key] = value
+	}
+	return fields
+}
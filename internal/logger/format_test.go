@@ -0,0 +1,149 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sgaunet/auto-mr/internal/logger"
+	"github.com/sgaunet/bullets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type entry struct {
+	Time   string         `json:"time"`
+	Level  string         `json:"level"`
+	Msg    string         `json:"msg"`
+	Fields map[string]any `json:"fields"`
+}
+
+func decodeLines(t *testing.T, out string) []entry {
+	t.Helper()
+
+	var entries []entry
+	for line := range strings.SplitSeq(strings.TrimSpace(out), "\n") {
+		var e entry
+		require.NoError(t, json.Unmarshal([]byte(line), &e), "line is not JSON: %q", line)
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestJSONWriter_Levels(t *testing.T) {
+	var buf bytes.Buffer
+	log := bullets.New(logger.NewJSONWriter(&buf))
+	log.SetLevel(bullets.DebugLevel)
+
+	log.Debug("debug message")
+	log.Info("info message")
+	log.Warn("warn message")
+	log.Error("error message")
+	log.Success("success message")
+	log.IncreasePadding()
+	log.WithField("iid", 42).Info("nested message")
+
+	entries := decodeLines(t, buf.String())
+	require.Len(t, entries, 6)
+
+	want := []struct{ level, msg string }{
+		{"debug", "debug message"},
+		{"info", "info message"},
+		{"warn", "warn message"},
+		{"error", "error message"},
+		{"success", "success message"},
+		{"info", "nested message"},
+	}
+	for i, w := range want {
+		assert.Equal(t, w.level, entries[i].Level)
+		assert.Equal(t, w.msg, entries[i].Msg)
+		assert.NotEmpty(t, entries[i].Time)
+	}
+	assert.Equal(t, map[string]any{"iid": "42"}, entries[5].Fields)
+	assert.NotContains(t, buf.String(), "\\u001b", "output must not contain ANSI escapes")
+}
+
+func TestJSONWriter_Spinner(t *testing.T) {
+	var buf bytes.Buffer
+	log := bullets.New(logger.NewJSONWriter(&buf))
+
+	spinner := log.SpinnerCircle(context.Background(), "build (running)")
+	spinner.Success("build (success)")
+
+	entries := decodeLines(t, buf.String())
+	require.Len(t, entries, 2)
+	assert.Equal(t, "build (running)", entries[0].Msg)
+	assert.Equal(t, "success", entries[1].Level)
+	assert.Equal(t, "build (success)", entries[1].Msg)
+}
+
+func TestPlainWriter(t *testing.T) {
+	var buf bytes.Buffer
+	log := bullets.New(logger.NewPlainWriter(&buf))
+
+	log.Info("info message")
+	spinner := log.SpinnerCircle(context.Background(), "build (running)")
+	spinner.Error("build (failed)")
+
+	out := buf.String()
+	assert.NotContains(t, out, "\x1b", "output must not contain ANSI escapes")
+	assert.Contains(t, out, "info message\n")
+	assert.Contains(t, out, "build (running)\n")
+	assert.Contains(t, out, "build (failed)\n")
+}
+
+func TestQuietWriter(t *testing.T) {
+	var buf bytes.Buffer
+	log := bullets.NewUpdatable(logger.NewQuietWriter(&buf))
+	log.SetLevel(bullets.DebugLevel)
+
+	log.Debug("debug message")
+	log.Info("info message")
+	log.Success("success message")
+	log.Warn("warn message")
+	log.Error("error message")
+	handle := log.InfoHandle("build (running)")
+	handle.Update(bullets.InfoLevel, "build (running, 1m)")
+	handle.Error("build (failed)")
+
+	out := buf.String()
+	assert.NotContains(t, out, "debug message")
+	assert.NotContains(t, out, "info message")
+	assert.NotContains(t, out, "success message")
+	assert.NotContains(t, out, "build (running")
+	assert.Contains(t, out, "warn message\n")
+	assert.Contains(t, out, "error message\n")
+	assert.Contains(t, out, "build (failed)\n")
+}
+
+func TestConfigure(t *testing.T) {
+	t.Cleanup(func() { _ = logger.Configure(logger.Options{}) })
+
+	require.NoError(t, logger.Configure(logger.Options{Format: logger.FormatJSON}))
+	assert.False(t, logger.Interactive())
+
+	require.NoError(t, logger.Configure(logger.Options{Format: logger.FormatText, NoSpinner: true}))
+	assert.False(t, logger.Interactive())
+
+	require.NoError(t, logger.Configure(logger.Options{NoColor: true}))
+	assert.False(t, logger.Interactive())
+
+	require.NoError(t, logger.Configure(logger.Options{Progress: logger.ProgressPlain}))
+	assert.False(t, logger.Interactive())
+	assert.True(t, logger.PlainProgress())
+
+	require.NoError(t, logger.Configure(logger.Options{Progress: logger.ProgressSpinner}))
+	assert.False(t, logger.PlainProgress())
+
+	require.NoError(t, logger.Configure(logger.Options{Quiet: true}))
+	assert.False(t, logger.Interactive())
+
+	err := logger.Configure(logger.Options{Format: "xml"})
+	assert.True(t, errors.Is(err, logger.ErrUnknownFormat))
+
+	err = logger.Configure(logger.Options{Progress: "dots"})
+	assert.True(t, errors.Is(err, logger.ErrUnknownProgress))
+}
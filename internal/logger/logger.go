@@ -9,11 +9,12 @@
 //	log.Debug("Starting operation")
 //
 //	silentLog := logger.NoLogger() // Suppresses all output
+//
+// Call [Configure] with [FormatJSON] before creating loggers to emit one JSON
+// object per line instead of colored bullets and spinners.
 package logger
 
 import (
-	"os"
-
 	"github.com/sgaunet/bullets"
 )
 
@@ -25,7 +26,7 @@ type Logger interface {
 	Error(msg string, args ...any)
 }
 
-// NewLogger creates a new logger that writes to stdout at the specified level.
+// NewLogger creates a new logger that writes to [Output] at the specified level.
 //
 // Parameters:
 //   - logLevel: one of "debug", "info", "warn", "error" (defaults to "info" for unknown values)
@@ -43,7 +44,7 @@ func NewLogger(logLevel string) *bullets.Logger {
 	default:
 		level = bullets.InfoLevel
 	}
-	logger := bullets.New(os.Stdout)
+	logger := bullets.New(Output())
 	logger.SetLevel(level)
 	return logger
 }
@@ -51,7 +52,7 @@ func NewLogger(logLevel string) *bullets.Logger {
 // NoLogger creates a logger that suppresses all output by setting the level to Fatal.
 // Useful for tests and silent operation.
 func NoLogger() *bullets.Logger {
-	logger := bullets.New(os.Stdout)
+	logger := bullets.New(Output())
 	logger.SetLevel(bullets.FatalLevel)
 	return logger
 }
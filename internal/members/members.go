@@ -0,0 +1,73 @@
+// Package members asks the user to choose the assignee or reviewer of a
+// merge/pull request among the project members, for configurations that
+// do not name one.
+package members
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/AlecAivazis/survey/v2"
+)
+
+// pageSize is the number of members shown at once in the selection UI.
+const pageSize = 15
+
+var (
+	errNoMembers          = errors.New("no project members to choose from")
+	errSelectionCancelled = errors.New("member selection cancelled")
+
+	// ErrNoMembers is returned by [Pick] when there is nobody to choose from.
+	ErrNoMembers = errNoMembers
+	// ErrSelectionCancelled is returned by [SurveyPrompter.Select] when the user cancels with Ctrl+C.
+	ErrSelectionCancelled = errSelectionCancelled
+)
+
+// Prompter shows options to the user and returns the index of the chosen one.
+type Prompter interface {
+	Select(message string, options []string) (int, error)
+}
+
+// SurveyPrompter implements [Prompter] with an interactive terminal prompt.
+type SurveyPrompter struct{}
+
+// Select shows a survey.Select prompt with at most [pageSize] options visible.
+// Returns [ErrSelectionCancelled] if the user cancels.
+func (SurveyPrompter) Select(message string, options []string) (int, error) {
+	prompt := &survey.Select{
+		Message:  message,
+		Options:  options,
+		PageSize: pageSize,
+	}
+
+	var index int
+	if err := survey.AskOne(prompt, &index); err != nil {
+		return -1, errSelectionCancelled
+	}
+	return index, nil
+}
+
+// Pick asks the user to choose the member playing role ("assignee" or "reviewer").
+// Members are offered in alphabetical order, without duplicates and without
+// exclude (e.g. the assignee when picking the reviewer).
+//
+// Returns [ErrNoMembers] if no member is left to choose from.
+func Pick(p Prompter, role string, members []string, exclude string) (string, error) {
+	options := make([]string, 0, len(members))
+	for _, member := range members {
+		if member != "" && member != exclude {
+			options = append(options, member)
+		}
+	}
+	slices.Sort(options)
+	options = slices.Compact(options)
+	if len(options) == 0 {
+		return "", errNoMembers
+	}
+
+	index, err := p.Select("Select the "+role+" of the merge/pull request:", options)
+	if err != nil {
+		return "", err //nolint:wrapcheck // Prompter errors are returned as is
+	}
+	return options[index], nil
+}
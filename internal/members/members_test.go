@@ -0,0 +1,43 @@
+package members_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sgaunet/auto-mr/internal/members"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePrompter records the options it is shown and picks a fixed index.
+type fakePrompter struct {
+	index   int
+	err     error
+	message string
+	options []string
+}
+
+func (f *fakePrompter) Select(message string, options []string) (int, error) {
+	f.message, f.options = message, options
+	return f.index, f.err
+}
+
+func TestPick(t *testing.T) {
+	p := &fakePrompter{index: 1}
+	picked, err := members.Pick(p, "reviewer", []string{"zoe", "alice", "", "bob", "alice"}, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "zoe"}, p.options)
+	assert.Equal(t, "zoe", picked)
+	assert.Contains(t, p.message, "reviewer")
+}
+
+func TestPickNoMembers(t *testing.T) {
+	_, err := members.Pick(&fakePrompter{}, "assignee", []string{"me"}, "me")
+	assert.ErrorIs(t, err, members.ErrNoMembers)
+}
+
+func TestPickCancelled(t *testing.T) {
+	cancelled := errors.New("cancelled")
+	_, err := members.Pick(&fakePrompter{err: cancelled}, "assignee", []string{"alice"}, "")
+	assert.ErrorIs(t, err, cancelled)
+}
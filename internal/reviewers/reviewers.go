@@ -0,0 +1,141 @@
+// Package reviewers picks the reviewer of a merge/pull request from a configured pool,
+// spreading the review load across a team.
+package reviewers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+)
+
+// Selection strategies accepted by [Picker.Pick].
+const (
+	StrategyRoundRobin = "round-robin" // Rotate through the pool, one reviewer per run
+	StrategyRandom     = "random"      // Pick a reviewer at random on each run
+)
+
+var (
+	errUnknownStrategy = errors.New("unknown reviewer strategy")
+	errEmptyPool       = errors.New("reviewer pool is empty")
+	errStateNotSaved   = errors.New("failed to save reviewer rotation")
+
+	// ErrUnknownStrategy is returned by [Picker.Pick] for a strategy other than
+	// [StrategyRoundRobin] or [StrategyRandom].
+	ErrUnknownStrategy = errUnknownStrategy
+	// ErrStateNotSaved is returned, along with the picked reviewer, when the
+	// round-robin position could not be persisted. The next run repeats the same reviewer.
+	ErrStateNotSaved = errStateNotSaved
+)
+
+// Picker selects reviewers, persisting the round-robin position in a JSON file
+// mapping each pool key to the index of the next reviewer.
+type Picker struct {
+	statePath string
+	intN      func(n int) int
+}
+
+// NewPicker creates a picker storing its round-robin state at statePath.
+// An empty statePath keeps no state, so round-robin always starts from the first reviewer.
+func NewPicker(statePath string) *Picker {
+	return &Picker{statePath: statePath, intN: rand.IntN}
+}
+
+// ValidStrategy reports whether strategy is a supported selection strategy.
+func ValidStrategy(strategy string) bool {
+	return strategy == StrategyRoundRobin || strategy == StrategyRandom
+}
+
+// Pick returns a reviewer from pool using strategy. key identifies the pool
+// (e.g. the platform name) so that separate pools rotate independently.
+//
+// Returns [ErrUnknownStrategy] for an unsupported strategy. With round-robin,
+// the reviewer is returned together with [ErrStateNotSaved] when the state file
+// cannot be written.
+func (p *Picker) Pick(key string, pool []string, strategy string) (string, error) {
+	return p.pick(key, pool, strategy, true)
+}
+
+// Peek returns the reviewer [Picker.Pick] would return, without advancing the
+// round-robin position, e.g. for a dry run. A random pick is drawn anew.
+func (p *Picker) Peek(key string, pool []string, strategy string) (string, error) {
+	return p.pick(key, pool, strategy, false)
+}
+
+// pick implements [Picker.Pick] and [Picker.Peek]; advance saves the next round-robin position.
+func (p *Picker) pick(key string, pool []string, strategy string, advance bool) (string, error) {
+	if len(pool) == 0 {
+		return "", errEmptyPool
+	}
+
+	switch strategy {
+	case StrategyRandom:
+		return pool[p.intN(len(pool))], nil
+	case StrategyRoundRobin:
+		return p.next(key, pool, advance)
+	default:
+		return "", fmt.Errorf("%w: %q (use %s or %s)", errUnknownStrategy, strategy,
+			StrategyRoundRobin, StrategyRandom)
+	}
+}
+
+// next returns the reviewer at the stored position for key, and advances it if asked to.
+func (p *Picker) next(key string, pool []string, advance bool) (string, error) {
+	if p.statePath == "" {
+		return pool[0], nil
+	}
+
+	// An unreadable state file restarts the rotation rather than failing the run.
+	state, err := p.load()
+	if err != nil {
+		state = make(map[string]int)
+	}
+
+	index := state[key] % len(pool)
+	if !advance {
+		return pool[index], nil
+	}
+	state[key] = index + 1
+	if err := p.save(state); err != nil {
+		return pool[index], fmt.Errorf("%w: %w", errStateNotSaved, err)
+	}
+	return pool[index], nil
+}
+
+// load reads the rotation state. A missing file yields an empty state.
+func (p *Picker) load() (map[string]int, error) {
+	state := make(map[string]int)
+
+	// #nosec G304 - State path is derived from the user's config directory
+	data, err := os.ReadFile(p.statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reviewer rotation: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse reviewer rotation: %w", err)
+	}
+	return state, nil
+}
+
+// save writes the rotation state, creating the parent directory if needed.
+func (p *Picker) save(state map[string]int) error {
+	if err := os.MkdirAll(filepath.Dir(p.statePath), 0o700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode reviewer rotation: %w", err)
+	}
+
+	if err := os.WriteFile(p.statePath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write reviewer rotation: %w", err)
+	}
+	return nil
+}
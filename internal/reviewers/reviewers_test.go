@@ -0,0 +1,98 @@
+package reviewers_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/sgaunet/auto-mr/internal/reviewers"
+)
+
+func TestPickRoundRobin(t *testing.T) {
+	picker := reviewers.NewPicker(filepath.Join(t.TempDir(), "state", "reviewers.json"))
+	pool := []string{"alice", "bob", "carol"}
+
+	want := []string{"alice", "bob", "carol", "alice"}
+	for i, expected := range want {
+		got, err := picker.Pick("gitlab", pool, reviewers.StrategyRoundRobin)
+		if err != nil {
+			t.Fatalf("run %d: unexpected error: %v", i, err)
+		}
+		if got != expected {
+			t.Errorf("run %d: expected %s, got %s", i, expected, got)
+		}
+	}
+
+	// Pools rotate independently per key.
+	got, err := picker.Pick("github", pool, reviewers.StrategyRoundRobin)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "alice" {
+		t.Errorf("expected alice for a new key, got %s", got)
+	}
+}
+
+func TestPickRoundRobinPersists(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "reviewers.json")
+	pool := []string{"alice", "bob"}
+
+	if _, err := reviewers.NewPicker(statePath).Pick("gitlab", pool, reviewers.StrategyRoundRobin); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := reviewers.NewPicker(statePath).Pick("gitlab", pool, reviewers.StrategyRoundRobin)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "bob" {
+		t.Errorf("expected bob on the next run, got %s", got)
+	}
+}
+
+func TestPeekRoundRobin(t *testing.T) {
+	picker := reviewers.NewPicker(filepath.Join(t.TempDir(), "reviewers.json"))
+	pool := []string{"alice", "bob"}
+
+	if _, err := picker.Pick("gitlab", pool, reviewers.StrategyRoundRobin); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := range 2 {
+		got, err := picker.Peek("gitlab", pool, reviewers.StrategyRoundRobin)
+		if err != nil {
+			t.Fatalf("peek %d: unexpected error: %v", i, err)
+		}
+		if got != "bob" {
+			t.Errorf("peek %d: expected bob, got %s", i, got)
+		}
+	}
+
+	got, err := picker.Pick("gitlab", pool, reviewers.StrategyRoundRobin)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "bob" {
+		t.Errorf("expected bob after peeking, got %s", got)
+	}
+}
+
+func TestPickRandom(t *testing.T) {
+	pool := []string{"alice", "bob", "carol"}
+	got, err := reviewers.NewPicker("").Pick("gitlab", pool, reviewers.StrategyRandom)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	found := false
+	for _, member := range pool {
+		found = found || member == got
+	}
+	if !found {
+		t.Errorf("expected a pool member, got %q", got)
+	}
+}
+
+func TestPickUnknownStrategy(t *testing.T) {
+	_, err := reviewers.NewPicker("").Pick("gitlab", []string{"alice"}, "least-busy")
+	if !errors.Is(err, reviewers.ErrUnknownStrategy) {
+		t.Errorf("expected ErrUnknownStrategy, got %v", err)
+	}
+}
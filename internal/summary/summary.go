@@ -0,0 +1,100 @@
+// Package summary holds the outcome of an auto-mr run and renders it as Markdown,
+// for the --summary-file report (e.g. a GitHub Actions step summary).
+//
+// Usage:
+//
+//	report := summary.Report{Platform: "GitHub", Branch: "feature", TargetBranch: "main"}
+//	report.Merged = true
+//	if err := summary.Append(os.Getenv("GITHUB_STEP_SUMMARY"), &report); err != nil {
+//	    log.Warnf("Failed to write the summary: %v", err)
+//	}
+package summary
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sgaunet/auto-mr/internal/timeutil"
+)
+
+const fileMode = 0o644
+
+// Report is the outcome of a run. Fields are filled in as the run progresses, so
+// a failed run leaves the later ones empty.
+type Report struct {
+	Platform      string        `json:"platform,omitempty"`
+	Branch        string        `json:"branch,omitempty"`
+	TargetBranch  string        `json:"target_branch,omitempty"`
+	URL           string        `json:"url,omitempty"`
+	Labels        []string      `json:"labels,omitempty"`
+	Pipeline      string        `json:"pipeline,omitempty"` // Final status, "skipped" (--no-wait) or "timeout"
+	Merged        bool          `json:"merged"`
+	BranchDeleted bool          `json:"branch_deleted"` // Local feature branch deleted by the cleanup
+	Duration      time.Duration `json:"duration"`
+	Error         string        `json:"error,omitempty"`
+}
+
+// Markdown renders the report as a Markdown section: a heading with the outcome,
+// a table of the fields that are set, and the error, if any.
+func (r *Report) Markdown() string {
+	var b strings.Builder
+
+	outcome := "merged"
+	switch {
+	case r.Error != "":
+		outcome = "failed"
+	case !r.Merged:
+		outcome = "not merged"
+	}
+	fmt.Fprintf(&b, "## auto-mr: %s\n\n", outcome)
+
+	b.WriteString("| | |\n|---|---|\n")
+	row := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "| %s | %s |\n", name, strings.ReplaceAll(value, "|", `\|`))
+		}
+	}
+	row("Platform", r.Platform)
+	if r.Branch != "" {
+		row("Branch", fmt.Sprintf("`%s` → `%s`", r.Branch, r.TargetBranch))
+	}
+	row("Merge/pull request", r.URL)
+	row("Labels", strings.Join(r.Labels, ", "))
+	row("Pipeline", r.Pipeline)
+	row("Merged", yesNo(r.Merged))
+	row("Local branch deleted", yesNo(r.BranchDeleted))
+	row("Duration", timeutil.FormatDuration(r.Duration))
+
+	if r.Error != "" {
+		fmt.Fprintf(&b, "\n**Error:**\n\n```\n%s\n```\n", r.Error)
+	}
+	return b.String()
+}
+
+// Append appends the Markdown report to the file at path, creating it if needed.
+// Appending keeps what earlier steps wrote to a shared file such as
+// $GITHUB_STEP_SUMMARY.
+func Append(path string, r *Report) error {
+	// #nosec G304 - The path comes from the user's own flags
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, fileMode)
+	if err != nil {
+		return fmt.Errorf("failed to open summary file: %w", err)
+	}
+	if _, err := f.WriteString(r.Markdown() + "\n"); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write summary file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close summary file: %w", err)
+	}
+	return nil
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,64 @@
+package summary_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sgaunet/auto-mr/internal/summary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdown(t *testing.T) {
+	t.Run("merged", func(t *testing.T) {
+		report := summary.Report{
+			Platform:      "GitHub",
+			Branch:        "feature",
+			TargetBranch:  "main",
+			URL:           "https://github.com/owner/repo/pull/5",
+			Labels:        []string{"bug", "ci"},
+			Pipeline:      "success",
+			Merged:        true,
+			BranchDeleted: true,
+			Duration:      83 * time.Second,
+		}
+		assert.Equal(t, "## auto-mr: merged\n\n"+
+			"| | |\n|---|---|\n"+
+			"| Platform | GitHub |\n"+
+			"| Branch | `feature` → `main` |\n"+
+			"| Merge/pull request | https://github.com/owner/repo/pull/5 |\n"+
+			"| Labels | bug, ci |\n"+
+			"| Pipeline | success |\n"+
+			"| Merged | yes |\n"+
+			"| Local branch deleted | yes |\n"+
+			"| Duration | 1m 23s |\n", report.Markdown())
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		report := summary.Report{
+			Platform: "GitLab",
+			Pipeline: "failed",
+			Duration: 5 * time.Second,
+			Error:    "pipeline failed with status: failed",
+		}
+		md := report.Markdown()
+		assert.Contains(t, md, "## auto-mr: failed\n")
+		assert.Contains(t, md, "| Merged | no |\n")
+		assert.NotContains(t, md, "| Branch |")
+		assert.Contains(t, md, "**Error:**\n\n```\npipeline failed with status: failed\n```\n")
+	})
+}
+
+func TestAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.md")
+	require.NoError(t, os.WriteFile(path, []byte("# Earlier step\n"), 0o600))
+
+	report := summary.Report{Platform: "Forgejo", Merged: true}
+	require.NoError(t, summary.Append(path, &report))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# Earlier step\n"+report.Markdown()+"\n", string(data))
+}
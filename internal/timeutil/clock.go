@@ -0,0 +1,73 @@
+package timeutil
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the time source of polling loops, so that their timeout and poll behavior
+// can be tested without real sleeps.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	Since(t time.Time) time.Duration
+}
+
+// RealClock is the [Clock] backed by the time package.
+type RealClock struct{}
+
+// Now returns the current time.
+func (RealClock) Now() time.Time { return time.Now() }
+
+// Sleep pauses the current goroutine for d.
+func (RealClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+// Since returns the time elapsed since t.
+func (RealClock) Since(t time.Time) time.Duration { return time.Since(t) }
+
+// FakeClock is a [Clock] whose time only moves when Sleep or Advance is called.
+// It is safe for concurrent use.
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps int
+}
+
+// NewFakeClock returns a [FakeClock] set to start.
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+// Now returns the fake current time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Sleep advances the fake time by d immediately and counts the call.
+func (c *FakeClock) Sleep(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.sleeps++
+}
+
+// Since returns the fake time elapsed since t.
+func (c *FakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// Advance moves the fake time forward by d without counting a sleep.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Sleeps returns how many times Sleep was called.
+func (c *FakeClock) Sleeps() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sleeps
+}
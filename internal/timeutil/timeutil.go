@@ -1,4 +1,5 @@
-// Package timeutil provides time formatting utilities for human-readable duration display.
+// Package timeutil provides time formatting utilities for human-readable duration display,
+// and the [Clock] used by polling loops.
 //
 // Durations are formatted as "Xm Ys" for durations of one minute or more,
 // and "Ys" for shorter durations. The value is rounded to the nearest second.
@@ -191,3 +191,22 @@ func TestFormatDuration_RoundingBehavior(t *testing.T) {
 		})
 	}
 }
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := timeutil.NewFakeClock(start)
+
+	clock.Sleep(5 * time.Second)
+	clock.Sleep(5 * time.Second)
+	clock.Advance(time.Minute)
+
+	if got := clock.Since(start); got != 70*time.Second {
+		t.Errorf("Since() = %v, want 70s", got)
+	}
+	if got := clock.Now(); !got.Equal(start.Add(70 * time.Second)) {
+		t.Errorf("Now() = %v, want %v", got, start.Add(70*time.Second))
+	}
+	if got := clock.Sleeps(); got != 2 {
+		t.Errorf("Sleeps() = %d, want 2", got)
+	}
+}
@@ -0,0 +1,80 @@
+// Package tlsutil provides TLS helpers for talking to self-hosted instances
+// that are signed by an internal certificate authority.
+//
+// A PEM bundle is loaded once with [LoadCABundle] and then installed on the
+// HTTP client used by the API clients via [NewHTTPClient]. The raw bundle is
+// also handed to go-git, which appends it to the system pool on its own.
+//
+// Usage:
+//
+//	bundle, err := tlsutil.LoadCABundle("/etc/ssl/internal-ca.pem")
+//	httpClient := tlsutil.NewHTTPClient(bundle)
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+var (
+	errCACertRead    = errors.New("failed to read CA certificate file")
+	errCACertInvalid = errors.New("no valid PEM certificates found in CA certificate file")
+
+	// ErrCACertRead is returned when the CA certificate file cannot be read.
+	ErrCACertRead = errCACertRead
+	// ErrCACertInvalid is returned when the CA certificate file contains no parsable PEM certificate.
+	ErrCACertInvalid = errCACertInvalid
+)
+
+// LoadCABundle reads a PEM-encoded CA bundle from path and checks that it
+// contains at least one certificate.
+//
+// Returns [ErrCACertRead] if the file cannot be read.
+// Returns [ErrCACertInvalid] if no certificate can be parsed from the file.
+func LoadCABundle(path string) ([]byte, error) {
+	// #nosec G304 - Reading a user-provided CA bundle is intentional
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", errCACertRead, path, err)
+	}
+
+	if !x509.NewCertPool().AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("%w: %s", errCACertInvalid, path)
+	}
+
+	return data, nil
+}
+
+// NewCertPool returns the system certificate pool extended with the
+// certificates from caBundle. When the system pool is unavailable, a pool
+// containing only caBundle is returned.
+func NewCertPool(caBundle []byte) *x509.CertPool {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	pool.AppendCertsFromPEM(caBundle)
+	return pool
+}
+
+// NewHTTPClient returns an HTTP client whose transport trusts the system
+// roots plus the certificates in caBundle. The transport is cloned from
+// [http.DefaultTransport] so proxy and timeout defaults are preserved.
+func NewHTTPClient(caBundle []byte) *http.Client {
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		transport = &http.Transport{}
+	}
+	transport = transport.Clone()
+
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	transport.TLSClientConfig.RootCAs = NewCertPool(caBundle)
+
+	return &http.Client{Transport: transport}
+}
@@ -0,0 +1,101 @@
+package tlsutil_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sgaunet/auto-mr/internal/tlsutil"
+)
+
+// writeTestCA generates a self-signed CA certificate and writes it as PEM to a temp file.
+func writeTestCA(t *testing.T) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "auto-mr test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("failed to write CA file: %v", err)
+	}
+	return path
+}
+
+func TestLoadCABundle(t *testing.T) {
+	t.Run("valid bundle", func(t *testing.T) {
+		bundle, err := tlsutil.LoadCABundle(writeTestCA(t))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(bundle) == 0 {
+			t.Error("expected non-empty bundle")
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := tlsutil.LoadCABundle(filepath.Join(t.TempDir(), "missing.pem"))
+		if !errors.Is(err, tlsutil.ErrCACertRead) {
+			t.Errorf("expected ErrCACertRead, got: %v", err)
+		}
+	})
+
+	t.Run("not PEM", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "garbage.pem")
+		if err := os.WriteFile(path, []byte("not a certificate"), 0o600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+
+		_, err := tlsutil.LoadCABundle(path)
+		if !errors.Is(err, tlsutil.ErrCACertInvalid) {
+			t.Errorf("expected ErrCACertInvalid, got: %v", err)
+		}
+	})
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	bundle, err := tlsutil.LoadCABundle(writeTestCA(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client := tlsutil.NewHTTPClient(bundle)
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected *http.Transport, got %T", client.Transport)
+	}
+	if transport.TLSClientConfig == nil || transport.TLSClientConfig.RootCAs == nil {
+		t.Fatal("expected RootCAs to be configured")
+	}
+	if transport == http.DefaultTransport {
+		t.Error("expected a cloned transport, not http.DefaultTransport")
+	}
+}
@@ -6,10 +6,18 @@
 //   - SSH colon: git@github.com:owner/repo
 //   - SSH protocol: ssh://git@github.com/owner/repo
 //
-// The .git suffix should be removed by the caller before calling [ExtractPathComponents].
+// The .git suffix should be removed by the caller before calling [ExtractPathComponents];
+// [ExtractPath] and [PathSegments] remove it themselves.
 package urlutil
 
-import "strings"
+import (
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// remoteSchemes are the URL schemes accepted by [PathSegments].
+var remoteSchemes = []string{"https", "http", "ssh", "git"}
 
 const (
 	// minColonParts is the minimum number of parts expected when splitting SSH colon format URLs.
@@ -63,3 +71,63 @@ func ExtractPathComponents(url string, componentCount int) string {
 	}
 	return ""
 }
+
+// ExtractPath extracts the whole path after the host from a git remote URL, as
+// returned by [PathSegments] joined with slashes, e.g. the full path of a GitLab
+// project in nested groups. Returns an empty string if the URL has no path or is
+// not a git remote URL.
+//
+// Examples:
+//
+//	ExtractPath("https://gitlab.com/group/subgroup/project.git") → "group/subgroup/project"
+//	ExtractPath("git@gitlab.com:group/subgroup/project") → "group/subgroup/project"
+//	ExtractPath("ssh://git@gitlab.com:2222/group/subgroup/project/") → "group/subgroup/project"
+func ExtractPath(remoteURL string) string {
+	return strings.Join(PathSegments(remoteURL), "/")
+}
+
+// PathSegments returns the URL-decoded path segments after the host of a git remote
+// URL. Credentials and a port in the host part are skipped, as are empty segments
+// (doubled or trailing slashes), and ".git" is removed from the end of the last one
+// only: "owner/repo.name.git" gives ["owner", "repo.name"].
+//
+// Accepted formats are http(s)://, ssh:// and git:// URLs and the scp-like
+// [user@]host:path form. Anything else, such as "github.com/owner/repo" (a local
+// path for git) or an ftp:// URL, returns nil.
+func PathSegments(remoteURL string) []string {
+	var path string
+	if scheme, rest, ok := strings.Cut(remoteURL, "://"); ok {
+		if !slices.Contains(remoteSchemes, strings.ToLower(scheme)) {
+			return nil
+		}
+		// scheme://[user@]host[:port]/path
+		_, path, _ = strings.Cut(rest, "/")
+	} else {
+		// [user@]host:path, where the host part has no slash
+		host, rest, ok := strings.Cut(remoteURL, ":")
+		if !ok || host == "" || strings.Contains(host, "/") {
+			return nil
+		}
+		path = rest
+	}
+
+	var segments []string
+	for segment := range strings.SplitSeq(strings.TrimRight(path, "/"), "/") {
+		if decoded, err := url.PathUnescape(segment); err == nil {
+			segment = decoded
+		}
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) == 0 {
+		return nil
+	}
+
+	last := len(segments) - 1
+	segments[last] = strings.TrimSuffix(segments[last], ".git")
+	if segments[last] == "" {
+		segments = segments[:last]
+	}
+	return segments
+}
@@ -0,0 +1,25 @@
+// Package userid recognizes users given by numeric ID ("id:12345") instead of
+// username in the GitLab assignee and reviewer settings.
+package userid
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Prefix marks a user given by numeric ID.
+const Prefix = "id:"
+
+// Parse returns the user ID of value when it has the form "id:<positive integer>".
+// Returns false for anything else, including usernames.
+func Parse(value string) (int64, bool) {
+	digits, found := strings.CutPrefix(value, Prefix)
+	if !found {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
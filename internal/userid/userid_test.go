@@ -0,0 +1,34 @@
+package userid_test
+
+import (
+	"testing"
+
+	"github.com/sgaunet/auto-mr/internal/userid"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		wantID int64
+		wantOK bool
+	}{
+		{"id", "id:12345", 12345, true},
+		{"username", "jane-doe", 0, false},
+		{"numeric username", "12345", 0, false},
+		{"empty id", "id:", 0, false},
+		{"zero", "id:0", 0, false},
+		{"negative", "id:-3", 0, false},
+		{"not a number", "id:jane", 0, false},
+		{"uppercase prefix", "ID:12345", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok := userid.Parse(tt.value)
+			if id != tt.wantID || ok != tt.wantOK {
+				t.Errorf("Parse(%q) = %d, %v, want %d, %v", tt.value, id, ok, tt.wantID, tt.wantOK)
+			}
+		})
+	}
+}
@@ -2,49 +2,181 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 	"time"
 
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/sgaunet/auto-mr/internal/browser"
+	"github.com/sgaunet/auto-mr/internal/codeowners"
+	"github.com/sgaunet/auto-mr/internal/hook"
 	autolabels "github.com/sgaunet/auto-mr/internal/labels"
 	"github.com/sgaunet/auto-mr/internal/logger"
+	"github.com/sgaunet/auto-mr/internal/members"
+	"github.com/sgaunet/auto-mr/internal/reviewers"
+	"github.com/sgaunet/auto-mr/internal/summary"
+	"github.com/sgaunet/auto-mr/internal/timeutil"
+	"github.com/sgaunet/auto-mr/internal/tlsutil"
 	"github.com/sgaunet/auto-mr/pkg/commits"
 	"github.com/sgaunet/auto-mr/pkg/config"
 	"github.com/sgaunet/auto-mr/pkg/git"
 	"github.com/sgaunet/auto-mr/pkg/platform"
 	"github.com/sgaunet/bullets"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
 )
 
 const (
-	maxLabelsToSelect      = 3
-	pipelineStartupDelay   = 2 * time.Second
+	defaultMaxLabels       = 3
+	mrVisibilityTimeout    = 15 * time.Second
 	defaultPipelineTimeout = 30 * time.Minute
+	caCertFileEnv          = "CA_CERT_FILE"
+	userAgentEnv           = "AUTO_MR_USER_AGENT"
+	defaultAPIConcurrency  = 4
+	defaultRequestTimeout  = 30 * time.Second
+	defaultDeployTimeout   = 15 * time.Minute
+	defaultStartupDelay    = 5 * time.Second
+	defaultChecksStart     = 2 * time.Minute
+	// freshCIClockSkew is subtracted from the local push time for --require-fresh-ci,
+	// compared with the creation time of the CI runs on the server.
+	freshCIClockSkew = 30 * time.Second
+)
+
+// --on-no-checks values.
+const (
+	onNoChecksFail  = "fail"
+	onNoChecksMerge = "merge"
+)
+
+// --show-diff values.
+const (
+	showDiffStat  = "stat"
+	showDiffPatch = "patch"
+)
+
+// --merge-method values.
+const (
+	mergeMethodSquash = "squash"
+	mergeMethodMerge  = "merge"
+	mergeMethodAuto   = "auto" // as enabled in the repository/project settings
+)
+
+// Process exit codes, so that scripts can tell why auto-mr failed.
+const (
+	exitOK              = 0 // merge/pull request merged (or nothing left to do)
+	exitError           = 1 // any other failure
+	exitPipelineFailed  = 2 // pipeline/workflows finished without success
+	exitPipelineTimeout = 3 // pipeline/workflows did not finish within the timeout
+	exitConfigError     = 4 // invalid or missing configuration, or invalid flag values
 )
 
 var (
-	errOnMainBranch  = errors.New("you are on the main branch. Please checkout to a feature branch")
-	errPipelineFailed = errors.New("pipeline failed")
-	errTooManyLabels  = errors.New("too many labels specified")
-	errLabelNotFound  = errors.New("label not found in repository")
+	errOnMainBranch         = errors.New("you are on the main branch. Please checkout to a feature branch")
+	errPipelineFailed       = errors.New("pipeline failed")
+	errTooManyLabels        = errors.New("too many labels specified")
+	errLabelNotFound        = errors.New("label not found in repository")
+	errRemoteBranchNotFound = errors.New("branch not found on remote")
+	errTargetBranchAdvanced = errors.New("target branch has advanced")
+	errInvalidFlag          = errors.New("invalid flag value")
+	errDeploymentFailed     = errors.New("deployment failed")
+	errPruneFailed          = errors.New("failed to delete stale branches")
+	errMergeDeclined        = errors.New("merge declined")
+	errCreateDeclined       = errors.New("merge/pull request creation declined")
+	errUnexpectedSHA        = errors.New("branch tip is not the expected commit")
+	errNoTitle              = errors.New("no usable merge/pull request title")
 )
 
 var (
 	logLevel        string
+	logFormat       string
+	noColor         bool
+	noSpinner       bool
+	quiet           bool   // Only warnings and errors, then the MR/PR URL; overrides --log-level
+	progressMode    string // How running jobs are shown while waiting: spinner or plain
 	showVersion     bool
 	noSquash        bool
+	mergeMethod     string // squash, merge or auto (empty: the squash settings)
+	mergeTitle      string // GitHub: title of the merge commit of a merge without squash
+	mergeMessage    string // GitHub: message of the merge commit of a merge without squash
+	noPush          bool
+	dryRun          bool // Print the merge/pull request that would be created, and stop before pushing
+	forceWithLease  bool // Push rewritten history unless the remote branch moved since the last fetch
+	requireUpToDate bool
+	pullBaseBranch  bool // Fast-forward the local target branch before opening the MR/PR
+	openWeb         bool
+	noUserCache     bool
+	targetRemote    string
+	mainBranchName  string // Merge target overriding detection and the main_branch setting
+	platformName    string // Platform used instead of detecting it from the remote URL
+	profile         string // Config profile whose platform settings are used (default: AUTO_MR_PROFILE)
+	showDiff        string // Print the branch diff (stat or patch) before creating the MR/PR
+	reviewStrategy  string // How the reviewer is picked from reviewer_pool
+	assumeYes       bool
+	confirmMerge    bool // Ask before merging, once CI passed (interactive runs only)
+	noAssignee      bool // Assign nobody, whatever the configuration says
+	noReviewer      bool // Request no review, whatever the configuration says
+	waitApprovals   bool
+	noWait          bool   // Merge without waiting for the pipeline/workflows
+	requireFreshCI  bool   // Only accept pipelines/workflow runs created after this run's push
+	rebase          bool   // GitLab: rebase the MR onto the target branch before waiting
+	triggerManual   bool   // GitLab: start manual jobs while waiting instead of ignoring them
+	changelogCmt    bool   // Post the branch commit list as a comment on the new MR/PR
+	codeOwners      bool   // Request review from the CODEOWNERS owners of the changed files
+	projectNumber   int    // GitHub: Projects (v2) board the PR is added to (0: none)
+	noCleanup       bool   // Leave the local repository untouched after the merge
+	noSwitch        bool   // After the merge, only fetch and prune: stay on the feature branch
+	pruneDryRun     bool   // prune subcommand: list the stale branches without deleting them
+	waitDeploy      bool   // After the merge, wait for the deployments of the target branch
+	closesIssue     bool   // Append "Closes #N" for the issue number in the branch name
+	preMergeHook    string // Shell command run after CI passes; a failure aborts the merge
+	postMergeHook   string // Shell command run after the merge; a failure is only reported
+	closeOnFailure  bool   // Close the MR/PR when the pipeline/workflows fail
+	deleteOnClose   bool   // With closeOnFailure: also delete the remote branch
+	closeDuplicates bool   // Close the other open MRs/PRs of the branch, keeping the newest
+	commitMessage   string // commit subcommand: message for the staged changes
 	msg             string
-	listLabels      bool   // List available labels and exit
-	labels          string // Comma-separated label names
-	pipelineTimeout string // Pipeline/workflow timeout duration
+	fromCommit      string        // Revision whose message becomes the MR/PR title and description
+	expectSHA       string        // Commit the pushed branch tip must be, or the run aborts
+	templateName    string        // .gitlab/merge_request_templates/ template used as the MR/PR description
+	listLabels      bool          // List available labels and exit
+	markReady       bool          // ready subcommand: mark the branch's draft MR/PR as ready and exit
+	autoMerge       bool          // ready subcommand: also enable auto-merge of the MR/PR
+	labels          string        // Comma-separated label names
+	mergedLabel     string        // Label added to the MR/PR right before merging
+	maxLabels       int           // Max labels applied to the MR/PR (0: unlimited)
+	pipelineTimeout string        // Pipeline/workflow timeout duration
+	apiConcurrency  int           // Max concurrent job fetches while waiting for pipelines
+	requestTimeout  time.Duration // Bound on each API call, separate from the pipeline timeout
+	deployTimeout   time.Duration // Bound on the --wait-deploy wait
+	startupDelay    time.Duration // GitHub: bound on the wait for workflows to be created after a push
+	checksStart     time.Duration // Bound on the wait for a pipeline/workflow to start (0: the whole timeout)
+	onNoChecks      string        // fail or merge when no pipeline/workflow started in time
+	ignoreChecks    string        // Comma-separated job/check name patterns that do not gate the merge
+	caCert          string        // PEM CA bundle for self-hosted instances
+	summaryFile     string        // Markdown run summary appended to this file (e.g. $GITHUB_STEP_SUMMARY)
 	log             *bullets.Logger
+	startTime       time.Time // start of the run, for the total duration report
 )
 
+// shaPattern matches the full or abbreviated commit SHAs accepted by --expect-sha.
+var shaPattern = regexp.MustCompile(`^[0-9a-fA-F]{7,40}$`)
+
+// runSummary is the outcome of the run, filled in as it progresses for --summary-file.
+var runSummary summary.Report
+
 var version = "dev"
 
 var rootCmd = &cobra.Command{
@@ -52,11 +184,18 @@ var rootCmd = &cobra.Command{
 	Short: "Automated merge request tool for GitLab, GitHub, and Forgejo",
 	Long: `auto-mr automates the process of creating and merging pull/merge requests
 on GitLab, GitHub, and Forgejo repositories. It handles pipeline waiting, auto-approval,
-and branch cleanup.`,
+and branch cleanup.
+
+Exit codes:
+  0  merged successfully
+  1  other error
+  2  pipeline/workflows failed
+  3  pipeline/workflows timed out
+  4  invalid configuration or flag values`,
 	Run: func(cmd *cobra.Command, _ []string) {
 		if showVersion {
 			fmt.Println(version)
-			os.Exit(0)
+			os.Exit(exitOK)
 		}
 		// Determine label selection mode
 		useManualLabels := cmd.Flags().Changed("labels")
@@ -64,7 +203,105 @@ and branch cleanup.`,
 
 		if err := runAutoMR(cmd, useManualLabels, manualLabelsValue); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			os.Exit(exitCode(err))
+		}
+	},
+}
+
+var commitCmd = &cobra.Command{
+	Use:   "commit",
+	Short: "Commit staged changes, then create and merge the merge/pull request",
+	Long: `commit records the staged changes as a new commit on the current branch
+(author from user.name and user.email in the git configuration), then runs the
+usual auto-mr flow: push, create, wait for the pipeline, merge and clean up.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		if strings.TrimSpace(commitMessage) == "" {
+			fmt.Fprintln(os.Stderr, "Error: a commit message is required (-m \"...\")")
+			os.Exit(exitConfigError)
+		}
+
+		if err := runAutoMR(cmd, cmd.Flags().Changed("labels"), labels); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+	},
+}
+
+var labelsCmd = &cobra.Command{
+	Use:   "labels",
+	Short: "List the labels available in the current repository, then exit",
+	Long: `labels prints the name, color and description of every label of the
+repository's merge/pull request platform, one per line, without creating anything.
+It is equivalent to auto-mr --list-labels.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		listLabels = true
+		if err := runAutoMR(cmd, false, ""); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+	},
+}
+
+var readyCmd = &cobra.Command{
+	Use:   "ready",
+	Short: "Mark the draft merge/pull request of the current branch as ready for review",
+	Long: `ready finds the open merge/pull request of the current branch and marks it as
+ready for review: the draft prefix ("Draft:", "[Draft]", "(Draft)", "WIP:", "[WIP]")
+is removed from the title on GitLab and Forgejo, and the draft state is cleared on GitHub.
+Nothing is pushed, created or merged.
+
+With --auto-merge, native auto-merge is enabled as well: the platform merges the
+merge/pull request with the configured method (squash unless --no-squash, --merge-method
+or the squash setting says otherwise) once its pipeline/checks succeed.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		markReady = true
+		if err := runAutoMR(cmd, false, ""); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+	},
+}
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete the local branches already merged or whose remote branch is gone",
+	Long: `prune runs git fetch --prune, then deletes the local branches left over from
+merged work: those whose upstream branch was deleted on the remote (as it is when
+auto-mr or the platform merges it), and those fully merged into the main branch.
+The main branch, the current branch and branches without commits of their own
+are kept. Use --dry-run to only list the branches that would be deleted.`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		if err := runPrune(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+	},
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the auto-mr configuration",
+	Args:  cobra.NoArgs,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration as YAML, then exit",
+	Long: `show loads the configuration the way a run would: the global config file,
+the repository's ` + config.RepoConfigFile + `, then the ` + config.EnvPrefix + `* environment
+variables. The run flags that set a configuration field (--main-branch,
+--pre-merge-hook, --post-merge-hook, --no-squash, --merge-method squash|merge,
+--pipeline-timeout) are applied on top, and the result is printed as YAML.
+An empty assignee or reviewer is accepted.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		if err := showConfig(cmd); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitCode(err))
 		}
 	},
 }
@@ -72,23 +309,248 @@ and branch cleanup.`,
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&logLevel, "log-level", "l", "info",
 		"Set log level (debug, info, warn, error)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", logger.FormatText,
+		"Set log output format (text, json); json disables colors and spinners")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false,
+		"Disable ANSI colors and spinners (automatic when stdout is not a terminal)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false,
+		"Only print warnings, errors and the final merge/pull request URL (overrides --log-level)")
+	rootCmd.PersistentFlags().BoolVar(&noSpinner, "no-spinner", false,
+		"Replace animated spinners with periodic status lines")
+	rootCmd.PersistentFlags().StringVar(&progressMode, "progress", logger.ProgressSpinner,
+		"How running jobs are shown while waiting (spinner, plain); plain prints one status line per job at every poll")
+	rootCmd.PersistentFlags().StringVar(&caCert, "ca-cert", "",
+		"Path to a PEM CA bundle to trust for self-hosted instances (env: "+caCertFileEnv+")")
 	rootCmd.Flags().BoolVarP(&showVersion, "version", "v", false, "Print version and exit")
-	rootCmd.Flags().BoolVar(&noSquash, "no-squash", false,
-		"Disable squash merge and preserve commit history (default: false, squashes commits)")
-	rootCmd.Flags().StringVar(&msg, "msg", "",
+	addRunFlags(rootCmd.Flags())
+
+	commitCmd.Flags().StringVarP(&commitMessage, "message", "m", "", "Commit message for the staged changes")
+	addRunFlags(commitCmd.Flags())
+	rootCmd.AddCommand(commitCmd)
+
+	labelsCmd.Flags().StringVar(&targetRemote, "target-remote", "",
+		"List the labels of this remote (e.g. upstream) instead of origin")
+	rootCmd.AddCommand(labelsCmd)
+
+	readyCmd.Flags().StringVar(&targetRemote, "target-remote", "",
+		"Look the merge/pull request up on this remote (e.g. upstream) instead of origin")
+	readyCmd.Flags().StringVar(&mainBranchName, "main-branch", "",
+		"Target branch of the merge/pull request, instead of the remote's default branch")
+	readyCmd.Flags().BoolVar(&autoMerge, "auto-merge", false,
+		"Also enable auto-merge: merge once the pipeline/checks succeed")
+	readyCmd.Flags().BoolVar(&noSquash, "no-squash", false,
+		"With --auto-merge: merge without squashing")
+	readyCmd.Flags().StringVar(&mergeMethod, "merge-method", "",
+		"With --auto-merge: squash, merge, or auto to follow the repository/project merge settings")
+	readyCmd.Flags().BoolVar(&closeDuplicates, "close-duplicates", false,
+		"When several merge/pull requests are open for the branch, close all but the newest, which is marked ready")
+	rootCmd.AddCommand(readyCmd)
+
+	pruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false,
+		"List the branches that would be deleted, without deleting them")
+	pruneCmd.Flags().StringVar(&mainBranchName, "main-branch", "",
+		"Branch the merged branches are compared with, instead of the remote's default branch")
+	rootCmd.AddCommand(pruneCmd)
+
+	addRunFlags(configShowCmd.Flags())
+	configCmd.AddCommand(configShowCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// addRunFlags registers the flags controlling the merge/pull request flow on flags.
+// They are shared by the root command and the commit subcommand.
+func addRunFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&profile, "profile", "",
+		"Config profile (under profiles:) whose gitlab/github/forgejo settings are used (default: $"+
+			config.EnvPrefix+"PROFILE)")
+	flags.BoolVar(&noSquash, "no-squash", false,
+		"Disable squash merge and preserve commit history (default: squash, unless the config sets squash: false)")
+	flags.StringVar(&mergeMethod, "merge-method", "",
+		"How to merge: squash, merge (like --no-squash), or auto to follow the repository/project merge settings")
+	flags.StringVar(&mergeTitle, "merge-commit-title", "",
+		"GitHub, merge without squash: title of the merge commit (default: the pull request title)")
+	flags.StringVar(&mergeMessage, "merge-commit-message", "",
+		"GitHub, merge without squash: message of the merge commit (default: the pull request description)")
+	flags.BoolVar(&noPush, "no-push", false,
+		"Skip pushing and use the branch already present on the remote")
+	flags.BoolVar(&dryRun, "dry-run", false,
+		"Print the merge/pull request that would be created (branches, title, assignee, reviewer, labels, squash), "+
+			"without pushing, creating or merging anything")
+	flags.StringVar(&showDiff, "show-diff", "",
+		"Print the diffstat of the branch against the target before pushing and creating the merge/pull request, "+
+			"then ask whether to go on; --show-diff=patch prints the full diff")
+	flags.Lookup("show-diff").NoOptDefVal = showDiffStat
+	flags.StringVar(&expectSHA, "expect-sha", "",
+		"Abort unless the branch tip, locally and on the remote before the merge/pull request is created, "+
+			"is this commit (full or abbreviated SHA), e.g. to guard CI runs against concurrent pushes")
+	flags.BoolVar(&forceWithLease, "force-with-lease", false,
+		"Push a rebased or amended branch, unless the remote branch changed since it was last fetched")
+	flags.BoolVar(&pullBaseBranch, "base-branch-auto-pull", false,
+		"Fast-forward the local target branch to the remote one (without switching to it) before opening the merge/pull request")
+	flags.BoolVar(&requireUpToDate, "require-up-to-date", false,
+		"Refuse to continue when the target branch has advanced since the feature branch diverged")
+	flags.BoolVar(&openWeb, "web", false,
+		"Open the merge/pull request in the browser once it is created")
+	flags.BoolVar(&waitApprovals, "wait-approvals", false,
+		"Wait (up to the pipeline timeout) for required GitLab approvals instead of failing")
+	flags.BoolVar(&noWait, "no-wait", false,
+		"Merge without waiting for the pipeline/workflows (CI results are ignored)")
+	flags.BoolVar(&requireFreshCI, "require-fresh-ci", false,
+		"Only accept pipelines/workflow runs created after the branch is pushed, never an earlier run of the same commit")
+	flags.BoolVar(&rebase, "rebase", false,
+		"Rebase the merge request onto the target branch on GitLab before waiting and merging")
+	flags.BoolVar(&triggerManual, "trigger-manual", false,
+		"Start the manual jobs of the GitLab pipeline while waiting, and wait for them too")
+	flags.BoolVar(&changelogCmt, "changelog-comment", false,
+		"Post the list of branch commits as a comment on the merge/pull request once it is created")
+	flags.BoolVar(&codeOwners, "reviewers-from-codeowners", false,
+		"Request review from the CODEOWNERS owners of the files changed on the branch, on top of the reviewer")
+	flags.IntVar(&projectNumber, "project", 0,
+		"Add the pull request to this GitHub Projects board of the repository owner (the number in the project URL)")
+	flags.BoolVar(&closesIssue, "closes-issue", false,
+		"Append \"Closes #N\" to the description when the branch name starts with an issue number (e.g. 123-fix-login)")
+	flags.BoolVar(&closeOnFailure, "close-on-failure", false,
+		"Close the merge/pull request when the pipeline/workflows fail")
+	flags.BoolVar(&deleteOnClose, "delete-branch-on-close", false,
+		"With --close-on-failure, also delete the remote branch")
+	flags.BoolVar(&closeDuplicates, "close-duplicates", false,
+		"When several merge/pull requests are open for the branch, close all but the newest, which is used")
+	flags.BoolVar(&noCleanup, "no-cleanup", false,
+		"Leave the local repository as is after the merge: no switch, pull, prune or branch deletion")
+	flags.BoolVar(&noSwitch, "no-switch", false,
+		"After the merge, stay on the feature branch and keep it; only fetch and prune remote branches")
+	flags.BoolVar(&waitDeploy, "wait-deploy", false,
+		"After the merge, wait for the GitHub deployments or GitLab environment deployments of the target branch and report their status")
+	flags.DurationVar(&deployTimeout, "deploy-timeout", defaultDeployTimeout,
+		"With --wait-deploy, how long to wait for the deployments (e.g. \"10m\", \"1h\")")
+	flags.StringVar(&preMergeHook, "pre-merge-hook", "",
+		"Shell command run from the repository root after CI passes; the merge is aborted if it fails (overrides pre_merge_hook)")
+	flags.StringVar(&postMergeHook, "post-merge-hook", "",
+		"Shell command run from the repository root after the merge, before cleanup; a failure only logs a warning (overrides post_merge_hook)")
+	flags.BoolVarP(&assumeYes, "yes", "y", false,
+		"Never prompt: use the most recent commit message and the configured default_labels")
+	flags.BoolVar(&confirmMerge, "confirm-merge", false,
+		"Ask for confirmation before merging, once CI passed (skipped with --yes or without a terminal)")
+	flags.BoolVar(&noAssignee, "no-assignee", false,
+		"Assign nobody to the merge/pull request, overriding the configured assignee")
+	flags.BoolVar(&noReviewer, "no-reviewer", false,
+		"Request no review of the merge/pull request, overriding the configured reviewer and reviewer_pool")
+	flags.StringVar(&targetRemote, "target-remote", "",
+		"Open the merge/pull request against this remote (e.g. upstream) while pushing to origin")
+	flags.StringVar(&reviewStrategy, "reviewer-strategy", reviewers.StrategyRoundRobin,
+		"How to pick the reviewer from reviewer_pool: round-robin or random")
+	flags.StringVar(&mainBranchName, "main-branch", "",
+		"Target branch to merge into, instead of the remote's default branch (overrides main_branch)")
+	flags.StringVar(&platformName, "platform", "",
+		"Platform of the remote: gitlab, github or forgejo (gitea), instead of detecting it from the remote URL")
+	flags.BoolVar(&noUserCache, "no-user-cache", false,
+		"Always resolve GitLab assignee/reviewer IDs via the API instead of the local cache")
+	flags.StringVar(&msg, "msg", "",
 		"Custom message for MR/PR (overrides commit message selection)")
-	rootCmd.Flags().BoolVar(&listLabels, "list-labels", false,
+	flags.StringVar(&fromCommit, "from-commit", "",
+		"Use the message of this commit (hash, branch, tag or e.g. HEAD~1) for the MR/PR title and description")
+	flags.StringVar(&templateName, "template", "",
+		"Use this template of .gitlab/merge_request_templates/ (e.g. Bug) as the MR/PR description, the commit summary replacing {{summary}}")
+	flags.BoolVar(&listLabels, "list-labels", false,
 		"List all available labels and exit")
-	rootCmd.Flags().StringVar(&labels, "labels", "",
+	flags.StringVar(&labels, "labels", "",
 		"Comma-separated label names (e.g., \"bug,enhancement\"). Use empty string to skip labels.")
-	rootCmd.Flags().StringVar(&pipelineTimeout, "pipeline-timeout", "",
+	flags.StringVar(&mergedLabel, "merged-label", "",
+		"Label added to the merge/pull request right before merging it (e.g. \"merged-by-bot\"); it must exist")
+	flags.IntVar(&maxLabels, "max-labels", defaultMaxLabels,
+		"Maximum number of labels applied to the merge/pull request (0 for unlimited)")
+	flags.StringVar(&pipelineTimeout, "pipeline-timeout", "",
 		"Pipeline/workflow timeout (e.g., \"30m\", \"1h\", \"90m\"). Overrides config file. (default: 30m)")
+	flags.StringVar(&summaryFile, "summary-file", "",
+		"Append a Markdown summary of the run to this file (e.g. \"$GITHUB_STEP_SUMMARY\")")
+	flags.IntVar(&apiConcurrency, "api-concurrency", defaultAPIConcurrency,
+		"Maximum concurrent API calls when fetching pipeline/workflow jobs")
+	flags.DurationVar(&requestTimeout, "request-timeout", defaultRequestTimeout,
+		"Timeout of each API call (e.g. \"30s\", \"2m\"), independent of the pipeline timeout")
+	flags.DurationVar(&startupDelay, "startup-delay", defaultStartupDelay,
+		"GitHub only: how long to look for the workflows of a new push before concluding there are none (0: check once)")
+	flags.DurationVar(&checksStart, "checks-start-timeout", defaultChecksStart,
+		"GitLab and GitHub: how long to wait for a pipeline/workflow to start before applying --on-no-checks "+
+			"(0: the whole pipeline timeout)")
+	flags.StringVar(&onNoChecks, "on-no-checks", onNoChecksFail,
+		"GitLab and GitHub: when no pipeline/workflow started within --checks-start-timeout: fail, or merge without checks")
+	flags.StringVar(&ignoreChecks, "ignore-checks", "",
+		"GitLab and GitHub: comma-separated job/check name patterns (e.g. \"lint-*,docs\") that are displayed "+
+			"but neither waited for nor able to fail the merge")
 }
 
 func main() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
+	}
+}
+
+// showConfig prints the configuration in effect for a run with the given flags.
+func showConfig(cmd *cobra.Command) error {
+	cfg, err := config.Load(config.AllowMissingUsers(), config.WithProfile(profile))
+	if err != nil {
+		return configError{formatConfigError(err)}
+	}
+
+	if branch := strings.TrimSpace(mainBranchName); branch != "" {
+		cfg.MainBranch = branch
+	}
+	if hookCmd := strings.TrimSpace(preMergeHook); hookCmd != "" {
+		cfg.PreMergeHook = hookCmd
+	}
+	if hookCmd := strings.TrimSpace(postMergeHook); hookCmd != "" {
+		cfg.PostMergeHook = hookCmd
+	}
+	if cmd.Flags().Changed("no-squash") || mergeMethod == mergeMethodSquash || mergeMethod == mergeMethodMerge {
+		// The flag overrides the platform sections as well as the global default.
+		cfg.Squash = new(getSquash(cmd, nil, nil))
+		cfg.GitLab.Squash, cfg.GitHub.Squash, cfg.Forgejo.Squash = nil, nil, nil
+	}
+	if cmd.Flags().Changed("pipeline-timeout") && pipelineTimeout != "" {
+		if _, err := getPipelineTimeout(cmd, ""); err != nil {
+			return configError{err}
+		}
+		cfg.GitLab.PipelineTimeout = pipelineTimeout
+		cfg.GitHub.PipelineTimeout = pipelineTimeout
+		cfg.Forgejo.PipelineTimeout = pipelineTimeout
+	}
+	dropUsers(cfg)
+
+	enc := yaml.NewEncoder(os.Stdout)
+	enc.SetIndent(2) //nolint:mnd // Same indentation as the documented config file
+	if err := enc.Encode(cfg); err != nil {
+		return fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	return nil
+}
+
+// configError marks an error caused by configuration (file, environment or flag
+// values) so that it maps to [exitConfigError]. It prints as the wrapped error.
+type configError struct {
+	err error
+}
+
+func (e configError) Error() string { return e.err.Error() }
+func (e configError) Unwrap() error { return e.err }
+
+// exitCode maps an error returned by runAutoMR to the process exit code.
+func exitCode(err error) int {
+	var cfgErr configError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &cfgErr):
+		return exitConfigError
+	case errors.Is(err, errPipelineFailed):
+		return exitPipelineFailed
+	case errors.Is(err, platform.ErrPipelineTimeout):
+		return exitPipelineTimeout
+	default:
+		return exitError
 	}
 }
 
@@ -125,6 +587,59 @@ func getPipelineTimeout(cmd *cobra.Command, platformConfig string) (time.Duratio
 	return defaultPipelineTimeout, nil
 }
 
+// getSquash resolves whether to squash from four sources with priority:
+// 1. CLI flag --merge-method squash or merge, or --no-squash when given explicitly.
+// 2. Config file platform-specific squash.
+// 3. Config file global squash.
+// 4. Default (squash).
+func getSquash(cmd *cobra.Command, platformConfig, globalConfig *bool) bool {
+	switch mergeMethod {
+	case mergeMethodSquash:
+		return true
+	case mergeMethodMerge:
+		return false
+	}
+	if cmd.Flags().Changed("no-squash") {
+		return !noSquash
+	}
+	if platformConfig != nil {
+		return *platformConfig
+	}
+	if globalConfig != nil {
+		return *globalConfig
+	}
+	return true
+}
+
+// resolveSquash returns whether to squash. With --merge-method auto, the merge methods
+// enabled on the repository/project decide, so that the merge is not refused: an
+// explicit choice (--no-squash or a squash setting) must be enabled there, otherwise
+// the preferred method is used.
+func resolveSquash(cmd *cobra.Command, provider platform.Provider, cfg *config.Config) (bool, error) {
+	if mergeMethod != mergeMethodAuto {
+		return getSquash(cmd, provider.Squash(), cfg.Squash), nil
+	}
+
+	var requested *bool
+	if cmd.Flags().Changed("no-squash") || provider.Squash() != nil || cfg.Squash != nil {
+		requested = new(getSquash(cmd, provider.Squash(), cfg.Squash))
+	}
+	methods, err := provider.MergeMethods()
+	if err != nil {
+		return false, fmt.Errorf("failed to get the merge methods of the repository: %w", err)
+	}
+	squash, err := methods.ChooseSquash(requested)
+	if err != nil {
+		return false, configError{err}
+	}
+	method := mergeMethodMerge
+	if squash {
+		method = mergeMethodSquash
+	}
+	log.Infof("Merge method from the repository settings: %s", method)
+	return squash, nil
+}
+
 // formatConfigError provides user-friendly error messages for configuration errors.
 func formatConfigError(err error) error {
 	homeDir, _ := os.UserHomeDir()
@@ -143,7 +658,9 @@ func formatConfigError(err error) error {
 	switch {
 	case errors.Is(err, config.ErrConfigNotFound):
 		return fmt.Errorf("%w\n\n"+
-			"Expected location: %s\n"+
+			"Expected location: %s (or "+config.RepoConfigFile+" at the repository root)\n"+
+			"Fields can also be set with "+config.EnvPrefix+"* environment variables "+
+			"(e.g. "+config.EnvPrefix+"GITLAB_ASSIGNEE).\n"+
 			"Please create a config file with the following structure:\n\n"+
 			"gitlab:\n"+
 			"  assignee: your-gitlab-username\n"+
@@ -180,7 +697,19 @@ func formatConfigError(err error) error {
 			"Usernames must:\n"+
 			"  - Contain only letters, numbers, hyphens (-), or underscores (_)\n"+
 			"  - Start and end with a letter or number\n"+
-			"  - Be between 1 and 39 characters long",
+			"  - Be between 1 and 39 characters long\n"+
+			"On GitLab, a user may also be given by numeric ID: id:12345",
+			err, configPath)
+
+	case errors.Is(err, config.ErrProfileNotFound):
+		return fmt.Errorf("%w\n\n"+
+			"Config file: %s\n"+
+			"Define the profile under profiles:, e.g.\n\n"+
+			"profiles:\n"+
+			"  work:\n"+
+			"    gitlab:\n"+
+			"      assignee: your-work-username\n"+
+			"      reviewer: reviewer-work-username",
 			err, configPath)
 
 	default:
@@ -238,14 +767,120 @@ func formatTimeoutError(err error, configPath string) error {
 	}
 }
 
-func runAutoMR(cmd *cobra.Command, useManualLabels bool, manualLabelsValue string) error {
+// setupLogging configures the output from the logging flags and creates the logger.
+func setupLogging() error {
+	if err := logger.Configure(logger.Options{
+		Format:    logFormat,
+		NoColor:   noColor,
+		NoSpinner: noSpinner,
+		Progress:  progressMode,
+		Quiet:     quiet,
+	}); err != nil {
+		if errors.Is(err, logger.ErrUnknownProgress) {
+			return configError{fmt.Errorf("invalid --progress: %w", err)}
+		}
+		return configError{fmt.Errorf("invalid --log-format: %w", err)}
+	}
+	if quiet {
+		logLevel = "warn"
+	}
 	log = logger.NewLogger(logLevel)
+	return nil
+}
+
+func runAutoMR(cmd *cobra.Command, useManualLabels bool, manualLabelsValue string) (err error) {
+	startTime = time.Now()
+	runSummary = summary.Report{}
+	if err := setupLogging(); err != nil {
+		return err
+	}
 	log.Info("auto-mr starting...")
+	if summaryFile != "" {
+		defer func() { writeSummary(err) }()
+	}
 
-	cfg, err := config.Load()
+	switch mergeMethod {
+	case "", mergeMethodAuto:
+	case mergeMethodSquash, mergeMethodMerge:
+		if cmd.Flags().Changed("no-squash") {
+			return configError{fmt.Errorf("%w: --merge-method %s cannot be combined with --no-squash",
+				errInvalidFlag, mergeMethod)}
+		}
+	default:
+		return configError{fmt.Errorf("%w: --merge-method must be %s, %s or %s, got %q", errInvalidFlag,
+			mergeMethodSquash, mergeMethodMerge, mergeMethodAuto, mergeMethod)}
+	}
+	if !reviewers.ValidStrategy(reviewStrategy) {
+		return configError{fmt.Errorf("%w: --reviewer-strategy must be %s or %s, got %q", errInvalidFlag,
+			reviewers.StrategyRoundRobin, reviewers.StrategyRandom, reviewStrategy)}
+	}
+	if maxLabels < 0 {
+		return configError{fmt.Errorf("%w: --max-labels must not be negative, got %d", errInvalidFlag, maxLabels)}
+	}
+	if msg != "" && fromCommit != "" {
+		return configError{fmt.Errorf("%w: --msg and --from-commit cannot be combined", errInvalidFlag)}
+	}
+	if forceWithLease && noPush {
+		return configError{fmt.Errorf("%w: --force-with-lease cannot be combined with --no-push", errInvalidFlag)}
+	}
+	if requireFreshCI && noPush {
+		return configError{fmt.Errorf("%w: --require-fresh-ci cannot be combined with --no-push", errInvalidFlag)}
+	}
+	if dryRun && commitMessage != "" {
+		return configError{fmt.Errorf("%w: --dry-run cannot be combined with the commit subcommand", errInvalidFlag)}
+	}
+	if deleteOnClose && !closeOnFailure {
+		return configError{fmt.Errorf("%w: --delete-branch-on-close requires --close-on-failure", errInvalidFlag)}
+	}
+	if apiConcurrency < 1 {
+		return configError{fmt.Errorf("%w: --api-concurrency must be at least 1, got %d",
+			errInvalidFlag, apiConcurrency)}
+	}
+	if requestTimeout <= 0 {
+		return configError{fmt.Errorf("%w: --request-timeout must be positive, got %s",
+			errInvalidFlag, requestTimeout)}
+	}
+	if startupDelay < 0 {
+		return configError{fmt.Errorf("%w: --startup-delay must not be negative, got %s",
+			errInvalidFlag, startupDelay)}
+	}
+	if checksStart < 0 {
+		return configError{fmt.Errorf("%w: --checks-start-timeout must not be negative, got %s",
+			errInvalidFlag, checksStart)}
+	}
+	if onNoChecks != onNoChecksFail && onNoChecks != onNoChecksMerge {
+		return configError{fmt.Errorf("%w: --on-no-checks must be %s or %s, got %q",
+			errInvalidFlag, onNoChecksFail, onNoChecksMerge, onNoChecks)}
+	}
+	if expectSHA != "" && !shaPattern.MatchString(expectSHA) {
+		return configError{fmt.Errorf("%w: --expect-sha must be a commit SHA of 7 to 40 hex digits, got %q",
+			errInvalidFlag, expectSHA)}
+	}
+	for _, pattern := range ignoredChecks() {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return configError{fmt.Errorf("%w: --ignore-checks pattern %q: %w", errInvalidFlag, pattern, err)}
+		}
+	}
+	if showDiff != "" && showDiff != showDiffStat && showDiff != showDiffPatch {
+		return configError{fmt.Errorf("%w: --show-diff must be %s or %s, got %q",
+			errInvalidFlag, showDiffStat, showDiffPatch, showDiff)}
+	}
+
+	loadOpts := []config.LoadOption{config.WithProfile(profile)}
+	if promptsAllowed() {
+		loadOpts = append(loadOpts, config.AllowMissingUsers()) // asked for by askMissingUsers
+	}
+	if noAssignee {
+		loadOpts = append(loadOpts, config.AllowMissingAssignee())
+	}
+	if noReviewer {
+		loadOpts = append(loadOpts, config.AllowMissingReviewer())
+	}
+	cfg, err := config.Load(loadOpts...)
 	if err != nil {
-		return formatConfigError(err)
+		return configError{formatConfigError(err)}
 	}
+	dropUsers(cfg)
 	log.Debug("Configuration loaded successfully")
 
 	repo, err := git.OpenRepository(".")
@@ -253,16 +888,113 @@ func runAutoMR(cmd *cobra.Command, useManualLabels bool, manualLabelsValue strin
 		return fmt.Errorf("failed to open git repository: %w", err)
 	}
 	repo.SetLogger(log)
+	if branch := cmp.Or(strings.TrimSpace(mainBranchName), cfg.MainBranch); branch != "" {
+		repo.SetMainBranch(branch)
+	}
+	preMergeHook = cmp.Or(strings.TrimSpace(preMergeHook), cfg.PreMergeHook)
+	postMergeHook = cmp.Or(strings.TrimSpace(postMergeHook), cfg.PostMergeHook)
+	namedTemplate, err := loadNamedTemplate(repo)
+	if err != nil {
+		return err
+	}
 
-	detectedPlatform, err := repo.DetectPlatform(cfg.Forgejo.URL)
+	httpClient, err := setupCACert(cmd, repo)
 	if err != nil {
-		return fmt.Errorf("failed to detect platform: %w", err)
+		return err
+	}
+	remotes, err := repo.ResolveRemotes(targetRemote)
+	if err != nil {
+		return fmt.Errorf("failed to resolve remotes: %w", err)
+	}
+	providerOpts := platform.Options{
+		HTTPClient:         httpClient,
+		UserCachePath:      userCachePath(),
+		APIConcurrency:     apiConcurrency,
+		RequestTimeout:     requestTimeout,
+		PlayManualJobs:     triggerManual,
+		StartupDelay:       &startupDelay,
+		ChecksStartTimeout: checksStart,
+		RequireChecks:      cmd.Flags().Changed("checks-start-timeout") || cmd.Flags().Changed("on-no-checks"),
+		IgnoredChecks:      ignoredChecks(),
+		UserAgent:          userAgent(),
+	}
+	if remotes.IsFork() {
+		log.Infof("Opening merge request against %s from %s", remotes.TargetURL, remotes.PushURL)
+		providerOpts.HeadRemoteURL = remotes.PushURL
+	}
+
+	detectedPlatform, err := resolvePlatform(repo, cfg)
+	if err != nil {
+		return err
+	}
+	if err := platform.CheckToken(detectedPlatform); err != nil {
+		return configError{err}
+	}
+	repo.SetPushUsername(pushUsername(detectedPlatform, cfg))
+	if rebase && detectedPlatform != git.PlatformGitLab {
+		return configError{fmt.Errorf("%w: --rebase is only supported on GitLab", errInvalidFlag)}
+	}
+	if triggerManual && detectedPlatform != git.PlatformGitLab {
+		return configError{fmt.Errorf("%w: --trigger-manual is only supported on GitLab", errInvalidFlag)}
+	}
+	if (mergeTitle != "" || mergeMessage != "") && detectedPlatform != git.PlatformGitHub {
+		return configError{fmt.Errorf("%w: --merge-commit-title and --merge-commit-message are only supported on GitHub",
+			errInvalidFlag)}
+	}
+	if cmd.Flags().Changed("startup-delay") && detectedPlatform != git.PlatformGitHub {
+		return configError{fmt.Errorf("%w: --startup-delay is only supported on GitHub", errInvalidFlag)}
+	}
+	if (cmd.Flags().Changed("checks-start-timeout") || cmd.Flags().Changed("on-no-checks")) &&
+		detectedPlatform == git.PlatformForgejo {
+		return configError{fmt.Errorf("%w: --checks-start-timeout and --on-no-checks are only supported "+
+			"on GitLab and GitHub", errInvalidFlag)}
+	}
+	if requireFreshCI && detectedPlatform == git.PlatformForgejo {
+		return configError{fmt.Errorf("%w: --require-fresh-ci is only supported on GitLab and GitHub", errInvalidFlag)}
+	}
+	if ignoreChecks != "" && detectedPlatform == git.PlatformForgejo {
+		return configError{fmt.Errorf("%w: --ignore-checks is only supported on GitLab and GitHub", errInvalidFlag)}
+	}
+	if waitDeploy {
+		if deployTimeout <= 0 {
+			return configError{fmt.Errorf("%w: --deploy-timeout must be positive, got %s",
+				errInvalidFlag, deployTimeout)}
+		}
+		if detectedPlatform == git.PlatformForgejo {
+			return configError{fmt.Errorf("%w: --wait-deploy is only supported on GitLab and GitHub", errInvalidFlag)}
+		}
+	}
+	if cmd.Flags().Changed("project") {
+		if projectNumber < 1 {
+			return configError{fmt.Errorf("%w: --project must be a positive project number, got %d",
+				errInvalidFlag, projectNumber)}
+		}
+		if detectedPlatform != git.PlatformGitHub {
+			return configError{fmt.Errorf("%w: --project is only supported on GitHub", errInvalidFlag)}
+		}
+	}
+
+	// Created once for the whole run: initializing it validates the project/repository
+	// with an API call. It reads cfg at each call, so pickReviewer and askMissingUsers
+	// may still fill in the participants.
+	provider, err := newProvider(detectedPlatform, cfg, remotes.TargetURL, providerOpts)
+	if err != nil {
+		return err
+	}
+
+	runSummary.Platform = provider.PlatformName()
+	squash, err := resolveSquash(cmd, provider, cfg)
+	if err != nil {
+		return err
+	}
+	if (mergeTitle != "" || mergeMessage != "") && squash {
+		return configError{fmt.Errorf("%w: --merge-commit-title and --merge-commit-message need a merge without "+
+			"squash (--merge-method merge, --no-squash or squash: false)", errInvalidFlag)}
 	}
-	log.Infof("Platform detected: %s", detectedPlatform)
 
 	// Handle --list-labels flag (list and exit)
 	if listLabels {
-		return handleListLabels(detectedPlatform, cfg, repo)
+		return handleListLabels(provider, remotes.TargetURL)
 	}
 
 	mainBranch, currentBranch, err := validateBranches(repo)
@@ -270,23 +1002,360 @@ func runAutoMR(cmd *cobra.Command, useManualLabels bool, manualLabelsValue strin
 		return err
 	}
 
-	if err := prepareRepository(repo, currentBranch); err != nil {
+	if markReady {
+		return handleReady(provider, currentBranch, mainBranch, squash)
+	}
+	runSummary.Branch, runSummary.TargetBranch = currentBranch, mainBranch
+
+	if commitMessage != "" {
+		if err := commitStaged(repo); err != nil {
+			return err
+		}
+	} else if merged := findMerged(provider, repo, currentBranch, mainBranch); merged != nil {
+		// A previous run merged this branch but was interrupted: only cleanup is left.
+		log.Infof("Merge/pull request already merged: %s", merged.WebURL)
+		if dryRun {
+			fmt.Printf("Would only clean up: %s is already merged\n", merged.WebURL)
+			return nil
+		}
+		runSummary.URL, runSummary.Merged = merged.WebURL, true
+		if err := cleanup(context.Background(), repo, mainBranch, currentBranch); err != nil {
+			return err
+		}
+		printQuietURL(merged.WebURL)
+		return nil
+	}
+
+	pickReviewer(detectedPlatform, cfg)
+	if err := askMissingUsers(provider, detectedPlatform, cfg); err != nil {
 		return err
 	}
 
-	title, body, err := getCommitInfo(repo)
+	warnUnsignedCommits(repo, mainBranch)
+
+	if showDiff != "" {
+		if err := showBranchDiff(repo, mainBranch); err != nil {
+			return err
+		}
+	}
+
+	if expectSHA != "" && !noPush {
+		if err := checkHeadSHA(repo); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case dryRun:
+		log.Info("Dry run: not pushing")
+	case noPush:
+		if err := verifyRemoteBranch(repo, currentBranch); err != nil {
+			return err
+		}
+	default:
+		pushedAt := time.Now().Add(-freshCIClockSkew)
+		pushed, err := prepareRepository(repo, currentBranch)
+		if err != nil {
+			return err
+		}
+		if requireFreshCI {
+			if err := requireFreshRuns(provider, pushed, pushedAt); err != nil {
+				return err
+			}
+		}
+	}
+
+	if expectSHA != "" && !dryRun {
+		if err := checkRemoteSHA(repo, currentBranch); err != nil {
+			return err
+		}
+	}
+
+	if pullBaseBranch && !dryRun {
+		updateTargetBranch(repo, mainBranch)
+	}
+	if err := checkTargetBranch(repo, remotes.Target, mainBranch, currentBranch); err != nil {
+		return err
+	}
+
+	title, body, err := getCommitInfo(repo, cfg.TitleTransform, namedTemplate)
 	if err != nil {
 		return err
 	}
+	if title, err = usableTitle(title, currentBranch); err != nil {
+		return err
+	}
+	if body, err = appendBodyFooter(cfg.BodyFooter, currentBranch, mainBranch, body); err != nil {
+		return configError{err}
+	}
+
+	if dryRun {
+		return printPlan(provider, detectedPlatform, cfg, currentBranch, mainBranch, title,
+			squash, useManualLabels, manualLabelsValue)
+	}
+	return handlePlatform(cmd, provider, currentBranch, mainBranch, title, body, repo,
+		squash, useManualLabels, manualLabelsValue)
+}
+
+// writeSummary appends the run summary, with the error the run ended with, to
+// --summary-file. A failure to write it is only logged.
+func writeSummary(runErr error) {
+	runSummary.Duration = time.Since(startTime)
+	if runErr != nil {
+		runSummary.Error = runErr.Error()
+	}
+	if err := summary.Append(summaryFile, &runSummary); err != nil {
+		log.Warnf("Failed to write the run summary: %v", err)
+	}
+}
+
+// runPrune deletes the stale local branches (prune subcommand), after refreshing
+// the remote-tracking branches.
+func runPrune() error {
+	if err := setupLogging(); err != nil {
+		return err
+	}
+
+	repo, err := git.OpenRepository(".")
+	if err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+	repo.SetLogger(log)
+	if cfg, err := config.Load(config.AllowMissingUsers(), config.WithProfile(profile)); err == nil {
+		repo.SetMainBranch(cfg.MainBranch)
+	} else {
+		log.Debugf("Configuration not loaded, detecting the main branch: %v", err)
+	}
+	if branch := strings.TrimSpace(mainBranchName); branch != "" {
+		repo.SetMainBranch(branch)
+	}
+	mainBranch, err := repo.GetMainBranch()
+	if err != nil {
+		return fmt.Errorf("failed to get main branch: %w", err)
+	}
+
+	ctx := context.Background()
+	if err := repo.FetchAndPrune(ctx); err != nil {
+		log.Warnf("Fetch and prune failed, using the remote branches from the last fetch: %v", err)
+	}
+
+	stale, err := repo.StaleBranches(mainBranch)
+	if err != nil {
+		return fmt.Errorf("failed to list stale branches: %w", err)
+	}
+	if len(stale) == 0 {
+		log.Info("No stale local branches")
+		return nil
+	}
+
+	failed := 0
+	for _, branch := range stale {
+		if pruneDryRun {
+			log.Infof("Would delete %s (%s)", branch.Name, branch.Reason)
+			continue
+		}
+		if err := repo.DeleteBranch(ctx, branch.Name); err != nil {
+			log.Warnf("%s Delete %s - %v", getStatusIcon(false, err), branch.Name, err)
+			failed++
+			continue
+		}
+		log.Infof("%s Deleted %s (%s)", getStatusIcon(true, nil), branch.Name, branch.Reason)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d branches", errPruneFailed, failed, len(stale))
+	}
+	return nil
+}
+
+// findMerged returns the merged merge/pull request of currentBranch at its current
+// HEAD when no open one exists, i.e. when a previous run merged the branch but stopped
+// before cleanup. Lookup failures are logged and reported as not merged.
+func findMerged(
+	provider platform.Provider, repo *git.Repository, currentBranch, mainBranch string,
+) *platform.MergeRequest {
+	_, err := provider.GetByBranch(currentBranch, mainBranch)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, platform.ErrNotFound) {
+		log.Debugf("Could not look up an open merge/pull request: %v", err)
+		return nil
+	}
+
+	headSHA, err := repo.HeadSHA()
+	if err != nil {
+		log.Debugf("Could not read HEAD: %v", err)
+		return nil
+	}
+	merged, err := provider.FindMerged(currentBranch, mainBranch, headSHA)
+	if err != nil {
+		if !errors.Is(err, platform.ErrNotFound) {
+			log.Debugf("Could not look up a merged merge/pull request: %v", err)
+		}
+		return nil
+	}
+	return merged
+}
+
+// setupCACert resolves the CA bundle path from two sources with priority:
+// 1. CLI flag --ca-cert (highest priority).
+// 2. CA_CERT_FILE environment variable.
+//
+// When a path is set, the bundle is installed on the git repository and an HTTP
+// client trusting it is returned for the API clients. Returns a nil client otherwise.
+func setupCACert(cmd *cobra.Command, repo *git.Repository) (*http.Client, error) {
+	path := os.Getenv(caCertFileEnv)
+	if cmd.Flags().Changed("ca-cert") {
+		path = caCert
+	}
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, nil //nolint:nilnil // nil client means library defaults
+	}
+
+	caBundle, err := tlsutil.LoadCABundle(path)
+	if err != nil {
+		return nil, configError{fmt.Errorf("invalid CA certificate: %w", err)}
+	}
+	log.Debugf("Using custom CA bundle: %s", path)
+
+	repo.SetCABundle(caBundle)
+	return tlsutil.NewHTTPClient(caBundle), nil
+}
+
+// ignoredChecks returns the non-empty patterns of --ignore-checks.
+func ignoredChecks() []string {
+	var patterns []string
+	for pattern := range strings.SplitSeq(ignoreChecks, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// userAgent returns the User-Agent of API requests: $AUTO_MR_USER_AGENT, or else
+// auto-mr/<version>, so that instance admins can tell auto-mr traffic apart.
+func userAgent() string {
+	return cmp.Or(strings.TrimSpace(os.Getenv(userAgentEnv)), "auto-mr/"+version)
+}
+
+// userCachePath returns the GitLab user ID cache file, or "" when caching is disabled.
+func userCachePath() string {
+	if noUserCache {
+		return ""
+	}
+	configDir, err := config.Dir()
+	if err != nil {
+		log.Debugf("User ID cache disabled: %v", err)
+		return ""
+	}
+	return filepath.Join(configDir, "cache", "gitlab-users.json")
+}
+
+// reviewerStatePath returns the file holding the reviewer rotation, or "" if unavailable.
+func reviewerStatePath() string {
+	configDir, err := config.Dir()
+	if err != nil {
+		log.Debugf("Reviewer rotation not persisted: %v", err)
+		return ""
+	}
+	return filepath.Join(configDir, "state", "reviewer-rotation.json")
+}
+
+// pickReviewer replaces the platform's configured reviewer with one picked from its
+// reviewer_pool, when a pool is configured.
+func pickReviewer(p git.Platform, cfg *config.Config) {
+	_, reviewer, pool := platformUsers(p, cfg)
+	if len(pool) == 0 {
+		return
+	}
+
+	picker := reviewers.NewPicker(reviewerStatePath())
+	pick := picker.Pick
+	if dryRun {
+		pick = picker.Peek // the next run still gets this reviewer
+	}
+	picked, err := pick(string(p), pool, reviewStrategy)
+	if err != nil {
+		// Only the rotation state can fail here: the strategy was validated at startup.
+		log.Warnf("%v", err)
+	}
+	*reviewer = picked
+	log.Infof("Reviewer picked from pool (%s): %s", reviewStrategy, picked)
+}
+
+// dropUsers clears the assignee (--no-assignee) and the reviewer and reviewer pool
+// (--no-reviewer) of every platform section, so that the run sets none.
+func dropUsers(cfg *config.Config) {
+	if noAssignee {
+		cfg.GitLab.Assignee, cfg.GitHub.Assignee, cfg.Forgejo.Assignee = "", "", ""
+	}
+	if noReviewer {
+		cfg.GitLab.Reviewer, cfg.GitHub.Reviewer, cfg.Forgejo.Reviewer = "", "", ""
+		cfg.GitLab.ReviewerPool, cfg.GitHub.ReviewerPool, cfg.Forgejo.ReviewerPool = nil, nil, nil
+	}
+}
 
-	return routeToPlatform(
-		cmd, detectedPlatform, cfg, currentBranch, mainBranch, title, body, repo,
-		useManualLabels, manualLabelsValue,
-	)
+// platformUsers returns the assignee and reviewer fields and the reviewer pool of
+// the configuration section of platform p.
+func platformUsers(p git.Platform, cfg *config.Config) (*string, *string, []string) {
+	switch p {
+	case git.PlatformGitHub:
+		return &cfg.GitHub.Assignee, &cfg.GitHub.Reviewer, cfg.GitHub.ReviewerPool
+	case git.PlatformForgejo:
+		return &cfg.Forgejo.Assignee, &cfg.Forgejo.Reviewer, cfg.Forgejo.ReviewerPool
+	default:
+		return &cfg.GitLab.Assignee, &cfg.GitLab.Reviewer, cfg.GitLab.ReviewerPool
+	}
+}
+
+// promptsAllowed reports whether the run may ask questions: not --yes, and stdin is a terminal.
+func promptsAllowed() bool {
+	return !assumeYes && term.IsTerminal(int(os.Stdin.Fd())) //nolint:gosec // fd fits int on supported platforms
+}
+
+// askMissingUsers lets the user pick the assignee and reviewer among the project
+// members when the configuration names none, unless --no-assignee or --no-reviewer
+// leaves it empty on purpose. Only interactive runs get here with other missing
+// users: the others fail configuration validation.
+func askMissingUsers(provider platform.Provider, p git.Platform, cfg *config.Config) error {
+	assignee, reviewer, _ := platformUsers(p, cfg)
+	askAssignee, askReviewer := *assignee == "" && !noAssignee, *reviewer == "" && !noReviewer
+	if !askAssignee && !askReviewer {
+		return nil
+	}
+
+	candidates, err := provider.ListMembers()
+	if err != nil {
+		return fmt.Errorf("failed to list members to pick the assignee/reviewer from: %w", err)
+	}
+
+	var picked []string
+	if askAssignee {
+		if *assignee, err = members.Pick(members.SurveyPrompter{}, "assignee", candidates, ""); err != nil {
+			return fmt.Errorf("failed to pick the assignee: %w", err)
+		}
+		picked = append(picked, "assignee: "+*assignee)
+	}
+	if askReviewer {
+		if *reviewer, err = members.Pick(members.SurveyPrompter{}, "reviewer", candidates, *assignee); err != nil {
+			return fmt.Errorf("failed to pick the reviewer: %w", err)
+		}
+		picked = append(picked, "reviewer: "+*reviewer)
+	}
+	log.Infof("To skip these questions, set %s in the %s configuration section",
+		strings.Join(picked, " and "), strings.ToLower(provider.PlatformName()))
+	return nil
 }
 
 func validateBranches(repo *git.Repository) (string, string, error) {
 	mainBranch, err := repo.GetMainBranch()
+	if errors.Is(err, git.ErrMainBranchNotFound) {
+		return "", "", fmt.Errorf("failed to get main branch: %w\n\n"+
+			"Set the branch to merge into with --main-branch <branch>,\n"+
+			"or main_branch: <branch> in the configuration", err)
+	}
 	if err != nil {
 		return "", "", fmt.Errorf("failed to get main branch: %w", err)
 	}
@@ -294,7 +1363,7 @@ func validateBranches(repo *git.Repository) (string, string, error) {
 
 	currentBranch, err := repo.GetCurrentBranch()
 	if err != nil {
-		return "", "", fmt.Errorf("failed to get current branch: %w", err)
+		return "", "", formatCurrentBranchError(err)
 	}
 	log.Infof("Current branch: %s", currentBranch)
 
@@ -305,19 +1374,192 @@ func validateBranches(repo *git.Repository) (string, string, error) {
 	return mainBranch, currentBranch, nil
 }
 
-func prepareRepository(repo *git.Repository, currentBranch string) error {
+// formatCurrentBranchError adds actionable guidance when HEAD is detached.
+// The original error stays in the chain so errors.Is still matches [git.ErrHEADNotBranch].
+func formatCurrentBranchError(err error) error {
+	if errors.Is(err, git.ErrHEADNotBranch) {
+		return fmt.Errorf("%w\n\n"+
+			"You are in detached HEAD state (e.g. after \"git checkout <sha>\").\n"+
+			"Create a branch from the current commit first:\n"+
+			"  git switch -c <branch-name>",
+			err)
+	}
+	return fmt.Errorf("failed to get current branch: %w", err)
+}
+
+// commitStaged commits the staged changes with the commit subcommand's message.
+func commitStaged(repo *git.Repository) error {
+	hash, err := repo.CommitStaged(commitMessage)
+	if errors.Is(err, git.ErrNothingStaged) {
+		return fmt.Errorf("%w\n\nStage the changes to include first:\n  git add <files>", err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	log.Infof("Committed staged changes: %s", hash[:7])
+	return nil
+}
+
+// warnUnsignedCommits warns when commit.gpgsign is enabled but some of the branch's
+// commits are unsigned, since repositories requiring signatures will reject them.
+// The merge or squash commit itself is created, and signed, by the platform.
+func warnUnsignedCommits(repo *git.Repository, mainBranch string) {
+	if !repo.SigningEnabled() {
+		return
+	}
+	unsigned, err := repo.UnsignedCommits(mainBranch)
+	if err != nil {
+		log.Debugf("Could not check commit signatures: %v", err)
+		return
+	}
+	if len(unsigned) > 0 {
+		log.Warnf("commit.gpgsign is enabled but %d commit(s) are unsigned: %s",
+			len(unsigned), strings.Join(unsigned, ", "))
+		log.Warn("Re-sign them with: git rebase --exec 'git commit --amend --no-edit -S' " + mainBranch)
+	}
+}
+
+// prepareRepository pushes the branch and returns what the push did.
+func prepareRepository(repo *git.Repository, currentBranch string) (git.PushResult, error) {
 	log.Infof("Pushing branch: %s", currentBranch)
 	log.IncreasePadding()
-	if err := repo.PushBranch(currentBranch); err != nil {
+	push := repo.PushBranch
+	if forceWithLease {
+		push = repo.PushBranchWithLease
+	}
+	result, err := push(currentBranch)
+	if err != nil {
 		log.DecreasePadding()
-		return fmt.Errorf("failed to push branch: %w", err)
+		return "", pushError(err, currentBranch)
+	}
+	switch result {
+	case git.PushCreated:
+		log.Info("Branch pushed successfully (new branch on origin)")
+	case git.PushUpToDate:
+		log.Info("Branch already up to date on origin, nothing pushed")
+	default:
+		log.Info("Branch pushed successfully")
 	}
-	log.Info("Branch pushed successfully")
 	log.DecreasePadding()
+	return result, nil
+}
+
+// requireFreshRuns applies --require-fresh-ci after a push made at pushedAt. When the
+// branch was already up to date, no new CI run will start, so the existing ones are
+// accepted instead of waiting for the timeout.
+func requireFreshRuns(provider platform.Provider, pushed git.PushResult, pushedAt time.Time) error {
+	if pushed == git.PushUpToDate {
+		log.Warn("--require-fresh-ci: nothing was pushed, so no new CI run will start; accepting the existing ones")
+		return nil
+	}
+	if err := provider.RequireFreshCI(pushedAt); err != nil {
+		return fmt.Errorf("failed to require fresh CI: %w", err)
+	}
+	log.Debugf("Only accepting CI runs created since %s", pushedAt.Format(time.RFC3339))
+	return nil
+}
+
+// verifyRemoteBranch ensures the branch already exists on origin when --no-push is set,
+// so MR/PR creation does not fail later with a less obvious platform error.
+func verifyRemoteBranch(repo *git.Repository, currentBranch string) error {
+	log.Infof("Skipping push, checking remote branch: %s", currentBranch)
+	exists, err := repo.RemoteBranchExists(currentBranch)
+	if err != nil {
+		return fmt.Errorf("failed to check remote branch: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s\n\nPush it first or run without --no-push", errRemoteBranchNotFound, currentBranch)
+	}
 	return nil
 }
 
-func getCommitInfo(repo *git.Repository) (string, string, error) {
+// checkHeadSHA aborts the run before pushing unless the local branch tip, the commit
+// about to be pushed, is the --expect-sha commit.
+func checkHeadSHA(repo *git.Repository) error {
+	head, err := repo.HeadSHA()
+	if err != nil {
+		return fmt.Errorf("failed to get the branch tip: %w", err)
+	}
+	if !matchesSHA(head, expectSHA) {
+		return fmt.Errorf("%w: HEAD is %s, expected %s", errUnexpectedSHA, head[:7], expectSHA)
+	}
+	return nil
+}
+
+// checkRemoteSHA aborts the run before the merge/pull request is created unless the
+// remote branch tip is the --expect-sha commit, i.e. nobody pushed to it meanwhile.
+func checkRemoteSHA(repo *git.Repository, currentBranch string) error {
+	remote, err := repo.RemoteBranchSHA(currentBranch)
+	if err != nil {
+		return fmt.Errorf("failed to check remote branch: %w", err)
+	}
+	if remote == "" {
+		return fmt.Errorf("%w: %s", errRemoteBranchNotFound, currentBranch)
+	}
+	if !matchesSHA(remote, expectSHA) {
+		return fmt.Errorf("%w: origin/%s is %s, expected %s (was it pushed to concurrently?)",
+			errUnexpectedSHA, currentBranch, remote[:7], expectSHA)
+	}
+	log.Debugf("Remote branch tip is the expected commit %s", remote[:7])
+	return nil
+}
+
+// matchesSHA reports whether the full commit SHA sha starts with the full or
+// abbreviated SHA expected, ignoring case.
+func matchesSHA(sha, expected string) bool {
+	return strings.HasPrefix(sha, strings.ToLower(expected))
+}
+
+// updateTargetBranch fast-forwards the local target branch to the remote one
+// (--base-branch-auto-pull), so that local diffs and merge bases match the merge/pull
+// request. Failures are logged: the merge/pull request targets the remote branch anyway.
+func updateTargetBranch(repo *git.Repository, mainBranch string) {
+	updated, err := repo.FastForwardBranch(context.Background(), mainBranch)
+	switch {
+	case errors.Is(err, git.ErrNotFastForward):
+		log.Warnf("Local %s has commits that are not on origin, leaving it as is", mainBranch)
+	case err != nil:
+		log.Warnf("Could not update local %s: %v", mainBranch, err)
+	case updated:
+		log.Infof("Fast-forwarded local %s to origin/%s", mainBranch, mainBranch)
+	default:
+		log.Debugf("Local %s is up to date (or does not exist)", mainBranch)
+	}
+}
+
+// checkTargetBranch warns when the target branch on targetRemote (the --target-remote
+// of a fork workflow, origin otherwise) has commits missing from the feature branch.
+// With --require-up-to-date the run is aborted until the branch is rebased.
+// Lookup failures are not fatal unless --require-up-to-date is set.
+func checkTargetBranch(repo *git.Repository, targetRemote, mainBranch, currentBranch string) error {
+	behind, err := repo.IsBehindRemoteBranch(targetRemote, mainBranch)
+	if err != nil {
+		if requireUpToDate {
+			return fmt.Errorf("failed to check target branch: %w", err)
+		}
+		log.Debugf("Could not check if %s has advanced: %v", mainBranch, err)
+		return nil
+	}
+	if !behind {
+		return nil
+	}
+
+	if requireUpToDate {
+		return fmt.Errorf("%w: %s/%s has commits not in %s\n\n"+
+			"Rebase your branch before merging:\n"+
+			"  git fetch %s && git rebase %s/%s",
+			errTargetBranchAdvanced, targetRemote, mainBranch, currentBranch, targetRemote, targetRemote, mainBranch)
+	}
+	log.Warnf("%s/%s has advanced since %s diverged; consider rebasing before merging",
+		targetRemote, mainBranch, currentBranch)
+	return nil
+}
+
+// getCommitInfo returns the merge/pull request title and description: from --msg
+// as is, otherwise from the selected commit message, with the title reshaped by
+// title_transform. The description is put into the --template template (namedTemplate)
+// when set, even with --msg.
+func getCommitInfo(repo *git.Repository, transform config.TitleTransform, namedTemplate string) (string, string, error) {
 	slogLogger := createSlogLogger()
 
 	// Create commit retriever
@@ -327,7 +1569,7 @@ func getCommitInfo(repo *git.Repository) (string, string, error) {
 	// Get current branch name
 	currentBranch, err := repo.GetCurrentBranch()
 	if err != nil {
-		return "", "", fmt.Errorf("failed to get current branch: %w", err)
+		return "", "", formatCurrentBranchError(err)
 	}
 
 	// Get main branch name
@@ -336,6 +1578,19 @@ func getCommitInfo(repo *git.Repository) (string, string, error) {
 		return "", "", fmt.Errorf("failed to get main branch: %w", err)
 	}
 
+	if fromCommit != "" {
+		selection, err := retriever.GetMessageFromRevision(fromCommit)
+		if err != nil {
+			return "", "", configError{fmt.Errorf("%w: --from-commit: %w", errInvalidFlag, err)}
+		}
+		log.Infof("Using the message of commit %s", selection.SourceCommitHash[:commits.DefaultShortHashLength])
+		title, err := transformTitle(transform, selection.Title)
+		if err != nil {
+			return "", "", err
+		}
+		return title, applyBodyTemplate(repo, namedTemplate, selection.Body), nil
+	}
+
 	// Get message selection (handles manual override, auto-select, and interactive selection)
 	selection, err := retriever.GetMessageForMR(currentBranch, mainBranch, msg)
 	if err != nil {
@@ -344,8 +1599,119 @@ func getCommitInfo(repo *git.Repository) (string, string, error) {
 			return "", "", err
 		}
 	}
-
-	return selection.Title, selection.Body, nil
+
+	if msg != "" {
+		if namedTemplate != "" {
+			return selection.Title, commits.ApplyBodyTemplate(namedTemplate, selection.Body), nil
+		}
+		return selection.Title, selection.Body, nil
+	}
+	title, err := transformTitle(transform, selection.Title)
+	if err != nil {
+		return "", "", err
+	}
+	return title, applyBodyTemplate(repo, namedTemplate, selection.Body), nil
+}
+
+// usableTitle returns title, or the branch name when the commit subject is empty, so
+// that the platform does not reject the merge/pull request with an opaque error.
+// Returns errNoTitle if neither is usable.
+func usableTitle(title, branch string) (string, error) {
+	if title = strings.TrimSpace(title); title != "" {
+		return title, nil
+	}
+	if branch = strings.TrimSpace(branch); branch == "" {
+		return "", fmt.Errorf("%w: the commit subject is empty, set a title with --msg", errNoTitle)
+	}
+	log.Warnf("The commit subject is empty: using the branch name %s as title", branch)
+	return branch, nil
+}
+
+// transformTitle applies title_transform to a title taken from a commit subject.
+// An empty subject is left for usableTitle to replace.
+func transformTitle(transform config.TitleTransform, subject string) (string, error) {
+	if transform.IsZero() || subject == "" {
+		return subject, nil
+	}
+	title, err := transform.Apply(subject)
+	if err != nil {
+		return "", configError{fmt.Errorf("%w (commit subject: %q)", err, subject)}
+	}
+	if title != subject {
+		log.Debugf("Title transformed: %q -> %q", subject, title)
+	}
+	return title, nil
+}
+
+// appendBodyFooter appends the body_footer setting, rendered for the branch, to body,
+// then with --closes-issue a "Closes #N" line for the issue the branch name references.
+func appendBodyFooter(footerTemplate, currentBranch, mainBranch, body string) (string, error) {
+	issue, found := commits.IssueFromBranch(currentBranch)
+	footer, err := commits.RenderFooter(footerTemplate, commits.FooterData{
+		Branch:       currentBranch,
+		TargetBranch: mainBranch,
+		Issue:        issue,
+	})
+	if err != nil {
+		return "", fmt.Errorf("body_footer: %w", err)
+	}
+	body = commits.AppendFooter(body, footer)
+
+	if closesIssue {
+		if !found {
+			log.Warnf("--closes-issue: no issue number in branch name %s", currentBranch)
+			return body, nil
+		}
+		body = commits.AppendFooter(body, fmt.Sprintf("Closes #%d", issue))
+	}
+	return body, nil
+}
+
+// loadNamedTemplate reads the --template merge request template, before anything is
+// pushed, so that a wrong name fails the run early. Returns an empty template without
+// --template.
+func loadNamedTemplate(repo *git.Repository) (string, error) {
+	if templateName == "" {
+		return "", nil
+	}
+	root, err := repo.RootDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the repository root: %w", err)
+	}
+	tmpl, err := commits.LoadNamedTemplate(root, templateName)
+	if err != nil {
+		if errors.Is(err, commits.ErrTemplateNotFound) {
+			return "", configError{fmt.Errorf("%w: --template: %w", errInvalidFlag, err)}
+		}
+		return "", err
+	}
+	log.Debugf("Using the %s merge request template as description", templateName)
+	return tmpl, nil
+}
+
+// applyBodyTemplate returns the MR/PR template with the commit-derived body substituted
+// for its {{summary}} placeholder: namedTemplate (--template) when set, otherwise the
+// repository's default template. The commit-derived body is returned unchanged when
+// the repository has no template or it cannot be read.
+func applyBodyTemplate(repo *git.Repository, namedTemplate, body string) string {
+	if namedTemplate != "" {
+		return commits.ApplyBodyTemplate(namedTemplate, body)
+	}
+	root, err := repo.RootDir()
+	if err != nil {
+		log.Debugf("Could not locate the repository root for the MR/PR template: %v", err)
+		return body
+	}
+	tmpl, err := commits.LoadBodyTemplate(root)
+	if err != nil {
+		log.Warnf("Ignoring MR/PR template: %v", err)
+		return body
+	}
+	if tmpl == "" {
+		return body
+	}
+	log.Debug("Using the repository's MR/PR template as description")
+	return commits.ApplyBodyTemplate(tmpl, body)
 }
 
 func createSlogLogger() *slog.Logger {
@@ -362,7 +1728,11 @@ func createSlogLogger() *slog.Logger {
 	default:
 		slogLevel = slog.LevelInfo
 	}
-	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slogLevel}))
+	opts := &slog.HandlerOptions{Level: slogLevel}
+	if logFormat == logger.FormatJSON {
+		return slog.New(slog.NewJSONHandler(os.Stderr, opts))
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, opts))
 }
 
 func handleInteractiveSelection(
@@ -374,7 +1744,12 @@ func handleInteractiveSelection(
 ) (commits.MessageSelection, error) {
 	// If multiple commits found, use interactive selector
 	if errors.Is(origErr, commits.ErrMultipleCommitsFound) {
-		selector := commits.NewSelector(commits.NewRenderer())
+		var renderer commits.SelectionRenderer = commits.NewRenderer()
+		if assumeYes {
+			log.Info("Multiple commits found, using the most recent commit message (--yes)")
+			renderer = commits.NewDefaultRenderer()
+		}
+		selector := commits.NewSelector(renderer)
 		selector.SetLogger(slogLogger)
 
 		// Get commits since divergence from main branch
@@ -393,31 +1768,58 @@ func handleInteractiveSelection(
 	return commits.MessageSelection{}, fmt.Errorf("failed to get commit message: %w", origErr)
 }
 
-func routeToPlatform(
-	cmd *cobra.Command,
-	detectedPlatform git.Platform,
-	cfg *config.Config,
-	currentBranch, mainBranch, title, body string,
-	repo *git.Repository,
-	useManualLabels bool,
-	manualLabelsValue string,
-) error {
-	provider, err := platform.NewProvider(detectedPlatform, cfg, log)
+// resolvePlatform returns the platform named by --platform, making pushes use its token,
+// or else the one detected from the origin remote URL.
+func resolvePlatform(repo *git.Repository, cfg *config.Config) (git.Platform, error) {
+	if platformName == "" {
+		detected, err := repo.DetectPlatform(cfg.Forgejo.URL)
+		if err != nil {
+			return "", fmt.Errorf("failed to detect platform: %w", err)
+		}
+		log.Infof("Platform detected: %s", detected)
+		return detected, nil
+	}
+
+	forced, err := git.ParsePlatform(platformName)
 	if err != nil {
-		return fmt.Errorf("failed to create platform client: %w", err)
+		return "", configError{fmt.Errorf("%w: --platform: %w", errInvalidFlag, err)}
 	}
+	repo.SetPlatform(forced)
+	log.Infof("Platform set by --platform: %s", forced)
+	return forced, nil
+}
 
-	remoteURL, err := repo.GetRemoteURL("origin")
+// newProvider creates the platform client for the detected platform and initializes it
+// for the repository at remoteURL.
+//
+//nolint:ireturn // Returns the platform abstraction.
+func newProvider(
+	detectedPlatform git.Platform, cfg *config.Config, remoteURL string, providerOpts platform.Options,
+) (platform.Provider, error) {
+	provider, err := platform.NewProvider(detectedPlatform, cfg, log, providerOpts)
 	if err != nil {
-		return fmt.Errorf("failed to get remote URL: %w", err)
+		return nil, fmt.Errorf("failed to create platform client: %w", err)
 	}
 
 	if err := provider.Initialize(remoteURL); err != nil {
-		return fmt.Errorf("failed to initialize %s client: %w", provider.PlatformName(), err)
+		return nil, fmt.Errorf("failed to initialize %s client: %w", provider.PlatformName(), err)
 	}
+	return provider, nil
+}
 
-	return handlePlatform(cmd, provider, currentBranch, mainBranch, title, body, repo,
-		useManualLabels, manualLabelsValue)
+// pushUsername returns the HTTPS push username configured for the detected platform,
+// or "" to keep the platform default.
+func pushUsername(detectedPlatform git.Platform, cfg *config.Config) string {
+	switch detectedPlatform {
+	case git.PlatformGitLab:
+		return cfg.GitLab.PushUsername
+	case git.PlatformGitHub:
+		return cfg.GitHub.PushUsername
+	case git.PlatformForgejo:
+		return cfg.Forgejo.PushUsername
+	default:
+		return ""
+	}
 }
 
 func handlePlatform(
@@ -425,6 +1827,7 @@ func handlePlatform(
 	provider platform.Provider,
 	currentBranch, mainBranch, title, body string,
 	repo *git.Repository,
+	squash bool,
 	useManualLabels bool,
 	manualLabelsValue string,
 ) error {
@@ -432,48 +1835,179 @@ func handlePlatform(
 	if err != nil {
 		return err
 	}
+	if err := checkMergedLabel(provider); err != nil {
+		return err
+	}
 
-	mr, err := createMR(provider, currentBranch, mainBranch, title, body, selectedLabels, !noSquash)
+	mr, created, err := createMR(provider, currentBranch, mainBranch, title, body, selectedLabels, squash)
 	if err != nil {
 		return err
 	}
+	runSummary.URL, runSummary.Labels = mr.WebURL, selectedLabels
+
+	if changelogCmt && created {
+		postChangelog(provider, repo, mr, mainBranch)
+	}
+
+	if codeOwners && created {
+		requestCodeOwnerReviews(provider, repo, mr, mainBranch)
+	}
+
+	if projectNumber > 0 {
+		addToProject(provider, mr, projectNumber)
+	}
+
+	if openWeb {
+		openInBrowser(mr.WebURL)
+	}
 
-	if err := waitAndMerge(cmd, provider, mr, !noSquash, title); err != nil {
+	mergedAt, err := waitAndMerge(cmd, provider, repo, mr, mainBranch, squash, title, body)
+	if err != nil {
 		return err
 	}
 
+	if postMergeHook != "" {
+		runPostMergeHook(provider, repo, mr, mainBranch)
+	}
+
 	ctx := context.Background()
-	return cleanup(ctx, repo, mainBranch, currentBranch)
+	if err := cleanup(ctx, repo, mainBranch, currentBranch); err != nil {
+		return err
+	}
+
+	if waitDeploy {
+		if err := waitForDeployment(provider, mainBranch, mergedAt); err != nil {
+			return err
+		}
+	}
+
+	printQuietURL(mr.WebURL)
+	return nil
 }
 
-func handleListLabels(detectedPlatform git.Platform, cfg *config.Config, repo *git.Repository) error {
-	provider, err := platform.NewProvider(detectedPlatform, cfg, log)
+// printPlan prints the merge/pull request a run would create (--dry-run), from the
+// values resolved from flags, configuration and environment. Labels are selected
+// as in a real run, prompting if needed.
+func printPlan(
+	provider platform.Provider,
+	p git.Platform,
+	cfg *config.Config,
+	currentBranch, mainBranch, title string,
+	squash bool,
+	useManualLabels bool,
+	manualLabelsValue string,
+) error {
+	selectedLabels, err := selectLabels(provider, useManualLabels, manualLabelsValue, title)
 	if err != nil {
-		return fmt.Errorf("failed to create platform client: %w", err)
+		return err
+	}
+	assignee, reviewer, _ := platformUsers(p, cfg)
+
+	fmt.Printf("Would create %s merge/pull request from %s to %s titled %q, assignee=%s, reviewer=%s, "+
+		"labels=[%s], squash=%t\n", provider.PlatformName(), currentBranch, mainBranch, title,
+		cmp.Or(*assignee, "none"), cmp.Or(*reviewer, "none"), strings.Join(selectedLabels, ", "), squash)
+	return nil
+}
+
+// showBranchDiff prints what the merge/pull request will propose (--show-diff): the
+// diffstat of the branch against mainBranch, or its full diff. Interactive runs then
+// ask whether to go on, so that nothing is pushed or created when the answer is no.
+func showBranchDiff(repo *git.Repository, mainBranch string) error {
+	if showDiff == showDiffPatch {
+		diff, err := repo.Diff(mainBranch)
+		if err != nil {
+			return fmt.Errorf("failed to compute the diff against %s: %w", mainBranch, err)
+		}
+		fmt.Print(diff)
+	} else {
+		stats, err := repo.DiffStat(mainBranch)
+		if err != nil {
+			return fmt.Errorf("failed to compute the diff against %s: %w", mainBranch, err)
+		}
+		var insertions, deletions int
+		for _, stat := range stats {
+			insertions += stat.Addition
+			deletions += stat.Deletion
+		}
+		// Like git diff --stat: one line per file, then the totals.
+		fmt.Print(stats.String())
+		fmt.Printf(" %d file(s) changed against %s, %d insertion(s)(+), %d deletion(s)(-)\n",
+			len(stats), mainBranch, insertions, deletions)
+	}
+
+	if dryRun || !promptsAllowed() {
+		return nil
+	}
+	var proceed bool
+	prompt := &survey.Confirm{Message: "Create the merge/pull request?"}
+	if err := survey.AskOne(prompt, &proceed); err != nil || !proceed {
+		return fmt.Errorf("%w: nothing was pushed or created", errCreateDeclined)
 	}
+	return nil
+}
+
+// printQuietURL prints the merge/pull request URL, the only output of a successful
+// --quiet run (none with JSON logs).
+func printQuietURL(webURL string) {
+	if quiet && logFormat != logger.FormatJSON {
+		fmt.Println(webURL)
+	}
+}
 
-	remoteURL, err := repo.GetRemoteURL("origin")
+// handleReady marks the open merge/pull request of currentBranch as ready for review
+// (ready subcommand). A merge/pull request that is not a draft is left as is. With
+// --auto-merge, auto-merge is then enabled with the squash method.
+func handleReady(provider platform.Provider, currentBranch, mainBranch string, squash bool) error {
+	mr, err := provider.GetByBranch(currentBranch, mainBranch)
 	if err != nil {
-		return fmt.Errorf("failed to get remote URL: %w", err)
+		return fmt.Errorf("failed to find the merge/pull request of %s: %w", currentBranch, err)
 	}
+	handleDuplicates(provider, mr)
 
-	if err := provider.Initialize(remoteURL); err != nil {
-		return fmt.Errorf("failed to initialize %s client: %w", provider.PlatformName(), err)
+	err = provider.MarkReady(mr.ID)
+	switch {
+	case errors.Is(err, platform.ErrNotDraft):
+		log.Infof("Merge/pull request is not a draft: %s", mr.WebURL)
+	case err != nil:
+		return fmt.Errorf("failed to update merge/pull request: %w", err)
+	default:
+		log.Infof("Merge/pull request marked as ready for review: %s", mr.WebURL)
 	}
 
+	if autoMerge {
+		if err := provider.EnableAutoMerge(mr.ID, squash); err != nil {
+			return fmt.Errorf("failed to enable auto-merge: %w", err)
+		}
+		method := mergeMethodMerge
+		if squash {
+			method = mergeMethodSquash
+		}
+		log.Infof("Merge/pull request ready for review with auto-merge enabled (%s): %s", method, mr.WebURL)
+	}
+	printQuietURL(mr.WebURL)
+	return nil
+}
+
+func handleListLabels(provider platform.Provider, remoteURL string) error {
 	availableLabels, err := provider.ListLabels()
 	if err != nil {
 		return fmt.Errorf("failed to list labels: %w", err)
 	}
 
 	fmt.Printf("Available labels for %s:%s:\n", provider.PlatformName(), remoteURL)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	for _, label := range availableLabels {
-		fmt.Printf("- %s\n", label.Name)
+		fmt.Fprintf(w, "- %s\t%s\t%s\n", label.Name, label.Color, label.Description)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to print labels: %w", err)
 	}
 	fmt.Printf("\nTotal: %d labels\n", len(availableLabels))
 	return nil
 }
 
+// selectLabels picks the labels of the new merge/pull request and ensures that at
+// most one GitLab scoped label per scope is selected, which the API would reject.
 func selectLabels(
 	provider platform.Provider, useManualSelection bool, manualLabels string, title string,
 ) ([]string, error) {
@@ -482,11 +2016,43 @@ func selectLabels(
 		return nil, fmt.Errorf("failed to list labels: %w", err)
 	}
 
+	// A new project may have no labels yet: there is nothing to select from. Labels
+	// given with --labels are still checked, so that a typo is not silently dropped.
+	if len(availableLabels) == 0 && !useManualSelection {
+		log.Info("No labels available in this project, proceeding without labels")
+		return []string{}, nil
+	}
+
+	selected, err := chooseLabels(availableLabels, provider.DefaultLabels(), useManualSelection, manualLabels, title)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := make(map[string]string, len(availableLabels))
+	for _, label := range availableLabels {
+		scopes[label.Name] = label.Scope
+	}
+	if err := autolabels.CheckScopes(selected, scopes); err != nil {
+		return nil, fmt.Errorf("%w. Keep one label per scope", err)
+	}
+	return selected, nil
+}
+
+// chooseLabels returns the labels given with --labels, the default_labels with --yes,
+// or those matching the commit type of title, in that order of preference.
+func chooseLabels(
+	availableLabels []platform.Label, defaults []string, useManualSelection bool, manualLabels string, title string,
+) ([]string, error) {
 	if useManualSelection {
 		log.Debug("Using manual label selection via --labels flag")
 		return validateManualLabels(availableLabels, manualLabels)
 	}
 
+	if assumeYes && len(defaults) > 0 {
+		log.Infof("Using default labels: %v", defaults)
+		return validateManualLabels(availableLabels, strings.Join(defaults, ","))
+	}
+
 	// Automatic selection based on conventional commit type
 	log.Debug("Using automatic label selection from commit type")
 	availableNames := make([]string, len(availableLabels))
@@ -509,7 +2075,7 @@ func createMR(
 	currentBranch, mainBranch, title, body string,
 	selectedLabels []string,
 	squash bool,
-) (*platform.MergeRequest, error) {
+) (*platform.MergeRequest, bool, error) {
 	log.IncreasePadding()
 	log.Infof("Creating %s merge/pull request...", provider.PlatformName())
 
@@ -526,67 +2092,500 @@ func createMR(
 			log.Warnf("Merge/pull request already exists for branch: %s", currentBranch)
 			existingMR, fetchErr := provider.GetByBranch(currentBranch, mainBranch)
 			if fetchErr != nil {
-				return nil, fmt.Errorf("failed to fetch existing merge/pull request: %w", fetchErr)
+				return nil, false, fmt.Errorf("failed to fetch existing merge/pull request: %w", fetchErr)
 			}
 			log.Infof("Using existing merge/pull request: %s", existingMR.WebURL)
+			handleDuplicates(provider, existingMR)
 			log.DecreasePadding()
-			return existingMR, nil
+			return existingMR, false, nil
 		}
 		log.DecreasePadding()
-		return nil, fmt.Errorf("failed to create merge/pull request: %w", err)
+		if errors.Is(err, platform.ErrIncomplete) {
+			return nil, false, fmt.Errorf("%w\n\nThe merge/pull request is open: add the missing assignees, "+
+				"labels or reviewers by hand, then run auto-mr again to wait for CI and merge it", err)
+		}
+		return nil, false, fmt.Errorf("failed to create merge/pull request: %w", err)
 	}
 
 	log.Infof("Merge/pull request created: %s", mr.WebURL)
 	log.DecreasePadding()
-	return mr, nil
+	return mr, true, nil
+}
+
+// handleDuplicates warns about the other open merge/pull requests of the branch of mr,
+// which concurrent runs may leave behind, and closes them with --close-duplicates.
+// mr is the newest one: the run goes on with it. Failures to close are logged.
+func handleDuplicates(provider platform.Provider, mr *platform.MergeRequest) {
+	if len(mr.Duplicates) == 0 {
+		return
+	}
+	ids := make([]string, len(mr.Duplicates))
+	for i, id := range mr.Duplicates {
+		ids[i] = strconv.FormatInt(id, 10)
+	}
+	if !closeDuplicates {
+		log.Warnf("Other merge/pull requests are open for this branch (%s), using the newest; "+
+			"run with --close-duplicates to close them", strings.Join(ids, ", "))
+		return
+	}
+	for i, id := range mr.Duplicates {
+		if err := provider.Close(id); err != nil {
+			log.Warnf("Failed to close duplicate merge/pull request %s: %v", ids[i], err)
+			continue
+		}
+		log.Infof("Closed duplicate merge/pull request %s", ids[i])
+	}
+}
+
+// postChangelog comments the list of branch commits on a newly created merge/pull
+// request (--changelog-comment). Failures are logged: the merge goes on without it.
+func postChangelog(provider platform.Provider, repo *git.Repository, mr *platform.MergeRequest, mainBranch string) {
+	gitCommits, err := repo.GetCommitsSinceMain(mainBranch)
+	if err != nil {
+		log.Warnf("Could not list the branch commits for the changelog comment: %v", err)
+		return
+	}
+	branchCommits := make([]commits.Commit, len(gitCommits))
+	for i, c := range gitCommits {
+		branchCommits[i] = commits.ParseCommit(c)
+	}
+
+	changelog := commits.FormatChangelog(branchCommits)
+	if changelog == "" {
+		log.Debug("No commits to post as changelog comment")
+		return
+	}
+	if err := provider.Comment(mr.ID, changelog); err != nil {
+		log.Warnf("Failed to post the changelog comment: %v", err)
+		return
+	}
+	log.Info("Posted the commit list as a comment")
+}
+
+// requestCodeOwnerReviews requests review of a newly created merge/pull request from
+// the CODEOWNERS owners of the files changed on the branch (--reviewers-from-codeowners).
+// Failures are logged: the merge goes on without them.
+func requestCodeOwnerReviews(
+	provider platform.Provider, repo *git.Repository, mr *platform.MergeRequest, mainBranch string,
+) {
+	root, err := repo.RootDir()
+	if err != nil {
+		log.Warnf("Could not locate the CODEOWNERS file: %v", err)
+		return
+	}
+	file, path, err := codeowners.Load(root)
+	if errors.Is(err, codeowners.ErrNotFound) {
+		log.Warnf("No review requested from code owners: %v", err)
+		return
+	}
+	if err != nil {
+		log.Warnf("Could not read the CODEOWNERS file: %v", err)
+		return
+	}
+
+	files, err := repo.ChangedFiles(mainBranch)
+	if err != nil {
+		log.Warnf("Could not list the files changed on the branch: %v", err)
+		return
+	}
+	owners := file.Owners(files)
+	if len(owners) == 0 {
+		log.Infof("No code owner in %s for the %d changed file(s)", path, len(files))
+		return
+	}
+	if err := provider.RequestReviewers(mr.ID, owners); err != nil {
+		log.Warnf("Failed to request review from the code owners: %v", err)
+		return
+	}
+	log.Infof("Review requested from the code owners: %s", strings.Join(owners, ", "))
+}
+
+// addToProject adds the pull request to a GitHub Projects board (--project).
+// Failures are logged: the merge goes on without it.
+func addToProject(provider platform.Provider, mr *platform.MergeRequest, project int) {
+	if err := provider.AddToProject(mr.ID, project); err != nil {
+		if errors.Is(err, platform.ErrProjectAccess) {
+			log.Warnf("Could not add the pull request to project %d: the token cannot access projects", project)
+			log.Warn("Classic tokens need the project scope; fine-grained tokens need the Projects permission")
+			log.Debugf("Project error: %v", err)
+			return
+		}
+		log.Warnf("Failed to add the pull request to project %d: %v", project, err)
+		return
+	}
+	log.Infof("Added to project %d", project)
+}
+
+// openInBrowser opens the merge/pull request page. Failures (e.g. headless
+// environments) are not fatal: the URL is printed instead.
+func openInBrowser(webURL string) {
+	if err := browser.Open(context.Background(), webURL); err != nil {
+		log.Debugf("Failed to open browser: %v", err)
+		log.Warnf("Could not open a browser, visit: %s", webURL)
+		return
+	}
+	log.Debug("Opened merge/pull request in browser")
+}
+
+// waitForPipeline waits for the pipeline/workflows and fails unless they succeed.
+// When none started within --checks-start-timeout, --on-no-checks merge lets the
+// merge go ahead as if there were no CI.
+func waitForPipeline(provider platform.Provider, timeout time.Duration) error {
+	status, err := provider.WaitForPipeline(timeout)
+	if errors.Is(err, platform.ErrNoChecks) {
+		if onNoChecks == onNoChecksMerge {
+			log.Warnf("No pipeline/workflow started within %s: merging without checks (--on-no-checks merge)",
+				timeutil.FormatDuration(checksStart))
+			runSummary.Pipeline = "none"
+			return nil
+		}
+		runSummary.Pipeline = "not started"
+		return fmt.Errorf("%w within %s: check the CI triggers, or use --on-no-checks merge "+
+			"to merge anyway (--checks-start-timeout 0 waits for the whole pipeline timeout)",
+			platform.ErrNoChecks, timeutil.FormatDuration(checksStart))
+	}
+	if err != nil {
+		if errors.Is(err, platform.ErrPipelineTimeout) {
+			runSummary.Pipeline = "timeout"
+		}
+		return fmt.Errorf("failed to wait for pipeline: %w", err)
+	}
+	runSummary.Pipeline = cmp.Or(status, "none")
+
+	if status != "success" && status != "" {
+		return fmt.Errorf("%w with status: %s", errPipelineFailed, status)
+	}
+	return nil
+}
+
+// pipelineTimeoutError explains that the pipeline/workflows are still running, not
+// failed, and how to finish: merge from the checks page or rerun with a longer timeout.
+// The merge/pull request is left open, even with --close-on-failure.
+// The platform's own timeout error is not wrapped as it repeats [platform.ErrPipelineTimeout].
+func pipelineTimeoutError(provider platform.Provider, mr *platform.MergeRequest, timeout time.Duration) error {
+	hint := "The pipeline/workflows did not fail: they are still running.\n" +
+		"The merge/pull request is left open. Follow them and merge it once they pass:\n  " +
+		platform.ChecksURL(provider, mr)
+	if timeout < config.MaxPipelineTimeout {
+		longer := min(2*timeout, config.MaxPipelineTimeout)
+		hint += "\nor run auto-mr again with a longer timeout: --pipeline-timeout " + shortDuration(longer)
+	}
+	return fmt.Errorf("%w after %s\n\n%s", platform.ErrPipelineTimeout, timeutil.FormatDuration(timeout), hint)
+}
+
+// askMergeConfirmation asks whether to merge mr (--confirm-merge). Anything but yes,
+// including Ctrl+C, returns errMergeDeclined and leaves the merge/pull request open.
+func askMergeConfirmation(mr *platform.MergeRequest) error {
+	merge := false
+	prompt := &survey.Confirm{Message: "Merge " + mr.WebURL + "?"}
+	if err := survey.AskOne(prompt, &merge); err != nil || !merge {
+		return fmt.Errorf("%w: the merge/pull request is left open: %s", errMergeDeclined, mr.WebURL)
+	}
+	return nil
+}
+
+// shortDuration formats d like [time.Duration.String] without the zero
+// trailing units ("1h0m0s" → "1h", "45m0s" → "45m").
+func shortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
 }
 
+// waitAndMerge waits for the pipeline/workflows and merges the merge/pull request.
+// A merge without squash describes its merge commit with --merge-commit-title and
+// --merge-commit-message, or commitTitle and commitBody (GitHub only).
+// It returns the time the merge was requested, from which --wait-deploy looks for
+// the deployments it triggers.
 func waitAndMerge(
 	cmd *cobra.Command,
 	provider platform.Provider,
+	repo *git.Repository,
 	mr *platform.MergeRequest,
+	targetBranch string,
 	squash bool,
-	commitTitle string,
-) error {
-	time.Sleep(pipelineStartupDelay)
+	commitTitle, commitBody string,
+) (time.Time, error) {
+	// Give the API time to list the new merge/pull request before polling its pipeline.
+	if err := platform.WaitUntilVisible(provider, mr.SourceBranch, targetBranch, mrVisibilityTimeout); err != nil {
+		log.Debugf("Merge/pull request not yet visible, continuing: %v", err)
+	}
 
 	timeout, err := getPipelineTimeout(cmd, provider.PipelineTimeout())
 	if err != nil {
-		return err
+		return time.Time{}, configError{err}
 	}
 
-	status, err := provider.WaitForPipeline(timeout)
-	if err != nil {
-		return fmt.Errorf("failed to wait for pipeline: %w", err)
+	if rebase {
+		if err := rebaseMergeRequest(provider, mr, targetBranch, timeout); err != nil {
+			return time.Time{}, err
+		}
 	}
 
-	if status != "success" && status != "" {
-		return fmt.Errorf("%w with status: %s", errPipelineFailed, status)
+	// approve_timing: before-wait gets the MR approved while the pipeline runs.
+	approveFirst := provider.ApproveTiming() == config.ApproveBeforeWait
+	if approveFirst {
+		approve(provider, mr)
+	}
+
+	if noWait {
+		log.Warn("CI SKIPPED (--no-wait): merging without waiting for the pipeline/workflows")
+		runSummary.Pipeline = "skipped"
+	} else if err := waitForPipeline(provider, timeout); err != nil {
+		if closeOnFailure && errors.Is(err, errPipelineFailed) {
+			return time.Time{}, closeFailed(provider, mr, err)
+		}
+		if errors.Is(err, platform.ErrPipelineTimeout) {
+			return time.Time{}, pipelineTimeoutError(provider, mr, timeout)
+		}
+		return time.Time{}, err
+	}
+
+	if preMergeHook != "" {
+		if err := runPreMergeHook(provider, repo, mr, targetBranch); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	if confirmMerge && promptsAllowed() {
+		if err := askMergeConfirmation(mr); err != nil {
+			return time.Time{}, err
+		}
 	}
 
 	log.Infof("Merging %s merge/pull request...", provider.PlatformName())
 	log.IncreasePadding()
 
-	log.Info("Approving merge/pull request...")
-	if err := provider.Approve(mr.ID); err != nil {
-		log.Warnf("Failed to approve merge/pull request: %v", err)
+	if mergedLabel != "" {
+		if err := provider.AddLabels(mr.ID, []string{mergedLabel}); err != nil {
+			log.Warnf("Could not add the %s label: %v", mergedLabel, err)
+		} else {
+			log.Infof("Label added: %s", mergedLabel)
+		}
+	}
+
+	if !approveFirst {
+		approve(provider, mr)
 	}
 
-	if err := provider.Merge(platform.MergeParams{
+	mergeParams := platform.MergeParams{
 		MRID:         mr.ID,
 		Squash:       squash,
 		CommitTitle:  commitTitle,
 		SourceBranch: mr.SourceBranch,
-	}); err != nil {
+	}
+	if !squash {
+		mergeParams.CommitTitle = cmp.Or(mergeTitle, commitTitle)
+		mergeParams.CommitMessage = cmp.Or(mergeMessage, commitBody)
+	}
+	if waitApprovals {
+		mergeParams.ApprovalTimeout = timeout
+	}
+
+	mergedAt := time.Now()
+	if err := provider.Merge(mergeParams); err != nil {
 		log.DecreasePadding()
-		return fmt.Errorf("failed to merge: %w", err)
+		if errors.Is(err, platform.ErrApprovalsPending) {
+			return time.Time{}, fmt.Errorf("%w\n\n"+
+				"The merge/pull request is still open: %s\n"+
+				"Ask the required approvers to review it, then run auto-mr again,\n"+
+				"or use --wait-approvals to wait for them",
+				err, mr.WebURL)
+		}
+		if errors.Is(err, platform.ErrMergeConflict) {
+			return time.Time{}, fmt.Errorf("%w\n\n"+
+				"The merge/pull request is still open: %s\n"+
+				"Resolve the conflicts, then run auto-mr again:\n"+
+				"  git fetch origin\n"+
+				"  git rebase origin/<target-branch>   # resolve conflicts, then git rebase --continue\n"+
+				"  git push --force-with-lease",
+				err, mr.WebURL)
+		}
+		return time.Time{}, fmt.Errorf("failed to merge: %w", err)
 	}
 
 	log.Info("Merge/pull request merged successfully")
+	runSummary.Merged = true
 	log.DecreasePadding()
+	return mergedAt, nil
+}
+
+// waitForDeployment waits for the deployments of targetBranch started since the merge
+// (--wait-deploy) and fails unless they succeed. The merge itself is already done.
+func waitForDeployment(provider platform.Provider, targetBranch string, mergedAt time.Time) error {
+	status, err := provider.WaitForDeployment(targetBranch, mergedAt, deployTimeout)
+	if err != nil {
+		if errors.Is(err, platform.ErrDeploymentTimeout) {
+			return fmt.Errorf("merged, but %w after %s", err, timeutil.FormatDuration(deployTimeout))
+		}
+		return fmt.Errorf("merged, but failed to wait for the deployment: %w", err)
+	}
+
+	if status != "success" {
+		return fmt.Errorf("merged, but %w with status: %s", errDeploymentFailed, status)
+	}
+	return nil
+}
+
+// closeFailed closes the merge/pull request whose pipeline failed (--close-on-failure)
+// and, with --delete-branch-on-close, its remote branch. The returned error wraps
+// pipelineErr so that the exit code still reports the pipeline failure.
+func closeFailed(provider platform.Provider, mr *platform.MergeRequest, pipelineErr error) error {
+	log.Infof("Closing merge/pull request: %s", mr.WebURL)
+	if err := provider.Close(mr.ID); err != nil {
+		log.Warnf("Failed to close merge/pull request: %v", err)
+		return pipelineErr
+	}
+
+	if !deleteOnClose {
+		return fmt.Errorf("%w; merge/pull request closed: %s", pipelineErr, mr.WebURL)
+	}
+	log.Infof("Deleting remote branch: %s", mr.SourceBranch)
+	if err := provider.DeleteBranch(mr.SourceBranch); err != nil {
+		log.Warnf("Failed to delete remote branch: %v", err)
+		return fmt.Errorf("%w; merge/pull request closed: %s", pipelineErr, mr.WebURL)
+	}
+	return fmt.Errorf("%w; merge/pull request closed and remote branch %s deleted: %s",
+		pipelineErr, mr.SourceBranch, mr.WebURL)
+}
+
+// runPreMergeHook runs the pre-merge hook from the repository root. The hook gets
+// the merge/pull request details in AUTO_MR_* environment variables.
+func runPreMergeHook(
+	provider platform.Provider, repo *git.Repository, mr *platform.MergeRequest, targetBranch string,
+) error {
+	dir, err := repo.RootDir()
+	if err != nil {
+		return fmt.Errorf("failed to locate repository root for the pre-merge hook: %w", err)
+	}
+
+	log.Infof("Running pre-merge hook: %s", preMergeHook)
+	if err := hook.Run(context.Background(), preMergeHook, dir, hookEnv(provider, mr, targetBranch)); err != nil {
+		return fmt.Errorf("%w\n\nThe merge/pull request is still open: %s", err, mr.WebURL)
+	}
+	log.Info("Pre-merge hook passed")
+	return nil
+}
+
+// runPostMergeHook runs the post-merge hook from the repository root, with the
+// merge/pull request details and AUTO_MR_MERGE_SHA in its environment. Failures are
+// logged: the merge is already done.
+func runPostMergeHook(
+	provider platform.Provider, repo *git.Repository, mr *platform.MergeRequest, targetBranch string,
+) {
+	dir, err := repo.RootDir()
+	if err != nil {
+		log.Warnf("Skipping post-merge hook, failed to locate repository root: %v", err)
+		return
+	}
+
+	log.Infof("Running post-merge hook: %s", postMergeHook)
+	env := append(hookEnv(provider, mr, targetBranch), "AUTO_MR_MERGE_SHA="+provider.MergeCommitSHA())
+	if err := hook.Run(context.Background(), postMergeHook, dir, env); err != nil {
+		log.Warnf("Post-merge hook failed (the merge is done): %v", err)
+		return
+	}
+	log.Info("Post-merge hook passed")
+}
+
+// hookEnv returns the AUTO_MR_* environment variables describing the merge/pull
+// request to the hooks.
+func hookEnv(provider platform.Provider, mr *platform.MergeRequest, targetBranch string) []string {
+	return []string{
+		"AUTO_MR_PLATFORM=" + provider.PlatformName(),
+		"AUTO_MR_SOURCE_BRANCH=" + mr.SourceBranch,
+		"AUTO_MR_TARGET_BRANCH=" + targetBranch,
+		"AUTO_MR_ID=" + strconv.FormatInt(mr.ID, 10),
+		"AUTO_MR_URL=" + mr.WebURL,
+	}
+}
+
+// approve approves the merge/pull request. Failures are logged: the merge may still
+// be allowed, and is otherwise reported by Merge.
+func approve(provider platform.Provider, mr *platform.MergeRequest) {
+	log.Info("Approving merge/pull request...")
+	if err := provider.Approve(mr.ID); err != nil {
+		log.Warnf("Failed to approve merge/pull request: %v", err)
+	}
+}
+
+// pushError wraps a push failure, appending the next step for recognized rejections.
+func pushError(err error, branch string) error {
+	switch {
+	case errors.Is(err, git.ErrPushNonFastForward):
+		return fmt.Errorf("failed to push branch: %w\n\n"+
+			"The remote branch %s has commits you do not have locally.\n"+
+			"Pull or rebase first, then run auto-mr again:\n"+
+			"  git pull --rebase origin %s\n"+
+			"If you rebased or amended the branch on purpose, run auto-mr with --force-with-lease instead.",
+			err, branch, branch)
+	case errors.Is(err, git.ErrPushStaleLease):
+		return fmt.Errorf("failed to push branch: %w\n\n"+
+			"Someone pushed to %s since you last fetched it, so it was not overwritten.\n"+
+			"Fetch and look at their commits before pushing again:\n"+
+			"  git fetch origin\n"+
+			"  git log %s..origin/%s",
+			err, branch, branch, branch)
+	case errors.Is(err, git.ErrPushProtected):
+		return fmt.Errorf("failed to push branch: %w\n\n"+
+			"The branch %s is protected on the remote and cannot be pushed directly.\n"+
+			"Move your commits to a feature branch, then run auto-mr again:\n"+
+			"  git switch -c <feature-branch>",
+			err, branch)
+	case errors.Is(err, git.ErrPushAuthFailed):
+		return fmt.Errorf("failed to push branch: %w\n\n"+
+			"Check that your SSH key or git credentials have write access to the repository", err)
+	default:
+		return fmt.Errorf("failed to push branch: %w", err)
+	}
+}
+
+// rebaseMergeRequest rebases the merge request onto targetBranch on the server (--rebase).
+// The pipeline waited for afterwards is the one of the rebased head.
+func rebaseMergeRequest(provider platform.Provider, mr *platform.MergeRequest, targetBranch string,
+	timeout time.Duration,
+) error {
+	log.Infof("Rebasing merge/pull request onto %s...", targetBranch)
+	if err := provider.Rebase(mr.ID, timeout); err != nil {
+		if errors.Is(err, platform.ErrRebaseFailed) {
+			return fmt.Errorf("%w\n\n"+
+				"The merge/pull request is still open: %s\n"+
+				"Rebase the branch locally, then run auto-mr again:\n"+
+				"  git fetch origin\n"+
+				"  git rebase origin/%s   # resolve conflicts, then git rebase --continue\n"+
+				"  git push --force-with-lease",
+				err, mr.WebURL, targetBranch)
+		}
+		return fmt.Errorf("failed to rebase: %w", err)
+	}
+	log.Info("Merge/pull request rebased")
 	return nil
 }
 
+// checkMergedLabel fails, before the merge/pull request is created, when the
+// --merged-label label does not exist in the repository.
+func checkMergedLabel(provider platform.Provider) error {
+	if mergedLabel == "" {
+		return nil
+	}
+	availableLabels, err := provider.ListLabels()
+	if err != nil {
+		return fmt.Errorf("failed to list labels: %w", err)
+	}
+	for _, label := range availableLabels {
+		if label.Name == mergedLabel {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: '%s' (--merged-label). Use --list-labels to see available labels",
+		errLabelNotFound, mergedLabel)
+}
+
 func validateManualLabels(availableLabels []platform.Label, requestedLabels string) ([]string, error) {
 	// Handle empty string case (skip labels)
 	if requestedLabels == "" {
@@ -596,9 +2595,9 @@ func validateManualLabels(availableLabels []platform.Label, requestedLabels stri
 	// Parse and clean labels
 	cleanedLabels := parseLabels(requestedLabels)
 
-	// Validate max selection limit
-	if len(cleanedLabels) > maxLabelsToSelect {
-		return nil, fmt.Errorf("%w: %d (max: %d)", errTooManyLabels, len(cleanedLabels), maxLabelsToSelect)
+	// Validate max selection limit (0: unlimited)
+	if maxLabels > 0 && len(cleanedLabels) > maxLabels {
+		return nil, fmt.Errorf("%w: %d (max: %d, see --max-labels)", errTooManyLabels, len(cleanedLabels), maxLabels)
 	}
 
 	// Build map of available labels for O(1) lookup
@@ -630,12 +2629,23 @@ func parseLabels(requestedLabels string) []string {
 }
 
 func cleanup(ctx context.Context, repo *git.Repository, mainBranch, currentBranch string) error {
+	if noCleanup {
+		log.Infof("Skipping cleanup (--no-cleanup): still on %s", currentBranch)
+		log.Info("auto-mr completed successfully in " + timeutil.FormatDuration(time.Since(startTime)) + "!")
+		return nil
+	}
+	if noSwitch {
+		pruneOnly(ctx, repo, currentBranch)
+		return nil
+	}
+
 	log.Info("Cleanup...")
 	log.IncreasePadding()
 	defer log.DecreasePadding()
 
 	log.Infof("Switching to main branch: %s", mainBranch)
 	report := repo.Cleanup(ctx, mainBranch, currentBranch)
+	runSummary.BranchDeleted = report.DeletedBranch
 
 	// Display results with status icons
 	displayCleanupStatus(report)
@@ -646,15 +2656,37 @@ func cleanup(ctx context.Context, repo *git.Repository, mainBranch, currentBranc
 	}
 
 	// Warn about non-critical failures
+	elapsed := timeutil.FormatDuration(time.Since(startTime))
 	if report.PruneError != nil || report.DeleteError != nil {
 		log.Warn("Cleanup completed with warnings (see above)")
+		log.Info("auto-mr completed in " + elapsed)
 	} else {
-		log.Info("auto-mr completed successfully!")
+		log.Info("auto-mr completed successfully in " + elapsed + "!")
 	}
 
 	return nil
 }
 
+// pruneOnly is the cleanup of --no-switch: the feature branch stays checked out and
+// is kept, only the remote-tracking branches are refreshed (best-effort).
+func pruneOnly(ctx context.Context, repo *git.Repository, currentBranch string) {
+	log.Info("Cleanup...")
+	log.IncreasePadding()
+	defer log.DecreasePadding()
+
+	err := repo.FetchAndPrune(ctx)
+	elapsed := timeutil.FormatDuration(time.Since(startTime))
+	if err != nil {
+		log.Warnf("%s Fetch and prune - %v", getStatusIcon(false, err), err)
+		log.Info("You can manually run: git fetch --prune")
+		log.Info("auto-mr completed in " + elapsed)
+		return
+	}
+	log.Infof("%s Fetch and prune", getStatusIcon(true, nil))
+	log.Infof("Staying on %s (--no-switch)", currentBranch)
+	log.Info("auto-mr completed successfully in " + elapsed + "!")
+}
+
 func displayCleanupStatus(report *git.CleanupReport) {
 	steps := []struct {
 		name      string
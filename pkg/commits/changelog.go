@@ -0,0 +1,28 @@
+package commits
+
+import "strings"
+
+// ChangelogHeading is the first line of the text built by [FormatChangelog].
+const ChangelogHeading = "**Commits**"
+
+// FormatChangelog returns a Markdown list of the commits, oldest first, one
+// "- ShortHash Title" item per commit. Merge commits and empty messages are
+// skipped (see [FilterValidCommits]).
+//
+// Parameters:
+//   - commits: the branch commits, newest first as returned by git log
+//
+// Returns an empty string if no commit is left after filtering.
+func FormatChangelog(commits []Commit) string {
+	valid := FilterValidCommits(commits)
+	if len(valid) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(ChangelogHeading + "\n\n")
+	for i := len(valid) - 1; i >= 0; i-- {
+		b.WriteString("- " + valid[i].ShortHash + " " + valid[i].Title + "\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
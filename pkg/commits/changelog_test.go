@@ -0,0 +1,52 @@
+package commits_test
+
+import (
+	"testing"
+
+	"github.com/sgaunet/auto-mr/pkg/commits"
+)
+
+func TestFormatChangelog(t *testing.T) {
+	tests := []struct {
+		name     string
+		commits  []commits.Commit
+		expected string
+	}{
+		{
+			name:     "no commits",
+			expected: "",
+		},
+		{
+			name: "oldest first",
+			commits: []commits.Commit{
+				{ShortHash: "bbbbbbb", Title: "fix: second", Message: "fix: second"},
+				{ShortHash: "aaaaaaa", Title: "feat: first", Message: "feat: first"},
+			},
+			expected: "**Commits**\n\n- aaaaaaa feat: first\n- bbbbbbb fix: second",
+		},
+		{
+			name: "merge commits skipped",
+			commits: []commits.Commit{
+				{ShortHash: "ccccccc", Title: "Merge branch 'main'", Message: "Merge branch 'main'",
+					ParentHashes: []string{"a", "b"}},
+				{ShortHash: "aaaaaaa", Title: "feat: first", Message: "feat: first"},
+			},
+			expected: "**Commits**\n\n- aaaaaaa feat: first",
+		},
+		{
+			name: "only merge commits",
+			commits: []commits.Commit{
+				{ShortHash: "ccccccc", Title: "Merge", Message: "Merge", ParentHashes: []string{"a", "b"}},
+			},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commits.FormatChangelog(tt.commits); got != tt.expected {
+				t.Errorf("FormatChangelog() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
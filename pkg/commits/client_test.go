@@ -244,3 +244,24 @@ func contains(s, substr string) bool {
 func startsWithStr(s, prefix string) bool {
 	return len(s) >= len(prefix) && s[:len(prefix)] == prefix
 }
+
+// TestDefaultRenderer verifies that unattended selection picks the most recent commit.
+func TestDefaultRenderer(t *testing.T) {
+	all := fixtures.MultipleCommits()
+	selector := commits.NewSelector(commits.NewDefaultRenderer())
+
+	selection, err := selector.GetMessageForMR(all, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if selection.Title != all[0].Title {
+		t.Errorf("expected title %q, got %q", all[0].Title, selection.Title)
+	}
+	if selection.SelectionMethod != commits.SelectionInteractive {
+		t.Errorf("expected SelectionInteractive, got %v", selection.SelectionMethod)
+	}
+
+	if _, err := commits.NewDefaultRenderer().DisplaySelectionPrompt(nil); err == nil {
+		t.Error("expected error for empty commit list")
+	}
+}
@@ -255,3 +255,32 @@ func (r *Retriever) GetMessageForMR(branch, mainBranch, msgFlagValue string) (Me
 	// For now, return error indicating multiple commits require interactive selection
 	return MessageSelection{}, fmt.Errorf("%w: found %d commits", ErrMultipleCommitsFound, len(validCommits))
 }
+
+// GetMessageFromRevision returns the message of the commit that revision resolves to
+// (a hash, branch, tag or expression such as "HEAD~1"), for --from-commit.
+//
+// Returns [ErrRevisionNotFound] if revision does not resolve to a commit.
+// Returns [ErrAllCommitsInvalid] if the commit message is empty.
+func (r *Retriever) GetMessageFromRevision(revision string) (MessageSelection, error) {
+	hash, err := r.repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return MessageSelection{}, fmt.Errorf("%w: %s: %w", ErrRevisionNotFound, revision, err)
+	}
+	gitCommit, err := r.repo.CommitObject(*hash)
+	if err != nil {
+		return MessageSelection{}, fmt.Errorf("%w: %s: %w", ErrRevisionNotFound, revision, err)
+	}
+
+	commit := ParseCommit(gitCommit)
+	if commit.Title == "" {
+		return MessageSelection{}, fmt.Errorf("%w: %s", ErrAllCommitsInvalid, commit.ShortHash)
+	}
+	r.logger.Debug("using message of revision", "revision", revision, "hash", commit.ShortHash)
+
+	return MessageSelection{
+		Title:            commit.Title,
+		Body:             commit.Body,
+		SourceCommitHash: commit.Hash,
+		SelectionMethod:  SelectionRevision,
+	}, nil
+}
@@ -0,0 +1,79 @@
+package commits_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/sgaunet/auto-mr/pkg/commits"
+)
+
+func TestParseCommitMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		title   string
+		body    string
+	}{
+		{"subject only", "feat: add x\n", "feat: add x", ""},
+		{"subject and body", "feat: add x\n\nDetails.\n", "feat: add x", "Details."},
+		{"leading blank lines", "\n\n  \nfix: y\n\nBody", "fix: y", "Body"},
+		{"CRLF", "fix: y\r\n\r\nLine 1\r\nLine 2\r\n", "fix: y", "Line 1\nLine 2"},
+		{"empty", " \n\t\n", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, body := commits.ParseCommitMessage(tt.message)
+			if title != tt.title || body != tt.body {
+				t.Errorf("expected (%q, %q), got (%q, %q)", tt.title, tt.body, title, body)
+			}
+		})
+	}
+}
+
+func TestGetMessageFromRevision(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("failed to init repository: %v", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	commit := func(message string) string {
+		t.Helper()
+		hash, err := wt.Commit(message, &git.CommitOptions{
+			AllowEmptyCommits: true,
+			Author:            &object.Signature{Name: "Test", Email: "test@test.com", When: time.Now()},
+		})
+		if err != nil {
+			t.Fatalf("failed to commit: %v", err)
+		}
+		return hash.String()
+	}
+	first := commit("feat: meaningful change\n\nWhy it matters.")
+	commit("fixup! feat: meaningful change")
+
+	retriever := commits.NewRetriever(repo)
+
+	for _, revision := range []string{"HEAD~1", first, first[:7]} {
+		selection, err := retriever.GetMessageFromRevision(revision)
+		if err != nil {
+			t.Fatalf("GetMessageFromRevision(%q): %v", revision, err)
+		}
+		if selection.Title != "feat: meaningful change" || selection.Body != "Why it matters." {
+			t.Errorf("GetMessageFromRevision(%q) = %q / %q", revision, selection.Title, selection.Body)
+		}
+		if selection.SourceCommitHash != first || selection.SelectionMethod != commits.SelectionRevision {
+			t.Errorf("GetMessageFromRevision(%q) source = %s, method = %d", revision, selection.SourceCommitHash, selection.SelectionMethod)
+		}
+	}
+
+	if _, err := retriever.GetMessageFromRevision("no-such-ref"); !errors.Is(err, commits.ErrRevisionNotFound) {
+		t.Errorf("expected ErrRevisionNotFound, got %v", err)
+	}
+}
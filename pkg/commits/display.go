@@ -52,3 +52,22 @@ func (r *Renderer) DisplaySelectionPrompt(commits []Commit) (int, error) {
 
 	return selectedIndex, nil
 }
+
+// DefaultRenderer implements the [SelectionRenderer] interface without prompting:
+// it always picks the first (most recent) commit, the option preselected by [Renderer].
+// It is used for unattended runs.
+type DefaultRenderer struct{}
+
+// NewDefaultRenderer creates a renderer that never prompts.
+func NewDefaultRenderer() *DefaultRenderer {
+	return &DefaultRenderer{}
+}
+
+// DisplaySelectionPrompt returns 0, the index of the most recent commit.
+// Returns [ErrAllCommitsInvalid] if commits is empty.
+func (r *DefaultRenderer) DisplaySelectionPrompt(commits []Commit) (int, error) {
+	if len(commits) == 0 {
+		return -1, ErrAllCommitsInvalid
+	}
+	return 0, nil
+}
@@ -14,4 +14,10 @@ var (
 
 	// ErrMultipleCommitsFound is returned when multiple commits exist and interactive selection is needed.
 	ErrMultipleCommitsFound = errors.New("multiple commits found")
+
+	// ErrRevisionNotFound is returned when a revision does not resolve to a commit.
+	ErrRevisionNotFound = errors.New("revision does not resolve to a commit")
+
+	// ErrTemplateNotFound is returned when a named merge request template does not exist.
+	ErrTemplateNotFound = errors.New("merge request template not found")
 )
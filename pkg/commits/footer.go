@@ -0,0 +1,65 @@
+package commits
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// FooterData holds the values available to the body_footer template,
+// e.g. "Branch: {{.Branch}}".
+type FooterData struct {
+	Branch       string // Source branch of the merge/pull request
+	TargetBranch string // Branch it is merged into
+	Issue        int    // Issue number found in the branch name (see [IssueFromBranch]), 0 if none
+}
+
+// issueBranchPattern matches an issue number at the start of the branch name or of
+// its last path segment, optionally prefixed by "issue-" or "#":
+// "123-fix-login", "feature/123-fix-login", "issue-123", "fix/#123".
+var issueBranchPattern = regexp.MustCompile(`(?i)(?:^|/)(?:issue-|#)?(\d+)(?:[-_]|$)`)
+
+// IssueFromBranch returns the issue number referenced by branch, following the
+// "<number>-description" naming used by GitLab and GitHub when creating a branch from an issue.
+// Returns false when the branch name does not reference an issue.
+func IssueFromBranch(branch string) (int, bool) {
+	match := issueBranchPattern.FindStringSubmatch(branch)
+	if match == nil {
+		return 0, false
+	}
+	issue, err := strconv.Atoi(match[1])
+	if err != nil || issue == 0 {
+		return 0, false
+	}
+	return issue, true
+}
+
+// RenderFooter executes the body_footer template text with data.
+// Returns an error for an invalid template or an unknown placeholder.
+func RenderFooter(text string, data FooterData) (string, error) {
+	tmpl, err := template.New("body_footer").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid footer template: %w", err)
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("invalid footer template: %w", err)
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// AppendFooter returns body followed by a blank line and footer.
+// An empty footer, or one the body already ends with, leaves body unchanged.
+func AppendFooter(body, footer string) string {
+	body = strings.TrimRight(body, "\n")
+	switch {
+	case footer == "" || strings.HasSuffix(body, footer):
+		return body
+	case body == "":
+		return footer
+	default:
+		return body + "\n\n" + footer
+	}
+}
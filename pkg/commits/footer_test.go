@@ -0,0 +1,71 @@
+package commits_test
+
+import (
+	"testing"
+
+	"github.com/sgaunet/auto-mr/pkg/commits"
+)
+
+func TestIssueFromBranch(t *testing.T) {
+	tests := []struct {
+		branch string
+		issue  int
+		ok     bool
+	}{
+		{"123-fix-login", 123, true},
+		{"feature/42-add-button", 42, true},
+		{"issue-7", 7, true},
+		{"fix/#15", 15, true},
+		{"feature/login", 0, false},
+		{"v2-migration", 0, false},
+		{"release-1.2", 0, false},
+		{"0-zero", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			issue, ok := commits.IssueFromBranch(tt.branch)
+			if issue != tt.issue || ok != tt.ok {
+				t.Errorf("IssueFromBranch(%q) = %d, %v, want %d, %v", tt.branch, issue, ok, tt.issue, tt.ok)
+			}
+		})
+	}
+}
+
+func TestRenderFooter(t *testing.T) {
+	data := commits.FooterData{Branch: "42-fix", TargetBranch: "main", Issue: 42}
+
+	got, err := commits.RenderFooter("Branch: {{.Branch}} → {{.TargetBranch}}\n{{if .Issue}}Refs #{{.Issue}}{{end}}\n", data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "Branch: 42-fix → main\nRefs #42"; got != want {
+		t.Errorf("RenderFooter() = %q, want %q", got, want)
+	}
+
+	if _, err := commits.RenderFooter("{{.Author}}", data); err == nil {
+		t.Error("expected an error for an unknown placeholder")
+	}
+	if _, err := commits.RenderFooter("{{.Branch", data); err == nil {
+		t.Error("expected an error for an invalid template")
+	}
+}
+
+func TestAppendFooter(t *testing.T) {
+	tests := []struct {
+		name, body, footer, want string
+	}{
+		{"appended after a blank line", "Body\n", "Closes #1", "Body\n\nCloses #1"},
+		{"empty body", "", "Closes #1", "Closes #1"},
+		{"empty footer", "Body", "", "Body"},
+		{"already present", "Body\n\nCloses #1", "Closes #1", "Body\n\nCloses #1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commits.AppendFooter(tt.body, tt.footer); got != tt.want {
+				t.Errorf("AppendFooter(%q, %q) = %q, want %q", tt.body, tt.footer, got, tt.want)
+			}
+		})
+	}
+}
@@ -33,21 +33,15 @@ func ParseCommit(gitCommit *object.Commit) Commit {
 	}
 }
 
-// ParseCommitMessage splits commit message into title (first line) and body (remaining lines).
+// ParseCommitMessage splits commit message into title (first non-blank line) and body
+// (remaining lines). CRLF line endings are normalized and leading blank lines skipped,
+// so that the title is only empty for an empty message.
 // Title and body are trimmed of whitespace.
 // Returns empty body if commit message is single-line.
 func ParseCommitMessage(fullMessage string) (string, string) {
-	lines := strings.Split(fullMessage, "\n")
-	title := strings.TrimSpace(lines[0])
-	body := ""
-
-	if len(lines) > 1 {
-		// Join remaining lines, preserve formatting
-		bodyLines := lines[1:]
-		body = strings.TrimSpace(strings.Join(bodyLines, "\n"))
-	}
-
-	return title, body
+	message := strings.TrimSpace(strings.ReplaceAll(fullMessage, "\r\n", "\n"))
+	title, body, _ := strings.Cut(message, "\n")
+	return strings.TrimSpace(title), strings.TrimSpace(body)
 }
 
 // FilterValidCommits returns commits that are not merge commits and have non-empty messages.
@@ -0,0 +1,148 @@
+package commits
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// SummaryPlaceholder is replaced by the commit-derived description when a
+// body template is applied with [ApplyBodyTemplate].
+const SummaryPlaceholder = "{{summary}}"
+
+// templateFiles lists the single-file MR/PR templates, in lookup order,
+// relative to the repository root.
+var templateFiles = []string{
+	".github/pull_request_template.md",
+	".github/PULL_REQUEST_TEMPLATE.md",
+	"PULL_REQUEST_TEMPLATE.md",
+	"pull_request_template.md",
+	"docs/pull_request_template.md",
+	".gitea/pull_request_template.md",
+	".forgejo/pull_request_template.md",
+}
+
+// gitlabTemplateDir holds GitLab merge request templates, one per file.
+const gitlabTemplateDir = ".gitlab/merge_request_templates"
+
+// gitlabDefaultTemplate is the template GitLab itself preselects.
+const gitlabDefaultTemplate = "Default.md"
+
+// FindBodyTemplate returns the path of the repository's MR/PR description template.
+//
+// The single-file locations used by GitHub and Forgejo are checked first. In
+// .gitlab/merge_request_templates/, Default.md is used, or the only template
+// when the directory holds exactly one.
+//
+// Returns an empty path (and no error) when the repository has no template.
+func FindBodyTemplate(repoRoot string) (string, error) {
+	for _, name := range templateFiles {
+		path := filepath.Join(repoRoot, name)
+		info, err := os.Stat(path)
+		if err == nil && info.Mode().IsRegular() {
+			return path, nil
+		}
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("failed to check template %s: %w", path, err)
+		}
+	}
+
+	dir := filepath.Join(repoRoot, gitlabTemplateDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read template directory %s: %w", dir, err)
+	}
+
+	var templates []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && strings.EqualFold(filepath.Ext(entry.Name()), ".md") {
+			if entry.Name() == gitlabDefaultTemplate {
+				return filepath.Join(dir, entry.Name()), nil
+			}
+			templates = append(templates, entry.Name())
+		}
+	}
+	if len(templates) == 1 {
+		return filepath.Join(dir, templates[0]), nil
+	}
+	return "", nil
+}
+
+// LoadBodyTemplate reads the repository's MR/PR description template
+// (see [FindBodyTemplate]).
+//
+// Returns an empty template (and no error) when the repository has none.
+func LoadBodyTemplate(repoRoot string) (string, error) {
+	path, err := FindBodyTemplate(repoRoot)
+	if err != nil || path == "" {
+		return "", err
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // Path is built from the repository root and fixed names
+	if err != nil {
+		return "", fmt.Errorf("failed to read template %s: %w", path, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// LoadNamedTemplate reads the GitLab merge request template called name, with or
+// without its .md extension, from .gitlab/merge_request_templates/.
+//
+// Returns [ErrTemplateNotFound], listing the available templates, when there is
+// no template by that name.
+func LoadNamedTemplate(repoRoot, name string) (string, error) {
+	base := strings.TrimSuffix(strings.TrimSpace(name), ".md")
+	available, err := ListTemplates(repoRoot)
+	if err != nil {
+		return "", err
+	}
+	if !slices.Contains(available, base) {
+		if len(available) == 0 {
+			return "", fmt.Errorf("%w: %q (no templates in %s)", ErrTemplateNotFound, name, gitlabTemplateDir)
+		}
+		return "", fmt.Errorf("%w: %q (available in %s: %s)",
+			ErrTemplateNotFound, name, gitlabTemplateDir, strings.Join(available, ", "))
+	}
+
+	path := filepath.Join(repoRoot, gitlabTemplateDir, base+".md")
+	data, err := os.ReadFile(path) //nolint:gosec // Name is checked against the directory listing
+	if err != nil {
+		return "", fmt.Errorf("failed to read template %s: %w", path, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// ListTemplates returns the names, without the .md extension, of the GitLab merge
+// request templates in .gitlab/merge_request_templates/, sorted.
+// Returns an empty list (and no error) when the directory does not exist.
+func ListTemplates(repoRoot string) ([]string, error) {
+	dir := filepath.Join(repoRoot, gitlabTemplateDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read template directory %s: %w", dir, err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && filepath.Ext(entry.Name()) == ".md" {
+			names = append(names, strings.TrimSuffix(entry.Name(), ".md"))
+		}
+	}
+	return names, nil
+}
+
+// ApplyBodyTemplate returns the MR/PR description built from tmpl, with every
+// [SummaryPlaceholder] replaced by summary (the commit-derived description).
+// A template without the placeholder is returned unchanged.
+func ApplyBodyTemplate(tmpl, summary string) string {
+	return strings.ReplaceAll(tmpl, SummaryPlaceholder, summary)
+}
@@ -0,0 +1,151 @@
+package commits_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sgaunet/auto-mr/pkg/commits"
+)
+
+func writeTemplate(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+}
+
+func TestLoadBodyTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected string
+	}{
+		{
+			name:     "no template",
+			expected: "",
+		},
+		{
+			name:     "github template",
+			files:    map[string]string{".github/pull_request_template.md": "## Summary\n\n{{summary}}\n"},
+			expected: "## Summary\n\n{{summary}}",
+		},
+		{
+			name: "github template wins over gitlab",
+			files: map[string]string{
+				".github/pull_request_template.md":           "github",
+				".gitlab/merge_request_templates/Default.md": "gitlab",
+			},
+			expected: "github",
+		},
+		{
+			name: "gitlab default template",
+			files: map[string]string{
+				".gitlab/merge_request_templates/Bug.md":     "bug",
+				".gitlab/merge_request_templates/Default.md": "default",
+			},
+			expected: "default",
+		},
+		{
+			name:     "single gitlab template",
+			files:    map[string]string{".gitlab/merge_request_templates/Feature.md": "feature"},
+			expected: "feature",
+		},
+		{
+			name: "ambiguous gitlab templates",
+			files: map[string]string{
+				".gitlab/merge_request_templates/Bug.md":     "bug",
+				".gitlab/merge_request_templates/Feature.md": "feature",
+			},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for name, content := range tt.files {
+				writeTemplate(t, root, name, content)
+			}
+
+			got, err := commits.LoadBodyTemplate(root)
+			if err != nil {
+				t.Fatalf("LoadBodyTemplate() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("LoadBodyTemplate() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestApplyBodyTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		tmpl     string
+		summary  string
+		expected string
+	}{
+		{
+			name:     "placeholder replaced",
+			tmpl:     "## Summary\n\n{{summary}}\n\n## Checklist",
+			summary:  "Fix the parser",
+			expected: "## Summary\n\nFix the parser\n\n## Checklist",
+		},
+		{
+			name:     "no placeholder",
+			tmpl:     "## Checklist",
+			summary:  "Fix the parser",
+			expected: "## Checklist",
+		},
+		{
+			name:     "empty summary",
+			tmpl:     "{{summary}}",
+			summary:  "",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commits.ApplyBodyTemplate(tt.tmpl, tt.summary); got != tt.expected {
+				t.Errorf("ApplyBodyTemplate() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoadNamedTemplate(t *testing.T) {
+	root := t.TempDir()
+	writeTemplate(t, root, ".gitlab/merge_request_templates/Bug.md", "## Bug\n\n{{summary}}\n")
+	writeTemplate(t, root, ".gitlab/merge_request_templates/Feature.md", "feature")
+
+	for _, name := range []string{"Bug", "Bug.md"} {
+		got, err := commits.LoadNamedTemplate(root, name)
+		if err != nil {
+			t.Fatalf("LoadNamedTemplate(%q) error = %v", name, err)
+		}
+		if got != "## Bug\n\n{{summary}}" {
+			t.Errorf("LoadNamedTemplate(%q) = %q", name, got)
+		}
+	}
+
+	_, err := commits.LoadNamedTemplate(root, "Release")
+	if !errors.Is(err, commits.ErrTemplateNotFound) {
+		t.Fatalf("LoadNamedTemplate() error = %v, want ErrTemplateNotFound", err)
+	}
+	if !strings.Contains(err.Error(), "Bug, Feature") {
+		t.Errorf("error %q does not list the available templates", err)
+	}
+
+	_, err = commits.LoadNamedTemplate(t.TempDir(), "Bug")
+	if !errors.Is(err, commits.ErrTemplateNotFound) {
+		t.Errorf("LoadNamedTemplate() without templates error = %v, want ErrTemplateNotFound", err)
+	}
+}
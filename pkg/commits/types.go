@@ -20,6 +20,8 @@ const (
 	SelectionInteractive
 	// SelectionManual indicates user provided custom message via -msg flag.
 	SelectionManual
+	// SelectionRevision indicates the message of a commit named via --from-commit.
+	SelectionRevision
 )
 
 // Commit represents a single git commit with its metadata and message content.
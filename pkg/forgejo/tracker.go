@@ -6,15 +6,19 @@ import (
 	"time"
 
 	"code.gitea.io/sdk/gitea"
+	"github.com/sgaunet/auto-mr/internal/logger"
 	"github.com/sgaunet/bullets"
 )
 
 // newStatusTracker creates a new status tracker with initialized maps.
+// With plain progress ([logger.PlainProgress]), pending contexts get static handles
+// reprinted at every poll instead of spinners.
 func newStatusTracker() *statusTracker {
 	return &statusTracker{
 		entries:  make(map[string]*statusEntry),
 		handles:  make(map[string]*bullets.BulletHandle),
 		spinners: make(map[string]*bullets.Spinner),
+		plain:    logger.PlainProgress(),
 	}
 }
 
@@ -81,9 +85,9 @@ func (st *statusTracker) deleteSpinner(ctx string) {
 }
 
 // update processes new commit statuses, creates/updates display handles, and returns
-// a list of state transition descriptions for debug logging.
-func (st *statusTracker) update(statuses []*gitea.Status, logger *bullets.UpdatableLogger) []string {
-	var transitions []string
+// the state transitions detected.
+func (st *statusTracker) update(statuses []*gitea.Status, logger *bullets.UpdatableLogger) []Transition {
+	var transitions []Transition
 
 	for _, s := range statuses {
 		if s == nil || s.Context == "" {
@@ -97,9 +101,8 @@ func (st *statusTracker) update(statuses []*gitea.Status, logger *bullets.Updata
 			description: s.Description,
 		}
 
-		transition := st.processStatusUpdate(entry, logger)
-		if transition != "" {
-			transitions = append(transitions, transition)
+		if transition := st.processStatusUpdate(entry, logger); transition != nil {
+			transitions = append(transitions, *transition)
 		}
 	}
 
@@ -107,7 +110,8 @@ func (st *statusTracker) update(statuses []*gitea.Status, logger *bullets.Updata
 }
 
 // processStatusUpdate handles the update logic for a single status entry.
-func (st *statusTracker) processStatusUpdate(newEntry *statusEntry, logger *bullets.UpdatableLogger) string {
+// Returns nil when the context's state did not change.
+func (st *statusTracker) processStatusUpdate(newEntry *statusEntry, logger *bullets.UpdatableLogger) *Transition {
 	oldEntry, exists := st.getEntry(newEntry.context)
 
 	if !exists {
@@ -120,38 +124,45 @@ func (st *statusTracker) processStatusUpdate(newEntry *statusEntry, logger *bull
 
 	// No state change – update the stored description in case it changed.
 	st.setEntry(newEntry.context, newEntry)
-	return ""
+	if st.plain && newEntry.state == gitea.StatusPending {
+		// Static handles print a new line on update: one status line per poll.
+		st.finalizeHandle(newEntry.context, newEntry.state, formatStatusLabel(newEntry))
+	}
+	return nil
 }
 
 // handleNewStatus processes a newly detected commit status context.
-func (st *statusTracker) handleNewStatus(entry *statusEntry, logger *bullets.UpdatableLogger) string {
+func (st *statusTracker) handleNewStatus(entry *statusEntry, logger *bullets.UpdatableLogger) *Transition {
 	st.setEntry(entry.context, entry)
 	label := formatStatusLabel(entry)
 
-	if entry.state == gitea.StatusPending {
+	switch {
+	case entry.state != gitea.StatusPending:
+		handle := logger.InfoHandle(label)
+		st.setHandle(entry.context, handle)
+		st.finalizeHandle(entry.context, entry.state, label)
+	case st.plain:
+		st.setHandle(entry.context, logger.InfoHandle(label))
+	default:
 		spinner := logger.SpinnerCircle(context.Background(), label)
 		st.setSpinner(entry.context, spinner)
 
 		go st.updateSpinnerLoop(entry.context, spinner)
-	} else {
-		handle := logger.InfoHandle(label)
-		st.setHandle(entry.context, handle)
-		st.finalizeHandle(entry.context, entry.state, label)
 	}
 
-	return fmt.Sprintf("status %s: new state %s", entry.context, entry.state)
+	return &Transition{Name: entry.context, NewStatus: string(entry.state), ObservedAt: time.Now()}
 }
 
 // handleStatusChange processes a commit status context that transitioned state.
 func (st *statusTracker) handleStatusChange(
 	oldEntry, newEntry *statusEntry,
 	logger *bullets.UpdatableLogger,
-) string {
+) *Transition {
 	st.setEntry(newEntry.context, newEntry)
 	label := formatStatusLabel(newEntry)
 
-	wasPending := oldEntry.state == gitea.StatusPending
-	isPending := newEntry.state == gitea.StatusPending
+	wasPending := !st.plain && oldEntry.state == gitea.StatusPending
+	isPending := !st.plain && newEntry.state == gitea.StatusPending
 
 	switch {
 	case isPending && !wasPending:
@@ -168,14 +179,16 @@ func (st *statusTracker) handleStatusChange(
 		}
 
 	default:
-		// Was already resolved; update the static handle if present.
-		if handle, exists := st.getHandle(newEntry.context); exists {
-			handle.Update(bullets.InfoLevel, label)
-			st.finalizeHandle(newEntry.context, newEntry.state, label)
-		}
+		// Static handle (resolved context, or plain progress): finalize it if present.
+		st.finalizeHandle(newEntry.context, newEntry.state, label)
 	}
 
-	return fmt.Sprintf("status %s: %s -> %s", newEntry.context, oldEntry.state, newEntry.state)
+	return &Transition{
+		Name:       newEntry.context,
+		OldStatus:  string(oldEntry.state),
+		NewStatus:  string(newEntry.state),
+		ObservedAt: time.Now(),
+	}
 }
 
 // finalizeSpinner stops a spinner with the appropriate symbol.
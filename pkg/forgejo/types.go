@@ -1,10 +1,13 @@
 package forgejo
 
 import (
+	"fmt"
+	"net/http"
 	"sync"
 	"time"
 
 	"code.gitea.io/sdk/gitea"
+	"github.com/sgaunet/auto-mr/internal/timeutil"
 	"github.com/sgaunet/bullets"
 )
 
@@ -13,7 +16,10 @@ const (
 	minURLParts         = 2
 	statusPollInterval  = 5 * time.Second
 	spinnerUpdateInterval = 1 * time.Second
+	statusLineInterval  = 30 * time.Second // periodic progress line when spinners are disabled
 	pipelineGraceCycles = 2 // grace poll cycles before treating "no statuses" as success
+	maxCollaborators    = 50
+	defaultRequestTimeout = 30 * time.Second
 )
 
 // State string constants for CI status display.
@@ -31,19 +37,44 @@ const (
 //
 // Not safe for concurrent use.
 type Client struct {
-	client      *gitea.Client
-	owner       string
-	repo        string
-	prIndex     int64
-	prSHA       string
-	log         *bullets.Logger
+	client       *gitea.Client
+	httpClient   *http.Client // Base HTTP client, copied with the request timeout applied
+	owner        string
+	repo         string
+	headOwner    string           // Fork owner holding PR branches (empty: same as owner)
+	prIndex      int64
+	duplicates   []int64 // Other open PRs found by the last GetPullRequestByBranch
+	prSHA        string
+	mergeSHA     string // Commit created by the last merge (see Client.MergeCommitSHA)
+	log          *bullets.Logger
 	updatableLog *bullets.UpdatableLogger
-	display     *displayRenderer
+	display      *displayRenderer
+	onTransition func(Transition) // Optional status transition hook (nil disables it)
+	clock        timeutil.Clock   // Time source of the polling loop
 }
 
 // Label represents a Forgejo repository label.
 type Label struct {
-	Name string
+	Name        string
+	Color       string // Hex color with a leading '#' (e.g. "#d73a4a"), empty if unknown
+	Description string
+}
+
+// Transition describes a commit status state change observed while waiting for a pipeline.
+// It is passed to the hook registered with [Client.SetTransitionHook].
+type Transition struct {
+	Name       string    // Commit status context (e.g. "ci/woodpecker/push/test")
+	OldStatus  string    // Previous state (empty when the context was first seen)
+	NewStatus  string    // Current state: "pending", "success", "error", "failure" or "warning"
+	ObservedAt time.Time // When the change was detected
+}
+
+// String returns a one-line description, as logged at debug level.
+func (t Transition) String() string {
+	if t.OldStatus == "" {
+		return fmt.Sprintf("status %s: new state %s", t.Name, t.NewStatus)
+	}
+	return fmt.Sprintf("status %s: %s -> %s", t.Name, t.OldStatus, t.NewStatus)
 }
 
 // statusEntry holds the per-status-context display state used by [statusTracker].
@@ -61,4 +92,5 @@ type statusTracker struct {
 	entries  map[string]*statusEntry
 	handles  map[string]*bullets.BulletHandle
 	spinners map[string]*bullets.Spinner
+	plain    bool // --progress=plain: handles printed at every poll instead of spinners
 }
@@ -2,9 +2,27 @@ package git
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Reasons a local branch is reported by [Repository.StaleBranches].
+const (
+	StaleUpstreamGone = "upstream gone"
+	StaleMerged       = "merged"
 )
 
+// StaleBranch is a local branch left over from merged work.
+type StaleBranch struct {
+	Name   string
+	Reason string // [StaleUpstreamGone] or [StaleMerged]
+}
+
 // CleanupReport tracks the state of each cleanup operation.
 type CleanupReport struct {
 	// Step completion status
@@ -111,3 +129,84 @@ func (r *Repository) Cleanup(ctx context.Context, mainBranch, currentBranch stri
 
 	return report
 }
+
+// StaleBranches lists the local branches left over from merged work, sorted by name:
+// those whose upstream branch no longer exists on its remote (deleted by the merge,
+// once a fetch with --prune has run), and those whose tip is contained in mainBranch.
+//
+// mainBranch, the current branch and branches pointing at the tip of mainBranch
+// (e.g. created but without commits yet) are never listed. mainBranch is read from
+// the local branch, or from origin when there is no local one.
+func (r *Repository) StaleBranches(mainBranch string) ([]StaleBranch, error) {
+	mainCommit, err := r.branchTip(mainBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := r.repo.Config()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git config: %w", err)
+	}
+	current := ""
+	if head, err := r.repo.Head(); err == nil && head.Name().IsBranch() {
+		current = head.Name().Short()
+	}
+
+	branches, err := r.repo.Branches()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+	var stale []StaleBranch
+	err = branches.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().Short()
+		if name == mainBranch || name == current || ref.Hash() == mainCommit.Hash {
+			return nil
+		}
+
+		if upstream, ok := cfg.Branches[name]; ok && upstream.Remote != "" && upstream.Remote != "." &&
+			upstream.Merge.IsBranch() {
+			tracking := plumbing.NewRemoteReferenceName(upstream.Remote, upstream.Merge.Short())
+			if _, err := r.repo.Reference(tracking, true); errors.Is(err, plumbing.ErrReferenceNotFound) {
+				stale = append(stale, StaleBranch{Name: name, Reason: StaleUpstreamGone})
+				return nil
+			}
+		}
+
+		commit, err := r.repo.CommitObject(ref.Hash())
+		if err != nil {
+			return fmt.Errorf("failed to read branch %s: %w", name, err)
+		}
+		merged, err := commit.IsAncestor(mainCommit)
+		if err != nil {
+			return fmt.Errorf("failed to compare branch %s with %s: %w", name, mainBranch, err)
+		}
+		if merged {
+			stale = append(stale, StaleBranch{Name: name, Reason: StaleMerged})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck // Errors are wrapped in the callback
+	}
+
+	slices.SortFunc(stale, func(a, b StaleBranch) int { return strings.Compare(a.Name, b.Name) })
+	return stale, nil
+}
+
+// branchTip returns the commit of the local branch, or of origin's branch when
+// there is no local one.
+func (r *Repository) branchTip(branchName string) (*object.Commit, error) {
+	ref, err := r.repo.Reference(plumbing.NewBranchReferenceName(branchName), true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		ref, err = r.repo.Reference(plumbing.NewRemoteReferenceName("origin", branchName), true)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve branch %s: %w", branchName, err)
+	}
+
+	commit, err := r.repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read branch %s: %w", branchName, err)
+	}
+	return commit, nil
+}
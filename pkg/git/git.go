@@ -28,6 +28,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 
@@ -52,6 +53,9 @@ const (
 	// networkGitTimeout for network git operations (pull, fetch).
 	networkGitTimeout = 2 * time.Minute
 
+	// signGitTimeout for signed commits, which may wait on a passphrase prompt.
+	signGitTimeout = 2 * time.Minute
+
 	// minSymrefFields is the minimum number of fields expected in "git ls-remote --symref" output.
 	minSymrefFields = 2
 
@@ -62,15 +66,77 @@ const (
 )
 
 var (
-	errMainBranchNotFound  = errors.New("could not determine main branch")
-	errHEADNotBranch       = errors.New("HEAD is not pointing to a branch")
-	errNoRemoteURLs        = errors.New("no URLs found for origin remote")
-	errUnsupportedPlatform = errors.New("repository is not hosted on GitLab, GitHub, or Forgejo")
-	errStopIteration       = errors.New("stop iteration")
-	errNoSSHKeys           = errors.New("no SSH keys found in ~/.ssh")
-	errNotGitRepository    = errors.New("not a git repository (or any parent up to mount point)")
+	errMainBranchNotFound   = errors.New("could not determine main branch")
+	errHEADNotBranch        = errors.New("HEAD is not pointing to a branch")
+	errNoRemoteURLs         = errors.New("no URLs found for origin remote")
+	errUnsupportedPlatform  = errors.New("repository is not hosted on GitLab, GitHub, or Forgejo")
+	errUnknownPlatform      = errors.New("unknown platform")
+	errStopIteration        = errors.New("stop iteration")
+	errNoSSHKeys            = errors.New("no SSH keys found in ~/.ssh")
+	errNotGitRepository     = errors.New("not a git repository (or any parent up to mount point)")
+	errRemoteBranchNotFound = errors.New("branch not found on remote")
+	errNothingStaged        = errors.New("no staged changes to commit")
+	errPushNonFastForward   = errors.New("push rejected: remote branch has commits that are not in the local branch")
+	errPushProtected        = errors.New("push rejected: branch is protected on the remote")
+	errPushAuthFailed       = errors.New("push rejected: authentication failed")
+	errPushStaleLease       = errors.New("push rejected: remote branch changed since it was last fetched")
+	errNotFastForward       = errors.New("local branch has commits that are not on the remote")
+	errNoMergeBase          = errors.New("no common ancestor with the target branch")
+
+	// ErrHEADNotBranch is returned by [Repository.GetCurrentBranch] when HEAD is detached.
+	ErrHEADNotBranch = errHEADNotBranch
+	// ErrNotGitRepository is returned by [FindRoot] when no enclosing repository exists.
+	ErrNotGitRepository = errNotGitRepository
+	// ErrNothingStaged is returned by [Repository.CommitStaged] when the index has no changes.
+	ErrNothingStaged = errNothingStaged
+	// ErrMainBranchNotFound is returned by [Repository.GetMainBranch] when detection fails.
+	ErrMainBranchNotFound = errMainBranchNotFound
+	// ErrPushNonFastForward is returned by [Repository.PushBranch] when the remote branch has diverged.
+	ErrPushNonFastForward = errPushNonFastForward
+	// ErrPushProtected is returned by [Repository.PushBranch] when the remote refuses pushes to the branch.
+	ErrPushProtected = errPushProtected
+	// ErrPushAuthFailed is returned by [Repository.PushBranch] when the remote rejects the credentials.
+	ErrPushAuthFailed = errPushAuthFailed
+	// ErrPushStaleLease is returned by [Repository.PushBranchWithLease] when the remote
+	// branch no longer matches its remote-tracking branch.
+	ErrPushStaleLease = errPushStaleLease
+	// ErrNotFastForward is returned by [Repository.FastForwardBranch] when the local branch
+	// has diverged from the remote one.
+	ErrNotFastForward = errNotFastForward
 )
 
+// pushRejections maps fragments of "git push" output to the rejection they denote.
+// Protected-branch markers are checked before non-fast-forward ones because
+// hosting platforms print both when a hook declines a diverged push.
+var pushRejections = []struct {
+	err       error
+	fragments []string
+}{
+	{errPushProtected, []string{
+		"protected branch",
+		"GH006",
+		"not allowed to push",
+		"not allowed to force push",
+		"pre-receive hook declined",
+	}},
+	{errPushStaleLease, []string{
+		"stale info",
+	}},
+	{errPushNonFastForward, []string{
+		"non-fast-forward",
+		"fetch first",
+		"tip of your current branch is behind",
+	}},
+	{errPushAuthFailed, []string{
+		"Authentication failed",
+		"HTTP Basic: Access denied",
+		"could not read Username",
+		"Permission denied (publickey",
+		"Permission to",
+		"The requested URL returned error: 403",
+	}},
+}
+
 // GitTimeoutError wraps timeout errors with the name of the operation that timed out
 // and the configured timeout duration. Use errors.As to check for this error type.
 //
@@ -106,10 +172,12 @@ type authMethod struct {
 //
 // Not safe for concurrent use.
 type Repository struct {
-	repo    *git.Repository
-	gitRoot string // absolute path to git repository root
-	auth    transport.AuthMethod
-	log     *bullets.Logger
+	repo     *git.Repository
+	gitRoot  string // absolute path to git repository root
+	auth     transport.AuthMethod
+	caBundle []byte // additional PEM CA certificates for HTTPS remotes
+	main     string // configured main branch, bypassing detection (empty: detect)
+	log      *bullets.Logger
 }
 
 // Platform represents a git hosting platform.
@@ -154,6 +222,14 @@ func findGitRoot(startPath string) (string, error) {
 	}
 }
 
+// FindRoot returns the root of the git repository containing path, searching
+// upward for a .git directory (or file, for linked worktrees).
+//
+// Returns [ErrNotGitRepository] if no repository is found.
+func FindRoot(path string) (string, error) {
+	return findGitRoot(path)
+}
+
 // OpenRepository opens a git repository at the given path.
 // It searches upward from path to find the .git directory and configures
 // authentication automatically based on the remote URL.
@@ -208,6 +284,51 @@ func (r *Repository) SetLogger(logger *bullets.Logger) {
 	r.log.Debug("Opening git repository")
 }
 
+// SetCABundle sets additional PEM-encoded CA certificates trusted by go-git
+// HTTPS operations (push, remote listing), on top of the system pool.
+// A nil bundle restores the default behavior.
+func (r *Repository) SetCABundle(caBundle []byte) {
+	r.caBundle = caBundle
+}
+
+// SetMainBranch makes [Repository.GetMainBranch] return branch without detection,
+// for repositories whose default branch cannot be detected or is not the merge target.
+// An empty branch restores detection.
+func (r *Repository) SetMainBranch(branch string) {
+	r.main = branch
+}
+
+// SetPushUsername replaces the username sent with the platform token for HTTPS pushes
+// (oauth2, x-access-token or forgejo by default), for instances that expect another one,
+// such as the account name. It has no effect with SSH remotes, without a token, or when
+// the push falls back to native git. An empty username keeps the default.
+func (r *Repository) SetPushUsername(username string) {
+	if basic, ok := r.auth.(*http.BasicAuth); ok && username != "" {
+		basic.Username = username
+	}
+}
+
+// SetPlatform makes HTTPS pushes use the token of platform p rather than the one
+// guessed from the remote host, for mirrors and self-hosted instances whose host
+// does not tell the platform (--platform). It has no effect with SSH remotes.
+// Call it before [Repository.SetPushUsername], which adjusts the resulting credentials.
+func (r *Repository) SetPlatform(p Platform) {
+	remote, err := r.repo.Remote("origin")
+	if err != nil || len(remote.Config().URLs) == 0 {
+		return
+	}
+	remoteURL := remote.Config().URLs[0]
+	if !strings.HasPrefix(remoteURL, "https://") {
+		return
+	}
+	auth, _ := platformHTTPSAuth(p, remoteURL, r.log)
+	if basic, ok := auth.method.(*http.BasicAuth); ok {
+		r.auth = basic
+	} else {
+		r.auth = nil
+	}
+}
+
 // getAuth determines the appropriate authentication method based on the remote URL.
 func getAuth(repo *git.Repository, logger *bullets.Logger) (*authMethod, error) {
 	remote, err := repo.Remote("origin")
@@ -237,10 +358,24 @@ func getAuth(repo *git.Repository, logger *bullets.Logger) (*authMethod, error)
 	return &authMethod{method: &noAuthMethod{}}, nil // No authentication needed
 }
 
-// getHTTPSAuth returns HTTP authentication for HTTPS URLs.
+// getHTTPSAuth returns HTTP authentication for HTTPS URLs, with the token of the
+// platform the host belongs to.
 func getHTTPSAuth(url string, logger *bullets.Logger) (*authMethod, error) {
 	switch {
 	case strings.Contains(url, "gitlab.com"):
+		return platformHTTPSAuth(PlatformGitLab, url, logger)
+	case strings.Contains(url, "github.com"):
+		return platformHTTPSAuth(PlatformGitHub, url, logger)
+	default:
+		// Forgejo / self-hosted Gitea: any URL that is neither gitlab.com nor github.com.
+		return platformHTTPSAuth(PlatformForgejo, url, logger)
+	}
+}
+
+// platformHTTPSAuth returns HTTP authentication for url with the token of platform p.
+func platformHTTPSAuth(p Platform, url string, logger *bullets.Logger) (*authMethod, error) {
+	switch p {
+	case PlatformGitLab:
 		if tokenStr := os.Getenv("GITLAB_TOKEN"); tokenStr != "" {
 			token := security.NewSecureToken(tokenStr)
 			security.DebugAuth(logger, "GitLab", map[string]string{
@@ -253,7 +388,7 @@ func getHTTPSAuth(url string, logger *bullets.Logger) (*authMethod, error) {
 			}}, nil
 		}
 		logger.Debug("GITLAB_TOKEN not found")
-	case strings.Contains(url, "github.com"):
+	case PlatformGitHub:
 		if tokenStr := os.Getenv("GITHUB_TOKEN"); tokenStr != "" {
 			token := security.NewSecureToken(tokenStr)
 			security.DebugAuth(logger, "GitHub", map[string]string{
@@ -266,8 +401,7 @@ func getHTTPSAuth(url string, logger *bullets.Logger) (*authMethod, error) {
 			}}, nil
 		}
 		logger.Debug("GITHUB_TOKEN not found")
-	default:
-		// Forgejo / self-hosted Gitea: any URL that is neither gitlab.com nor github.com.
+	case PlatformForgejo:
 		if tokenStr := os.Getenv("FORGEJO_TOKEN"); tokenStr != "" {
 			token := security.NewSecureToken(tokenStr)
 			security.DebugAuth(logger, "Forgejo", map[string]string{
@@ -353,9 +487,14 @@ func setupSSHAuth(logger *bullets.Logger) (*authMethod, error) {
 // If that fails (common with certain SSH configurations), it falls back to native
 // "git ls-remote --symref" which uses the system's SSH agent and config.
 // As a last resort, it checks for local "main" or "master" branches.
+// A branch set with [Repository.SetMainBranch] takes precedence over all of these.
 //
-// Returns errMainBranchNotFound if no method succeeds.
+// Returns [ErrMainBranchNotFound], listing the origin branches, if no method succeeds.
 func (r *Repository) GetMainBranch() (string, error) {
+	if r.main != "" {
+		r.log.Debug("Main branch configured: " + r.main)
+		return r.main, nil
+	}
 	r.log.Debug("Determining main branch")
 
 	// Priority 1: Try go-git's remote.List
@@ -380,12 +519,37 @@ func (r *Repository) GetMainBranch() (string, error) {
 		}
 	}
 
+	if remoteBranches := r.remoteTrackingBranches(); len(remoteBranches) > 0 {
+		return "", fmt.Errorf("%w (branches on origin: %s)",
+			errMainBranchNotFound, strings.Join(remoteBranches, ", "))
+	}
 	return "", errMainBranchNotFound
 }
 
+// remoteTrackingBranches returns the sorted names of the branches known on origin,
+// from the local remote-tracking references (no network access).
+func (r *Repository) remoteTrackingBranches() []string {
+	refs, err := r.repo.References()
+	if err != nil {
+		return nil
+	}
+	defer refs.Close()
+
+	var branches []string
+	_ = refs.ForEach(func(ref *plumbing.Reference) error {
+		branch, ok := strings.CutPrefix(ref.Name().String(), "refs/remotes/origin/")
+		if ok && branch != "HEAD" {
+			branches = append(branches, branch)
+		}
+		return nil
+	})
+	slices.Sort(branches)
+	return branches
+}
+
 // GetCurrentBranch returns the short name of the currently checked out branch.
 //
-// Returns errHEADNotBranch if HEAD is in detached state.
+// Returns [ErrHEADNotBranch] if HEAD is in detached state.
 func (r *Repository) GetCurrentBranch() (string, error) {
 	head, err := r.repo.Head()
 	if err != nil {
@@ -399,6 +563,15 @@ func (r *Repository) GetCurrentBranch() (string, error) {
 	return head.Name().Short(), nil
 }
 
+// HeadSHA returns the full hash of the commit checked out at HEAD.
+func (r *Repository) HeadSHA() (string, error) {
+	head, err := r.repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to get HEAD reference: %w", err)
+	}
+	return head.Hash().String(), nil
+}
+
 // HasStagedChanges checks if there are any staged changes in the repository.
 func (r *Repository) HasStagedChanges() (bool, error) {
 	worktree, err := r.repo.Worktree()
@@ -420,6 +593,110 @@ func (r *Repository) HasStagedChanges() (bool, error) {
 	return false, nil
 }
 
+// CommitStaged records the staged changes as a new commit on the current branch.
+// Author and committer are taken from user.name and user.email in the git configuration.
+// When commit.gpgsign is enabled the commit is created by native "git commit" so it is
+// signed with user.signingkey, honoring gpg.format; go-git cannot read those keys.
+//
+// Returns the hash of the new commit.
+// Returns [ErrNothingStaged] if there is nothing staged.
+func (r *Repository) CommitStaged(message string) (string, error) {
+	staged, err := r.HasStagedChanges()
+	if err != nil {
+		return "", err
+	}
+	if !staged {
+		return "", errNothingStaged
+	}
+
+	if r.SigningEnabled() {
+		return r.commitViaNativeGit(message)
+	}
+
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	hash, err := worktree.Commit(message, &git.CommitOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to commit staged changes: %w", err)
+	}
+
+	r.log.Debug("Created commit " + hash.String())
+	return hash.String(), nil
+}
+
+// SigningEnabled reports whether commit.gpgsign is set in the git configuration
+// (repository, global or system) as resolved by native git.
+// Returns false when git is unavailable or the option is unset.
+func (r *Repository) SigningEnabled() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), localGitTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "config", "--bool", "--get", "commit.gpgsign")
+	cmd.Dir = r.gitRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(output)) == "true"
+}
+
+// UnsignedCommits returns the short hashes of the commits on HEAD since mainBranch
+// that carry no signature, oldest first.
+func (r *Repository) UnsignedCommits(mainBranch string) ([]string, error) {
+	commits, err := r.GetCommitsSinceMain(mainBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	var unsigned []string
+	for _, commit := range slices.Backward(commits) {
+		if commit.PGPSignature == "" {
+			unsigned = append(unsigned, commit.Hash.String()[:7])
+		}
+	}
+	return unsigned, nil
+}
+
+// commitViaNativeGit commits the index with native "git commit", which signs the
+// commit according to commit.gpgsign, user.signingkey and gpg.format.
+// The message is passed on stdin so it is recorded verbatim.
+//
+// Returns [*GitTimeoutError] if the operation exceeds signGitTimeout (2m).
+func (r *Repository) commitViaNativeGit(message string) (string, error) {
+	r.log.Debug("Creating signed commit using git commit")
+
+	ctx, cancel := context.WithTimeout(context.Background(), signGitTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "commit", "--quiet", "--cleanup=verbatim", "--file=-")
+	cmd.Dir = r.gitRoot
+	cmd.Stdin = strings.NewReader(message)
+	output, err := cmd.CombinedOutput()
+
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "", &GitTimeoutError{
+			Operation: "commit",
+			Timeout:   signGitTimeout,
+			Err:       err,
+		}
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to commit staged changes: %w\nOutput: %s", err, string(output))
+	}
+
+	hash, err := r.HeadSHA()
+	if err != nil {
+		return "", err
+	}
+
+	r.log.Debug("Created signed commit " + hash)
+	return hash, nil
+}
+
 // DetectPlatform determines if the repository is hosted on GitLab, GitHub, or Forgejo
 // by inspecting the origin remote URL.
 //
@@ -459,6 +736,23 @@ func (r *Repository) DetectPlatform(forgejoURL string) (Platform, error) {
 	return "", errUnsupportedPlatform
 }
 
+// ParsePlatform returns the platform named name, as given to --platform: "gitlab",
+// "github" or "forgejo", case-insensitively. "gitea" is accepted for Forgejo, which
+// shares its API.
+//
+// Returns errUnknownPlatform for any other name.
+func ParsePlatform(name string) (Platform, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case string(PlatformGitLab):
+		return PlatformGitLab, nil
+	case string(PlatformGitHub):
+		return PlatformGitHub, nil
+	case string(PlatformForgejo), "gitea":
+		return PlatformForgejo, nil
+	}
+	return "", fmt.Errorf("%w %q: expected gitlab, github, forgejo or gitea", errUnknownPlatform, name)
+}
+
 // extractHost returns the hostname from a URL string.
 // It uses net/url.Parse; if that fails or yields no host, it strips the scheme
 // prefix as a fallback.
@@ -476,32 +770,196 @@ func extractHost(rawURL string) string {
 	return host
 }
 
+// PushResult describes what a push did to the remote branch.
+type PushResult string
+
+const (
+	// PushCreated means the branch did not exist on origin and was created.
+	PushCreated PushResult = "created"
+	// PushUpdated means the existing remote branch was moved to the local tip.
+	PushUpdated PushResult = "updated"
+	// PushUpToDate means the remote branch already matched, nothing was pushed.
+	PushUpToDate PushResult = "up to date"
+)
+
 // PushBranch pushes the specified branch to the origin remote.
 // It first tries go-git for authentication consistency, then falls back to native
 // "git push" which uses the system's SSH agent and config.
-// If the branch is already up to date, no error is returned.
+// If the branch is already up to date, no error is returned and the result is
+// [PushUpToDate].
 //
 // Parameters:
 //   - branchName: the local branch name to push
-func (r *Repository) PushBranch(branchName string) error {
+//
+// Returns whether the remote branch was created, updated or already up to date.
+// A branch with no remote-tracking ref (origin/<branchName>) is reported as created.
+func (r *Repository) PushBranch(branchName string) (PushResult, error) {
+	return r.pushBranch(branchName, false)
+}
+
+// PushBranchWithLease is like [Repository.PushBranch] but replaces the remote branch
+// even when the push is not a fast-forward (e.g. after a local rebase), as long as the
+// remote branch still points where origin/<branchName> does locally. Commits pushed by
+// someone else since the last fetch are therefore never overwritten.
+//
+// Returns [ErrPushStaleLease] if the remote branch has moved since the last fetch.
+func (r *Repository) PushBranchWithLease(branchName string) (PushResult, error) {
+	return r.pushBranch(branchName, true)
+}
+
+func (r *Repository) pushBranch(branchName string, withLease bool) (PushResult, error) {
 	r.log.Debug("Pushing branch: " + branchName)
 
-	// Priority 1: Try go-git push
-	err := r.repo.Push(&git.PushOptions{
+	options := &git.PushOptions{
 		RemoteName: "origin",
 		RefSpecs: []config.RefSpec{
 			config.RefSpec("refs/heads/" + branchName + ":refs/heads/" + branchName),
 		},
-		Auth: r.auth,
-	})
-	if err == nil || errors.Is(err, git.NoErrAlreadyUpToDate) {
+		Auth:     r.auth,
+		CABundle: r.caBundle,
+	}
+	if withLease {
+		// An empty lease expects the remote branch to match origin/<branchName>.
+		options.ForceWithLease = &git.ForceWithLease{}
+	}
+
+	// Checked before pushing: go-git updates the remote-tracking ref on success.
+	_, trackErr := r.repo.Reference(plumbing.NewRemoteReferenceName("origin", branchName), true)
+	tracked := trackErr == nil
+
+	// Priority 1: Try go-git push
+	err := r.repo.Push(options)
+	switch {
+	case errors.Is(err, git.NoErrAlreadyUpToDate):
+		r.log.Debug("Branch already up to date (go-git): " + branchName)
+		return PushUpToDate, nil
+	case err == nil && !tracked:
+		r.log.Debug("New branch pushed successfully (go-git): " + branchName)
+		return PushCreated, nil
+	case err == nil:
 		r.log.Debug("Branch pushed successfully (go-git): " + branchName)
-		return nil
+		return PushUpdated, nil
 	}
 
-	// Priority 2: Fall back to native git push (uses system SSH agent/config)
+	// Priority 2: Fall back to native git push (uses system SSH agent/config),
+	// which checks the lease again itself.
 	r.log.Debug("go-git push failed, falling back to native git: " + err.Error())
-	return r.pushBranchViaNativeGit(branchName)
+	return r.pushBranchViaNativeGit(branchName, withLease)
+}
+
+// RemoteBranchExists reports whether the given branch exists on the origin remote.
+// It first lists remote references with go-git, then falls back to native
+// "git ls-remote --heads" which uses the system's SSH agent and config.
+//
+// Parameters:
+//   - branchName: the branch name to look up (without "refs/heads/" prefix)
+func (r *Repository) RemoteBranchExists(branchName string) (bool, error) {
+	r.log.Debug("Checking remote branch: " + branchName)
+
+	hash, err := r.remoteBranchHash("origin", branchName)
+	if err != nil {
+		return false, err
+	}
+	return !hash.IsZero(), nil
+}
+
+// RemoteBranchSHA returns the commit the given branch points to on the origin remote,
+// or "" if the branch does not exist there. The lookup is the same as [Repository.RemoteBranchExists].
+//
+// Parameters:
+//   - branchName: the branch name to look up (without "refs/heads/" prefix)
+func (r *Repository) RemoteBranchSHA(branchName string) (string, error) {
+	hash, err := r.remoteBranchHash("origin", branchName)
+	if err != nil || hash.IsZero() {
+		return "", err
+	}
+	return hash.String(), nil
+}
+
+// IsBehindRemoteBranch reports whether the remote target branch has commits that
+// are not contained in the current HEAD, i.e. the target advanced since the
+// feature branch diverged and a rebase is needed to be up to date.
+// A remote tip that is unknown locally (not fetched yet) is treated as ahead.
+//
+// Parameters:
+//   - remoteName: the remote the merge request targets (e.g., "origin", or "upstream" for a fork)
+//   - targetBranch: the branch the feature branch will be merged into (e.g., "main")
+func (r *Repository) IsBehindRemoteBranch(remoteName, targetBranch string) (bool, error) {
+	r.log.Debug("Checking if target branch advanced: " + remoteName + "/" + targetBranch)
+
+	remoteHash, err := r.remoteBranchHash(remoteName, targetBranch)
+	if err != nil {
+		return false, err
+	}
+	if remoteHash.IsZero() {
+		return false, fmt.Errorf("%w: %s", errRemoteBranchNotFound, targetBranch)
+	}
+
+	head, err := r.repo.Head()
+	if err != nil {
+		return false, fmt.Errorf("failed to get HEAD reference: %w", err)
+	}
+	if head.Hash() == remoteHash {
+		return false, nil
+	}
+
+	remoteTip, err := r.repo.CommitObject(remoteHash)
+	if errors.Is(err, plumbing.ErrObjectNotFound) {
+		r.log.Debug("Remote tip not found locally, target branch has advanced: " + remoteHash.String())
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get remote tip commit: %w", err)
+	}
+
+	headCommit, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		return false, fmt.Errorf("failed to get commit object: %w", err)
+	}
+
+	contained, err := remoteTip.IsAncestor(headCommit)
+	if err != nil {
+		return false, fmt.Errorf("failed to compare with remote tip: %w", err)
+	}
+	return !contained, nil
+}
+
+// remoteBranchHash returns the commit hash of a branch on the given remote, or
+// the zero hash if the branch does not exist. It first lists remote references
+// with go-git, then falls back to native "git ls-remote --heads".
+func (r *Repository) remoteBranchHash(remoteName, branchName string) (plumbing.Hash, error) {
+	target := plumbing.NewBranchReferenceName(branchName)
+
+	refs, err := r.listRemoteRefs(remoteName)
+	if err == nil {
+		for _, ref := range refs {
+			if ref.Name() == target {
+				return ref.Hash(), nil
+			}
+		}
+		return plumbing.ZeroHash, nil
+	}
+	r.log.Debug("go-git remote list failed, falling back to native git: " + err.Error())
+
+	ctx, cancel := context.WithTimeout(context.Background(), networkGitTimeout)
+	defer cancel()
+
+	// #nosec G204 - remoteName and branchName come from git, not user input
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", "--heads", remoteName, target.String())
+	cmd.Dir = r.gitRoot
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		//nolint:wrapcheck // Error is sanitized to prevent token leakage
+		return plumbing.ZeroHash, security.SanitizeError(
+			fmt.Errorf("git ls-remote failed: %w\nOutput: %s", err, string(output)))
+	}
+
+	// Parse output like: "<sha>\trefs/heads/<branch>"
+	fields := strings.Fields(string(output))
+	if len(fields) == 0 {
+		return plumbing.ZeroHash, nil
+	}
+	return plumbing.NewHash(fields[0]), nil
 }
 
 // SwitchBranch switches to the specified branch using native "git switch".
@@ -648,7 +1106,60 @@ func (r *Repository) FetchAndPrune(ctx context.Context) error {
 	return nil
 }
 
-// GetLatestCommitMessage returns the full commit message of the current HEAD commit.
+// FastForwardBranch fast-forwards the local branch to the one on origin without checking
+// it out, using native "git fetch origin <branch>:<branch>". It returns false without
+// error when there is no such local branch, since there is nothing to keep up to date.
+// The branch must not be the current one: git refuses to update it this way.
+//
+// Parameters:
+//   - ctx: context for cancellation (further bounded by networkGitTimeout)
+//   - branchName: the local branch to update
+//
+// Returns true if the branch moved.
+// Returns [ErrNotFastForward] if the local branch has commits that are not on origin.
+// Returns [*GitTimeoutError] if the operation exceeds networkGitTimeout (2m).
+func (r *Repository) FastForwardBranch(ctx context.Context, branchName string) (bool, error) {
+	ref := plumbing.NewBranchReferenceName(branchName)
+	before, err := r.repo.Reference(ref, true)
+	if err != nil {
+		r.log.Debug("No local branch to fast-forward: " + branchName)
+		return false, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, networkGitTimeout)
+	defer cancel()
+
+	// #nosec G204 - branchName comes from git, not user input
+	cmd := exec.CommandContext(ctx, "git", "fetch", "origin", branchName+":"+branchName)
+	cmd.Dir = r.gitRoot
+	output, err := cmd.CombinedOutput()
+
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return false, &GitTimeoutError{
+			Operation: "fetch",
+			Timeout:   networkGitTimeout,
+			Err:       err,
+		}
+	}
+
+	if err != nil {
+		sanitized := security.SanitizeError(fmt.Errorf("failed to fetch %s: %w\nOutput: %s", branchName, err, string(output)))
+		if strings.Contains(string(output), "non-fast-forward") {
+			return false, fmt.Errorf("%w: %w", errNotFastForward, sanitized)
+		}
+		//nolint:wrapcheck // Error is sanitized to prevent token leakage
+		return false, sanitized
+	}
+
+	after, err := r.repo.Reference(ref, true)
+	if err != nil {
+		return false, fmt.Errorf("failed to read branch %s: %w", branchName, err)
+	}
+	return after.Hash() != before.Hash(), nil
+}
+
+// GetLatestCommitMessage returns the full commit message of the current HEAD commit,
+// with CRLF line endings normalized and surrounding blank lines trimmed.
 func (r *Repository) GetLatestCommitMessage() (string, error) {
 	head, err := r.repo.Head()
 	if err != nil {
@@ -660,7 +1171,7 @@ func (r *Repository) GetLatestCommitMessage() (string, error) {
 		return "", fmt.Errorf("failed to get commit object: %w", err)
 	}
 
-	return commit.Message, nil
+	return strings.TrimSpace(strings.ReplaceAll(commit.Message, "\r\n", "\n")), nil
 }
 
 // GetCommitsSinceMain returns all commits on the current branch since it diverged from the main branch.
@@ -703,6 +1214,117 @@ func (r *Repository) GetCommitsSinceMain(mainBranch string) ([]*object.Commit, e
 	return commits, nil
 }
 
+// ChangedFiles returns the paths (slash-separated, relative to the repository root)
+// changed on the current branch since it diverged from targetBranch: the diff
+// between their merge base and HEAD. A renamed file is listed under both names.
+// origin/<targetBranch> is preferred to the local branch, which may be stale.
+//
+// Parameters:
+//   - targetBranch: the branch the feature branch will be merged into (e.g., "main")
+func (r *Repository) ChangedFiles(targetBranch string) ([]string, error) {
+	changes, err := r.branchChanges(targetBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(changes))
+	for _, change := range changes {
+		for _, name := range []string{change.From.Name, change.To.Name} {
+			if name != "" && !slices.Contains(paths, name) {
+				paths = append(paths, name)
+			}
+		}
+	}
+	return paths, nil
+}
+
+// DiffStat returns the lines added and deleted per file on the current branch since
+// it diverged from targetBranch, like git diff --stat <merge base>: the same diff as
+// [Repository.ChangedFiles]. Binary files are listed with no line counts.
+//
+// Parameters:
+//   - targetBranch: the branch the feature branch will be merged into (e.g., "main")
+func (r *Repository) DiffStat(targetBranch string) (object.FileStats, error) {
+	patch, err := r.branchPatch(targetBranch)
+	if err != nil {
+		return nil, err
+	}
+	return patch.Stats(), nil
+}
+
+// Diff returns the unified diff of the current branch since it diverged from
+// targetBranch, like git diff <merge base>: the same diff as [Repository.DiffStat].
+//
+// Parameters:
+//   - targetBranch: the branch the feature branch will be merged into (e.g., "main")
+func (r *Repository) Diff(targetBranch string) (string, error) {
+	patch, err := r.branchPatch(targetBranch)
+	if err != nil {
+		return "", err
+	}
+	return patch.String(), nil
+}
+
+// branchPatch returns the patch of the changes returned by branchChanges.
+func (r *Repository) branchPatch(targetBranch string) (*object.Patch, error) {
+	changes, err := r.branchChanges(targetBranch)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := changes.Patch()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute the diff against %s: %w", targetBranch, err)
+	}
+	return patch, nil
+}
+
+// branchChanges returns the tree changes between the merge base of HEAD and
+// targetBranch, and HEAD. origin/<targetBranch> is preferred to the local branch.
+func (r *Repository) branchChanges(targetBranch string) (object.Changes, error) {
+	head, err := r.repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD reference: %w", err)
+	}
+	headCommit, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD commit: %w", err)
+	}
+
+	targetRef, err := r.repo.Reference(plumbing.NewRemoteReferenceName("origin", targetBranch), true)
+	if err != nil {
+		targetRef, err = r.repo.Reference(plumbing.NewBranchReferenceName(targetBranch), true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s branch reference: %w", targetBranch, err)
+		}
+	}
+	targetCommit, err := r.repo.CommitObject(targetRef.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s commit: %w", targetBranch, err)
+	}
+
+	bases, err := headCommit.MergeBase(targetCommit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find merge base with %s: %w", targetBranch, err)
+	}
+	if len(bases) == 0 {
+		return nil, fmt.Errorf("%w: %s", errNoMergeBase, targetBranch)
+	}
+
+	baseTree, err := bases[0].Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge base tree: %w", err)
+	}
+	headTree, err := headCommit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD tree: %w", err)
+	}
+	changes, err := object.DiffTree(baseTree, headTree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff against %s: %w", targetBranch, err)
+	}
+	return changes, nil
+}
+
 // GetRemoteURL returns the first URL configured for the specified remote.
 //
 // Parameters:
@@ -723,24 +1345,80 @@ func (r *Repository) GetRemoteURL(remoteName string) (string, error) {
 	return urls[0], nil
 }
 
+// Remotes identifies where a branch is pushed and where its merge request is opened.
+// They differ in a fork workflow: the branch goes to the fork ("origin") while the
+// pull request targets the upstream repository.
+type Remotes struct {
+	PushURL   string // URL of origin, the remote branches are pushed to
+	TargetURL string // URL of the remote the merge request is opened against
+	Target    string // Name of the remote the merge request is opened against ("origin" if not a fork)
+}
+
+// IsFork reports whether the merge request targets another repository than the one pushed to.
+func (r Remotes) IsFork() bool {
+	return r.PushURL != r.TargetURL
+}
+
+// ResolveRemotes returns the push remote (origin) and the target remote URLs.
+// An empty targetRemote, or "origin", targets origin itself.
+func (r *Repository) ResolveRemotes(targetRemote string) (Remotes, error) {
+	pushURL, err := r.GetRemoteURL("origin")
+	if err != nil {
+		return Remotes{}, err
+	}
+
+	remotes := Remotes{PushURL: pushURL, TargetURL: pushURL, Target: "origin"}
+	if targetRemote == "" || targetRemote == "origin" {
+		return remotes, nil
+	}
+	remotes.Target = targetRemote
+
+	remotes.TargetURL, err = r.GetRemoteURL(targetRemote)
+	if err != nil {
+		return Remotes{}, err
+	}
+	r.log.Debug(fmt.Sprintf("Fork workflow: pushing to %s, targeting %s", remotes.PushURL, remotes.TargetURL))
+	return remotes, nil
+}
+
+// RootDir returns the root directory of the repository's working tree.
+func (r *Repository) RootDir() (string, error) {
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("failed to get worktree: %w", err)
+	}
+	return worktree.Filesystem.Root(), nil
+}
+
 // GoGitRepository returns the underlying go-git Repository.
 // This is used by the commits package to retrieve commit history.
 func (r *Repository) GoGitRepository() *git.Repository {
 	return r.repo
 }
 
-// getMainBranchViaGoGit attempts to determine the main branch using go-git's remote listing.
-func (r *Repository) getMainBranchViaGoGit() (string, error) {
-	remote, err := r.repo.Remote("origin")
+// listRemoteRefs lists the references advertised by the given remote using go-git.
+func (r *Repository) listRemoteRefs(remoteName string) ([]*plumbing.Reference, error) {
+	remote, err := r.repo.Remote(remoteName)
 	if err != nil {
-		return "", fmt.Errorf("failed to get origin remote: %w", err)
+		return nil, fmt.Errorf("failed to get %s remote: %w", remoteName, err)
 	}
 
 	refs, err := remote.List(&git.ListOptions{
-		Auth: r.auth,
+		Auth:     r.auth,
+		CABundle: r.caBundle,
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to list remote references: %w", err)
+		return nil, fmt.Errorf("failed to list remote references: %w", err)
+	}
+
+	return refs, nil
+}
+
+// getMainBranchViaGoGit attempts to determine the main branch using go-git's remote listing.
+func (r *Repository) getMainBranchViaGoGit() (string, error) {
+	refs, err := r.listRemoteRefs("origin")
+	if err != nil {
+		return "", err
 	}
 
 	for _, ref := range refs {
@@ -790,17 +1468,21 @@ func (r *Repository) getMainBranchViaNativeGit() (string, error) {
 // pushBranchViaNativeGit pushes a branch using native git push.
 // This uses the system's SSH binary and agent, which handles more SSH configurations
 // than go-git's built-in SSH implementation.
-func (r *Repository) pushBranchViaNativeGit(branchName string) error {
+func (r *Repository) pushBranchViaNativeGit(branchName string, withLease bool) (PushResult, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), networkGitTimeout)
 	defer cancel()
 
+	args := []string{"push", "-u"}
+	if withLease {
+		args = append(args, "--force-with-lease")
+	}
 	// #nosec G204 - branchName comes from git, not user input
-	cmd := exec.CommandContext(ctx, "git", "push", "-u", "origin", branchName)
+	cmd := exec.CommandContext(ctx, "git", append(args, "origin", branchName)...)
 	cmd.Dir = r.gitRoot
 	output, err := cmd.CombinedOutput()
 
 	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
-		return &GitTimeoutError{
+		return "", &GitTimeoutError{
 			Operation: "push",
 			Timeout:   networkGitTimeout,
 			Err:       err,
@@ -808,11 +1490,41 @@ func (r *Repository) pushBranchViaNativeGit(branchName string) error {
 	}
 
 	if err != nil {
+		sanitized := security.SanitizeError(fmt.Errorf("failed to push branch: %w\nOutput: %s", err, string(output)))
+		if rejection := classifyPushOutput(string(output)); rejection != nil {
+			return "", fmt.Errorf("%w: %w", rejection, sanitized)
+		}
 		//nolint:wrapcheck // Error is sanitized to prevent token leakage
-		return security.SanitizeError(fmt.Errorf("failed to push branch: %w\nOutput: %s", err, string(output)))
+		return "", sanitized
+	}
+
+	result := pushResultFromOutput(string(output))
+	r.log.Debug(fmt.Sprintf("Branch pushed successfully (native git, %s): %s", result, branchName))
+	return result, nil
+}
+
+// pushResultFromOutput returns what a successful "git push" did, from its output.
+func pushResultFromOutput(output string) PushResult {
+	switch {
+	case strings.Contains(output, "Everything up-to-date"):
+		return PushUpToDate
+	case strings.Contains(output, "[new branch]"):
+		return PushCreated
+	default:
+		return PushUpdated
 	}
+}
 
-	r.log.Debug("Branch pushed successfully (native git): " + branchName)
+// classifyPushOutput returns the push rejection sentinel matching the output of
+// "git push", or nil when the failure is not a recognized rejection.
+func classifyPushOutput(output string) error {
+	for _, rejection := range pushRejections {
+		for _, fragment := range rejection.fragments {
+			if strings.Contains(output, fragment) {
+				return rejection.err
+			}
+		}
+	}
 	return nil
 }
 
@@ -1,6 +1,9 @@
 package git_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -493,3 +496,1051 @@ func TestDetectPlatform_Forgejo_EmptyURL(t *testing.T) {
 		t.Fatal("Expected unsupported-platform error, got nil")
 	}
 }
+
+// TestParsePlatform verifies the names accepted by --platform.
+func TestParsePlatform(t *testing.T) {
+	tests := map[string]git.Platform{
+		"gitlab":  git.PlatformGitLab,
+		"GitHub":  git.PlatformGitHub,
+		"forgejo": git.PlatformForgejo,
+		" gitea ": git.PlatformForgejo,
+	}
+	for name, want := range tests {
+		got, err := git.ParsePlatform(name)
+		if err != nil {
+			t.Errorf("ParsePlatform(%q): %v", name, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ParsePlatform(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	for _, name := range []string{"", "bitbucket"} {
+		if _, err := git.ParsePlatform(name); err == nil {
+			t.Errorf("ParsePlatform(%q): expected an error, got nil", name)
+		}
+	}
+}
+
+// TestGetCurrentBranch_DetachedHEAD verifies the sentinel error is returned when HEAD is detached.
+func TestGetCurrentBranch_DetachedHEAD(t *testing.T) {
+	tmpDir := t.TempDir()
+	initTestRepo(t, tmpDir)
+
+	goRepo, err := gogit.PlainOpen(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open repo: %v", err)
+	}
+	wt, err := goRepo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "README.md"), []byte("# Test\n"), 0644); err != nil {
+		t.Fatalf("Failed to write README: %v", err)
+	}
+	if _, err := wt.Add("README.md"); err != nil {
+		t.Fatalf("Failed to add README: %v", err)
+	}
+	hash, err := wt.Commit("initial commit", &gogit.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@test.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	if err := wt.Checkout(&gogit.CheckoutOptions{Hash: hash}); err != nil {
+		t.Fatalf("Failed to detach HEAD: %v", err)
+	}
+
+	repo, err := git.OpenRepository(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+
+	_, err = repo.GetCurrentBranch()
+	if !errors.Is(err, git.ErrHEADNotBranch) {
+		t.Errorf("Expected ErrHEADNotBranch, got: %v", err)
+	}
+}
+
+// TestRemoteBranchExists verifies remote branch lookup against a local bare origin.
+func TestRemoteBranchExists(t *testing.T) {
+	originDir := t.TempDir()
+	if _, err := gogit.PlainInit(originDir, true); err != nil {
+		t.Fatalf("Failed to init bare origin: %v", err)
+	}
+
+	workDir := t.TempDir()
+	goRepo, err := gogit.PlainInit(workDir, false)
+	if err != nil {
+		t.Fatalf("Failed to init repo: %v", err)
+	}
+	if _, err := goRepo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{originDir}}); err != nil {
+		t.Fatalf("Failed to create remote: %v", err)
+	}
+
+	wt, err := goRepo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(workDir, "README.md"), []byte("# Test\n"), 0644); err != nil {
+		t.Fatalf("Failed to write README: %v", err)
+	}
+	if _, err := wt.Add("README.md"); err != nil {
+		t.Fatalf("Failed to add README: %v", err)
+	}
+	if _, err := wt.Commit("initial commit", &gogit.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@test.com", When: time.Now()},
+	}); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+
+	head, err := goRepo.Head()
+	if err != nil {
+		t.Fatalf("Failed to get HEAD: %v", err)
+	}
+
+	repo, err := git.OpenRepository(workDir)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	if _, err := repo.PushBranch(head.Name().Short()); err != nil {
+		t.Fatalf("Failed to push: %v", err)
+	}
+
+	exists, err := repo.RemoteBranchExists(head.Name().Short())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !exists {
+		t.Error("Expected pushed branch to exist on remote")
+	}
+	if sha, err := repo.RemoteBranchSHA(head.Name().Short()); err != nil || sha != head.Hash().String() {
+		t.Errorf("Expected remote branch SHA %s, got %q (err: %v)", head.Hash(), sha, err)
+	}
+
+	exists, err = repo.RemoteBranchExists("never-pushed")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if exists {
+		t.Error("Expected unpushed branch to be missing on remote")
+	}
+	if sha, err := repo.RemoteBranchSHA("never-pushed"); err != nil || sha != "" {
+		t.Errorf("Expected no SHA for an unpushed branch, got %q (err: %v)", sha, err)
+	}
+}
+
+// TestPushBranchResult verifies that a push reports whether it created, updated or left the remote branch alone.
+func TestPushBranchResult(t *testing.T) {
+	originDir := t.TempDir()
+	if _, err := gogit.PlainInit(originDir, true); err != nil {
+		t.Fatalf("Failed to init bare origin: %v", err)
+	}
+
+	workDir := t.TempDir()
+	goRepo, err := gogit.PlainInit(workDir, false)
+	if err != nil {
+		t.Fatalf("Failed to init repo: %v", err)
+	}
+	if _, err := goRepo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{originDir}}); err != nil {
+		t.Fatalf("Failed to create remote: %v", err)
+	}
+	wt, err := goRepo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+
+	commitFile := func(name string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(workDir, name), []byte(name+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		if _, err := wt.Add(name); err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+		if _, err := wt.Commit("add "+name, &gogit.CommitOptions{
+			Author: &object.Signature{Name: "Test", Email: "test@test.com", When: time.Now()},
+		}); err != nil {
+			t.Fatalf("Failed to commit %s: %v", name, err)
+		}
+	}
+
+	commitFile("base.txt")
+	head, err := goRepo.Head()
+	if err != nil {
+		t.Fatalf("Failed to get HEAD: %v", err)
+	}
+	branch := head.Name().Short()
+
+	repo, err := git.OpenRepository(workDir)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+
+	push := func(want git.PushResult) {
+		t.Helper()
+		result, err := repo.PushBranch(branch)
+		if err != nil {
+			t.Fatalf("Failed to push: %v", err)
+		}
+		if result != want {
+			t.Errorf("Expected push result %q, got %q", want, result)
+		}
+	}
+
+	push(git.PushCreated)
+	push(git.PushUpToDate)
+	commitFile("next.txt")
+	push(git.PushUpdated)
+}
+
+// TestChangedFiles verifies that only the files changed on the branch since it diverged are listed and diffed.
+func TestChangedFiles(t *testing.T) {
+	workDir := t.TempDir()
+	goRepo, err := gogit.PlainInit(workDir, false)
+	if err != nil {
+		t.Fatalf("Failed to init repo: %v", err)
+	}
+	if _, err := goRepo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{t.TempDir()}}); err != nil {
+		t.Fatalf("Failed to create remote: %v", err)
+	}
+	wt, err := goRepo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+
+	commitFile := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(workDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		if _, err := wt.Add(name); err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+		if _, err := wt.Commit("update "+name, &gogit.CommitOptions{
+			Author: &object.Signature{Name: "Test", Email: "test@test.com", When: time.Now()},
+		}); err != nil {
+			t.Fatalf("Failed to commit %s: %v", name, err)
+		}
+	}
+	checkout := func(branch string, create bool) {
+		t.Helper()
+		if err := wt.Checkout(&gogit.CheckoutOptions{
+			Branch: plumbing.NewBranchReferenceName(branch),
+			Create: create,
+		}); err != nil {
+			t.Fatalf("Failed to checkout %s: %v", branch, err)
+		}
+	}
+
+	commitFile("README.md", "base\n")
+	commitFile("docs/guide.md", "base\n")
+	head, err := goRepo.Head()
+	if err != nil {
+		t.Fatalf("Failed to get HEAD: %v", err)
+	}
+	mainBranch := head.Name().Short()
+
+	checkout("feature", true)
+	commitFile("docs/guide.md", "changed\n")
+	commitFile("pkg/new.go", "package pkg\n")
+	checkout(mainBranch, false)
+	commitFile("main-only.txt", "main\n")
+	checkout("feature", false)
+
+	repo, err := git.OpenRepository(workDir)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	files, err := repo.ChangedFiles(mainBranch)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(files, ",") != "docs/guide.md,pkg/new.go" {
+		t.Errorf("Expected docs/guide.md and pkg/new.go, got %v", files)
+	}
+
+	stats, err := repo.DiffStat(mainBranch)
+	if err != nil {
+		t.Fatalf("Unexpected DiffStat error: %v", err)
+	}
+	var got []string
+	for _, stat := range stats {
+		got = append(got, fmt.Sprintf("%s+%d-%d", stat.Name, stat.Addition, stat.Deletion))
+	}
+	if strings.Join(got, ",") != "docs/guide.md+1-1,pkg/new.go+1-0" {
+		t.Errorf("Expected docs/guide.md+1-1 and pkg/new.go+1-0, got %v", got)
+	}
+
+	diff, err := repo.Diff(mainBranch)
+	if err != nil {
+		t.Fatalf("Unexpected Diff error: %v", err)
+	}
+	if !strings.Contains(diff, "-base\n+changed\n") || strings.Contains(diff, "main-only.txt") {
+		t.Errorf("Expected the branch changes only in the diff, got:\n%s", diff)
+	}
+}
+
+// TestIsBehindRemoteBranch verifies detection of a target branch that advanced after divergence.
+func TestIsBehindRemoteBranch(t *testing.T) {
+	originDir := t.TempDir()
+	if _, err := gogit.PlainInit(originDir, true); err != nil {
+		t.Fatalf("Failed to init bare origin: %v", err)
+	}
+
+	workDir := t.TempDir()
+	goRepo, err := gogit.PlainInit(workDir, false)
+	if err != nil {
+		t.Fatalf("Failed to init repo: %v", err)
+	}
+	if _, err := goRepo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{originDir}}); err != nil {
+		t.Fatalf("Failed to create remote: %v", err)
+	}
+	wt, err := goRepo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+
+	commitFile := func(name string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(workDir, name), []byte(name+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		if _, err := wt.Add(name); err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+		if _, err := wt.Commit("add "+name, &gogit.CommitOptions{
+			Author: &object.Signature{Name: "Test", Email: "test@test.com", When: time.Now()},
+		}); err != nil {
+			t.Fatalf("Failed to commit %s: %v", name, err)
+		}
+	}
+	checkout := func(branch string, create bool) {
+		t.Helper()
+		if err := wt.Checkout(&gogit.CheckoutOptions{
+			Branch: plumbing.NewBranchReferenceName(branch),
+			Create: create,
+		}); err != nil {
+			t.Fatalf("Failed to checkout %s: %v", branch, err)
+		}
+	}
+
+	commitFile("base.txt")
+	head, err := goRepo.Head()
+	if err != nil {
+		t.Fatalf("Failed to get HEAD: %v", err)
+	}
+	mainBranch := head.Name().Short()
+
+	repo, err := git.OpenRepository(workDir)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	if _, err := repo.PushBranch(mainBranch); err != nil {
+		t.Fatalf("Failed to push %s: %v", mainBranch, err)
+	}
+
+	checkout("feature", true)
+	commitFile("feature.txt")
+
+	behind, err := repo.IsBehindRemoteBranch("origin", mainBranch)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if behind {
+		t.Error("Expected feature branch to be up to date with target")
+	}
+
+	checkout(mainBranch, false)
+	commitFile("main-only.txt")
+	if _, err := repo.PushBranch(mainBranch); err != nil {
+		t.Fatalf("Failed to push %s: %v", mainBranch, err)
+	}
+	checkout("feature", false)
+
+	behind, err = repo.IsBehindRemoteBranch("origin", mainBranch)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !behind {
+		t.Error("Expected target branch to be detected as advanced")
+	}
+
+	// A fork targets another remote, whose branch is compared instead of origin's
+	upstreamDir := t.TempDir()
+	if _, err := gogit.PlainInit(upstreamDir, true); err != nil {
+		t.Fatalf("Failed to init bare upstream: %v", err)
+	}
+	if _, err := goRepo.CreateRemote(&config.RemoteConfig{Name: "upstream", URLs: []string{upstreamDir}}); err != nil {
+		t.Fatalf("Failed to create remote upstream: %v", err)
+	}
+	if err := goRepo.Push(&gogit.PushOptions{
+		RemoteName: "upstream",
+		RefSpecs:   []config.RefSpec{config.RefSpec("refs/heads/feature:refs/heads/" + mainBranch)},
+	}); err != nil {
+		t.Fatalf("Failed to push to upstream: %v", err)
+	}
+
+	behind, err = repo.IsBehindRemoteBranch("upstream", mainBranch)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if behind {
+		t.Error("Expected feature branch to be up to date with the upstream target")
+	}
+}
+
+// TestResolveRemotes verifies push/target remote resolution for fork workflows.
+func TestResolveRemotes(t *testing.T) {
+	tmpDir := t.TempDir()
+	initTestRepoWithRemote(t, tmpDir, "https://github.com/me/repo.git")
+
+	goRepo, err := gogit.PlainOpen(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	if _, err := goRepo.CreateRemote(&config.RemoteConfig{
+		Name: "upstream",
+		URLs: []string{"https://github.com/owner/repo.git"},
+	}); err != nil {
+		t.Fatalf("Failed to create remote upstream: %v", err)
+	}
+
+	repo, err := git.OpenRepository(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		targetRemote string
+		expectTarget string
+		expectName   string
+		expectFork   bool
+		expectErr    bool
+	}{
+		{"default targets origin", "", "https://github.com/me/repo.git", "origin", false, false},
+		{"explicit origin", "origin", "https://github.com/me/repo.git", "origin", false, false},
+		{"upstream", "upstream", "https://github.com/owner/repo.git", "upstream", true, false},
+		{"unknown remote", "missing", "", "", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remotes, err := repo.ResolveRemotes(tt.targetRemote)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatal("Expected error for unknown remote")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if remotes.PushURL != "https://github.com/me/repo.git" {
+				t.Errorf("PushURL = %q", remotes.PushURL)
+			}
+			if remotes.TargetURL != tt.expectTarget {
+				t.Errorf("TargetURL = %q, want %q", remotes.TargetURL, tt.expectTarget)
+			}
+			if remotes.Target != tt.expectName {
+				t.Errorf("Target = %q, want %q", remotes.Target, tt.expectName)
+			}
+			if remotes.IsFork() != tt.expectFork {
+				t.Errorf("IsFork() = %v, want %v", remotes.IsFork(), tt.expectFork)
+			}
+		})
+	}
+}
+
+// TestCommitStaged verifies that staged changes are committed with the configured identity.
+func TestCommitStaged(t *testing.T) {
+	tmpDir := t.TempDir()
+	initTestRepo(t, tmpDir)
+
+	goRepo, err := gogit.PlainOpen(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	cfg, err := goRepo.Config()
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	cfg.User.Name = "Jane Doe"
+	cfg.User.Email = "jane@example.com"
+	if err := goRepo.SetConfig(cfg); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	repo, err := git.OpenRepository(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+
+	if _, err := repo.CommitStaged("feat: nothing"); !errors.Is(err, git.ErrNothingStaged) {
+		t.Fatalf("Expected ErrNothingStaged, got: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "file.txt"), []byte("content"), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	worktree, err := goRepo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+	if _, err := worktree.Add("file.txt"); err != nil {
+		t.Fatalf("Failed to stage file: %v", err)
+	}
+
+	hash, err := repo.CommitStaged("feat: add file")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	commit, err := goRepo.CommitObject(plumbing.NewHash(hash))
+	if err != nil {
+		t.Fatalf("Failed to read commit: %v", err)
+	}
+	if commit.Message != "feat: add file" {
+		t.Errorf("Message = %q", commit.Message)
+	}
+	if commit.Author.Name != "Jane Doe" || commit.Author.Email != "jane@example.com" {
+		t.Errorf("Author = %s <%s>", commit.Author.Name, commit.Author.Email)
+	}
+}
+
+// TestGetMainBranch_Unusual verifies the configured main branch override and the
+// error listing origin branches when detection fails.
+func TestGetMainBranch_Unusual(t *testing.T) {
+	tmpDir := t.TempDir()
+	goRepo, err := gogit.PlainInitWithOptions(tmpDir, &gogit.PlainInitOptions{
+		InitOptions: gogit.InitOptions{DefaultBranch: plumbing.NewBranchReferenceName("trunk")},
+	})
+	if err != nil {
+		t.Fatalf("Failed to initialize git repository: %v", err)
+	}
+	// An unreachable origin makes remote HEAD detection fail without network access.
+	_, err = goRepo.CreateRemote(&config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{filepath.Join(t.TempDir(), "missing.git")},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create remote origin: %v", err)
+	}
+
+	worktree, err := goRepo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+	hash, err := worktree.Commit("initial", &gogit.CommitOptions{
+		AllowEmptyCommits: true,
+		Author:            &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	for _, branch := range []string{"trunk", "develop"} {
+		ref := plumbing.NewHashReference(plumbing.NewRemoteReferenceName("origin", branch), hash)
+		if err := goRepo.Storer.SetReference(ref); err != nil {
+			t.Fatalf("Failed to create remote-tracking branch: %v", err)
+		}
+	}
+
+	repo, err := git.OpenRepository(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+
+	_, err = repo.GetMainBranch()
+	if !errors.Is(err, git.ErrMainBranchNotFound) {
+		t.Fatalf("Expected ErrMainBranchNotFound, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "develop, trunk") {
+		t.Errorf("Expected origin branches in error, got: %v", err)
+	}
+
+	repo.SetMainBranch("trunk")
+	branch, err := repo.GetMainBranch()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if branch != "trunk" {
+		t.Errorf("Expected trunk, got %q", branch)
+	}
+}
+
+// TestPushBranchRejections verifies that push rejections are reported with a specific sentinel.
+func TestPushBranchRejections(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not available")
+	}
+
+	setup := func(t *testing.T) (string, string, *gogit.Repository) {
+		t.Helper()
+		originDir := t.TempDir()
+		if _, err := gogit.PlainInit(originDir, true); err != nil {
+			t.Fatalf("Failed to init bare origin: %v", err)
+		}
+		workDir := t.TempDir()
+		goRepo, err := gogit.PlainInit(workDir, false)
+		if err != nil {
+			t.Fatalf("Failed to init repo: %v", err)
+		}
+		if _, err := goRepo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{originDir}}); err != nil {
+			t.Fatalf("Failed to create remote: %v", err)
+		}
+		return originDir, workDir, goRepo
+	}
+	commitFile := func(t *testing.T, workDir string, goRepo *gogit.Repository, name string) {
+		t.Helper()
+		wt, err := goRepo.Worktree()
+		if err != nil {
+			t.Fatalf("Failed to get worktree: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(workDir, name), []byte(name+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		if _, err := wt.Add(name); err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+		if _, err := wt.Commit("add "+name, &gogit.CommitOptions{
+			Author: &object.Signature{Name: "Test", Email: "test@test.com", When: time.Now()},
+		}); err != nil {
+			t.Fatalf("Failed to commit %s: %v", name, err)
+		}
+	}
+
+	t.Run("protected branch", func(t *testing.T) {
+		originDir, workDir, goRepo := setup(t)
+		hook := "#!/bin/sh\necho 'GitLab: You are not allowed to push code to protected branches on this project.' >&2\nexit 1\n"
+		if err := os.MkdirAll(filepath.Join(originDir, "hooks"), 0755); err != nil {
+			t.Fatalf("Failed to create hooks dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(originDir, "hooks", "pre-receive"), []byte(hook), 0755); err != nil {
+			t.Fatalf("Failed to write hook: %v", err)
+		}
+		commitFile(t, workDir, goRepo, "a.txt")
+		head, err := goRepo.Head()
+		if err != nil {
+			t.Fatalf("Failed to get HEAD: %v", err)
+		}
+
+		repo, err := git.OpenRepository(workDir)
+		if err != nil {
+			t.Fatalf("Failed to open repository: %v", err)
+		}
+		_, err = repo.PushBranch(head.Name().Short())
+		if !errors.Is(err, git.ErrPushProtected) {
+			t.Errorf("Expected ErrPushProtected, got %v", err)
+		}
+	})
+
+	t.Run("non-fast-forward", func(t *testing.T) {
+		originDir, workDir, goRepo := setup(t)
+		commitFile(t, workDir, goRepo, "a.txt")
+		head, err := goRepo.Head()
+		if err != nil {
+			t.Fatalf("Failed to get HEAD: %v", err)
+		}
+		branch := head.Name().Short()
+
+		repo, err := git.OpenRepository(workDir)
+		if err != nil {
+			t.Fatalf("Failed to open repository: %v", err)
+		}
+		if _, err := repo.PushBranch(branch); err != nil {
+			t.Fatalf("Failed to push: %v", err)
+		}
+
+		// Advance the remote from a second clone so the local branch falls behind.
+		otherDir := t.TempDir()
+		other, err := gogit.PlainClone(otherDir, false, &gogit.CloneOptions{URL: originDir})
+		if err != nil {
+			t.Fatalf("Failed to clone: %v", err)
+		}
+		commitFile(t, otherDir, other, "b.txt")
+		if err := other.Push(&gogit.PushOptions{}); err != nil {
+			t.Fatalf("Failed to push from clone: %v", err)
+		}
+
+		commitFile(t, workDir, goRepo, "c.txt")
+		_, err = repo.PushBranch(branch)
+		if !errors.Is(err, git.ErrPushNonFastForward) {
+			t.Errorf("Expected ErrPushNonFastForward, got %v", err)
+		}
+	})
+
+	t.Run("force with lease", func(t *testing.T) {
+		originDir, workDir, goRepo := setup(t)
+		commitFile(t, workDir, goRepo, "a.txt")
+		base, err := goRepo.Head()
+		if err != nil {
+			t.Fatalf("Failed to get HEAD: %v", err)
+		}
+		branch := base.Name().Short()
+		commitFile(t, workDir, goRepo, "b.txt")
+
+		repo, err := git.OpenRepository(workDir)
+		if err != nil {
+			t.Fatalf("Failed to open repository: %v", err)
+		}
+		if _, err := repo.PushBranch(branch); err != nil {
+			t.Fatalf("Failed to push: %v", err)
+		}
+
+		// Rewrite the pushed history, as a rebase would.
+		rewrite := func(name string) plumbing.Hash {
+			t.Helper()
+			wt, err := goRepo.Worktree()
+			if err != nil {
+				t.Fatalf("Failed to get worktree: %v", err)
+			}
+			if err := wt.Reset(&gogit.ResetOptions{Commit: base.Hash(), Mode: gogit.HardReset}); err != nil {
+				t.Fatalf("Failed to reset: %v", err)
+			}
+			commitFile(t, workDir, goRepo, name)
+			head, err := goRepo.Head()
+			if err != nil {
+				t.Fatalf("Failed to get HEAD: %v", err)
+			}
+			return head.Hash()
+		}
+		rewritten := rewrite("c.txt")
+
+		if _, err := repo.PushBranch(branch); !errors.Is(err, git.ErrPushNonFastForward) {
+			t.Fatalf("Expected ErrPushNonFastForward without lease, got %v", err)
+		}
+		if _, err := repo.PushBranchWithLease(branch); err != nil {
+			t.Fatalf("PushBranchWithLease: %v", err)
+		}
+		origin, err := gogit.PlainOpen(originDir)
+		if err != nil {
+			t.Fatalf("Failed to open origin: %v", err)
+		}
+		ref, err := origin.Reference(plumbing.NewBranchReferenceName(branch), true)
+		if err != nil {
+			t.Fatalf("Failed to read origin branch: %v", err)
+		}
+		if ref.Hash() != rewritten {
+			t.Errorf("origin/%s = %s, want %s", branch, ref.Hash(), rewritten)
+		}
+
+		// Someone else pushes in the meantime: the lease must not overwrite their commit.
+		otherDir := t.TempDir()
+		other, err := gogit.PlainClone(otherDir, false, &gogit.CloneOptions{URL: originDir})
+		if err != nil {
+			t.Fatalf("Failed to clone: %v", err)
+		}
+		commitFile(t, otherDir, other, "d.txt")
+		if err := other.Push(&gogit.PushOptions{}); err != nil {
+			t.Fatalf("Failed to push from clone: %v", err)
+		}
+
+		rewrite("e.txt")
+		if _, err := repo.PushBranchWithLease(branch); !errors.Is(err, git.ErrPushStaleLease) {
+			t.Errorf("Expected ErrPushStaleLease, got %v", err)
+		}
+	})
+}
+
+// TestCommitStaged_Signed verifies that commit.gpgsign produces a signed commit and
+// that UnsignedCommits reports only the branch commits without a signature.
+func TestCommitStaged_Signed(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not available")
+	}
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not available")
+	}
+
+	tmpDir := t.TempDir()
+	initTestRepo(t, tmpDir)
+
+	gitCmd := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmpDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+	commitFile := func(repo *git.Repository, name string) string {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(name), 0o600); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		gitCmd("add", name)
+		hash, err := repo.CommitStaged("feat: add " + name)
+		if err != nil {
+			t.Fatalf("CommitStaged: %v", err)
+		}
+		return hash
+	}
+
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	if out, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", keyPath).CombinedOutput(); err != nil {
+		t.Fatalf("ssh-keygen: %v\n%s", err, out)
+	}
+	gitCmd("config", "user.name", "Jane Doe")
+	gitCmd("config", "user.email", "jane@example.com")
+	gitCmd("config", "gpg.format", "ssh")
+	gitCmd("config", "user.signingkey", keyPath)
+	gitCmd("config", "commit.gpgsign", "false")
+
+	repo, err := git.OpenRepository(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	if repo.SigningEnabled() {
+		t.Fatal("SigningEnabled() = true with commit.gpgsign=false")
+	}
+
+	commitFile(repo, "base.txt")
+	gitCmd("branch", "main")
+	unsignedHash := commitFile(repo, "unsigned.txt")
+
+	gitCmd("config", "commit.gpgsign", "true")
+	if !repo.SigningEnabled() {
+		t.Fatal("SigningEnabled() = false with commit.gpgsign=true")
+	}
+	signedHash := commitFile(repo, "signed.txt")
+
+	goRepo, err := gogit.PlainOpen(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	commit, err := goRepo.CommitObject(plumbing.NewHash(signedHash))
+	if err != nil {
+		t.Fatalf("Failed to read commit: %v", err)
+	}
+	if !strings.Contains(commit.PGPSignature, "BEGIN SSH SIGNATURE") {
+		t.Errorf("PGPSignature = %q, want an SSH signature", commit.PGPSignature)
+	}
+	if commit.Message != "feat: add signed.txt" {
+		t.Errorf("Message = %q", commit.Message)
+	}
+
+	unsigned, err := repo.UnsignedCommits("main")
+	if err != nil {
+		t.Fatalf("UnsignedCommits: %v", err)
+	}
+	if len(unsigned) != 1 || unsigned[0] != unsignedHash[:7] {
+		t.Errorf("UnsignedCommits = %v, want [%s]", unsigned, unsignedHash[:7])
+	}
+}
+
+// TestFastForwardBranch verifies that the local target branch is fast-forwarded without
+// being checked out, and left alone when it has diverged.
+func TestFastForwardBranch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not available")
+	}
+
+	originDir := t.TempDir()
+	if _, err := gogit.PlainInit(originDir, true); err != nil {
+		t.Fatalf("Failed to init bare origin: %v", err)
+	}
+	workDir := t.TempDir()
+	goRepo, err := gogit.PlainInit(workDir, false)
+	if err != nil {
+		t.Fatalf("Failed to init repo: %v", err)
+	}
+	if _, err := goRepo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{originDir}}); err != nil {
+		t.Fatalf("Failed to create remote: %v", err)
+	}
+	wt, err := goRepo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+	commitFile := func(name string) plumbing.Hash {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(workDir, name), []byte(name+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		if _, err := wt.Add(name); err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+		hash, err := wt.Commit("add "+name, &gogit.CommitOptions{
+			Author: &object.Signature{Name: "Test", Email: "test@test.com", When: time.Now()},
+		})
+		if err != nil {
+			t.Fatalf("Failed to commit %s: %v", name, err)
+		}
+		return hash
+	}
+	checkout := func(branch string, create bool) {
+		t.Helper()
+		if err := wt.Checkout(&gogit.CheckoutOptions{
+			Branch: plumbing.NewBranchReferenceName(branch),
+			Create: create,
+		}); err != nil {
+			t.Fatalf("Failed to checkout %s: %v", branch, err)
+		}
+	}
+	branchHash := func(branch string) plumbing.Hash {
+		t.Helper()
+		ref, err := goRepo.Reference(plumbing.NewBranchReferenceName(branch), true)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", branch, err)
+		}
+		return ref.Hash()
+	}
+
+	base := commitFile("base.txt")
+	head, err := goRepo.Head()
+	if err != nil {
+		t.Fatalf("Failed to get HEAD: %v", err)
+	}
+	mainBranch := head.Name().Short()
+	repo, err := git.OpenRepository(workDir)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+
+	// Advance origin's main branch, then reset the local one to the previous commit
+	advanced := commitFile("main-only.txt")
+	if _, err := repo.PushBranch(mainBranch); err != nil {
+		t.Fatalf("Failed to push %s: %v", mainBranch, err)
+	}
+	if err := wt.Reset(&gogit.ResetOptions{Commit: base, Mode: gogit.HardReset}); err != nil {
+		t.Fatalf("Failed to reset: %v", err)
+	}
+	checkout("feature", true)
+	commitFile("feature.txt")
+
+	updated, err := repo.FastForwardBranch(context.Background(), mainBranch)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !updated || branchHash(mainBranch) != advanced {
+		t.Errorf("Expected %s fast-forwarded to %s, got updated=%v at %s",
+			mainBranch, advanced, updated, branchHash(mainBranch))
+	}
+
+	updated, err = repo.FastForwardBranch(context.Background(), mainBranch)
+	if err != nil || updated {
+		t.Errorf("Expected an up-to-date branch to be left as is, got updated=%v err=%v", updated, err)
+	}
+
+	checkout(mainBranch, false)
+	diverged := commitFile("local-only.txt")
+	checkout("feature", false)
+	_, err = repo.FastForwardBranch(context.Background(), mainBranch)
+	if !errors.Is(err, git.ErrNotFastForward) {
+		t.Errorf("Expected ErrNotFastForward, got %v", err)
+	}
+	if branchHash(mainBranch) != diverged {
+		t.Error("Expected the diverged branch to be left as is")
+	}
+
+	updated, err = repo.FastForwardBranch(context.Background(), "missing")
+	if err != nil || updated {
+		t.Errorf("Expected a missing branch to be skipped, got updated=%v err=%v", updated, err)
+	}
+}
+
+// TestStaleBranches verifies that local branches whose upstream is gone or that are
+// merged into main are listed, and that main, the current branch, branches without
+// commits of their own and unmerged branches are not.
+func TestStaleBranches(t *testing.T) {
+	workDir := t.TempDir()
+	goRepo, err := gogit.PlainInit(workDir, false)
+	if err != nil {
+		t.Fatalf("Failed to init repo: %v", err)
+	}
+	if _, err := goRepo.CreateRemote(&config.RemoteConfig{
+		Name: "origin", URLs: []string{"https://github.com/test/test.git"},
+	}); err != nil {
+		t.Fatalf("Failed to create remote: %v", err)
+	}
+	wt, err := goRepo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+	commitFile := func(name string) plumbing.Hash {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(workDir, name), []byte(name+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		if _, err := wt.Add(name); err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+		hash, err := wt.Commit("add "+name, &gogit.CommitOptions{
+			Author: &object.Signature{Name: "Test", Email: "test@test.com", When: time.Now()},
+		})
+		if err != nil {
+			t.Fatalf("Failed to commit %s: %v", name, err)
+		}
+		return hash
+	}
+	checkout := func(branch string, create bool) {
+		t.Helper()
+		if err := wt.Checkout(&gogit.CheckoutOptions{
+			Branch: plumbing.NewBranchReferenceName(branch),
+			Create: create,
+		}); err != nil {
+			t.Fatalf("Failed to checkout %s: %v", branch, err)
+		}
+	}
+
+	commitFile("base.txt")
+	head, err := goRepo.Head()
+	if err != nil {
+		t.Fatalf("Failed to get HEAD: %v", err)
+	}
+	mainBranch := head.Name().Short()
+
+	checkout("merged", true) // behind main once main advances
+	checkout(mainBranch, false)
+	commitFile("main.txt")
+	checkout("fresh", true) // at main's tip, no commits of its own
+	checkout("gone", true)
+	commitFile("gone.txt")
+	checkout("tracked", true)
+	tracked := commitFile("tracked.txt")
+	checkout("unmerged", true)
+	commitFile("unmerged.txt")
+	checkout("current", true)
+
+	cfg, err := goRepo.Config()
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	for _, name := range []string{"gone", "tracked"} {
+		cfg.Branches[name] = &config.Branch{Name: name, Remote: "origin", Merge: plumbing.NewBranchReferenceName(name)}
+	}
+	if err := goRepo.SetConfig(cfg); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if err := goRepo.Storer.SetReference(plumbing.NewHashReference(
+		plumbing.NewRemoteReferenceName("origin", "tracked"), tracked)); err != nil {
+		t.Fatalf("Failed to create tracking branch: %v", err)
+	}
+
+	repo, err := git.OpenRepository(workDir)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	stale, err := repo.StaleBranches(mainBranch)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []git.StaleBranch{
+		{Name: "gone", Reason: git.StaleUpstreamGone},
+		{Name: "merged", Reason: git.StaleMerged},
+	}
+	if len(stale) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, stale)
+	}
+	for i := range expected {
+		if stale[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], stale[i])
+		}
+	}
+}
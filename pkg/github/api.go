@@ -2,10 +2,16 @@ package github
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/go-github/v69/github"
+	"github.com/sgaunet/auto-mr/internal/timeutil"
 	"github.com/sgaunet/auto-mr/internal/urlutil"
 )
 
@@ -21,24 +27,18 @@ func (c *Client) SetRepositoryFromURL(url string) error {
 	// Supports both HTTPS and SSH formats:
 	// - https://github.com/owner/repo.git
 	// - git@github.com:owner/repo.git
-	url = strings.TrimSuffix(url, ".git")
-
-	ownerRepo := urlutil.ExtractPathComponents(url, minURLParts)
-	if ownerRepo == "" {
-		return errInvalidURLFormat
-	}
-
-	parts := strings.Split(ownerRepo, "/")
-	if len(parts) != minURLParts {
-		return errInvalidURLFormat
+	owner, repo, err := parseOwnerRepo(url)
+	if err != nil {
+		return err
 	}
-
-	c.owner = parts[0]
-	c.repo = parts[1]
+	c.owner = owner
+	c.repo = repo
 
 	c.log.Debug(fmt.Sprintf("Setting GitHub repository: %s/%s", c.owner, c.repo))
 	// Validate repository exists
-	_, _, err := c.client.Repositories.Get(c.ctx(), c.owner, c.repo)
+	ctx, cancel := c.ctx()
+	defer cancel()
+	_, _, err = c.client.Repositories.Get(ctx, c.owner, c.repo)
 	if err != nil {
 		return fmt.Errorf("failed to get repository information: %w", err)
 	}
@@ -47,31 +47,120 @@ func (c *Client) SetRepositoryFromURL(url string) error {
 	return nil
 }
 
+// SetHeadRepositoryFromURL sets the repository pull request branches live in,
+// when it differs from the target repository (fork workflow). Pull requests are
+// then opened with an "owner:branch" head and branches are deleted from that repository.
+//
+// Returns [ErrInvalidURLFormat] if the URL cannot be parsed into owner/repo.
+func (c *Client) SetHeadRepositoryFromURL(url string) error {
+	owner, repo, err := parseOwnerRepo(url)
+	if err != nil {
+		return err
+	}
+	c.headOwner = owner
+	c.headRepo = repo
+
+	c.log.Debug(fmt.Sprintf("Setting GitHub head repository: %s/%s", c.headOwner, c.headRepo))
+	return nil
+}
+
+// parseOwnerRepo extracts owner and repository names from an HTTPS or SSH remote URL,
+// with or without a ".git" suffix or trailing slash (see [urlutil.PathSegments]).
+func parseOwnerRepo(url string) (string, string, error) {
+	segments := urlutil.PathSegments(url)
+	if len(segments) < minURLParts {
+		return "", "", errInvalidURLFormat
+	}
+	ownerRepo := segments[len(segments)-minURLParts:]
+	return ownerRepo[0], ownerRepo[1], nil
+}
+
+// headRepository returns the owner and name of the repository holding PR branches.
+func (c *Client) headRepository() (string, string) {
+	if c.headOwner == "" {
+		return c.owner, c.repo
+	}
+	return c.headOwner, c.headRepo
+}
+
+// head returns the head reference for creating a pull request from branch,
+// prefixed with the fork owner when the branch lives in another repository.
+func (c *Client) head(branch string) string {
+	owner, name := c.splitHead(branch)
+	if owner != c.owner {
+		return owner + ":" + name
+	}
+	return name
+}
+
+// qualifiedHead returns "owner:branch", the head filter used to list pull requests.
+func (c *Client) qualifiedHead(branch string) string {
+	owner, name := c.splitHead(branch)
+	return owner + ":" + name
+}
+
+// splitHead returns the owner and branch name of a head given as "owner:branch",
+// which names the fork explicitly. A plain branch name belongs to the owner of the
+// head repository (see [Client.SetHeadRepositoryFromURL]). Git branch names
+// cannot contain ':', so the two forms are unambiguous.
+func (c *Client) splitHead(head string) (string, string) {
+	if owner, branch, ok := strings.Cut(head, ":"); ok && owner != "" {
+		return owner, branch
+	}
+	owner, _ := c.headRepository()
+	return owner, head
+}
+
 // ListLabels returns all labels for the repository.
 // [Client.SetRepositoryFromURL] must be called before this method.
 //
 // Returns an empty slice if no labels are configured.
 func (c *Client) ListLabels() ([]*Label, error) {
 	c.log.Debug("Listing GitHub labels")
-	labels, _, err := c.client.Issues.ListLabels(c.ctx(), c.owner, c.repo, nil)
+	ctx, cancel := c.ctx()
+	defer cancel()
+	labels, _, err := c.client.Issues.ListLabels(ctx, c.owner, c.repo, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list labels: %w", err)
 	}
 
 	result := make([]*Label, len(labels))
 	for i, label := range labels {
-		result[i] = &Label{Name: *label.Name}
+		result[i] = &Label{Name: label.GetName(), Color: label.GetColor(), Description: label.GetDescription()}
+		if result[i].Color != "" && !strings.HasPrefix(result[i].Color, "#") {
+			result[i].Color = "#" + result[i].Color
+		}
 	}
 
 	c.log.Debug(fmt.Sprintf("Labels retrieved, count: %d", len(labels)))
 	return result, nil
 }
 
+// ListCollaboratorLogins returns the logins of the repository collaborators (first 100).
+func (c *Client) ListCollaboratorLogins() ([]string, error) {
+	c.log.Debug("Listing GitHub collaborators")
+
+	ctx, cancel := c.ctx()
+	defer cancel()
+	users, _, err := c.client.Repositories.ListCollaborators(ctx, c.owner, c.repo, &github.ListCollaboratorsOptions{
+		ListOptions: github.ListOptions{PerPage: maxCollaborators},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collaborators: %w", err)
+	}
+
+	logins := make([]string, len(users))
+	for i, user := range users {
+		logins[i] = user.GetLogin()
+	}
+	return logins, nil
+}
+
 // CreatePullRequest creates a new pull request with assignees, reviewers, and labels.
 // Reviewers that match the PR author are automatically filtered out.
 //
 // Parameters:
-//   - head: the source branch name
+//   - head: the source branch name, or "owner:branch" for a branch of another owner's fork
 //   - base: the target branch (e.g., "main")
 //   - title: PR title (must not be empty)
 //   - body: PR description
@@ -79,7 +168,14 @@ func (c *Client) ListLabels() ([]*Label, error) {
 //   - reviewers: GitHub usernames to request review from (may be nil)
 //   - labels: label names to apply (may be nil)
 //
+// GitHub does not take assignees and labels when creating a pull request: they are set
+// right after in a single call, then reviewers are requested.
+//
+// Returns [ErrAssigneeNotFound] or [ErrReviewerNotFound], before creating anything,
+// if an assignee or reviewer is not a GitHub user.
 // Returns [ErrPRAlreadyExists] if a PR already exists for the same branches.
+// Returns [ErrPRIncomplete], with the pull request URL, if it was created but setting its
+// assignees, labels or reviewers failed: it is then left open as is.
 // Stores the PR number and SHA internally for use by [Client.WaitForWorkflows].
 func (c *Client) CreatePullRequest(
 	head, base, title, body string,
@@ -87,14 +183,24 @@ func (c *Client) CreatePullRequest(
 ) (*github.PullRequest, error) {
 	c.log.Debug(fmt.Sprintf("Creating pull request from %s to %s", head, base))
 
+	// Check users first, so that a typo does not leave a half-configured pull request.
+	if err := c.checkUsersExist(assignees, errAssigneeNotFound); err != nil {
+		return nil, err
+	}
+	if err := c.checkUsersExist(reviewers, errReviewerNotFound); err != nil {
+		return nil, err
+	}
+
 	newPR := &github.NewPullRequest{
 		Title: new(title),
-		Head:  new(head),
+		Head:  new(c.head(head)),
 		Base:  new(base),
 		Body:  new(body),
 	}
 
-	pr, _, err := c.client.PullRequests.Create(c.ctx(), c.owner, c.repo, newPR)
+	ctx, cancel := c.ctx()
+	pr, _, err := c.client.PullRequests.Create(ctx, c.owner, c.repo, newPR)
+	cancel()
 	if err != nil {
 		// Check if error indicates PR already exists
 		errMsg := strings.ToLower(err.Error())
@@ -105,26 +211,14 @@ func (c *Client) CreatePullRequest(
 		return nil, fmt.Errorf("failed to create pull request: %w", err)
 	}
 
-	// Add assignees if provided
-	if len(assignees) > 0 {
-		_, _, err = c.client.Issues.AddAssignees(c.ctx(), c.owner, c.repo, *pr.Number, assignees)
-		if err != nil {
-			return nil, fmt.Errorf("failed to add assignees: %w", err)
-		}
+	if err := c.setAssigneesAndLabels(*pr.Number, assignees, labels); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", errPRIncomplete, pr.GetHTMLURL(), err)
 	}
 
 	// Add reviewers if provided (filter out PR author)
 	if len(reviewers) > 0 {
-		if err := c.addReviewers(pr, reviewers); err != nil {
-			return nil, err
-		}
-	}
-
-	// Add labels if provided
-	if len(labels) > 0 {
-		_, _, err = c.client.Issues.AddLabelsToIssue(c.ctx(), c.owner, c.repo, *pr.Number, labels)
-		if err != nil {
-			return nil, fmt.Errorf("failed to add labels: %w", err)
+		if err := c.addReviewers(pr, reviewers, nil); err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", errPRIncomplete, pr.GetHTMLURL(), err)
 		}
 	}
 
@@ -134,15 +228,44 @@ func (c *Client) CreatePullRequest(
 	return pr, nil
 }
 
+// setAssigneesAndLabels sets the assignees and labels of a new pull request in one
+// call; pull requests share them with their issue.
+func (c *Client) setAssigneesAndLabels(prNumber int, assignees, labels []string) error {
+	if len(assignees) == 0 && len(labels) == 0 {
+		return nil
+	}
+	request := &github.IssueRequest{}
+	if len(assignees) > 0 {
+		request.Assignees = &assignees
+	}
+	if len(labels) > 0 {
+		request.Labels = &labels
+	}
+
+	ctx, cancel := c.ctx()
+	defer cancel()
+	if _, _, err := c.client.Issues.Edit(ctx, c.owner, c.repo, prNumber, request); err != nil {
+		return fmt.Errorf("failed to set assignees and labels: %w", err)
+	}
+	return nil
+}
+
 // GetPullRequestByBranch fetches an existing open pull request by head and base branches.
-// Only the first matching PR is returned. Stores the PR number and SHA internally.
+// The head may be "owner:branch" to look up a pull request from another owner's fork.
+// When several match (e.g. opened by concurrent runs), the most recently created one is
+// returned and the numbers of the others are kept for [Client.DuplicatePullRequests].
+// Stores the PR number and SHA internally.
 //
 // Returns [ErrPRNotFound] if no open PR matches the given branches.
 func (c *Client) GetPullRequestByBranch(head, base string) (*github.PullRequest, error) {
-	prs, _, err := c.client.PullRequests.List(c.ctx(), c.owner, c.repo, &github.PullRequestListOptions{
-		State: "open",
-		Head:  fmt.Sprintf("%s:%s", c.owner, head),
-		Base:  base,
+	ctx, cancel := c.ctx()
+	defer cancel()
+	prs, _, err := c.client.PullRequests.List(ctx, c.owner, c.repo, &github.PullRequestListOptions{
+		State:     "open",
+		Head:      c.qualifiedHead(head),
+		Base:      base,
+		Sort:      "created",
+		Direction: "desc",
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list pull requests: %w", err)
@@ -151,6 +274,10 @@ func (c *Client) GetPullRequestByBranch(head, base string) (*github.PullRequest,
 	if len(prs) == 0 {
 		return nil, fmt.Errorf("%w: %s", errPRNotFound, head)
 	}
+	c.duplicates = nil
+	for _, duplicate := range prs[1:] {
+		c.duplicates = append(c.duplicates, duplicate.GetNumber())
+	}
 
 	pr := prs[0]
 	c.prNumber = *pr.Number
@@ -158,33 +285,361 @@ func (c *Client) GetPullRequestByBranch(head, base string) (*github.PullRequest,
 	return pr, nil
 }
 
+// DuplicatePullRequests returns the numbers of the open pull requests that the last
+// [Client.GetPullRequestByBranch] found besides the one it returned, newest first.
+func (c *Client) DuplicatePullRequests() []int {
+	return c.duplicates
+}
+
+// GetMergedPullRequest fetches a merged pull request by head and base branches
+// whose head commit is sha, i.e. the merge of exactly that branch state.
+//
+// Returns [ErrPRNotFound] if no merged PR matches.
+func (c *Client) GetMergedPullRequest(head, base, sha string) (*github.PullRequest, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+	prs, _, err := c.client.PullRequests.List(ctx, c.owner, c.repo, &github.PullRequestListOptions{
+		State: "closed",
+		Head:  c.qualifiedHead(head),
+		Base:  base,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+
+	for _, pr := range prs {
+		if pr.MergedAt != nil && pr.GetHead().GetSHA() == sha {
+			return pr, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: merged %s at %s", errPRNotFound, head, sha)
+}
+
+// AllowedMergeMethods returns the merge methods enabled in the repository settings,
+// among "merge", "squash" and "rebase" (see [Client.MergePullRequest]). Merging with
+// another one fails with "405 Method Not Allowed".
+func (c *Client) AllowedMergeMethods() ([]string, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+	repo, _, err := c.client.Repositories.Get(ctx, c.owner, c.repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository information: %w", err)
+	}
+
+	var methods []string
+	if repo.GetAllowMergeCommit() {
+		methods = append(methods, "merge")
+	}
+	if repo.GetAllowSquashMerge() {
+		methods = append(methods, "squash")
+	}
+	if repo.GetAllowRebaseMerge() {
+		methods = append(methods, "rebase")
+	}
+	c.log.Debug(fmt.Sprintf("Allowed merge methods: %v", methods))
+	return methods, nil
+}
+
 // MergePullRequest merges a pull request using the specified merge method.
 //
 // Parameters:
 //   - prNumber: the pull request number
 //   - mergeMethod: one of "merge", "squash", or "rebase" (see [GetMergeMethod])
-//   - commitTitle: used as the merge commit message
-func (c *Client) MergePullRequest(prNumber int, mergeMethod, commitTitle string) error {
+//   - commitTitle: used as the merge commit title
+//   - commitMessage: used as the merge commit message (empty: commitTitle)
+func (c *Client) MergePullRequest(prNumber int, mergeMethod, commitTitle, commitMessage string) error {
 	c.log.Debug(fmt.Sprintf("Merging pull request #%d using method: %s", prNumber, mergeMethod))
 	options := &github.PullRequestOptions{
 		MergeMethod: mergeMethod, // "squash", "merge", or "rebase"
 		CommitTitle: commitTitle, // Use selected commit title as merge commit title
 	}
+	if commitMessage == "" {
+		commitMessage = commitTitle
+	}
 
-	// Pass commit title as the merge commit message
-	_, _, err := c.client.PullRequests.Merge(c.ctx(), c.owner, c.repo, prNumber, commitTitle, options)
+	ctx, cancel := c.ctx()
+	defer cancel()
+	result, _, err := c.client.PullRequests.Merge(ctx, c.owner, c.repo, prNumber, commitMessage, options)
 	if err != nil {
 		return fmt.Errorf("failed to merge pull request: %w", err)
 	}
 
 	c.log.Debug("Pull request merged successfully")
+	c.mergeSHA = result.GetSHA()
+	return nil
+}
+
+// MergeCommitSHA returns the SHA of the commit the last [Client.MergePullRequest]
+// created on the base branch, empty if unknown.
+func (c *Client) MergeCommitSHA() string {
+	return c.mergeSHA
+}
+
+// markReadyMutation is the GraphQL mutation turning a draft pull request into a
+// regular one; the REST API cannot do it.
+const markReadyMutation = `mutation($id: ID!) {
+  markPullRequestReadyForReview(input: {pullRequestId: $id}) { pullRequest { isDraft } }
+}`
+
+// graphQLRequest and graphQLResponse are the envelopes of a GraphQL API call.
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// graphQL runs query with variables and decodes its data into data (nil to ignore it).
+//
+// Returns [ErrGraphQL] if the request reports errors, also wrapping errGraphQLForbidden
+// when the token is not allowed to access the resource.
+func (c *Client) graphQL(query string, variables map[string]any, data any) error {
+	ctx, cancel := c.ctx()
+	defer cancel()
+	req, err := c.client.NewRequest(http.MethodPost, "graphql", graphQLRequest{
+		Query:     query,
+		Variables: variables,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build GraphQL request: %w", err)
+	}
+	var resp graphQLResponse
+	if _, err := c.client.Do(ctx, req, &resp); err != nil {
+		return fmt.Errorf("GraphQL request failed: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		switch resp.Errors[0].Type {
+		case "INSUFFICIENT_SCOPES", "FORBIDDEN":
+			return fmt.Errorf("%w: %w: %s", errGraphQL, errGraphQLForbidden, resp.Errors[0].Message)
+		default:
+			return fmt.Errorf("%w: %s", errGraphQL, resp.Errors[0].Message)
+		}
+	}
+	if data != nil && len(resp.Data) > 0 {
+		if err := json.Unmarshal(resp.Data, data); err != nil {
+			return fmt.Errorf("failed to decode GraphQL response: %w", err)
+		}
+	}
 	return nil
 }
 
+// MarkPullRequestReady marks a draft pull request as ready for review through
+// the markPullRequestReadyForReview GraphQL mutation.
+//
+// Returns false without updating anything if the pull request is not a draft.
+// Returns [ErrGraphQL] if the mutation is rejected.
+func (c *Client) MarkPullRequestReady(prNumber int) (bool, error) {
+	c.log.Debug(fmt.Sprintf("Marking pull request #%d as ready for review", prNumber))
+
+	ctx, cancel := c.ctx()
+	defer cancel()
+	pr, _, err := c.client.PullRequests.Get(ctx, c.owner, c.repo, prNumber)
+	if err != nil {
+		return false, fmt.Errorf("failed to get pull request: %w", err)
+	}
+	if !pr.GetDraft() {
+		return false, nil
+	}
+
+	if err := c.graphQL(markReadyMutation, map[string]any{"id": pr.GetNodeID()}, nil); err != nil {
+		return false, fmt.Errorf("failed to mark pull request as ready: %w", err)
+	}
+	return true, nil
+}
+
+// enableAutoMergeMutation turns on auto-merge for a pull request; the REST API cannot do it.
+const enableAutoMergeMutation = `mutation($id: ID!, $method: PullRequestMergeMethod!) {
+  enablePullRequestAutoMerge(input: {pullRequestId: $id, mergeMethod: $method}) { clientMutationId }
+}`
+
+// EnablePullRequestAutoMerge makes GitHub merge a pull request with mergeMethod ("merge",
+// "squash" or "rebase", see [GetMergeMethod]) once its required checks and reviews pass,
+// through the enablePullRequestAutoMerge GraphQL mutation.
+//
+// Returns [ErrGraphQL] if the mutation is rejected, e.g. when auto-merge is not allowed
+// in the repository settings or the pull request has no pending requirement.
+func (c *Client) EnablePullRequestAutoMerge(prNumber int, mergeMethod string) error {
+	c.log.Debug(fmt.Sprintf("Enabling auto-merge of pull request #%d using method: %s", prNumber, mergeMethod))
+
+	ctx, cancel := c.ctx()
+	defer cancel()
+	pr, _, err := c.client.PullRequests.Get(ctx, c.owner, c.repo, prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to get pull request: %w", err)
+	}
+
+	variables := map[string]any{"id": pr.GetNodeID(), "method": strings.ToUpper(mergeMethod)}
+	if err := c.graphQL(enableAutoMergeMutation, variables, nil); err != nil {
+		return fmt.Errorf("failed to enable auto-merge: %w", err)
+	}
+	return nil
+}
+
+// projectQuery resolves the node ID of a Projects (v2) board from the number shown
+// in its URL, whether the repository owner is an organization or a user.
+const projectQuery = `query($owner: String!, $number: Int!) {
+  repositoryOwner(login: $owner) { ... on ProjectV2Owner { projectV2(number: $number) { id } } }
+}`
+
+// addProjectItemMutation adds an issue or pull request to a Projects (v2) board.
+// Adding one that is already on the board is a no-op.
+const addProjectItemMutation = `mutation($project: ID!, $content: ID!) {
+  addProjectV2ItemById(input: {projectId: $project, contentId: $content}) { item { id } }
+}`
+
+// AddPullRequestToProject adds a pull request to the Projects (v2) board numbered
+// projectNumber of the repository owner, through the GraphQL API.
+//
+// Returns [ErrProjectNotFound] if the owner has no such project,
+// [ErrProjectAccess] if the token cannot access projects (classic tokens need the
+// project scope) and [ErrGraphQL] for other rejections.
+func (c *Client) AddPullRequestToProject(prNumber, projectNumber int) error {
+	c.log.Debug(fmt.Sprintf("Adding pull request #%d to project %d", prNumber, projectNumber))
+
+	ctx, cancel := c.ctx()
+	pr, _, err := c.client.PullRequests.Get(ctx, c.owner, c.repo, prNumber)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to get pull request: %w", err)
+	}
+
+	var data struct {
+		RepositoryOwner struct {
+			ProjectV2 *struct {
+				ID string `json:"id"`
+			} `json:"projectV2"`
+		} `json:"repositoryOwner"`
+	}
+	err = c.graphQL(projectQuery, map[string]any{"owner": c.owner, "number": projectNumber}, &data)
+	if errors.Is(err, errGraphQLForbidden) {
+		return fmt.Errorf("%w: %w", errProjectAccess, err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up project %d: %w", projectNumber, err)
+	}
+	if data.RepositoryOwner.ProjectV2 == nil {
+		return fmt.Errorf("%w: %s/%d", errProjectNotFound, c.owner, projectNumber)
+	}
+
+	err = c.graphQL(addProjectItemMutation, map[string]any{
+		"project": data.RepositoryOwner.ProjectV2.ID,
+		"content": pr.GetNodeID(),
+	}, nil)
+	if errors.Is(err, errGraphQLForbidden) {
+		return fmt.Errorf("%w: %w", errProjectAccess, err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add pull request to project %d: %w", projectNumber, err)
+	}
+	return nil
+}
+
+// ClosePullRequest closes a pull request without merging it.
+func (c *Client) ClosePullRequest(prNumber int) error {
+	c.log.Debug(fmt.Sprintf("Closing pull request #%d", prNumber))
+
+	ctx, cancel := c.ctx()
+	defer cancel()
+	_, _, err := c.client.PullRequests.Edit(ctx, c.owner, c.repo, prNumber,
+		&github.PullRequest{State: new("closed")})
+	if err != nil {
+		return fmt.Errorf("failed to close pull request: %w", err)
+	}
+	return nil
+}
+
+// CreatePullRequestComment posts a comment on a pull request's conversation.
+func (c *Client) CreatePullRequestComment(prNumber int, body string) error {
+	c.log.Debug(fmt.Sprintf("Commenting on pull request #%d", prNumber))
+
+	ctx, cancel := c.ctx()
+	defer cancel()
+	_, _, err := c.client.Issues.CreateComment(ctx, c.owner, c.repo, prNumber, &github.IssueComment{Body: &body})
+	if err != nil {
+		return fmt.Errorf("failed to comment on pull request: %w", err)
+	}
+	return nil
+}
+
+// AddPullRequestLabels adds labels to a pull request, keeping its current ones.
+func (c *Client) AddPullRequestLabels(prNumber int, labels []string) error {
+	c.log.Debug(fmt.Sprintf("Adding labels to pull request #%d: %v", prNumber, labels))
+
+	ctx, cancel := c.ctx()
+	defer cancel()
+	_, _, err := c.client.Issues.AddLabelsToIssue(ctx, c.owner, c.repo, prNumber, labels)
+	if err != nil {
+		return fmt.Errorf("failed to add labels to pull request: %w", err)
+	}
+	return nil
+}
+
+// CheckMergeable verifies that a pull request can be merged without conflicts,
+// waiting a few seconds for GitHub to compute its mergeability (see [Client.WaitForMergeability]).
+// If it is still unknown after that, the merge call is left to decide.
+//
+// Returns [ErrPRConflict] if the pull request is not mergeable because of conflicts.
+// Other blocking states (checks, reviews, ...) are left to the merge call.
+func (c *Client) CheckMergeable(prNumber int) error {
+	pr, err := c.WaitForMergeability(prNumber, mergeabilityTimeout)
+	if err != nil {
+		if errors.Is(err, errMergeabilityTimeout) {
+			c.log.Debug("Mergeability still being computed, proceeding with merge")
+			return nil
+		}
+		return err
+	}
+
+	c.log.Debug(fmt.Sprintf("Pull request #%d mergeable: %v (state: %s)",
+		prNumber, pr.GetMergeable(), pr.GetMergeableState()))
+
+	if !pr.GetMergeable() && pr.GetMergeableState() == mergeableStateDirty {
+		return fmt.Errorf("%w: #%d (%s into %s)", errPRConflict, prNumber,
+			pr.GetHead().GetRef(), pr.GetBase().GetRef())
+	}
+	return nil
+}
+
+// WaitForMergeability polls a pull request every 2 seconds until GitHub has computed
+// its mergeability, which it does in the background and reports as null right after
+// the pull request is created or its branches move.
+//
+// Returns the pull request, with Mergeable set.
+// Returns [ErrMergeabilityTimeout] if it is still unknown when the timeout is exceeded.
+func (c *Client) WaitForMergeability(prNumber int, timeout time.Duration) (*github.PullRequest, error) {
+	start := c.clock.Now()
+	for {
+		ctx, cancel := c.ctx()
+		pr, _, err := c.client.PullRequests.Get(ctx, c.owner, c.repo, prNumber)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pull request: %w", err)
+		}
+		if pr.Mergeable != nil {
+			return pr, nil
+		}
+
+		c.log.Debug(fmt.Sprintf("Mergeability of pull request #%d not computed yet", prNumber))
+		if c.clock.Since(start)+mergeabilityInterval > timeout {
+			return nil, fmt.Errorf("%w: #%d after %s", errMergeabilityTimeout, prNumber,
+				timeutil.FormatDuration(c.clock.Since(start)))
+		}
+		c.clock.Sleep(mergeabilityInterval)
+	}
+}
+
 // GetPullRequestsByHead returns all open pull requests for the given head branch.
 func (c *Client) GetPullRequestsByHead(head string) ([]*github.PullRequest, error) {
-	prs, _, err := c.client.PullRequests.List(c.ctx(), c.owner, c.repo, &github.PullRequestListOptions{
-		Head:  fmt.Sprintf("%s:%s", c.owner, head),
+	ctx, cancel := c.ctx()
+	defer cancel()
+	prs, _, err := c.client.PullRequests.List(ctx, c.owner, c.repo, &github.PullRequestListOptions{
+		Head:  c.qualifiedHead(head),
 		State: "open",
 	})
 	if err != nil {
@@ -199,45 +654,145 @@ func (c *Client) GetPullRequestsByHead(head string) ([]*github.PullRequest, erro
 // Parameters:
 //   - branch: the branch name to delete (without "refs/heads/" prefix)
 func (c *Client) DeleteBranch(branch string) error {
-	_, err := c.client.Git.DeleteRef(c.ctx(), c.owner, c.repo, "heads/"+branch)
+	owner, repo := c.headRepository()
+	ctx, cancel := c.ctx()
+	defer cancel()
+	_, err := c.client.Git.DeleteRef(ctx, owner, repo, "heads/"+branch)
 	if err != nil {
 		return fmt.Errorf("failed to delete branch: %w", err)
 	}
 	return nil
 }
 
-// addReviewers adds reviewers to a pull request, filtering out the PR author.
-func (c *Client) addReviewers(pr *github.PullRequest, reviewers []string) error {
+// CurrentUsername returns the login of the user the token belongs to.
+func (c *Client) CurrentUsername() (string, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+	user, _, err := c.client.Users.Get(ctx, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to get current user: %w", err)
+	}
+	return user.GetLogin(), nil
+}
+
+// checkUsersExist returns notFound, wrapped with the login, for the first login
+// that is not a GitHub user.
+func (c *Client) checkUsersExist(logins []string, notFound error) error {
+	for _, login := range logins {
+		ctx, cancel := c.ctx()
+		_, resp, err := c.client.Users.Get(ctx, login)
+		cancel()
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("%w: %s", notFound, login)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to look up user %s: %w", login, err)
+		}
+	}
+	return nil
+}
+
+// SetCISince makes [Client.WaitForWorkflows] ignore the workflow runs and check runs
+// created before since, e.g. an earlier run of the same commit, and wait for newer ones.
+// The zero time accepts every run.
+func (c *Client) SetCISince(since time.Time) {
+	c.ciSince = since
+}
+
+// SetKeepAuthorReviewers disables dropping reviewers equal to the pull request author
+// (the token's account). Use it when the token belongs to a bot sharing the reviewer's name.
+func (c *Client) SetKeepAuthorReviewers(keep bool) {
+	c.keepAuthorReviewers = keep
+}
+
+// RequestReviewers requests review of a pull request from users and teams (slugs of
+// teams of the repository's organization), e.g. its code owners, on top of the
+// reviewers already requested. The PR author is left out as in [Client.CreatePullRequest].
+func (c *Client) RequestReviewers(prNumber int, reviewers, teams []string) error {
+	c.log.Debug(fmt.Sprintf("Requesting review of pull request #%d from %v and teams %v", prNumber, reviewers, teams))
+
+	ctx, cancel := c.ctx()
+	pr, _, err := c.client.PullRequests.Get(ctx, c.owner, c.repo, prNumber)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to get pull request: %w", err)
+	}
+	return c.addReviewers(pr, reviewers, teams)
+}
+
+// addReviewers adds reviewers and team reviewers to a pull request, filtering out
+// the PR author unless [Client.SetKeepAuthorReviewers] was enabled.
+//
+// In a single-person repository the author is also the assignee and the only
+// reviewer: no review is requested, which is logged rather than treated as an error,
+// including when GitHub rejects a kept author reviewer.
+func (c *Client) addReviewers(pr *github.PullRequest, reviewers, teams []string) error {
 	prAuthor := pr.User.GetLogin()
 	filteredReviewers := make([]string, 0, len(reviewers))
 	for _, reviewer := range reviewers {
-		if reviewer != prAuthor {
-			filteredReviewers = append(filteredReviewers, reviewer)
+		if reviewer == prAuthor && !c.keepAuthorReviewers {
+			c.log.Debug(fmt.Sprintf("Not requesting review from %s: it is the pull request author "+
+				"(set github.keep_author_reviewer to keep it)", reviewer))
+			continue
 		}
+		filteredReviewers = append(filteredReviewers, reviewer)
 	}
 
-	if len(filteredReviewers) > 0 {
-		reviewRequest := github.ReviewersRequest{
-			Reviewers: filteredReviewers,
-		}
-		_, _, err := c.client.PullRequests.RequestReviewers(c.ctx(), c.owner, c.repo, *pr.Number, reviewRequest)
-		if err != nil {
-			return fmt.Errorf("failed to add reviewers: %w", err)
+	if len(filteredReviewers) == 0 && len(teams) == 0 {
+		c.log.Info(fmt.Sprintf("No review requested: %s is the pull request author", prAuthor))
+		return nil
+	}
+
+	reviewRequest := github.ReviewersRequest{
+		Reviewers:     filteredReviewers,
+		TeamReviewers: teams,
+	}
+	ctx, cancel := c.ctx()
+	defer cancel()
+	_, resp, err := c.client.PullRequests.RequestReviewers(ctx, c.owner, c.repo, *pr.Number, reviewRequest)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusUnprocessableEntity &&
+			strings.Contains(strings.ToLower(err.Error()), "pull request author") {
+			c.log.Info(fmt.Sprintf("No review requested: GitHub does not allow %s, the pull request author, "+
+				"to review it", prAuthor))
+			return nil
 		}
+		return fmt.Errorf("failed to add reviewers: %w", err)
 	}
 	return nil
 }
 
+// awaitWorkflowRuns reports whether the PR has workflow runs or check suites, checking
+// again every workflowCreationPoll until the startup delay elapses, since they are
+// created a moment after a push.
+func (c *Client) awaitWorkflowRuns() bool {
+	start := c.clock.Now()
+	for {
+		if c.hasWorkflowRuns() {
+			return true
+		}
+		remaining := c.startupDelay - c.clock.Since(start)
+		if remaining <= 0 {
+			return false
+		}
+		c.clock.Sleep(min(workflowCreationPoll, remaining))
+	}
+}
+
 // hasWorkflowRuns checks if there are any workflow runs (in any state) for this PR.
+// Check suites are those of every app, so checks of external CI services (e.g. CircleCI
+// or Jenkins through the Checks API) count even without GitHub Actions workflows.
 func (c *Client) hasWorkflowRuns() bool {
 	// Check for workflow runs associated with this commit SHA
+	ctx, cancel := c.ctx()
 	runs, _, err := c.client.Actions.ListRepositoryWorkflowRuns(
-		c.ctx(), c.owner, c.repo,
+		ctx, c.owner, c.repo,
 		&github.ListWorkflowRunsOptions{
 			Event:   "pull_request",
 			HeadSHA: c.prSHA,
 		},
 	)
+	cancel()
 	if err != nil {
 		c.log.Debug(fmt.Sprintf("Failed to list workflow runs, assuming workflows exist - error: %v", err))
 		return true // Assume workflows exist on error to be safe
@@ -249,8 +804,10 @@ func (c *Client) hasWorkflowRuns() bool {
 	}
 
 	// Also check suites as they're created even before runs start
+	ctx, cancel = c.ctx()
+	defer cancel()
 	checkSuites, _, err := c.client.Checks.ListCheckSuitesForRef(
-		c.ctx(), c.owner, c.repo, c.prSHA,
+		ctx, c.owner, c.repo, c.prSHA,
 		&github.ListCheckSuiteOptions{},
 	)
 	if err != nil {
@@ -271,28 +828,46 @@ func (c *Client) fetchWorkflowJobs() ([]*JobInfo, error) {
 	c.log.Debug("Fetching workflow jobs for PR")
 
 	// First, get workflow runs for this PR
+	ctx, cancel := c.ctx()
 	runs, _, err := c.client.Actions.ListRepositoryWorkflowRuns(
-		c.ctx(), c.owner, c.repo,
+		ctx, c.owner, c.repo,
 		&github.ListWorkflowRunsOptions{
 			Event:   "pull_request",
 			HeadSHA: c.prSHA,
 		},
 	)
+	cancel()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list workflow runs: %w", err)
 	}
 
-	if runs.GetTotalCount() == 0 {
+	workflowRuns := c.freshWorkflowRuns(runs.WorkflowRuns)
+	if len(workflowRuns) == 0 {
 		c.log.Debug("No workflow runs found for PR")
 		return nil, nil
 	}
+	c.announceWorkflowRuns(workflowRuns)
+
+	// Fetch the jobs of all workflow runs concurrently, with at most c.concurrency()
+	// requests in flight across all runs and pages
+	runJobs := make([][]*JobInfo, len(workflowRuns))
+	runErrs := make([]error, len(workflowRuns))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, c.concurrency())
+	for i, run := range workflowRuns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runJobs[i], runErrs[i] = c.fetchJobsForRun(run.GetID(), sem)
+		}()
+	}
+	wg.Wait()
 
-	// Collect all jobs from all workflow runs
+	// Collect all jobs, in workflow run order
 	var allJobs []*JobInfo
-	for _, run := range runs.WorkflowRuns {
-		jobs, err := c.fetchJobsForRun(run.GetID())
-		if err != nil {
-			return nil, err
+	for i, jobs := range runJobs {
+		if runErrs[i] != nil {
+			return nil, runErrs[i]
 		}
 		allJobs = append(allJobs, jobs...)
 	}
@@ -301,50 +876,123 @@ func (c *Client) fetchWorkflowJobs() ([]*JobInfo, error) {
 	return allJobs, nil
 }
 
-// fetchJobsForRun fetches all jobs for a specific workflow run with pagination.
-func (c *Client) fetchJobsForRun(runID int64) ([]*JobInfo, error) {
-	var allJobs []*JobInfo
-	page := 1
-	perPage := 100
-
-	for {
-		jobs, resp, err := c.client.Actions.ListWorkflowJobs(
-			c.ctx(), c.owner, c.repo, runID,
-			&github.ListWorkflowJobsOptions{
-				ListOptions: github.ListOptions{
-					Page:    page,
-					PerPage: perPage,
-				},
-			},
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to list workflow jobs for run %d: %w", runID, err)
+// freshWorkflowRuns returns the workflow runs created at or after the time set with
+// [Client.SetCISince], all of them when it is not set.
+func (c *Client) freshWorkflowRuns(runs []*github.WorkflowRun) []*github.WorkflowRun {
+	if c.ciSince.IsZero() {
+		return runs
+	}
+	fresh := make([]*github.WorkflowRun, 0, len(runs))
+	for _, run := range runs {
+		if !run.GetCreatedAt().Before(c.ciSince) {
+			fresh = append(fresh, run)
 		}
+	}
+	return fresh
+}
 
-		// Convert GitHub workflow jobs to our JobInfo struct
-		for _, ghJob := range jobs.Jobs {
-			job := &JobInfo{
-				ID:          ghJob.GetID(),
-				Name:        ghJob.GetName(),
-				Status:      ghJob.GetStatus(),
-				Conclusion:  ghJob.GetConclusion(),
-				StartedAt:   ghJob.StartedAt.GetTime(),
-				CompletedAt: ghJob.CompletedAt.GetTime(),
-				HTMLURL:     ghJob.GetHTMLURL(),
-			}
-			allJobs = append(allJobs, job)
+// freshCheckRuns returns the check runs started at or after the time set with
+// [Client.SetCISince], all of them when it is not set. Check runs not started yet
+// (queued ones have no start time) are fresh.
+func (c *Client) freshCheckRuns(checkRuns []*github.CheckRun) []*github.CheckRun {
+	if c.ciSince.IsZero() {
+		return checkRuns
+	}
+	fresh := make([]*github.CheckRun, 0, len(checkRuns))
+	for _, run := range checkRuns {
+		if run.StartedAt == nil || !run.GetStartedAt().Before(c.ciSince) {
+			fresh = append(fresh, run)
 		}
+	}
+	return fresh
+}
 
-		// Check if there are more pages
-		if resp.NextPage == 0 {
-			break
+// announceWorkflowRuns prints the URL of each workflow run the first time it is seen
+// while waiting, to follow it in the browser.
+func (c *Client) announceWorkflowRuns(runs []*github.WorkflowRun) {
+	if c.announcedRuns == nil {
+		return
+	}
+	for _, run := range runs {
+		if !c.announcedRuns[run.GetID()] && run.GetHTMLURL() != "" {
+			c.announcedRuns[run.GetID()] = true
+			c.display.Info(fmt.Sprintf("Workflow %s: %s", run.GetName(), run.GetHTMLURL()))
 		}
-		page = resp.NextPage
 	}
+}
+
+// fetchJobsForRun fetches all jobs of a workflow run, in page order. The first page
+// tells how many pages there are; the others are then fetched concurrently. Each
+// request holds a slot of sem, so that the fetches of all runs share one bound.
+func (c *Client) fetchJobsForRun(runID int64, sem chan struct{}) ([]*JobInfo, error) {
+	firstJobs, lastPage, err := c.fetchJobsPage(runID, 1, sem)
+	if err != nil {
+		return nil, err
+	}
+	if lastPage <= 1 {
+		return firstJobs, nil
+	}
+
+	pageJobs := make([][]*JobInfo, lastPage)
+	pageErrs := make([]error, lastPage)
+	pageJobs[0] = firstJobs
+	var wg sync.WaitGroup
+	for page := 2; page <= lastPage; page++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pageJobs[page-1], _, pageErrs[page-1] = c.fetchJobsPage(runID, page, sem)
+		}()
+	}
+	wg.Wait()
 
+	var allJobs []*JobInfo
+	for i, jobs := range pageJobs {
+		if pageErrs[i] != nil {
+			return nil, pageErrs[i]
+		}
+		allJobs = append(allJobs, jobs...)
+	}
 	return allJobs, nil
 }
 
+// fetchJobsPage fetches one page of the jobs of a workflow run, holding a slot of sem
+// during the request. It also returns the number of the last page (0 when page is the last one).
+func (c *Client) fetchJobsPage(runID int64, page int, sem chan struct{}) ([]*JobInfo, int, error) {
+	sem <- struct{}{}
+	defer func() { <-sem }()
+
+	ctx, cancel := c.ctx()
+	defer cancel()
+	jobs, resp, err := c.client.Actions.ListWorkflowJobs(
+		ctx, c.owner, c.repo, runID,
+		&github.ListWorkflowJobsOptions{
+			ListOptions: github.ListOptions{
+				Page:    page,
+				PerPage: jobsPerPage,
+			},
+		},
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list workflow jobs for run %d: %w", runID, err)
+	}
+
+	// Convert GitHub workflow jobs to our JobInfo struct
+	result := make([]*JobInfo, 0, len(jobs.Jobs))
+	for _, ghJob := range jobs.Jobs {
+		result = append(result, &JobInfo{
+			ID:          ghJob.GetID(),
+			Name:        ghJob.GetName(),
+			Status:      ghJob.GetStatus(),
+			Conclusion:  ghJob.GetConclusion(),
+			StartedAt:   ghJob.StartedAt.GetTime(),
+			CompletedAt: ghJob.CompletedAt.GetTime(),
+			HTMLURL:     ghJob.GetHTMLURL(),
+		})
+	}
+	return result, resp.LastPage, nil
+}
+
 // convertCheckRunsToJobInfo converts GitHub CheckRuns to JobInfo format.
 func (c *Client) convertCheckRunsToJobInfo(checkRuns []*github.CheckRun) []*JobInfo {
 	jobs := make([]*JobInfo, 0, len(checkRuns))
@@ -374,9 +1022,14 @@ func (c *Client) convertCheckRunsToJobInfo(checkRuns []*github.CheckRun) []*JobI
 	return jobs
 }
 
-// ctx returns the context for API calls.
-func (c *Client) ctx() context.Context {
-	return context.Background()
+// ctx returns the context for a single API call, bounded by the request timeout
+// (see [Client.SetRequestTimeout]). The caller must call the cancel function.
+func (c *Client) ctx() (context.Context, context.CancelFunc) {
+	timeout := c.requestTimeout
+	if timeout <= 0 {
+		timeout = defaultRequestTimeout
+	}
+	return context.WithTimeout(context.Background(), timeout)
 }
 
 // Ensure Client implements APIClient interface at compile time.
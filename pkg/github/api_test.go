@@ -0,0 +1,1147 @@
+package github_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sgaunet/auto-mr/internal/timeutil"
+	ghpkg "github.com/sgaunet/auto-mr/pkg/github"
+)
+
+// redirectTransport sends every request to the test server, whatever its original host.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (rt redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = rt.target.Scheme
+	req.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(req) //nolint:wrapcheck // test transport
+}
+
+// newServerClient returns a GitHub client backed by mux, with owner/repo selected.
+// The repository is served unless mux already serves it.
+func newServerClient(t *testing.T, mux *http.ServeMux) *ghpkg.Client {
+	t.Helper()
+
+	repoRequest := httptest.NewRequest(http.MethodGet, "/repos/owner/repo", nil)
+	if _, pattern := mux.Handler(repoRequest); pattern == "" {
+		mux.HandleFunc("GET /repos/owner/repo", func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `{"name": "repo"}`)
+		})
+	}
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("failed to parse server URL: %v", err)
+	}
+
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	client, err := ghpkg.NewClient(&http.Client{Transport: redirectTransport{target: target}})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if err := client.SetRepositoryFromURL("https://github.com/owner/repo.git"); err != nil {
+		t.Fatalf("failed to set repository: %v", err)
+	}
+	return client
+}
+
+// TestCreatePullRequestFromFork verifies that fork branches are referenced as owner:branch
+// and deleted from the fork.
+func TestCreatePullRequestFromFork(t *testing.T) {
+	var head string
+	deleted := false
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /repos/owner/repo/pulls", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Head string `json:"head"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		head = body.Head
+		fmt.Fprint(w, `{"number": 3, "html_url": "https://github.com/owner/repo/pull/3", "head": {"sha": "abc"}}`)
+	})
+	mux.HandleFunc("DELETE /repos/me/repo/git/refs/heads/feature", func(w http.ResponseWriter, _ *http.Request) {
+		deleted = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	client := newServerClient(t, mux)
+	if err := client.SetHeadRepositoryFromURL("git@github.com:me/repo.git"); err != nil {
+		t.Fatalf("failed to set head repository: %v", err)
+	}
+
+	if _, err := client.CreatePullRequest("feature", "main", "Title", "", nil, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if head != "me:feature" {
+		t.Errorf("expected head me:feature, got %q", head)
+	}
+
+	if err := client.DeleteBranch("feature"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !deleted {
+		t.Error("expected branch to be deleted from the fork")
+	}
+}
+
+// TestPullRequestExplicitHeadOwner verifies that an "owner:branch" head is used as is,
+// instead of being prefixed with the head repository owner again.
+func TestPullRequestExplicitHeadOwner(t *testing.T) {
+	tests := []struct {
+		name       string
+		head       string
+		createHead string
+		listHead   string
+	}{
+		{"other fork", "alice:feature", "alice:feature", "alice:feature"},
+		{"base owner", "owner:feature", "feature", "owner:feature"},
+		{"plain branch", "feature", "me:feature", "me:feature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created, listed string
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /repos/owner/repo/pulls", func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Head string `json:"head"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("failed to decode request: %v", err)
+				}
+				created = body.Head
+				fmt.Fprint(w, `{"number": 3, "html_url": "https://github.com/owner/repo/pull/3", "head": {"sha": "abc"}}`)
+			})
+			mux.HandleFunc("GET /repos/owner/repo/pulls", func(w http.ResponseWriter, r *http.Request) {
+				listed = r.URL.Query().Get("head")
+				fmt.Fprint(w, `[{"number": 3, "head": {"sha": "abc"}}]`)
+			})
+
+			client := newServerClient(t, mux)
+			if err := client.SetHeadRepositoryFromURL("git@github.com:me/repo.git"); err != nil {
+				t.Fatalf("failed to set head repository: %v", err)
+			}
+
+			if _, err := client.CreatePullRequest(tt.head, "main", "Title", "", nil, nil, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if created != tt.createHead {
+				t.Errorf("expected created head %q, got %q", tt.createHead, created)
+			}
+			if _, err := client.GetPullRequestByBranch(tt.head, "main"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if listed != tt.listHead {
+				t.Errorf("expected listed head %q, got %q", tt.listHead, listed)
+			}
+		})
+	}
+}
+
+// TestCreatePullRequestSinglePerson verifies that a pull request whose assignee and
+// reviewer are both its author is created without requesting a review, even when the
+// author reviewer is kept and GitHub rejects it.
+func TestCreatePullRequestSinglePerson(t *testing.T) {
+	for _, keep := range []bool{false, true} {
+		t.Run(fmt.Sprintf("keep=%t", keep), func(t *testing.T) {
+			requests := 0
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /users/solo", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `{"login": "solo"}`)
+			})
+			mux.HandleFunc("POST /repos/owner/repo/pulls", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `{"number": 3, "html_url": "https://github.com/owner/repo/pull/3", `+
+					`"user": {"login": "solo"}, "head": {"sha": "abc"}}`)
+			})
+			mux.HandleFunc("PATCH /repos/owner/repo/issues/3", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `{"number": 3}`)
+			})
+			mux.HandleFunc("POST /repos/owner/repo/pulls/3/requested_reviewers",
+				func(w http.ResponseWriter, _ *http.Request) {
+					requests++
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					fmt.Fprint(w, `{"message": "Review cannot be requested from pull request author."}`)
+				})
+
+			client := newServerClient(t, mux)
+			client.SetKeepAuthorReviewers(keep)
+
+			_, err := client.CreatePullRequest("feature", "main", "Title", "",
+				[]string{"solo"}, []string{"solo"}, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if wantRequests := map[bool]int{false: 0, true: 1}[keep]; requests != wantRequests {
+				t.Errorf("expected %d review requests, got %d", wantRequests, requests)
+			}
+		})
+	}
+}
+
+// TestCreatePullRequestAssigneesAndLabels verifies that assignees and labels are set
+// in a single call, and that a failure after creation reports the open pull request.
+func TestCreatePullRequestAssigneesAndLabels(t *testing.T) {
+	for _, fail := range []bool{false, true} {
+		t.Run(fmt.Sprintf("fail=%t", fail), func(t *testing.T) {
+			edits := 0
+			var sent struct {
+				Assignees []string `json:"assignees"`
+				Labels    []string `json:"labels"`
+			}
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /users/alice", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `{"login": "alice"}`)
+			})
+			mux.HandleFunc("POST /repos/owner/repo/pulls", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `{"number": 3, "html_url": "https://github.com/owner/repo/pull/3", `+
+					`"user": {"login": "alice"}, "head": {"sha": "abc"}}`)
+			})
+			mux.HandleFunc("PATCH /repos/owner/repo/issues/3", func(w http.ResponseWriter, r *http.Request) {
+				edits++
+				if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+					t.Errorf("failed to decode request: %v", err)
+				}
+				if fail {
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					fmt.Fprint(w, `{"message": "Validation Failed"}`)
+					return
+				}
+				fmt.Fprint(w, `{"number": 3}`)
+			})
+
+			client := newServerClient(t, mux)
+			_, err := client.CreatePullRequest("feature", "main", "Title", "",
+				[]string{"alice"}, nil, []string{"bug", "ci"})
+			if fail {
+				if !errors.Is(err, ghpkg.ErrPRIncomplete) || !strings.Contains(err.Error(), "pull/3") {
+					t.Fatalf("expected ErrPRIncomplete with the pull request URL, got %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if edits != 1 || len(sent.Assignees) != 1 || len(sent.Labels) != 2 {
+				t.Errorf("expected one call setting assignees and labels, got %d calls with %+v", edits, sent)
+			}
+		})
+	}
+}
+
+// TestCreatePullRequestAuthorReviewer verifies that a reviewer equal to the PR author is
+// dropped by default and kept when SetKeepAuthorReviewers is enabled.
+func TestCreatePullRequestAuthorReviewer(t *testing.T) {
+	for _, keep := range []bool{false, true} {
+		t.Run(fmt.Sprintf("keep=%t", keep), func(t *testing.T) {
+			var requested []string
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /users/bot", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `{"login": "bot"}`)
+			})
+			mux.HandleFunc("POST /repos/owner/repo/pulls", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `{"number": 3, "html_url": "https://github.com/owner/repo/pull/3", `+
+					`"user": {"login": "bot"}, "head": {"sha": "abc"}}`)
+			})
+			mux.HandleFunc("POST /repos/owner/repo/pulls/3/requested_reviewers",
+				func(w http.ResponseWriter, r *http.Request) {
+					var body struct {
+						Reviewers []string `json:"reviewers"`
+					}
+					if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+						t.Errorf("failed to decode request: %v", err)
+					}
+					requested = body.Reviewers
+					fmt.Fprint(w, `{"number": 3}`)
+				})
+
+			client := newServerClient(t, mux)
+			client.SetKeepAuthorReviewers(keep)
+
+			_, err := client.CreatePullRequest("feature", "main", "Title", "", nil, []string{"bot"}, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if keep && (len(requested) != 1 || requested[0] != "bot") {
+				t.Errorf("expected review requested from bot, got %v", requested)
+			}
+			if !keep && requested != nil {
+				t.Errorf("expected no review request, got %v", requested)
+			}
+		})
+	}
+}
+
+// TestRequestReviewers verifies that users and teams are requested, leaving out the author.
+func TestRequestReviewers(t *testing.T) {
+	var requested struct {
+		Reviewers     []string `json:"reviewers"`
+		TeamReviewers []string `json:"team_reviewers"`
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/repo/pulls/3", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"number": 3, "user": {"login": "author"}}`)
+	})
+	mux.HandleFunc("POST /repos/owner/repo/pulls/3/requested_reviewers",
+		func(w http.ResponseWriter, r *http.Request) {
+			if err := json.NewDecoder(r.Body).Decode(&requested); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			fmt.Fprint(w, `{"number": 3}`)
+		})
+	client := newServerClient(t, mux)
+
+	if err := client.RequestReviewers(3, []string{"author", "alice"}, []string{"backend"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(requested.Reviewers) != "[alice]" || fmt.Sprint(requested.TeamReviewers) != "[backend]" {
+		t.Errorf("expected review requested from alice and team backend, got %+v", requested)
+	}
+}
+
+// TestListLabelsColorAndDescription verifies that label colors get a leading '#'
+// and descriptions are kept.
+func TestListLabelsColorAndDescription(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/repo/labels", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[{"name": "bug", "color": "d73a4a", "description": "Something isn't working"},`+
+			`{"name": "plain"}]`)
+	})
+	client := newServerClient(t, mux)
+
+	labels, err := client.ListLabels()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(labels) != 2 {
+		t.Fatalf("expected 2 labels, got %d", len(labels))
+	}
+	if labels[0].Color != "#d73a4a" || labels[0].Description != "Something isn't working" {
+		t.Errorf("unexpected first label: %+v", *labels[0])
+	}
+	if labels[1].Color != "" {
+		t.Errorf("expected no color for the second label, got %q", labels[1].Color)
+	}
+}
+
+// TestCreatePullRequestUnknownUser verifies that unknown assignees are reported
+// before the pull request is created.
+func TestCreatePullRequestUnknownUser(t *testing.T) {
+	created := false
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/ghost", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message": "Not Found"}`)
+	})
+	mux.HandleFunc("POST /repos/owner/repo/pulls", func(w http.ResponseWriter, _ *http.Request) {
+		created = true
+		fmt.Fprint(w, `{"number": 3, "html_url": "https://github.com/owner/repo/pull/3", "head": {"sha": "abc"}}`)
+	})
+	client := newServerClient(t, mux)
+
+	_, err := client.CreatePullRequest("feature", "main", "Title", "", []string{"ghost"}, nil, nil)
+	if !errors.Is(err, ghpkg.ErrAssigneeNotFound) {
+		t.Fatalf("expected ErrAssigneeNotFound, got %v", err)
+	}
+	if created {
+		t.Error("expected no pull request to be created")
+	}
+}
+
+// TestRequestTimeout verifies that a stalled API call fails once the request timeout expires.
+func TestRequestTimeout(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/repo/labels", func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	client := newServerClient(t, mux)
+	client.SetRequestTimeout(50 * time.Millisecond)
+
+	_, err := client.ListLabels()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+// TestMergePullRequestCommitMessage verifies that the merge commit title and message
+// are sent, the message defaulting to the title.
+func TestMergePullRequestCommitMessage(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		commitMessage string
+		expectMessage string
+	}{
+		{"merge with message", "merge", "Body of the pull request", "Body of the pull request"},
+		{"squash without message", "squash", "", "Add feature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent map[string]string
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT /repos/owner/repo/pulls/5/merge", func(w http.ResponseWriter, r *http.Request) {
+				if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+					t.Errorf("failed to decode request: %v", err)
+				}
+				fmt.Fprint(w, `{"merged": true}`)
+			})
+			client := newServerClient(t, mux)
+
+			if err := client.MergePullRequest(5, tt.method, "Add feature", tt.commitMessage); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sent["merge_method"] != tt.method || sent["commit_title"] != "Add feature" ||
+				sent["commit_message"] != tt.expectMessage {
+				t.Errorf("unexpected merge request: %v", sent)
+			}
+		})
+	}
+}
+
+// TestAllowedMergeMethods verifies that the merge methods are read from the repository settings.
+func TestAllowedMergeMethods(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/repo", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"allow_merge_commit": false, "allow_squash_merge": true, "allow_rebase_merge": true}`)
+	})
+	client := newServerClient(t, mux)
+
+	methods, err := client.AllowedMergeMethods()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(methods, []string{"squash", "rebase"}) {
+		t.Errorf("expected [squash rebase], got %v", methods)
+	}
+}
+
+// TestWaitForMergeability verifies that the pull request is polled until GitHub has
+// computed its mergeability, and that CheckMergeable reports conflicts once it is known.
+func TestWaitForMergeability(t *testing.T) {
+	t.Run("computed after two polls", func(t *testing.T) {
+		polls := 0
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repos/owner/repo/pulls/5", func(w http.ResponseWriter, _ *http.Request) {
+			polls++
+			if polls < 3 {
+				fmt.Fprint(w, `{"number": 5, "mergeable": null}`)
+				return
+			}
+			fmt.Fprint(w, `{"number": 5, "mergeable": false, "mergeable_state": "dirty"}`)
+		})
+		client := newServerClient(t, mux)
+		clock := timeutil.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+		client.SetClock(clock)
+
+		pr, err := client.WaitForMergeability(5, time.Minute)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if pr.Mergeable == nil || clock.Sleeps() != 2 {
+			t.Errorf("expected mergeability after 2 sleeps, got %v after %d sleeps", pr.Mergeable, clock.Sleeps())
+		}
+		if err := client.CheckMergeable(5); !errors.Is(err, ghpkg.ErrPRConflict) {
+			t.Errorf("expected ErrPRConflict, got %v", err)
+		}
+	})
+
+	t.Run("never computed", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repos/owner/repo/pulls/5", func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `{"number": 5, "mergeable": null}`)
+		})
+		client := newServerClient(t, mux)
+		clock := timeutil.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+		client.SetClock(clock)
+
+		_, err := client.WaitForMergeability(5, 10*time.Second)
+		if !errors.Is(err, ghpkg.ErrMergeabilityTimeout) {
+			t.Fatalf("expected ErrMergeabilityTimeout, got %v", err)
+		}
+		if clock.Sleeps() != 5 {
+			t.Errorf("expected 5 sleeps in 10s, got %d", clock.Sleeps())
+		}
+		if err := client.CheckMergeable(5); err != nil {
+			t.Errorf("expected CheckMergeable to leave an unknown mergeability to the merge, got %v", err)
+		}
+	})
+}
+
+// TestMarkPullRequestReady verifies that drafts are marked ready through the GraphQL mutation.
+func TestMarkPullRequestReady(t *testing.T) {
+	var nodeID string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/repo/pulls/5", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"number": 5, "draft": true, "node_id": "PR_kw5"}`)
+	})
+	mux.HandleFunc("GET /repos/owner/repo/pulls/6", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"number": 6, "draft": false, "node_id": "PR_kw6"}`)
+	})
+	mux.HandleFunc("POST /graphql", func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Variables map[string]string `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		nodeID = payload.Variables["id"]
+		fmt.Fprint(w, `{"data": {"markPullRequestReadyForReview": {"pullRequest": {"isDraft": false}}}}`)
+	})
+	client := newServerClient(t, mux)
+
+	changed, err := client.MarkPullRequestReady(5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !changed || nodeID != "PR_kw5" {
+		t.Errorf("expected mutation for PR_kw5, got changed=%v id=%q", changed, nodeID)
+	}
+
+	nodeID = ""
+	changed, err = client.MarkPullRequestReady(6)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if changed || nodeID != "" {
+		t.Error("expected a non-draft pull request to be left as is")
+	}
+}
+
+func TestEnablePullRequestAutoMerge(t *testing.T) {
+	var variables map[string]string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/repo/pulls/5", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"number": 5, "node_id": "PR_kw5"}`)
+	})
+	mux.HandleFunc("POST /graphql", func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Variables map[string]string `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		variables = payload.Variables
+		if variables["method"] == "MERGE" {
+			fmt.Fprint(w, `{"errors": [{"message": "Merge commits are not allowed on this repository."}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"data": {"enablePullRequestAutoMerge": {"clientMutationId": null}}}`)
+	})
+	client := newServerClient(t, mux)
+
+	if err := client.EnablePullRequestAutoMerge(5, "squash"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if variables["id"] != "PR_kw5" || variables["method"] != "SQUASH" {
+		t.Errorf("expected auto-merge of PR_kw5 with SQUASH, got %v", variables)
+	}
+
+	err := client.EnablePullRequestAutoMerge(5, "merge")
+	if !errors.Is(err, ghpkg.ErrGraphQL) {
+		t.Errorf("expected ErrGraphQL, got %v", err)
+	}
+}
+
+// newWorkflowClient returns a client with PR 5 selected, whose check runs are served by checkRuns,
+// and a fake clock driving the wait.
+func newWorkflowClient(t *testing.T, checkRuns http.HandlerFunc) (*ghpkg.Client, *timeutil.FakeClock) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/repo/pulls", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[{"number": 5, "head": {"sha": "abc"}}]`)
+	})
+	mux.HandleFunc("GET /repos/owner/repo/actions/runs", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"total_count": 0, "workflow_runs": []}`)
+	})
+	mux.HandleFunc("GET /repos/owner/repo/commits/abc/check-suites", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"total_count": 1, "check_suites": [{"id": 1}]}`)
+	})
+	mux.HandleFunc("GET /repos/owner/repo/commits/abc/check-runs", checkRuns)
+	client := newServerClient(t, mux)
+	if _, err := client.GetPullRequestByBranch("feature", "main"); err != nil {
+		t.Fatalf("failed to get pull request: %v", err)
+	}
+
+	clock := timeutil.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	client.SetClock(clock)
+	return client, clock
+}
+
+// TestWaitForWorkflowsTimeout verifies that the wait polls every 5 seconds until the timeout.
+func TestWaitForWorkflowsTimeout(t *testing.T) {
+	client, clock := newWorkflowClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"total_count": 1, "check_runs": [{"id": 1, "name": "ci", "status": "in_progress"}]}`)
+	})
+
+	_, err := client.WaitForWorkflows(time.Minute)
+	if !errors.Is(err, ghpkg.ErrWorkflowTimeout) {
+		t.Fatalf("expected ErrWorkflowTimeout, got %v", err)
+	}
+	if clock.Sleeps() != 12 {
+		t.Errorf("expected 12 poll sleeps in 1m, got %d sleeps", clock.Sleeps())
+	}
+}
+
+// TestWaitForWorkflowsStartTimeout verifies that the wait gives up once no check run showed
+// up within the start timeout, well before the workflow timeout.
+func TestWaitForWorkflowsStartTimeout(t *testing.T) {
+	client, clock := newWorkflowClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"total_count": 0, "check_runs": []}`)
+	})
+	client.SetStartTimeout(30 * time.Second)
+
+	_, err := client.WaitForWorkflows(time.Hour)
+	if !errors.Is(err, ghpkg.ErrNoWorkflow) {
+		t.Fatalf("expected ErrNoWorkflow, got %v", err)
+	}
+	if clock.Sleeps() != 6 {
+		t.Errorf("expected 6 poll sleeps in 30s, got %d sleeps", clock.Sleeps())
+	}
+}
+
+// TestWaitForWorkflowsStartupDelay verifies that workflows are looked for every second
+// until the startup delay elapses, and that the wait starts as soon as they show up.
+func TestWaitForWorkflowsStartupDelay(t *testing.T) {
+	tests := []struct {
+		name        string
+		delay       time.Duration
+		suitesAfter int // checks for workflows before check suites show up
+		expect      string
+		expectSleep int
+	}{
+		{"created after 3s", 5 * time.Second, 3, "failure", 3},
+		{"never created", 5 * time.Second, 100, "success", 5},
+		{"no delay", 0, 1, "success", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := 0
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /repos/owner/repo/pulls", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `[{"number": 5, "head": {"sha": "abc"}}]`)
+			})
+			mux.HandleFunc("GET /repos/owner/repo/actions/runs", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `{"total_count": 0, "workflow_runs": []}`)
+			})
+			mux.HandleFunc("GET /repos/owner/repo/commits/abc/check-suites", func(w http.ResponseWriter, _ *http.Request) {
+				checks++
+				if checks <= tt.suitesAfter {
+					fmt.Fprint(w, `{"total_count": 0, "check_suites": []}`)
+					return
+				}
+				fmt.Fprint(w, `{"total_count": 1, "check_suites": [{"id": 1}]}`)
+			})
+			mux.HandleFunc("GET /repos/owner/repo/commits/abc/check-runs", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `{"total_count": 1, "check_runs": [{"id": 1, "name": "ci", "status": "completed", "conclusion": "failure"}]}`)
+			})
+			client := newServerClient(t, mux)
+			if _, err := client.GetPullRequestByBranch("feature", "main"); err != nil {
+				t.Fatalf("failed to get pull request: %v", err)
+			}
+			clock := timeutil.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+			client.SetClock(clock)
+			client.SetStartupDelay(tt.delay)
+
+			conclusion, err := client.WaitForWorkflows(time.Hour)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if conclusion != tt.expect {
+				t.Errorf("expected %q, got %q", tt.expect, conclusion)
+			}
+			if clock.Sleeps() != tt.expectSleep {
+				t.Errorf("expected %d sleeps, got %d", tt.expectSleep, clock.Sleeps())
+			}
+		})
+	}
+}
+
+// TestWaitForWorkflowsCompletes verifies that the wait returns the conclusion once the checks complete.
+func TestWaitForWorkflowsCompletes(t *testing.T) {
+	polls := 0
+	client, clock := newWorkflowClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		polls++
+		if polls < 5 {
+			fmt.Fprint(w, `{"total_count": 1, "check_runs": [{"id": 1, "name": "ci", "status": "in_progress"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"total_count": 1, "check_runs": [{"id": 1, "name": "ci", "status": "completed", "conclusion": "success"}]}`)
+	})
+
+	conclusion, err := client.WaitForWorkflows(time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conclusion != "success" {
+		t.Errorf("expected success, got %q", conclusion)
+	}
+	// Each poll lists the check runs twice, so the checks are seen completed on the third poll.
+	if clock.Sleeps() != 2 {
+		t.Errorf("expected 2 poll sleeps, got %d sleeps", clock.Sleeps())
+	}
+}
+
+// TestWaitForWorkflowsFreshCI verifies that check runs started before the time set with
+// SetCISince are ignored, so that an earlier green run of the same commit is not accepted.
+func TestWaitForWorkflowsFreshCI(t *testing.T) {
+	polls := 0
+	client, clock := newWorkflowClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		polls++
+		old := `{"id": 1, "name": "ci", "status": "completed", "conclusion": "success",
+			"started_at": "2024-12-31T12:00:00Z"}`
+		switch {
+		case polls <= 2:
+			fmt.Fprintf(w, `{"total_count": 1, "check_runs": [%s]}`, old)
+		case polls <= 4:
+			fmt.Fprintf(w, `{"total_count": 2, "check_runs": [{"id": 2, "name": "ci", "status": "in_progress",
+				"started_at": "2025-01-01T00:00:10Z"}, %s]}`, old)
+		default:
+			fmt.Fprintf(w, `{"total_count": 2, "check_runs": [{"id": 2, "name": "ci", "status": "completed",
+				"conclusion": "failure", "started_at": "2025-01-01T00:00:10Z"}, %s]}`, old)
+		}
+	})
+	client.SetCISince(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	conclusion, err := client.WaitForWorkflows(time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conclusion != "failure" {
+		t.Errorf("expected the new run's failure, got %q", conclusion)
+	}
+	// Polls seeing only the old run list the check runs once, the other ones twice.
+	if clock.Sleeps() != 3 {
+		t.Errorf("expected 3 poll sleeps, got %d sleeps", clock.Sleeps())
+	}
+}
+
+// TestWaitForWorkflowsFreshCIQueued verifies that a queued check run, which has no
+// start time yet, is waited for instead of being taken for a stale one (and for no
+// check started once the start timeout elapses).
+func TestWaitForWorkflowsFreshCIQueued(t *testing.T) {
+	polls := 0
+	client, _ := newWorkflowClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		polls++
+		if polls <= 20 {
+			fmt.Fprint(w, `{"total_count": 1, "check_runs": [{"id": 1, "name": "ci", "status": "queued",
+				"app": {"slug": "circleci"}}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"total_count": 1, "check_runs": [{"id": 1, "name": "ci", "status": "completed",
+			"conclusion": "failure", "started_at": "2025-01-01T00:00:10Z", "app": {"slug": "circleci"}}]}`)
+	})
+	client.SetCISince(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	client.SetStartTimeout(15 * time.Second)
+
+	conclusion, err := client.WaitForWorkflows(time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conclusion != "failure" {
+		t.Errorf("expected the queued run's failure, got %q", conclusion)
+	}
+}
+
+// TestWaitForWorkflowsFreshCIStartTimeout verifies that only stale check runs count as
+// no check started, so that the start timeout still applies.
+func TestWaitForWorkflowsFreshCIStartTimeout(t *testing.T) {
+	client, _ := newWorkflowClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"total_count": 1, "check_runs": [{"id": 1, "name": "ci", "status": "completed",
+			"conclusion": "success", "started_at": "2024-12-31T12:00:00Z"}]}`)
+	})
+	client.SetCISince(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	client.SetStartTimeout(30 * time.Second)
+
+	_, err := client.WaitForWorkflows(time.Hour)
+	if !errors.Is(err, ghpkg.ErrNoWorkflow) {
+		t.Fatalf("expected ErrNoWorkflow, got %v", err)
+	}
+}
+
+// TestAddPullRequestToProject verifies that the project is resolved from its number
+// and the pull request added to it, and that access errors are recognized.
+func TestAddPullRequestToProject(t *testing.T) {
+	tests := []struct {
+		name        string
+		projectResp string
+		expectErr   error
+		expectAdded bool
+	}{
+		{
+			name:        "added",
+			projectResp: `{"data": {"repositoryOwner": {"projectV2": {"id": "PVT_kw1"}}}}`,
+			expectAdded: true,
+		},
+		{
+			name:        "unknown project",
+			projectResp: `{"data": {"repositoryOwner": {"projectV2": null}}}`,
+			expectErr:   ghpkg.ErrProjectNotFound,
+		},
+		{
+			name: "missing project scope",
+			projectResp: `{"data": null, "errors": [{"type": "INSUFFICIENT_SCOPES",
+				"message": "Your token has not been granted the required scopes"}]}`,
+			expectErr: ghpkg.ErrProjectAccess,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var added map[string]any
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /repos/owner/repo/pulls/5", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `{"number": 5, "node_id": "PR_kw5"}`)
+			})
+			mux.HandleFunc("POST /graphql", func(w http.ResponseWriter, r *http.Request) {
+				var payload struct {
+					Query     string         `json:"query"`
+					Variables map[string]any `json:"variables"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+					t.Errorf("failed to decode request: %v", err)
+				}
+				if strings.Contains(payload.Query, "addProjectV2ItemById") {
+					added = payload.Variables
+					fmt.Fprint(w, `{"data": {"addProjectV2ItemById": {"item": {"id": "PVTI_1"}}}}`)
+					return
+				}
+				if payload.Variables["owner"] != "owner" || payload.Variables["number"] != float64(3) {
+					t.Errorf("unexpected project lookup variables: %v", payload.Variables)
+				}
+				fmt.Fprint(w, tt.projectResp)
+			})
+			client := newServerClient(t, mux)
+
+			err := client.AddPullRequestToProject(5, 3)
+			if tt.expectErr != nil {
+				if !errors.Is(err, tt.expectErr) {
+					t.Fatalf("expected %v, got %v", tt.expectErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.expectAdded != (added != nil) {
+				t.Fatalf("expected added=%v, got variables %v", tt.expectAdded, added)
+			}
+			if added != nil && (added["project"] != "PVT_kw1" || added["content"] != "PR_kw5") {
+				t.Errorf("unexpected mutation variables: %v", added)
+			}
+		})
+	}
+}
+
+// TestWaitForWorkflowsFetchesJobsConcurrently verifies that the job pages of all workflow
+// runs are fetched concurrently within the API concurrency bound, and reported in order.
+func TestWaitForWorkflowsFetchesJobsConcurrently(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	// Run 1 has three pages of two jobs, run 2 a single page.
+	pages := map[string][]int{
+		"1/1": {1, 2}, "1/2": {3, 4}, "1/3": {5, 6},
+		"2/1": {7},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/repo/pulls", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[{"number": 5, "head": {"sha": "abc"}}]`)
+	})
+	mux.HandleFunc("GET /repos/owner/repo/actions/runs", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"total_count": 2, "workflow_runs": [{"id": 1}, {"id": 2}]}`)
+	})
+	mux.HandleFunc("GET /repos/owner/repo/commits/abc/check-runs", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"total_count": 1, "check_runs": [{"id": 100, "name": "ci", "status": "in_progress"}]}`)
+	})
+	mux.HandleFunc("GET /repos/owner/repo/actions/runs/{run}/jobs", func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			highest := maxInFlight.Load()
+			if current <= highest || maxInFlight.CompareAndSwap(highest, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		run, page := r.PathValue("run"), r.URL.Query().Get("page")
+		if run == "1" && page == "1" {
+			w.Header().Set("Link", `<https://api.github.com/repositories/1/actions/runs/1/jobs?page=2>; rel="next", `+
+				`<https://api.github.com/repositories/1/actions/runs/1/jobs?page=3>; rel="last"`)
+		}
+		jobs := make([]string, 0, 2)
+		for _, id := range pages[run+"/"+page] {
+			jobs = append(jobs, fmt.Sprintf(`{"id": %d, "name": "job%d", "status": "completed", "conclusion": "success"}`, id, id))
+		}
+		fmt.Fprintf(w, `{"total_count": 7, "jobs": [%s]}`, strings.Join(jobs, ", "))
+	})
+	client := newServerClient(t, mux)
+	if _, err := client.GetPullRequestByBranch("feature", "main"); err != nil {
+		t.Fatalf("failed to get pull request: %v", err)
+	}
+	client.SetClock(timeutil.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)))
+	client.SetAPIConcurrency(2)
+	var seen []int64
+	client.SetTransitionHook(func(tr ghpkg.Transition) {
+		seen = append(seen, tr.JobID)
+	})
+
+	conclusion, err := client.WaitForWorkflows(time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conclusion != "success" {
+		t.Errorf("expected success, got %q", conclusion)
+	}
+	if got := maxInFlight.Load(); got != 2 {
+		t.Errorf("expected job pages fetched 2 at a time, got at most %d at once", got)
+	}
+	if fmt.Sprint(seen) != "[1 2 3 4 5 6 7]" {
+		t.Errorf("expected jobs reported in run and page order, got %v", seen)
+	}
+}
+
+// TestWaitForWorkflowsExternalChecks verifies that the check runs of external CI apps
+// are waited for along with the workflow jobs, and gate the conclusion.
+func TestWaitForWorkflowsExternalChecks(t *testing.T) {
+	polls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/repo/pulls", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[{"number": 5, "head": {"sha": "abc"}}]`)
+	})
+	mux.HandleFunc("GET /repos/owner/repo/actions/runs", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"total_count": 1, "workflow_runs": [{"id": 1}]}`)
+	})
+	mux.HandleFunc("GET /repos/owner/repo/actions/runs/1/jobs", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"total_count": 1, "jobs": [{"id": 10, "name": "build", "status": "completed",
+			"conclusion": "success"}]}`)
+	})
+	mux.HandleFunc("GET /repos/owner/repo/commits/abc/check-runs", func(w http.ResponseWriter, _ *http.Request) {
+		polls++
+		actions := `{"id": 10, "name": "build", "status": "completed", "conclusion": "success",
+			"app": {"slug": "github-actions"}}`
+		circleci := `{"id": 20, "name": "ci/circleci: test", "status": "in_progress", "app": {"slug": "circleci-checks"}}`
+		if polls > 2 {
+			circleci = `{"id": 20, "name": "ci/circleci: test", "status": "completed", "conclusion": "failure",
+				"app": {"slug": "circleci-checks"}}`
+		}
+		fmt.Fprintf(w, `{"total_count": 2, "check_runs": [%s, %s]}`, actions, circleci)
+	})
+	client := newServerClient(t, mux)
+	if _, err := client.GetPullRequestByBranch("feature", "main"); err != nil {
+		t.Fatalf("failed to get pull request: %v", err)
+	}
+	clock := timeutil.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	client.SetClock(clock)
+	var tracked []int64
+	client.SetTransitionHook(func(tr ghpkg.Transition) {
+		tracked = append(tracked, tr.JobID)
+	})
+
+	conclusion, err := client.WaitForWorkflows(time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conclusion != "failure" {
+		t.Errorf("expected the external check's failure, got %q", conclusion)
+	}
+	if !slices.Contains(tracked, 20) {
+		t.Errorf("expected the external check to be tracked, got transitions for %v", tracked)
+	}
+	if clock.Sleeps() != 2 {
+		t.Errorf("expected 2 poll sleeps, got %d sleeps", clock.Sleeps())
+	}
+}
+
+// TestWaitForWorkflowsSkippedAndNeutral verifies that workflows whose checks were all
+// skipped or neutral succeed, while checks waiting for an approval are still running.
+func TestWaitForWorkflowsSkippedAndNeutral(t *testing.T) {
+	tests := []struct {
+		name        string
+		first       string // check runs on the first poll, then all succeed
+		expectSleep int
+	}{
+		{
+			name: "all skipped",
+			first: `[{"id": 1, "name": "lint", "status": "completed", "conclusion": "skipped"},
+				{"id": 2, "name": "test", "status": "completed", "conclusion": "skipped"}]`,
+			expectSleep: 0,
+		},
+		{
+			name: "neutral and skipped",
+			first: `[{"id": 1, "name": "lint", "status": "completed", "conclusion": "neutral"},
+				{"id": 2, "name": "deploy", "status": "completed", "conclusion": "skipped"}]`,
+			expectSleep: 0,
+		},
+		{
+			name:        "waiting for approval",
+			first:       `[{"id": 1, "name": "deploy", "status": "waiting"}]`,
+			expectSleep: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := 0
+			client, clock := newWorkflowClient(t, func(w http.ResponseWriter, _ *http.Request) {
+				polls++
+				checkRuns := tt.first
+				if polls > 2 { // each poll lists the check runs twice
+					checkRuns = `[{"id": 1, "name": "deploy", "status": "completed", "conclusion": "success"}]`
+				}
+				fmt.Fprintf(w, `{"total_count": 1, "check_runs": %s}`, checkRuns)
+			})
+
+			conclusion, err := client.WaitForWorkflows(time.Hour)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if conclusion != "success" {
+				t.Errorf("expected success, got %q", conclusion)
+			}
+			if clock.Sleeps() != tt.expectSleep {
+				t.Errorf("expected %d sleeps, got %d", tt.expectSleep, clock.Sleeps())
+			}
+		})
+	}
+}
+
+// TestWaitForDeployments verifies that only the deployments of the ref created since
+// the merge are followed, until they finish or the timeout.
+func TestWaitForDeployments(t *testing.T) {
+	tests := []struct {
+		name      string
+		states    []string // state of the new deployment at each poll ("" for no status yet)
+		expect    string
+		wantError error
+	}{
+		{"success", []string{"", "in_progress", "success"}, "success", nil},
+		{"failure", []string{"queued", "failure"}, "failure", nil},
+		{"timeout", []string{"in_progress"}, "", ghpkg.ErrDeploymentTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := 0
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /repos/owner/repo/deployments", func(w http.ResponseWriter, r *http.Request) {
+				if got := r.URL.Query().Get("ref"); got != "main" {
+					t.Errorf("expected ref=main, got %q", got)
+				}
+				fmt.Fprint(w, `[{"id": 2, "environment": "production", "created_at": "2025-01-01T00:00:10Z"},
+					{"id": 1, "environment": "production", "created_at": "2024-12-31T00:00:00Z"}]`)
+			})
+			mux.HandleFunc("GET /repos/owner/repo/deployments/1/statuses", func(_ http.ResponseWriter, _ *http.Request) {
+				t.Error("deployment created before the merge should be ignored")
+			})
+			mux.HandleFunc("GET /repos/owner/repo/deployments/2/statuses", func(w http.ResponseWriter, _ *http.Request) {
+				state := tt.states[min(polls, len(tt.states)-1)]
+				polls++
+				if state == "" {
+					fmt.Fprint(w, `[]`)
+					return
+				}
+				fmt.Fprintf(w, `[{"state": %q}]`, state)
+			})
+			client := newServerClient(t, mux)
+			since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			client.SetClock(timeutil.NewFakeClock(since))
+
+			state, err := client.WaitForDeployments("main", since, time.Minute)
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Fatalf("expected %v, got %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if state != tt.expect {
+				t.Errorf("expected %q, got %q", tt.expect, state)
+			}
+			if polls != len(tt.states) {
+				t.Errorf("expected %d polls, got %d", len(tt.states), polls)
+			}
+		})
+	}
+}
+
+// TestGetPullRequestByBranchDuplicates verifies that the newest of several open PRs is used
+// and the others are reported as duplicates.
+func TestGetPullRequestByBranchDuplicates(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/repo/pulls", func(w http.ResponseWriter, r *http.Request) {
+		if query := r.URL.Query(); query.Get("sort") != "created" || query.Get("direction") != "desc" {
+			t.Errorf("expected newest first, got %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `[{"number": 7, "head": {"sha": "def"}}, {"number": 5, "head": {"sha": "abc"}}]`)
+	})
+	client := newServerClient(t, mux)
+
+	pr, err := client.GetPullRequestByBranch("feature", "main")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pr.GetNumber() != 7 {
+		t.Errorf("expected PR 7, got %d", pr.GetNumber())
+	}
+	if duplicates := client.DuplicatePullRequests(); !slices.Equal(duplicates, []int{5}) {
+		t.Errorf("expected duplicates [5], got %v", duplicates)
+	}
+}
+
+// TestSetUserAgent verifies that API requests carry the configured User-Agent.
+func TestSetUserAgent(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/repo/pulls", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.UserAgent(); got != "auto-mr/1.2.3" {
+			t.Errorf("expected User-Agent auto-mr/1.2.3, got %q", got)
+		}
+		fmt.Fprint(w, `[]`)
+	})
+	client := newServerClient(t, mux)
+	client.SetUserAgent("auto-mr/1.2.3")
+
+	if _, err := client.GetPullRequestsByHead("feature"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// TestWaitForWorkflowsIgnoredChecks verifies that ignored check runs neither fail nor hold up the wait.
+func TestWaitForWorkflowsIgnoredChecks(t *testing.T) {
+	client, _ := newWorkflowClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"total_count": 3, "check_runs": [
+			{"id": 1, "name": "ci", "status": "completed", "conclusion": "success"},
+			{"id": 2, "name": "flaky-e2e", "status": "completed", "conclusion": "failure"},
+			{"id": 3, "name": "flaky-perf", "status": "in_progress"}]}`)
+	})
+	client.SetIgnoredChecks([]string{"flaky-*"})
+
+	conclusion, err := client.WaitForWorkflows(time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conclusion != "success" {
+		t.Errorf("expected success, got %q", conclusion)
+	}
+}
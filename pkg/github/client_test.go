@@ -42,7 +42,7 @@ func TestNewClientWhitespaceTokenTrimmed(t *testing.T) {
 		}
 	}()
 
-	_, err := ghpkg.NewClient()
+	_, err := ghpkg.NewClient(nil)
 	if !errors.Is(err, ghpkg.ErrTokenRequired) {
 		t.Errorf("expected ErrTokenRequired for whitespace-only token, got: %v", err)
 	}
@@ -327,7 +327,7 @@ func TestMergePullRequest(t *testing.T) {
 		t.Run("merge with "+strategy.method, func(t *testing.T) {
 			mockAPI := mocks.NewGitHubAPIClient()
 
-			err := mockAPI.MergePullRequest(123, strategy.method, "Test commit")
+			err := mockAPI.MergePullRequest(123, strategy.method, "Test commit", "")
 			if err != nil {
 				t.Fatalf("Unexpected error: %v", err)
 			}
@@ -348,7 +348,7 @@ func TestMergePullRequest(t *testing.T) {
 		mockAPI := mocks.NewGitHubAPIClient()
 		mockAPI.MergePullRequestError = ghpkg.ErrInvalidURLFormat
 
-		err := mockAPI.MergePullRequest(123, "merge", "Test commit")
+		err := mockAPI.MergePullRequest(123, "merge", "Test commit", "")
 		if err == nil {
 			t.Error("Expected merge error")
 		}
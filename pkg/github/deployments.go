@@ -0,0 +1,117 @@
+package github
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/sgaunet/auto-mr/internal/timeutil"
+)
+
+// Deployment states, see https://docs.github.com/rest/deployments/statuses.
+const (
+	deploymentStateSuccess  = "success"
+	deploymentStateInactive = "inactive"
+	deploymentStateFailure  = "failure"
+	deploymentStateError    = "error"
+	deploymentStatePending  = "pending"
+)
+
+// WaitForDeployments waits for the deployments of ref created at or after since,
+// typically the ones triggered by merging a pull request into ref, to finish.
+// Deployments are matched by ref as GitHub Actions creates them for the branch
+// that triggered the workflow.
+//
+// Returns "success" once every deployment succeeded, or the state of the first
+// one that failed ("failure" or "error").
+// Returns [ErrDeploymentTimeout] if no deployment appeared, or one was still
+// running, when the timeout is exceeded.
+func (c *Client) WaitForDeployments(ref string, since time.Time, timeout time.Duration) (string, error) {
+	c.log.Debug(fmt.Sprintf("Waiting for deployments of %s since %s, timeout: %v",
+		ref, since.Format(time.RFC3339), timeout))
+	c.display.Info("Waiting for deployments of " + ref + "...")
+	c.display.IncreasePadding()
+	defer c.display.DecreasePadding()
+
+	start := c.clock.Now()
+	reported := make(map[int64]string)
+	for {
+		deployments, err := c.listDeploymentsSince(ref, since)
+		if err != nil {
+			return "", err
+		}
+
+		done := len(deployments) > 0
+		for _, deployment := range deployments {
+			state, err := c.deploymentState(deployment.GetID())
+			if err != nil {
+				return "", err
+			}
+			if reported[deployment.GetID()] != state {
+				reported[deployment.GetID()] = state
+				c.display.Info(fmt.Sprintf("Deployment to %s: %s", deployment.GetEnvironment(), state))
+			}
+
+			switch state {
+			case deploymentStateSuccess, deploymentStateInactive:
+			case deploymentStateFailure, deploymentStateError:
+				c.display.Error(fmt.Sprintf("Deployment to %s failed - total time: %s",
+					deployment.GetEnvironment(), timeutil.FormatDuration(c.clock.Since(start))))
+				return state, nil
+			default:
+				done = false
+			}
+		}
+
+		if done {
+			c.display.Success("Deployments completed successfully - total time: " +
+				timeutil.FormatDuration(c.clock.Since(start)))
+			return deploymentStateSuccess, nil
+		}
+
+		if c.clock.Since(start)+checkPollInterval > timeout {
+			c.display.Error("Timeout after " + timeutil.FormatDuration(c.clock.Since(start)))
+			if len(deployments) == 0 {
+				return "", fmt.Errorf("%w: no deployment of %s started", errDeploymentTimeout, ref)
+			}
+			return "", fmt.Errorf("%w: deployment of %s still in progress", errDeploymentTimeout, ref)
+		}
+		c.clock.Sleep(checkPollInterval)
+	}
+}
+
+// listDeploymentsSince returns the deployments of ref created at or after since.
+// Only the most recent page is fetched: GitHub lists deployments newest first.
+func (c *Client) listDeploymentsSince(ref string, since time.Time) ([]*github.Deployment, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+	deployments, _, err := c.client.Repositories.ListDeployments(ctx, c.owner, c.repo,
+		&github.DeploymentsListOptions{Ref: ref, ListOptions: github.ListOptions{PerPage: maxDeploymentsPerPage}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+
+	recent := make([]*github.Deployment, 0, len(deployments))
+	for _, deployment := range deployments {
+		if !deployment.GetCreatedAt().Before(since) {
+			recent = append(recent, deployment)
+		}
+	}
+	return recent, nil
+}
+
+// deploymentState returns the state of the latest status of a deployment,
+// "pending" when it has none yet.
+func (c *Client) deploymentState(deploymentID int64) (string, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+	statuses, _, err := c.client.Repositories.ListDeploymentStatuses(ctx, c.owner, c.repo, deploymentID,
+		&github.ListOptions{PerPage: 1})
+	if err != nil {
+		return "", fmt.Errorf("failed to list deployment statuses: %w", err)
+	}
+	if len(statuses) == 0 {
+		return deploymentStatePending, nil
+	}
+	return statuses[0].GetState(), nil
+}
@@ -1,6 +1,9 @@
 package github_test
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
 	"strings"
 	"testing"
 	"time"
@@ -195,7 +198,7 @@ func TestEdgeCaseBoundaryValues(t *testing.T) {
 		mockAPI := mocks.NewGitHubAPIClient()
 
 		// PR number 0 might be treated as invalid
-		err := mockAPI.MergePullRequest(0, "squash", "Test commit")
+		err := mockAPI.MergePullRequest(0, "squash", "Test commit", "")
 		// Behavior depends on implementation - just verify it's handled
 		_ = err
 	})
@@ -204,7 +207,7 @@ func TestEdgeCaseBoundaryValues(t *testing.T) {
 		mockAPI := mocks.NewGitHubAPIClient()
 
 		// Negative PR number should be invalid
-		err := mockAPI.MergePullRequest(-1, "squash", "Test commit")
+		err := mockAPI.MergePullRequest(-1, "squash", "Test commit", "")
 		// Behavior depends on implementation - just verify it's handled
 		_ = err
 	})
@@ -276,40 +279,58 @@ func TestEdgeCaseMaximumLimits(t *testing.T) {
 	})
 }
 
-// TestEdgeCaseURLVariations tests various URL format variations.
+// TestEdgeCaseURLVariations verifies the owner and repository extracted from
+// various remote URL formats by SetRepositoryFromURL.
 func TestEdgeCaseURLVariations(t *testing.T) {
 	urlVariations := []struct {
-		name    string
-		url     string
-		wantErr bool
+		name      string
+		url       string
+		wantOwner string
+		wantRepo  string
+		wantErr   bool
 	}{
-		{"HTTPS with www", "https://www.github.com/owner/repo.git", false},
-		{"HTTPS without www", "https://github.com/owner/repo.git", false},
-		{"SSH git@ format", "git@github.com:owner/repo.git", false},
-		{"SSH with ssh://", "ssh://git@github.com/owner/repo.git", false},
-		{"Mixed case domain", "https://GitHub.com/owner/repo.git", false},
-		{"Trailing slash", "https://github.com/owner/repo/", false},
-		{"Multiple dots", "https://github.com/owner/repo.name.git", false},
-		{"Hyphens in names", "https://github.com/owner-name/repo-name.git", false},
-		{"Underscores", "https://github.com/owner_name/repo_name.git", false},
-		{"Numbers", "https://github.com/owner123/repo456.git", false},
-		{"Single char names", "https://github.com/a/b.git", false},
-		{"No protocol", "github.com/owner/repo.git", true},
-		{"Invalid protocol", "ftp://github.com/owner/repo.git", true},
-		{"Missing repo", "https://github.com/owner/.git", true},
-		{"Extra slashes", "https://github.com//owner//repo.git", false},
+		{"HTTPS with www", "https://www.github.com/owner/repo.git", "owner", "repo", false},
+		{"HTTPS without www", "https://github.com/owner/repo.git", "owner", "repo", false},
+		{"SSH git@ format", "git@github.com:owner/repo.git", "owner", "repo", false},
+		{"SSH with ssh://", "ssh://git@github.com/owner/repo.git", "owner", "repo", false},
+		{"Mixed case domain", "https://GitHub.com/owner/repo.git", "owner", "repo", false},
+		{"Trailing slash", "https://github.com/owner/repo/", "owner", "repo", false},
+		{"Suffix and trailing slash", "https://github.com/owner/repo.git/", "owner", "repo", false},
+		{"Multiple dots", "https://github.com/owner/repo.name.git", "owner", "repo.name", false},
+		{"Git inside the name", "https://github.com/owner/owner.github.io", "owner", "owner.github.io", false},
+		{"Hyphens in names", "https://github.com/owner-name/repo-name.git", "owner-name", "repo-name", false},
+		{"Underscores", "https://github.com/owner_name/repo_name.git", "owner_name", "repo_name", false},
+		{"Numbers", "https://github.com/owner123/repo456.git", "owner123", "repo456", false},
+		{"Single char names", "https://github.com/a/b.git", "a", "b", false},
+		{"No protocol", "github.com/owner/repo.git", "", "", true},
+		{"Invalid protocol", "ftp://github.com/owner/repo.git", "", "", true},
+		{"Missing repo", "https://github.com/owner/.git", "", "", true},
+		{"Extra slashes", "https://github.com//owner//repo.git", "owner", "repo", false},
 	}
 
+	var gotOwner, gotRepo string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/{owner}/{repo}", func(w http.ResponseWriter, r *http.Request) {
+		gotOwner, gotRepo = r.PathValue("owner"), r.PathValue("repo")
+		fmt.Fprint(w, `{"name": "repo"}`)
+	})
+	client := newServerClient(t, mux)
+
 	for _, tc := range urlVariations {
 		t.Run(tc.name, func(t *testing.T) {
-			mockAPI := mocks.NewGitHubAPIClient()
+			gotOwner, gotRepo = "", ""
+			err := client.SetRepositoryFromURL(tc.url)
 			if tc.wantErr {
-				mockAPI.SetRepositoryFromURLError = ghpkg.ErrInvalidURLFormat
+				if !errors.Is(err, ghpkg.ErrInvalidURLFormat) {
+					t.Errorf("URL %s: expected ErrInvalidURLFormat, got %v", tc.url, err)
+				}
+				return
 			}
-
-			err := mockAPI.SetRepositoryFromURL(tc.url)
-			if (err != nil) != tc.wantErr {
-				t.Errorf("URL %s: error = %v, wantErr %v", tc.url, err, tc.wantErr)
+			if err != nil {
+				t.Fatalf("URL %s: unexpected error: %v", tc.url, err)
+			}
+			if gotOwner != tc.wantOwner || gotRepo != tc.wantRepo {
+				t.Errorf("URL %s: got %s/%s, want %s/%s", tc.url, gotOwner, gotRepo, tc.wantOwner, tc.wantRepo)
 			}
 		})
 	}
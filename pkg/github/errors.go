@@ -7,8 +7,20 @@ var (
 	errTokenRequired    = errors.New("GITHUB_TOKEN environment variable is required")
 	errInvalidURLFormat = errors.New("invalid GitHub URL format")
 	errWorkflowTimeout  = errors.New("timeout waiting for workflow completion")
+	errNoWorkflow       = errors.New("no workflow started")
 	errPRNotFound       = errors.New("no pull request found for branch")
 	errPRAlreadyExists  = errors.New("pull request already exists for this branch")
+	errPRConflict       = errors.New("pull request has conflicts with the base branch")
+	errPRIncomplete     = errors.New("pull request created but not fully configured")
+	errAssigneeNotFound = errors.New("failed to find assignee user")
+	errReviewerNotFound = errors.New("failed to find reviewer user")
+	errGraphQL          = errors.New("GitHub GraphQL request failed")
+	errProjectNotFound  = errors.New("project not found")
+	errProjectAccess    = errors.New("token cannot access GitHub projects")
+	errGraphQLForbidden = errors.New("access denied")
+
+	errDeploymentTimeout   = errors.New("timeout waiting for deployment completion")
+	errMergeabilityTimeout = errors.New("timeout waiting for GitHub to compute pull request mergeability")
 
 	// ErrTokenRequired is returned when GITHUB_TOKEN environment variable is missing.
 	ErrTokenRequired = errTokenRequired
@@ -16,8 +28,30 @@ var (
 	ErrInvalidURLFormat = errInvalidURLFormat
 	// ErrWorkflowTimeout is returned when waiting for workflow completion times out.
 	ErrWorkflowTimeout = errWorkflowTimeout
+	// ErrNoWorkflow is returned when no workflow started within the start timeout.
+	ErrNoWorkflow = errNoWorkflow
 	// ErrPRNotFound is returned when no pull request is found for the branch.
 	ErrPRNotFound = errPRNotFound
 	// ErrPRAlreadyExists is returned when a pull request already exists for the branch.
 	ErrPRAlreadyExists = errPRAlreadyExists
+	// ErrPRConflict is returned when a pull request cannot be merged because of conflicts.
+	ErrPRConflict = errPRConflict
+	// ErrPRIncomplete is returned when a pull request was created but setting its assignees,
+	// labels or reviewers failed.
+	ErrPRIncomplete = errPRIncomplete
+	// ErrAssigneeNotFound is returned when an assignee is not a GitHub user.
+	ErrAssigneeNotFound = errAssigneeNotFound
+	// ErrReviewerNotFound is returned when a reviewer is not a GitHub user.
+	ErrReviewerNotFound = errReviewerNotFound
+	// ErrGraphQL is returned when a GraphQL API call reports errors.
+	ErrGraphQL = errGraphQL
+	// ErrProjectNotFound is returned when the repository owner has no project with the given number.
+	ErrProjectNotFound = errProjectNotFound
+	// ErrProjectAccess is returned when the token is not allowed to access projects.
+	ErrProjectAccess = errProjectAccess
+	// ErrDeploymentTimeout is returned when waiting for deployments times out.
+	ErrDeploymentTimeout = errDeploymentTimeout
+	// ErrMergeabilityTimeout is returned when GitHub does not compute the mergeability of a
+	// pull request in time.
+	ErrMergeabilityTimeout = errMergeabilityTimeout
 )
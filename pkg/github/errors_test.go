@@ -121,6 +121,34 @@ func TestErrorPRNotFound(t *testing.T) {
 	})
 }
 
+// TestErrorPRConflict tests conflict detection before merging.
+func TestErrorPRConflict(t *testing.T) {
+	t.Run("conflicting PR is not merged", func(t *testing.T) {
+		mockAPI := mocks.NewGitHubAPIClient()
+		mockAPI.CheckMergeableError = fmt.Errorf("%w: #42", ghpkg.ErrPRConflict)
+
+		err := mockAPI.CheckMergeable(42)
+		if !errors.Is(err, ghpkg.ErrPRConflict) {
+			t.Errorf("Expected ErrPRConflict, got: %v", err)
+		}
+		if mockAPI.GetCallCount("MergePullRequest") != 0 {
+			t.Error("MergePullRequest should not be called")
+		}
+	})
+
+	t.Run("mergeable PR", func(t *testing.T) {
+		mockAPI := mocks.NewGitHubAPIClient()
+
+		if err := mockAPI.CheckMergeable(42); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		lastCall := mockAPI.GetLastCall("CheckMergeable")
+		if lastCall == nil || lastCall.Args["prNumber"] != 42 {
+			t.Errorf("Expected CheckMergeable call with prNumber 42, got %v", lastCall)
+		}
+	})
+}
+
 // TestErrorPRAlreadyExists tests PR already exists error detection.
 func TestErrorPRAlreadyExists(t *testing.T) {
 	scenarios := []struct {
@@ -316,7 +344,7 @@ func TestErrorAPIFailures(t *testing.T) {
 		mockAPI := mocks.NewGitHubAPIClient()
 		mockAPI.MergePullRequestError = errors.New("405 Method Not Allowed")
 
-		err := mockAPI.MergePullRequest(123, "squash", "Test commit")
+		err := mockAPI.MergePullRequest(123, "squash", "Test commit", "")
 		if err == nil {
 			t.Error("Expected merge error")
 		}
@@ -450,7 +478,7 @@ func TestErrorAuthenticationFailures(t *testing.T) {
 		mockAPI := mocks.NewGitHubAPIClient()
 		mockAPI.MergePullRequestError = errors.New("403 Resource not accessible by integration")
 
-		err := mockAPI.MergePullRequest(123, "squash", "Test commit")
+		err := mockAPI.MergePullRequest(123, "squash", "Test commit", "")
 		if err == nil {
 			t.Error("Expected insufficient permissions error")
 		}
@@ -12,7 +12,7 @@
 //
 // Usage:
 //
-//	client, err := github.NewClient()
+//	client, err := github.NewClient(nil)
 //	client.SetLogger(logger)
 //	client.SetRepositoryFromURL("https://github.com/owner/repo.git")
 //	labels, _ := client.ListLabels()
@@ -26,7 +26,10 @@ package github
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"path"
+	"slices"
 	"strings"
 	"time"
 
@@ -39,14 +42,20 @@ import (
 
 // NewClient creates a new GitHub client authenticated via the GITHUB_TOKEN environment variable.
 //
+// Parameters:
+//   - httpClient: base HTTP client wrapped by the OAuth2 transport (nil uses [http.DefaultClient])
+//
 // Returns [ErrTokenRequired] if GITHUB_TOKEN is not set.
-func NewClient() (*Client, error) {
+func NewClient(httpClient *http.Client) (*Client, error) {
 	token := strings.TrimSpace(os.Getenv("GITHUB_TOKEN"))
 	if token == "" {
 		return nil, errTokenRequired
 	}
 
 	ctx := context.Background()
+	if httpClient != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+	}
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token},
 	)
@@ -54,13 +63,15 @@ func NewClient() (*Client, error) {
 	client := github.NewClient(tc)
 
 	log := logger.NoLogger()
-	updatable := bullets.NewUpdatable(os.Stdout)
+	updatable := bullets.NewUpdatable(logger.Output())
 	display := newDisplayRenderer(log, updatable)
 
 	return &Client{
-		client:  client,
-		log:     log,
-		display: display,
+		client:       client,
+		log:          log,
+		display:      display,
+		clock:        timeutil.RealClock{},
+		startupDelay: workflowCreationDelay,
 	}, nil
 }
 
@@ -71,8 +82,107 @@ func (c *Client) SetLogger(logger *bullets.Logger) {
 	c.log.Debug("GitHub client logger configured")
 }
 
+// SetTransitionHook registers hook to receive every job/check state transition observed
+// while waiting for the workflows, e.g. to feed a dashboard. The hook runs synchronously
+// in the polling loop, so it should return quickly. A nil hook disables it.
+func (c *Client) SetTransitionHook(hook func(Transition)) {
+	c.onTransition = hook
+}
+
+// SetClock replaces the time source of the polling loops (workflow and mergeability waits), so that tests can drive
+// timeouts and polls without real sleeps. A nil clock restores the real one.
+func (c *Client) SetClock(clock timeutil.Clock) {
+	if clock == nil {
+		clock = timeutil.RealClock{}
+	}
+	c.clock = clock
+}
+
+// SetAPIConcurrency limits how many pages of workflow jobs are fetched at once, across all
+// workflow runs, while waiting for the workflows, to avoid bursts of API calls hitting rate limits.
+// Values below 1 restore the default (4).
+func (c *Client) SetAPIConcurrency(n int) {
+	c.apiConcurrency = n
+}
+
+// SetRequestTimeout bounds each API call, so that a stalled connection cannot hang the run.
+// The wait for the workflows as a whole is bounded separately by its own timeout.
+// Values below or equal to zero restore the default (30s).
+func (c *Client) SetRequestTimeout(timeout time.Duration) {
+	c.requestTimeout = timeout
+}
+
+// SetIgnoredChecks makes [Client.WaitForWorkflows] leave out the jobs and check runs whose
+// name matches one of patterns ([path.Match] syntax, e.g. "lint-*"), such as a flaky
+// advisory check: they are still displayed, but neither waited for nor able to fail the wait.
+func (c *Client) SetIgnoredChecks(patterns []string) {
+	c.ignoredChecks = patterns
+}
+
+// ignoredCheck reports whether the job or check run named name matches a pattern of
+// [Client.SetIgnoredChecks].
+func (c *Client) ignoredCheck(name string) bool {
+	return slices.ContainsFunc(c.ignoredChecks, func(pattern string) bool {
+		matched, _ := path.Match(pattern, name)
+		return matched
+	})
+}
+
+// SetUserAgent sets the User-Agent header of API requests, e.g. "auto-mr/1.2.0", so that
+// server admins can attribute the traffic. An empty value keeps the go-github default.
+func (c *Client) SetUserAgent(userAgent string) {
+	if userAgent != "" {
+		c.client.UserAgent = userAgent
+	}
+}
+
+// SetStartupDelay sets how long [Client.WaitForWorkflows] looks for the workflows of
+// the pull request, created a moment after a push, before concluding it has none.
+// The wait starts as soon as they show up. Zero checks once; negative values restore
+// the default (5s).
+func (c *Client) SetStartupDelay(delay time.Duration) {
+	if delay < 0 {
+		delay = workflowCreationDelay
+	}
+	c.startupDelay = delay
+}
+
+// SetStartTimeout bounds how long [Client.WaitForWorkflows] waits for the check runs of
+// the pull request to show up once its workflows exist, e.g. when a misconfigured
+// trigger never starts them. Zero, the default, waits for the whole timeout.
+func (c *Client) SetStartTimeout(timeout time.Duration) {
+	c.startTimeout = timeout
+}
+
+// SetRequireChecks makes [Client.WaitForWorkflows] wait for a check run to start, within
+// the start timeout, when the pull request has no workflow yet instead of proceeding
+// without checks.
+func (c *Client) SetRequireChecks(require bool) {
+	c.requireChecks = require
+}
+
+// concurrency returns the configured fetch concurrency, or the default when unset.
+func (c *Client) concurrency() int {
+	if c.apiConcurrency < 1 {
+		return defaultAPIConcurrency
+	}
+	return c.apiConcurrency
+}
+
+// reportTransitions logs transitions at debug level and passes them to the transition hook.
+func (c *Client) reportTransitions(transitions []Transition) {
+	for _, transition := range transitions {
+		c.log.Debug(transition.String())
+		if c.onTransition != nil {
+			c.onTransition(transition)
+		}
+	}
+}
+
 // WaitForWorkflows waits for all GitHub Actions workflow runs to complete for the pull request.
 // It polls at 5-second intervals and displays real-time job-level progress with animated spinners.
+// When spinners are disabled (non-TTY, --no-spinner, --no-color or JSON output), they become
+// static lines and a progress line is logged every 30 seconds.
 // If no workflows are configured, it returns "success" immediately.
 //
 // Parameters:
@@ -80,55 +190,70 @@ func (c *Client) SetLogger(logger *bullets.Logger) {
 //
 // Returns the overall conclusion ("success", "failure", "cancelled", etc.).
 // Returns [ErrWorkflowTimeout] if the timeout is exceeded.
+// Returns [ErrNoWorkflow] if no check run showed up within the start timeout (see
+// [Client.SetStartTimeout]).
 //
 // A pull request must have been created or fetched before calling this method.
 func (c *Client) WaitForWorkflows(timeout time.Duration) (string, error) {
 	c.log.Debug(fmt.Sprintf("Waiting for workflows, timeout: %v", timeout))
-	start := time.Now()
+	start := c.clock.Now()
 
 	// First check if any workflow runs are expected for this PR
-	if !c.hasWorkflowRuns() {
+	if !c.requireChecks && !c.awaitWorkflowRuns() {
 		c.log.Info("No workflow runs configured for this pull request, proceeding without checks")
 		return conclusionSuccess, nil
 	}
 
 	// Create updatable handle for workflow status
 	c.display.Info("Waiting for workflows to complete...")
-	time.Sleep(workflowCreationDelay) // Let the time to workflows to be created
 	c.display.IncreasePadding()
 	defer c.display.DecreasePadding()
 
 	// Initialize check tracker for managing individual job handles
 	tracker := newCheckTracker()
+	lastStatusLine := start
+	c.announcedRuns = make(map[int64]bool)
+
+	for c.clock.Since(start) < timeout {
+		if !logger.Interactive() && c.clock.Since(lastStatusLine) >= statusLineInterval {
+			c.display.Info("Still waiting for workflows - elapsed: " + timeutil.FormatDuration(c.clock.Since(start)))
+			lastStatusLine = c.clock.Now()
+		}
 
-	for time.Since(start) < timeout {
+		ctx, cancel := c.ctx()
 		checkRuns, _, err := c.client.Checks.ListCheckRunsForRef(
-			c.ctx(), c.owner, c.repo, c.prSHA,
+			ctx, c.owner, c.repo, c.prSHA,
 			&github.ListCheckRunsOptions{
 				ListOptions: github.ListOptions{PerPage: maxCheckRunsPerPage},
 			},
 		)
+		cancel()
 		if err != nil {
 			c.display.Error(fmt.Sprintf("Failed to list check runs: %v", err))
 			return "", fmt.Errorf("failed to list check runs: %w", err)
 		}
 
-		if checkRuns.GetTotal() == 0 {
+		fresh := c.freshCheckRuns(checkRuns.CheckRuns)
+		if len(fresh) == 0 {
+			if c.startTimeout > 0 && c.clock.Since(start) >= c.startTimeout {
+				c.display.Error("No workflow started after " + timeutil.FormatDuration(c.clock.Since(start)))
+				return "", errNoWorkflow
+			}
 			// Wait silently for workflows to appear (they'll show as individual spinners when they start)
-			time.Sleep(checkPollInterval)
+			c.clock.Sleep(checkPollInterval)
 			continue
 		}
 
 		// Try to fetch and display job-level information with check tracker
-		allCompleted, conclusion := c.processWorkflowsWithJobTracking(tracker)
+		allCompleted, conclusion := c.processWorkflowsWithJobTracking(tracker, fresh)
 
 		if !allCompleted {
-			time.Sleep(checkPollInterval)
+			c.clock.Sleep(checkPollInterval)
 			continue
 		}
 
 		// All workflows completed - display final summary
-		totalDuration := time.Since(start)
+		totalDuration := c.clock.Since(start)
 		if conclusion == conclusionSuccess {
 			c.display.Success("Workflows completed successfully - total time: " +
 				timeutil.FormatDuration(totalDuration))
@@ -141,13 +266,15 @@ func (c *Client) WaitForWorkflows(timeout time.Duration) (string, error) {
 		return conclusion, nil
 	}
 
-	totalDuration := time.Since(start)
+	totalDuration := c.clock.Since(start)
 	c.display.Error("Timeout after " + timeutil.FormatDuration(totalDuration))
 	return "", errWorkflowTimeout
 }
 
 // processWorkflowsWithJobTracking processes workflows using checkTracker for individual job display.
-func (c *Client) processWorkflowsWithJobTracking(tracker *checkTracker) (bool, string) {
+// The check runs of other apps than GitHub Actions (e.g. CircleCI or Jenkins through the
+// Checks API) are tracked along with the workflow jobs, so they gate the merge too.
+func (c *Client) processWorkflowsWithJobTracking(tracker *checkTracker, checkRuns []*github.CheckRun) (bool, string) {
 	// Try to fetch workflow jobs
 	jobs, err := c.fetchWorkflowJobs()
 	if err != nil {
@@ -161,38 +288,62 @@ func (c *Client) processWorkflowsWithJobTracking(tracker *checkTracker) (bool, s
 		return c.fallbackToCheckRuns(tracker)
 	}
 
+	jobs = append(jobs, c.convertCheckRunsToJobInfo(externalCheckRuns(c.freshCheckRuns(checkRuns)))...)
+
 	// Update check tracker with new jobs (creates/updates handles automatically)
 	transitions := tracker.update(jobs, c.display.GetUpdatable())
-	for _, transition := range transitions {
-		c.log.Debug(transition)
-	}
+	c.reportTransitions(transitions)
 
 	// Analyze job statuses for completion
 	return c.analyzeJobCompletion(jobs)
 }
 
+// externalCheckRuns returns the check runs created by another app than GitHub Actions,
+// whose jobs are fetched from the workflow runs instead. Check runs without app
+// details are taken for GitHub Actions ones.
+func externalCheckRuns(checkRuns []*github.CheckRun) []*github.CheckRun {
+	external := make([]*github.CheckRun, 0, len(checkRuns))
+	for _, check := range checkRuns {
+		if slug := check.GetApp().GetSlug(); slug != "" && slug != githubActionsAppSlug {
+			external = append(external, check)
+		}
+	}
+	return external
+}
+
 // fallbackToCheckRuns attempts to fall back to check runs API.
 func (c *Client) fallbackToCheckRuns(tracker *checkTracker) (bool, string) {
+	ctx, cancel := c.ctx()
+	defer cancel()
 	checkRuns, _, err := c.client.Checks.ListCheckRunsForRef(
-		c.ctx(), c.owner, c.repo, c.prSHA,
+		ctx, c.owner, c.repo, c.prSHA,
 		&github.ListCheckRunsOptions{
 			ListOptions: github.ListOptions{PerPage: maxCheckRunsPerPage},
 		},
 	)
-	if err == nil && checkRuns.GetTotal() > 0 {
-		return c.processCheckRunsFallback(tracker, checkRuns.CheckRuns)
+	if err != nil {
+		return false, ""
+	}
+	if fresh := c.freshCheckRuns(checkRuns.CheckRuns); len(fresh) > 0 {
+		return c.processCheckRunsFallback(tracker, fresh)
 	}
 	return false, ""
 }
 
 // analyzeJobCompletion checks if all jobs are completed and determines overall conclusion.
+// Skipped and neutral jobs do not affect the conclusion, so workflows made only of them
+// succeed; jobs waiting for a runner or a deployment approval are still running.
+// Ignored jobs (see [Client.SetIgnoredChecks]) are left out.
 func (c *Client) analyzeJobCompletion(jobs []*JobInfo) (bool, string) {
 	allCompleted := true
 	conclusion := conclusionSuccess
 
 	for _, job := range jobs {
+		if c.ignoredCheck(job.Name) {
+			continue
+		}
 		switch job.Status {
-		case statusInProgress, statusQueued:
+		case statusInProgress, statusQueued, statusWaiting, statusPending, statusRequested:
 			allCompleted = false
 		case statusCompleted:
 			if job.Conclusion != conclusionSuccess && job.Conclusion != conclusionSkipped &&
@@ -213,9 +364,7 @@ func (c *Client) processCheckRunsFallback(tracker *checkTracker, checkRuns []*gi
 
 	// Update check tracker with converted jobs (creates/updates spinners automatically)
 	transitions := tracker.update(jobs, c.display.GetUpdatable())
-	for _, transition := range transitions {
-		c.log.Debug(transition)
-	}
+	c.reportTransitions(transitions)
 
 	// Analyze completion status
 	return c.analyzeJobCompletion(jobs)
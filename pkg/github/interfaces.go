@@ -16,6 +16,10 @@ type APIClient interface {
 	// Supports both HTTPS and SSH formats.
 	SetRepositoryFromURL(url string) error
 
+	// SetHeadRepositoryFromURL configures the fork holding pull request branches,
+	// when it differs from the target repository.
+	SetHeadRepositoryFromURL(url string) error
+
 	// ListLabels returns all labels available in the repository.
 	ListLabels() ([]*Label, error)
 
@@ -34,16 +38,25 @@ type APIClient interface {
 	// Returns the overall conclusion (success, failure, etc.) or an error on timeout.
 	WaitForWorkflows(timeout time.Duration) (string, error)
 
+	// CheckMergeable verifies that a pull request has no conflicts with its base branch.
+	// Returns ErrPRConflict if GitHub reports the pull request as conflicting.
+	CheckMergeable(prNumber int) error
+
 	// MergePullRequest merges a pull request using the specified merge method.
 	// mergeMethod can be "merge", "squash", or "rebase".
-	// commitTitle is used as the merge commit message.
-	MergePullRequest(prNumber int, mergeMethod, commitTitle string) error
+	// commitTitle is used as the merge commit title, commitMessage as its message
+	// (empty: commitTitle).
+	MergePullRequest(prNumber int, mergeMethod, commitTitle, commitMessage string) error
 
 	// GetPullRequestsByHead returns all open pull requests for the given head branch.
 	GetPullRequestsByHead(head string) ([]*github.PullRequest, error)
 
 	// DeleteBranch deletes a branch from the remote repository.
 	DeleteBranch(branch string) error
+
+	// CurrentUsername returns the username of the authenticated user.
+	// It is used to resolve the "@me" assignee.
+	CurrentUsername() (string, error)
 }
 
 // StateTracker defines the interface for thread-safe job/check state management.
@@ -51,8 +64,8 @@ type APIClient interface {
 // of state transitions and display handle management without real API calls.
 type StateTracker interface {
 	// update processes new jobs/checks, detects state transitions, and updates handles.
-	// Returns a list of state transition descriptions for logging/debugging.
-	update(newChecks []*JobInfo, logger *bullets.UpdatableLogger) []string
+	// Returns the state transitions detected, for logging and the transition hook.
+	update(newChecks []*JobInfo, logger *bullets.UpdatableLogger) []Transition
 
 	// getCheck retrieves a job/check by ID with read lock.
 	// Returns the JobInfo and a boolean indicating if the check exists.
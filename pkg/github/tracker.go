@@ -2,18 +2,21 @@ package github
 
 import (
 	"context"
-	"fmt"
 	"time"
 
+	"github.com/sgaunet/auto-mr/internal/logger"
 	"github.com/sgaunet/bullets"
 )
 
 // newCheckTracker creates a new check tracker with initialized maps.
+// With plain progress ([logger.PlainProgress]), running checks get static handles
+// reprinted at every poll instead of spinners.
 func newCheckTracker() *checkTracker {
 	return &checkTracker{
 		checks:   make(map[int64]*JobInfo),
 		handles:  make(map[int64]*bullets.BulletHandle),
 		spinners: make(map[int64]*bullets.Spinner),
+		plain:    logger.PlainProgress(),
 	}
 }
 
@@ -73,9 +76,9 @@ func (ct *checkTracker) deleteSpinner(id int64) {
 }
 
 // update processes new jobs/checks, detects state transitions, and updates handles.
-// Returns a list of state transition descriptions.
-func (ct *checkTracker) update(newChecks []*JobInfo, logger *bullets.UpdatableLogger) []string {
-	var transitions []string
+// Returns the state transitions detected.
+func (ct *checkTracker) update(newChecks []*JobInfo, logger *bullets.UpdatableLogger) []Transition {
+	var transitions []Transition
 	newCheckIDs := make(map[int64]bool)
 
 	for _, newCheck := range newChecks {
@@ -84,9 +87,8 @@ func (ct *checkTracker) update(newChecks []*JobInfo, logger *bullets.UpdatableLo
 		}
 
 		newCheckIDs[newCheck.ID] = true
-		transition := ct.processCheckUpdate(newCheck, logger)
-		if transition != "" {
-			transitions = append(transitions, transition)
+		if transition := ct.processCheckUpdate(newCheck, logger); transition != nil {
+			transitions = append(transitions, *transition)
 		}
 	}
 
@@ -97,7 +99,8 @@ func (ct *checkTracker) update(newChecks []*JobInfo, logger *bullets.UpdatableLo
 }
 
 // processCheckUpdate handles the update logic for a single check.
-func (ct *checkTracker) processCheckUpdate(newCheck *JobInfo, logger *bullets.UpdatableLogger) string {
+// Returns nil when the check's status did not change.
+func (ct *checkTracker) processCheckUpdate(newCheck *JobInfo, logger *bullets.UpdatableLogger) *Transition {
 	oldCheck, exists := ct.getCheck(newCheck.ID)
 
 	switch {
@@ -107,16 +110,28 @@ func (ct *checkTracker) processCheckUpdate(newCheck *JobInfo, logger *bullets.Up
 		return ct.handleCheckStatusChange(oldCheck, newCheck, logger)
 	default:
 		ct.setCheck(newCheck.ID, newCheck)
-		return ""
+		ct.reportPlainProgress(newCheck)
+		return nil
+	}
+}
+
+// reportPlainProgress reprints the status line of a running check in plain progress mode.
+// Static handles print a new line on update, so this emits one line per poll.
+func (ct *checkTracker) reportPlainProgress(check *JobInfo) {
+	if !ct.plain || check.Status != statusInProgress {
+		return
+	}
+	if handle, exists := ct.getHandle(check.ID); exists {
+		handle.Update(bullets.InfoLevel, formatJobStatus(check))
 	}
 }
 
 // handleNewCheck processes a newly detected check.
-func (ct *checkTracker) handleNewCheck(newCheck *JobInfo, logger *bullets.UpdatableLogger) string {
+func (ct *checkTracker) handleNewCheck(newCheck *JobInfo, logger *bullets.UpdatableLogger) *Transition {
 	ct.setCheck(newCheck.ID, newCheck)
 	statusText := formatJobStatus(newCheck)
 
-	if newCheck.Status == statusInProgress || newCheck.Status == statusQueued {
+	if !ct.plain && (newCheck.Status == statusInProgress || newCheck.Status == statusQueued) {
 		spinner := logger.SpinnerCircle(context.Background(), statusText)
 		ct.setSpinner(newCheck.ID, spinner)
 		// Start time update loop for any check with spinner that has started timing
@@ -128,30 +143,32 @@ func (ct *checkTracker) handleNewCheck(newCheck *JobInfo, logger *bullets.Updata
 		ct.setHandle(newCheck.ID, handle)
 	}
 
-	return fmt.Sprintf("Job %d started: %s", newCheck.ID, newCheck.Name)
+	return newTransition(nil, newCheck)
 }
 
 // handleCheckStatusChange processes a check with changed status.
 func (ct *checkTracker) handleCheckStatusChange(
 	oldCheck, newCheck *JobInfo, logger *bullets.UpdatableLogger,
-) string {
-	wasPulsing := oldCheck.Status == statusInProgress
-	isPulsing := newCheck.Status == statusInProgress
+) *Transition {
+	wasPulsing := !ct.plain && oldCheck.Status == statusInProgress
+	isPulsing := !ct.plain && newCheck.Status == statusInProgress
 
 	ct.updateHandleForCheck(logger, newCheck, wasPulsing, isPulsing)
 	ct.setCheck(newCheck.ID, newCheck)
-	return ct.formatTransition(oldCheck, newCheck)
+	return newTransition(oldCheck, newCheck)
 }
 
 // detectRemovedChecks detects checks that have been removed.
-func (ct *checkTracker) detectRemovedChecks(newCheckIDs map[int64]bool) []string {
-	var transitions []string
+func (ct *checkTracker) detectRemovedChecks(newCheckIDs map[int64]bool) []Transition {
+	var transitions []Transition
 	ct.mu.RLock()
 	defer ct.mu.RUnlock()
 
-	for id := range ct.checks {
+	for id, check := range ct.checks {
 		if !newCheckIDs[id] {
-			transitions = append(transitions, fmt.Sprintf("Job %d removed", id))
+			removed := newTransition(check, check)
+			removed.NewStatus = ""
+			transitions = append(transitions, *removed)
 		}
 	}
 	return transitions
@@ -162,19 +179,28 @@ func (ct *checkTracker) hasStatusChanged(oldCheck, newCheck *JobInfo) bool {
 	return oldCheck.Status != newCheck.Status || oldCheck.Conclusion != newCheck.Conclusion
 }
 
-// formatTransition creates a transition message for status changes.
-func (ct *checkTracker) formatTransition(oldCheck, newCheck *JobInfo) string {
-	oldState := oldCheck.Status
-	if oldCheck.Status == statusCompleted && oldCheck.Conclusion != "" {
-		oldState = oldCheck.Conclusion
+// newTransition describes newCheck moving from oldCheck's state (nil: first seen).
+func newTransition(oldCheck, newCheck *JobInfo) *Transition {
+	transition := &Transition{
+		JobID:      newCheck.ID,
+		Name:       newCheck.Name,
+		NewStatus:  checkState(newCheck),
+		StartedAt:  newCheck.StartedAt,
+		FinishedAt: newCheck.CompletedAt,
+		ObservedAt: time.Now(),
 	}
-
-	newState := newCheck.Status
-	if newCheck.Status == statusCompleted && newCheck.Conclusion != "" {
-		newState = newCheck.Conclusion
+	if oldCheck != nil {
+		transition.OldStatus = checkState(oldCheck)
 	}
+	return transition
+}
 
-	return fmt.Sprintf("Job %d: %s -> %s", newCheck.ID, oldState, newState)
+// checkState returns the conclusion of completed checks and the status otherwise.
+func checkState(check *JobInfo) string {
+	if check.Status == statusCompleted && check.Conclusion != "" {
+		return check.Conclusion
+	}
+	return check.Status
 }
 
 // updateHandleForCheck updates display based on job status transitions.
@@ -1,10 +1,12 @@
 package github
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/google/go-github/v69/github"
+	"github.com/sgaunet/auto-mr/internal/timeutil"
 	"github.com/sgaunet/bullets"
 )
 
@@ -12,16 +14,30 @@ import (
 const (
 	minURLParts            = 2
 	maxCheckRunsPerPage    = 100
+	maxDeploymentsPerPage  = 30
+	jobsPerPage            = 100
+	maxCollaborators       = 100
 	maxJobDetailsToDisplay = 3
 	checkPollInterval      = 5 * time.Second
 	spinnerUpdateInterval  = 1 * time.Second
-	workflowCreationDelay  = 5 * time.Second
+	statusLineInterval     = 30 * time.Second // periodic progress line when spinners are disabled
+	workflowCreationDelay  = 5 * time.Second  // default startup delay, see Client.SetStartupDelay
+	workflowCreationPoll   = 1 * time.Second  // interval of the checks for workflows during the startup delay
+	mergeabilityTimeout    = 8 * time.Second  // bound of the mergeability poll before merging
+	mergeabilityInterval   = 2 * time.Second
+	defaultAPIConcurrency  = 4 // concurrent workflow job page fetches
+	defaultRequestTimeout  = 30 * time.Second
+	mergeableStateDirty    = "dirty"
 	conclusionSuccess      = "success"
 	statusInProgress       = "in_progress"
 	statusQueued           = "queued"
+	statusWaiting          = "waiting"
+	statusPending          = "pending"
+	statusRequested        = "requested"
 	statusCompleted        = "completed"
 	conclusionSkipped      = "skipped"
 	conclusionNeutral      = "neutral"
+	githubActionsAppSlug   = "github-actions" // app of the check runs of workflow jobs
 )
 
 // Client represents a GitHub API client wrapper that manages pull request
@@ -30,18 +46,35 @@ const (
 //
 // Not safe for concurrent use.
 type Client struct {
-	client  *github.Client
-	owner   string
-	repo    string
-	prNumber int
-	prSHA   string
-	log     *bullets.Logger
-	display *displayRenderer // Display renderer for UI output
+	client              *github.Client
+	owner               string
+	repo                string
+	headOwner           string // Fork owner holding PR branches (empty: same as owner)
+	headRepo            string // Fork repository holding PR branches (empty: same as repo)
+	keepAuthorReviewers bool   // Request reviews from the PR author instead of dropping them
+	prNumber            int
+	prSHA               string
+	duplicates          []int // Other open PRs found by the last GetPullRequestByBranch
+	log                 *bullets.Logger
+	display             *displayRenderer // Display renderer for UI output
+	onTransition        func(Transition) // Optional job transition hook (nil disables it)
+	apiConcurrency      int              // Max concurrent workflow job page fetches (<1: default)
+	requestTimeout      time.Duration    // Per API call timeout (<=0: default)
+	startupDelay        time.Duration    // Longest wait for workflows to be created (0: check once)
+	startTimeout        time.Duration    // Longest wait for a check run to start (0: the whole timeout)
+	requireChecks       bool             // Wait for a check run to start even when the PR has no workflow yet
+	ignoredChecks       []string         // Job/check run name patterns left out of the conclusion
+	announcedRuns       map[int64]bool   // Workflow runs whose URL was printed during the current wait
+	clock               timeutil.Clock   // Time source of the polling loops
+	ciSince             time.Time        // Workflow runs created before are ignored while waiting (zero: none)
+	mergeSHA            string           // Commit created by the last merge (see Client.MergeCommitSHA)
 }
 
 // Label represents a GitHub label.
 type Label struct {
-	Name string
+	Name        string
+	Color       string // Hex color with a leading '#' (e.g. "#d73a4a"), empty if unknown
+	Description string
 }
 
 // JobInfo represents a GitHub workflow job with detailed status information.
@@ -57,10 +90,36 @@ type JobInfo struct {
 	HTMLURL     string     // Browser URL for the job
 }
 
+// Transition describes a job/check state change observed while waiting for workflows.
+// It is passed to the hook registered with [Client.SetTransitionHook].
+// Statuses of completed jobs are their conclusion (e.g. "success", "failure").
+type Transition struct {
+	JobID      int64      // Job/check ID
+	Name       string     // Job/check name
+	OldStatus  string     // Previous status (empty when the job was first seen)
+	NewStatus  string     // Current status (empty when the job left the run)
+	StartedAt  *time.Time // When the job started (nil if queued)
+	FinishedAt *time.Time // When the job finished (nil if still running)
+	ObservedAt time.Time  // When the change was detected
+}
+
+// String returns a one-line description, as logged at debug level.
+func (t Transition) String() string {
+	switch {
+	case t.OldStatus == "":
+		return fmt.Sprintf("Job %d started: %s", t.JobID, t.Name)
+	case t.NewStatus == "":
+		return fmt.Sprintf("Job %d removed", t.JobID)
+	default:
+		return fmt.Sprintf("Job %d: %s -> %s", t.JobID, t.OldStatus, t.NewStatus)
+	}
+}
+
 // checkTracker tracks workflow jobs/checks and their display handles with thread-safe access.
 type checkTracker struct {
 	mu       sync.RWMutex
 	checks   map[int64]*JobInfo
 	handles  map[int64]*bullets.BulletHandle
 	spinners map[int64]*bullets.Spinner // Spinners for running jobs
+	plain    bool                       // --progress=plain: handles printed at every poll instead of spinners
 }
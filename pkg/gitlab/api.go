@@ -1,43 +1,67 @@
 package gitlab
 
 import (
+	"cmp"
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"path"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/sgaunet/auto-mr/internal/draft"
 	"github.com/sgaunet/auto-mr/internal/logger"
 	"github.com/sgaunet/auto-mr/internal/timeutil"
 	"github.com/sgaunet/auto-mr/internal/urlutil"
+	"github.com/sgaunet/auto-mr/internal/userid"
 	"github.com/sgaunet/bullets"
 	gitlab "gitlab.com/gitlab-org/api/client-go"
 )
 
 // NewClient creates a new GitLab client authenticated via the GITLAB_TOKEN environment variable.
+// The optional GITLAB_APPROVAL_PASSWORD is sent along with approvals, for instances that require it.
+// The optional GITLAB_SUDO (username or user ID) makes every API call on behalf of that user,
+// which needs an administrator token with the sudo scope.
+//
+// Parameters:
+//   - httpClient: HTTP client used for API requests (nil uses the library default)
 //
 // Returns [ErrTokenRequired] if GITLAB_TOKEN is not set.
 // Returns a wrapped error if the underlying GitLab client creation fails.
-func NewClient() (*Client, error) {
+func NewClient(httpClient *http.Client) (*Client, error) {
 	token := strings.TrimSpace(os.Getenv("GITLAB_TOKEN"))
 	if token == "" {
 		return nil, errTokenRequired
 	}
 
-	client, err := gitlab.NewClient(token)
+	var opts []gitlab.ClientOptionFunc
+	if httpClient != nil {
+		opts = append(opts, gitlab.WithHTTPClient(httpClient))
+	}
+	if sudo := strings.TrimSpace(os.Getenv("GITLAB_SUDO")); sudo != "" {
+		opts = append(opts, gitlab.WithRequestOptions(gitlab.WithSudo(sudo)))
+	}
+
+	client, err := gitlab.NewClient(token, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GitLab client: %w", err)
 	}
 
 	log := logger.NoLogger()
-	updatable := bullets.NewUpdatable(os.Stdout)
+	updatable := bullets.NewUpdatable(logger.Output())
 
 	return &Client{
 		client:       client,
 		log:          log,
 		updatableLog: updatable,
 		display:      newDisplayRenderer(log, updatable),
+		approvalPass: os.Getenv("GITLAB_APPROVAL_PASSWORD"),
+		clock:        timeutil.RealClock{},
 	}, nil
 }
 
@@ -49,31 +73,140 @@ func (c *Client) SetLogger(logger *bullets.Logger) {
 	c.log.Debug("GitLab client logger configured")
 }
 
+// SetTransitionHook registers hook to receive every job state transition observed
+// while waiting for the pipeline, e.g. to feed a dashboard. The hook runs synchronously
+// in the polling loop, so it should return quickly. A nil hook disables it.
+func (c *Client) SetTransitionHook(hook func(Transition)) {
+	c.onTransition = hook
+}
+
+// SetClock replaces the time source of the polling loops (pipeline, rebase, approval and
+// mergeability waits), so that tests can drive
+// timeouts and polls without real sleeps. A nil clock restores the real one.
+func (c *Client) SetClock(clock timeutil.Clock) {
+	if clock == nil {
+		clock = timeutil.RealClock{}
+	}
+	c.clock = clock
+}
+
+// SetPlayManualJobs makes [Client.WaitForPipeline] start the manual jobs of the merge request
+// pipelines and wait for them, instead of treating them as not blocking.
+func (c *Client) SetPlayManualJobs(play bool) {
+	c.playManual = play
+}
+
+// SetCISince makes [Client.WaitForPipeline] ignore the merge request pipelines created
+// before since, e.g. an earlier run of the same commit, and wait for a newer one.
+// The zero time accepts every pipeline.
+func (c *Client) SetCISince(since time.Time) {
+	c.ciSince = since
+}
+
+// SetStartTimeout bounds how long [Client.WaitForPipeline] waits for a pipeline of the
+// merge request to show up, e.g. when a misconfigured trigger never starts one.
+// Zero, the default, waits for the whole pipeline timeout.
+func (c *Client) SetStartTimeout(timeout time.Duration) {
+	c.startTimeout = timeout
+}
+
+// SetRequireChecks makes [Client.WaitForPipeline] wait for a pipeline to start, within
+// the start timeout, when the commit has none yet instead of proceeding without checks.
+func (c *Client) SetRequireChecks(require bool) {
+	c.requireChecks = require
+}
+
+// SetAPIConcurrency limits how many pipelines' jobs are fetched at once while waiting for the
+// pipeline, to avoid bursts of API calls hitting rate limits.
+// Values below 1 restore the default (4).
+func (c *Client) SetAPIConcurrency(n int) {
+	c.apiConcurrency = n
+}
+
+// concurrency returns the configured fetch concurrency, or the default when unset.
+func (c *Client) concurrency() int {
+	if c.apiConcurrency < 1 {
+		return defaultAPIConcurrency
+	}
+	return c.apiConcurrency
+}
+
+// SetRequestTimeout bounds each API call, so that a stalled connection cannot hang the run.
+// The wait for the pipeline as a whole is bounded separately by its own timeout.
+// Values below or equal to zero restore the default (30s).
+func (c *Client) SetRequestTimeout(timeout time.Duration) {
+	c.requestTimeout = timeout
+}
+
+// SetIgnoredChecks makes [Client.WaitForPipeline] leave out the jobs whose name matches
+// one of patterns ([path.Match] syntax, e.g. "lint-*"), such as a flaky advisory job:
+// they are still displayed, but neither waited for nor able to fail the wait.
+func (c *Client) SetIgnoredChecks(patterns []string) {
+	c.ignoredChecks = patterns
+}
+
+// ignoredCheck reports whether the job named name matches a pattern of [Client.SetIgnoredChecks].
+func (c *Client) ignoredCheck(name string) bool {
+	return slices.ContainsFunc(c.ignoredChecks, func(pattern string) bool {
+		matched, _ := path.Match(pattern, name)
+		return matched
+	})
+}
+
+// SetUserAgent sets the User-Agent header of API requests, e.g. "auto-mr/1.2.0", so that
+// instance admins can attribute the traffic. An empty value keeps the client-go default.
+func (c *Client) SetUserAgent(userAgent string) {
+	if userAgent != "" {
+		c.client.UserAgent = userAgent
+	}
+}
+
+// ctx returns the context for a single API call, bounded by the request timeout
+// (see [Client.SetRequestTimeout]). The caller must call the cancel function.
+func (c *Client) ctx() (context.Context, context.CancelFunc) {
+	timeout := c.requestTimeout
+	if timeout <= 0 {
+		timeout = defaultRequestTimeout
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// reportTransitions logs transitions at debug level and passes them to the transition hook.
+func (c *Client) reportTransitions(transitions []Transition) {
+	for _, transition := range transitions {
+		c.log.Debug(transition.String())
+		if c.onTransition != nil {
+			c.onTransition(transition)
+		}
+	}
+}
+
 // SetProjectFromURL sets the project from a git remote URL.
 // Supports both HTTPS and SSH URL formats:
 //   - https://gitlab.com/group/project.git
 //   - git@gitlab.com:group/project.git
 //
-// The .git suffix should already be present; it is stripped internally.
+// The project path is everything after the host, so projects in subgroups
+// (group/subgroup/project) are found too. A .git suffix and trailing slashes are stripped.
 //
 // Returns [ErrInvalidURLFormat] if the URL cannot be parsed.
 // Returns a wrapped error if the project does not exist or the API call fails.
 func (c *Client) SetProjectFromURL(url string) error {
 	// Extract project path from URL
 	// Supports both HTTPS and SSH formats:
-	// - https://gitlab.com/user/project.git
-	// - git@gitlab.com:user/project.git
-	url = strings.TrimSuffix(url, ".git")
-
-	projectPath := urlutil.ExtractPathComponents(url, minURLParts)
-	if projectPath == "" {
+	// - https://gitlab.com/group/subgroup/project.git
+	// - git@gitlab.com:group/subgroup/project.git
+	projectPath := urlutil.ExtractPath(url)
+	if len(strings.Split(projectPath, "/")) < minURLParts {
 		return errInvalidURLFormat
 	}
 
 	c.log.Debug("Setting GitLab project: " + projectPath)
 
 	// Get project info to validate and get project ID
-	project, _, err := c.client.Projects.GetProject(projectPath, nil)
+	ctx, cancel := c.ctx()
+	defer cancel()
+	project, _, err := c.client.Projects.GetProject(projectPath, nil, gitlab.WithContext(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to get project information: %w", err)
 	}
@@ -90,22 +223,47 @@ func (c *Client) SetProjectFromURL(url string) error {
 func (c *Client) ListLabels() ([]*Label, error) {
 	c.log.Debug("Listing GitLab labels")
 
+	ctx, cancel := c.ctx()
+	defer cancel()
 	labels, _, err := c.client.Labels.ListLabels(c.projectID, &gitlab.ListLabelsOptions{
 		IncludeAncestorGroups: new(true),
-	})
+	}, gitlab.WithContext(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to list labels: %w", err)
 	}
 
 	result := make([]*Label, len(labels))
 	for i, label := range labels {
-		result[i] = &Label{Name: label.Name}
+		result[i] = &Label{Name: label.Name, Color: label.Color, Description: label.Description}
 	}
 
 	c.log.Debug(fmt.Sprintf("Labels retrieved, count: %d", len(labels)))
 	return result, nil
 }
 
+// ListMemberUsernames returns the usernames of the active project members,
+// including members inherited from parent groups (first 100).
+func (c *Client) ListMemberUsernames() ([]string, error) {
+	c.log.Debug("Listing GitLab project members")
+
+	ctx, cancel := c.ctx()
+	defer cancel()
+	members, _, err := c.client.ProjectMembers.ListAllProjectMembers(c.projectID, &gitlab.ListProjectMembersOptions{
+		ListOptions: gitlab.ListOptions{PerPage: membersPageSize},
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list project members: %w", err)
+	}
+
+	usernames := make([]string, 0, len(members))
+	for _, member := range members {
+		if member.State == "" || member.State == "active" {
+			usernames = append(usernames, member.Username)
+		}
+	}
+	return usernames, nil
+}
+
 // CreateMergeRequest creates a new merge request with assignees, reviewers, and labels.
 // The created MR automatically sets RemoveSourceBranch to true.
 //
@@ -114,8 +272,8 @@ func (c *Client) ListLabels() ([]*Label, error) {
 //   - targetBranch: the target branch (e.g., "main")
 //   - title: MR title (must not be empty)
 //   - description: MR body/description
-//   - assignee: GitLab username to assign
-//   - reviewer: GitLab username to request review from
+//   - assignee: GitLab username, or user ID as "id:<n>", to assign (empty string is skipped)
+//   - reviewer: GitLab username, or user ID as "id:<n>", to request review from (empty string is skipped)
 //   - labels: list of label names to apply (may be nil)
 //   - squash: whether to squash commits on merge
 //
@@ -129,37 +287,40 @@ func (c *Client) CreateMergeRequest(
 	c.log.Debug(fmt.Sprintf("Creating merge request from %s to %s", sourceBranch, targetBranch))
 
 	// Get user IDs for assignee and reviewer
-	assigneeUser, _, err := c.client.Users.ListUsers(&gitlab.ListUsersOptions{
-		Username: &assignee,
-	})
-	if err != nil || len(assigneeUser) == 0 {
-		return nil, fmt.Errorf("%w: %s", errAssigneeNotFound, assignee)
+	var assigneeID *int64
+	if assignee != "" {
+		id, err := c.resolveUserID(assignee)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errAssigneeNotFound, assignee)
+		}
+		assigneeID = &id
 	}
 
-	reviewerUser, _, err := c.client.Users.ListUsers(&gitlab.ListUsersOptions{
-		Username: &reviewer,
-	})
-	if err != nil || len(reviewerUser) == 0 {
-		return nil, fmt.Errorf("%w: %s", errReviewerNotFound, reviewer)
+	reviewerIDs := []int64{}
+	if reviewer != "" {
+		reviewerID, err := c.resolveUserID(reviewer)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errReviewerNotFound, reviewer)
+		}
+		reviewerIDs = append(reviewerIDs, reviewerID)
 	}
 
-	assigneeID := assigneeUser[0].ID
-	reviewerIDs := []int64{reviewerUser[0].ID}
-
 	labelOptions := (*gitlab.LabelOptions)(&labels)
 	createOptions := &gitlab.CreateMergeRequestOptions{
 		Title:              &title,
 		Description:        &description,
 		SourceBranch:       &sourceBranch,
 		TargetBranch:       &targetBranch,
-		AssigneeID:         &assigneeID,
+		AssigneeID:         assigneeID,
 		ReviewerIDs:        &reviewerIDs,
 		Labels:             labelOptions,
 		Squash:             new(squash),
 		RemoveSourceBranch: new(true),
 	}
 
-	mr, _, err := c.client.MergeRequests.CreateMergeRequest(c.projectID, createOptions)
+	ctx, cancel := c.ctx()
+	defer cancel()
+	mr, _, err := c.client.MergeRequests.CreateMergeRequest(c.projectID, createOptions, gitlab.WithContext(ctx))
 	if err != nil {
 		// Check if error indicates MR already exists
 		errMsg := strings.ToLower(err.Error())
@@ -168,6 +329,9 @@ func (c *Client) CreateMergeRequest(
 			return nil, fmt.Errorf("%w: source=%s, target=%s: %w",
 				errMRAlreadyExists, sourceBranch, targetBranch, err)
 		}
+		// A stale cached ID may be the cause; resolve afresh next time.
+		c.invalidateUserID(assignee)
+		c.invalidateUserID(reviewer)
 		return nil, fmt.Errorf("failed to create merge request: %w", err)
 	}
 
@@ -177,16 +341,87 @@ func (c *Client) CreateMergeRequest(
 	return mr, nil
 }
 
+// SetUserCache enables caching of username→ID lookups in the file at path,
+// saving the ListUsers round-trips on later runs. Entries expire after 24 hours.
+// An empty path disables caching.
+func (c *Client) SetUserCache(path string) {
+	if path == "" {
+		c.users = nil
+		return
+	}
+	c.users = newUserCache(path)
+}
+
+// resolveUserID returns the ID of the GitLab user with the given username,
+// consulting the user cache first when enabled. A numeric ID given as "id:<n>"
+// is returned as is, without any API call.
+func (c *Client) resolveUserID(username string) (int64, error) {
+	if id, ok := userid.Parse(username); ok {
+		return id, nil
+	}
+
+	key := c.userCacheKey(username)
+	if c.users != nil {
+		if id, ok := c.users.lookup(key); ok {
+			c.log.Debug(fmt.Sprintf("Using cached ID %d for user %s", id, username))
+			return id, nil
+		}
+	}
+
+	ctx, cancel := c.ctx()
+	defer cancel()
+	users, _, err := c.client.Users.ListUsers(&gitlab.ListUsersOptions{
+		Username: &username,
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		c.invalidateUserID(username)
+		return 0, fmt.Errorf("failed to list users: %w", err)
+	}
+	if len(users) == 0 {
+		c.invalidateUserID(username)
+		return 0, fmt.Errorf("%w: %s", errUserNotFound, username)
+	}
+
+	id := users[0].ID
+	if c.users != nil {
+		if err := c.users.store(key, id); err != nil {
+			c.log.Debug(fmt.Sprintf("Failed to cache user ID: %v", err))
+		}
+	}
+	return id, nil
+}
+
+// invalidateUserID drops the cached ID for username, if any.
+func (c *Client) invalidateUserID(username string) {
+	if c.users == nil {
+		return
+	}
+	if err := c.users.invalidate(c.userCacheKey(username)); err != nil {
+		c.log.Debug(fmt.Sprintf("Failed to invalidate cached user ID: %v", err))
+	}
+}
+
+// userCacheKey scopes a username to the GitLab instance it belongs to.
+func (c *Client) userCacheKey(username string) string {
+	return c.client.BaseURL().Host + "/" + username
+}
+
 // GetMergeRequestByBranch fetches an existing open merge request by source and target branches.
-// Only the first matching MR is returned. Stores the MR IID and SHA internally.
+// When several match (e.g. opened by concurrent runs), the most recently created one is
+// returned and the IIDs of the others are kept for [Client.DuplicateMergeRequests].
+// Stores the MR IID and SHA internally.
 //
 // Returns [ErrMRNotFound] if no open MR matches the given branches.
 func (c *Client) GetMergeRequestByBranch(sourceBranch, targetBranch string) (*gitlab.MergeRequest, error) {
+	ctx, cancel := c.ctx()
 	mrs, _, err := c.client.MergeRequests.ListProjectMergeRequests(c.projectID, &gitlab.ListProjectMergeRequestsOptions{
 		State:        new("opened"),
 		SourceBranch: &sourceBranch,
 		TargetBranch: &targetBranch,
-	})
+		OrderBy:      new("created_at"),
+		Sort:         new("desc"),
+	}, gitlab.WithContext(ctx))
+	cancel()
 	if err != nil {
 		return nil, fmt.Errorf("failed to list merge requests: %w", err)
 	}
@@ -194,9 +429,15 @@ func (c *Client) GetMergeRequestByBranch(sourceBranch, targetBranch string) (*gi
 	if len(mrs) == 0 {
 		return nil, fmt.Errorf("%w: %s", errMRNotFound, sourceBranch)
 	}
+	c.duplicates = nil
+	for _, duplicate := range mrs[1:] {
+		c.duplicates = append(c.duplicates, duplicate.IID)
+	}
 
 	// Get full MR details
-	mr, _, err := c.client.MergeRequests.GetMergeRequest(c.projectID, mrs[0].IID, nil)
+	ctx, cancel = c.ctx()
+	defer cancel()
+	mr, _, err := c.client.MergeRequests.GetMergeRequest(c.projectID, mrs[0].IID, nil, gitlab.WithContext(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get merge request details: %w", err)
 	}
@@ -206,23 +447,65 @@ func (c *Client) GetMergeRequestByBranch(sourceBranch, targetBranch string) (*gi
 	return mr, nil
 }
 
+// DuplicateMergeRequests returns the IIDs of the open merge requests that the last
+// [Client.GetMergeRequestByBranch] found besides the one it returned, newest first.
+func (c *Client) DuplicateMergeRequests() []int64 {
+	return c.duplicates
+}
+
+// GetMergedMergeRequest fetches a merged merge request by source and target branches
+// whose head commit is sha, i.e. the merge of exactly that branch state.
+//
+// Returns [ErrMRNotFound] if no merged MR matches.
+func (c *Client) GetMergedMergeRequest(sourceBranch, targetBranch, sha string) (*gitlab.BasicMergeRequest, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+	mrs, _, err := c.client.MergeRequests.ListProjectMergeRequests(c.projectID, &gitlab.ListProjectMergeRequestsOptions{
+		State:        new("merged"),
+		SourceBranch: &sourceBranch,
+		TargetBranch: &targetBranch,
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merge requests: %w", err)
+	}
+
+	for _, mr := range mrs {
+		if mr.SHA == sha {
+			return mr, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: merged %s at %s", errMRNotFound, sourceBranch, sha)
+}
+
 // WaitForPipeline waits for all pipelines to complete for the merge request.
 // It polls at 5-second intervals and displays real-time job-level progress with animated spinners.
+// When spinners are disabled (non-TTY, --no-spinner, --no-color or JSON output), they become
+// static lines and a progress line is logged every 30 seconds.
 // If no pipelines are configured, it returns "success" immediately.
+// Manual jobs are not waited for, unless enabled with [Client.SetPlayManualJobs]: they are then
+// started once and waited for like the others.
+// The head commit of the merge request is re-read at each poll: when the branch is pushed to
+// during the wait, the pipelines of the previous commit are dropped and those of the new one
+// are waited for instead.
 //
 // Parameters:
 //   - timeout: maximum wait duration (typically 1m to 8h)
 //
 // Returns the overall pipeline status ("success", "failed", "canceled").
 // Returns [ErrPipelineTimeout] if the timeout is exceeded.
+// Returns [ErrNoPipeline] if no pipeline showed up within the start timeout (see
+// [Client.SetStartTimeout]).
 //
 // A merge request must have been created or fetched before calling this method.
 func (c *Client) WaitForPipeline(timeout time.Duration) (string, error) {
 	c.log.Debug(fmt.Sprintf("Waiting for pipeline, timeout: %v", timeout))
-	start := time.Now()
+	start := c.clock.Now()
+
+	// The branch may have been pushed to since the merge request was created or fetched
+	headChanged := c.refreshHeadSHA()
 
 	// First check if any pipelines are expected for this commit
-	if !c.hasPipelineRuns() {
+	if !c.requireChecks && !c.hasPipelineRuns() {
 		c.log.Info("No pipeline runs configured for this merge request, proceeding without checks")
 		return statusSuccess, nil
 	}
@@ -234,30 +517,62 @@ func (c *Client) WaitForPipeline(timeout time.Duration) (string, error) {
 
 	// Initialize job tracker for managing individual job handles
 	tracker := newJobTracker()
+	lastStatusLine := start
+	announced := make(map[int64]bool) // pipelines whose URL was printed
+	c.playedJobs = make(map[int64]bool)
+	var supersededID int64 // pipelines up to this ID ran for an earlier head commit
+
+	for c.clock.Since(start) < timeout {
+		if !logger.Interactive() && c.clock.Since(lastStatusLine) >= statusLineInterval {
+			c.updatableLog.Info("Still waiting for pipelines - elapsed: " + timeutil.FormatDuration(c.clock.Since(start)))
+			lastStatusLine = c.clock.Now()
+		}
 
-	for time.Since(start) < timeout {
-		pipelines, _, err := c.client.MergeRequests.ListMergeRequestPipelines(c.projectID, c.mrIID, nil)
+		ctx, cancel := c.ctx()
+		pipelines, _, err := c.client.MergeRequests.ListMergeRequestPipelines(c.projectID, c.mrIID, nil,
+			gitlab.WithContext(ctx))
+		cancel()
 		if err != nil {
 			c.updatableLog.Error(fmt.Sprintf("Failed to list MR pipelines: %v", err))
 			return "", fmt.Errorf("failed to list MR pipelines: %w", err)
 		}
 
+		if headChanged {
+			supersededID = supersededPipelineID(pipelines, c.mrSHA, supersededID)
+			tracker = newJobTracker()
+			headChanged = false
+		}
+		pipelines = currentPipelines(c.freshPipelines(pipelines), supersededID)
 		if len(pipelines) == 0 {
+			if c.startTimeout > 0 && c.clock.Since(start) >= c.startTimeout {
+				c.updatableLog.Error("No pipeline started after " + timeutil.FormatDuration(c.clock.Since(start)))
+				return "", errNoPipeline
+			}
 			// Wait silently for pipelines to appear (they'll show as individual spinners when they start)
-			time.Sleep(pipelinePollInterval)
+			c.clock.Sleep(pipelinePollInterval)
+			headChanged = c.refreshHeadSHA()
 			continue
 		}
 
+		// Print each pipeline's URL as soon as it appears, to follow it in the browser
+		for _, pipeline := range pipelines {
+			if !announced[pipeline.ID] && pipeline.WebURL != "" {
+				announced[pipeline.ID] = true
+				c.updatableLog.Info("Pipeline: " + pipeline.WebURL)
+			}
+		}
+
 		// Process all pipelines with individual job tracking
 		allCompleted, overallStatus := c.processPipelinesWithJobTracking(pipelines, tracker)
 
 		if !allCompleted {
-			time.Sleep(pipelinePollInterval)
+			c.clock.Sleep(pipelinePollInterval)
+			headChanged = c.refreshHeadSHA()
 			continue
 		}
 
 		// All pipelines completed - display final summary
-		totalDuration := time.Since(start)
+		totalDuration := c.clock.Since(start)
 		if overallStatus == statusSuccess {
 			c.updatableLog.Success("Pipeline completed successfully - total time: " +
 				timeutil.FormatDuration(totalDuration))
@@ -270,19 +585,33 @@ func (c *Client) WaitForPipeline(timeout time.Duration) (string, error) {
 		return overallStatus, nil
 	}
 
-	totalDuration := time.Since(start)
+	totalDuration := c.clock.Since(start)
 	c.updatableLog.Error("Timeout after " + timeutil.FormatDuration(totalDuration))
 	return "", errPipelineTimeout
 }
 
 // ApproveMergeRequest approves a merge request by its internal ID.
+// Approval is skipped when the authenticated user is the merge request author,
+// since GitLab usually rejects self-approval.
 //
 // Parameters:
 //   - mrIID: the merge request internal ID (IID), not the global ID
 func (c *Client) ApproveMergeRequest(mrIID int64) error {
 	c.log.Debug(fmt.Sprintf("Approving merge request, IID: %d", mrIID))
 
-	_, _, err := c.client.MergeRequestApprovals.ApproveMergeRequest(c.projectID, mrIID, nil)
+	if c.isAuthor(mrIID) {
+		c.log.Info("Skipping approval: you are the author of this merge request")
+		return nil
+	}
+
+	ctx, cancel := c.ctx()
+	defer cancel()
+	var err error
+	if c.approvalPass != "" {
+		err = c.approveWithPassword(ctx, mrIID)
+	} else {
+		_, _, err = c.client.MergeRequestApprovals.ApproveMergeRequest(c.projectID, mrIID, nil, gitlab.WithContext(ctx))
+	}
 	if err != nil {
 		return fmt.Errorf("failed to approve merge request: %w", err)
 	}
@@ -290,6 +619,237 @@ func (c *Client) ApproveMergeRequest(mrIID int64) error {
 	return nil
 }
 
+// approveOptions is the approve request body including approval_password,
+// which [gitlab.ApproveMergeRequestOptions] does not expose.
+type approveOptions struct {
+	ApprovalPassword string `json:"approval_password"` //nolint:gosec // Sent to the API, never logged
+}
+
+// approveWithPassword approves a merge request sending the approval password.
+func (c *Client) approveWithPassword(ctx context.Context, mrIID int64) error {
+	path := fmt.Sprintf("projects/%s/merge_requests/%d/approve", gitlab.PathEscape(c.projectID), mrIID)
+	req, err := c.client.NewRequest(http.MethodPost, path, &approveOptions{ApprovalPassword: c.approvalPass},
+		[]gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return fmt.Errorf("failed to build approval request: %w", err)
+	}
+	if _, err := c.client.Do(req, nil); err != nil {
+		return fmt.Errorf("failed to send approval: %w", err)
+	}
+	return nil
+}
+
+// CreateMergeRequestNote posts a comment (note) on a merge request.
+//
+// Parameters:
+//   - mrIID: the merge request internal ID (IID), not the global ID
+//   - body: the comment text (Markdown)
+func (c *Client) CreateMergeRequestNote(mrIID int64, body string) error {
+	c.log.Debug(fmt.Sprintf("Commenting on merge request, IID: %d", mrIID))
+
+	ctx, cancel := c.ctx()
+	defer cancel()
+	_, _, err := c.client.Notes.CreateMergeRequestNote(c.projectID, mrIID,
+		&gitlab.CreateMergeRequestNoteOptions{Body: &body}, gitlab.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to comment on merge request: %w", err)
+	}
+	return nil
+}
+
+// AddMergeRequestLabels adds labels to a merge request, keeping its current ones.
+func (c *Client) AddMergeRequestLabels(mrIID int64, labels []string) error {
+	c.log.Debug(fmt.Sprintf("Adding labels to merge request %d: %v", mrIID, labels))
+
+	ctx, cancel := c.ctx()
+	defer cancel()
+	_, _, err := c.client.MergeRequests.UpdateMergeRequest(c.projectID, mrIID,
+		&gitlab.UpdateMergeRequestOptions{AddLabels: new(gitlab.LabelOptions(labels))}, gitlab.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to add labels to merge request: %w", err)
+	}
+	return nil
+}
+
+// MarkMergeRequestReady marks a draft merge request as ready by removing the
+// draft prefix ("Draft:", "[Draft]", "(Draft)") from its title.
+//
+// Returns false without updating anything if the merge request is not a draft.
+func (c *Client) MarkMergeRequestReady(mrIID int64) (bool, error) {
+	c.log.Debug(fmt.Sprintf("Marking merge request as ready, IID: %d", mrIID))
+
+	ctx, cancel := c.ctx()
+	defer cancel()
+	mr, _, err := c.client.MergeRequests.GetMergeRequest(c.projectID, mrIID, nil, gitlab.WithContext(ctx))
+	if err != nil {
+		return false, fmt.Errorf("failed to get merge request: %w", err)
+	}
+	title, isDraft := draft.StripPrefix(mr.Title)
+	if !mr.Draft || !isDraft {
+		return false, nil
+	}
+
+	_, _, err = c.client.MergeRequests.UpdateMergeRequest(c.projectID, mrIID,
+		&gitlab.UpdateMergeRequestOptions{Title: &title}, gitlab.WithContext(ctx))
+	if err != nil {
+		return false, fmt.Errorf("failed to update merge request title: %w", err)
+	}
+	return true, nil
+}
+
+// AddReviewers requests review of a merge request from owners, e.g. its code owners,
+// on top of its current reviewers. Each owner is a username or, when no user has that
+// name, a group path ("group" or "group/subgroup") standing for its direct members
+// (first 100). The merge request author is left out.
+//
+// Returns [ErrReviewerNotFound] if an owner is neither a user nor a group.
+func (c *Client) AddReviewers(mrIID int64, owners []string) error {
+	c.log.Debug(fmt.Sprintf("Adding reviewers to merge request %d: %v", mrIID, owners))
+
+	ctx, cancel := c.ctx()
+	defer cancel()
+	mr, _, err := c.client.MergeRequests.GetMergeRequest(c.projectID, mrIID, nil, gitlab.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to get merge request: %w", err)
+	}
+
+	reviewerIDs := make([]int64, 0, len(mr.Reviewers)+len(owners))
+	for _, reviewer := range mr.Reviewers {
+		reviewerIDs = append(reviewerIDs, reviewer.ID)
+	}
+	added := false
+	for _, owner := range owners {
+		ids, err := c.ownerUserIDs(owner)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if (mr.Author == nil || id != mr.Author.ID) && !slices.Contains(reviewerIDs, id) {
+				reviewerIDs = append(reviewerIDs, id)
+				added = true
+			}
+		}
+	}
+	if !added {
+		c.log.Debug("No reviewer to add")
+		return nil
+	}
+
+	_, _, err = c.client.MergeRequests.UpdateMergeRequest(c.projectID, mrIID,
+		&gitlab.UpdateMergeRequestOptions{ReviewerIDs: &reviewerIDs}, gitlab.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to update merge request reviewers: %w", err)
+	}
+	return nil
+}
+
+// ownerUserIDs returns the ID of the user named owner or, failing that, the IDs of
+// the active direct members of the group with that path.
+func (c *Client) ownerUserIDs(owner string) ([]int64, error) {
+	id, err := c.resolveUserID(owner)
+	if err == nil {
+		return []int64{id}, nil
+	}
+	if !errors.Is(err, errUserNotFound) {
+		return nil, err
+	}
+
+	ctx, cancel := c.ctx()
+	defer cancel()
+	members, _, err := c.client.Groups.ListGroupMembers(owner, &gitlab.ListGroupMembersOptions{
+		ListOptions: gitlab.ListOptions{PerPage: membersPageSize},
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", errReviewerNotFound, owner, err)
+	}
+
+	ids := make([]int64, 0, len(members))
+	for _, member := range members {
+		if member.State == "" || member.State == "active" {
+			ids = append(ids, member.ID)
+		}
+	}
+	return ids, nil
+}
+
+// CloseMergeRequest closes a merge request without merging it.
+func (c *Client) CloseMergeRequest(mrIID int64) error {
+	c.log.Debug(fmt.Sprintf("Closing merge request, IID: %d", mrIID))
+
+	ctx, cancel := c.ctx()
+	defer cancel()
+	_, _, err := c.client.MergeRequests.UpdateMergeRequest(c.projectID, mrIID,
+		&gitlab.UpdateMergeRequestOptions{StateEvent: new("close")}, gitlab.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to close merge request: %w", err)
+	}
+	return nil
+}
+
+// DeleteBranch deletes a branch from the project repository.
+//
+// Parameters:
+//   - branch: the branch name to delete (without "refs/heads/" prefix)
+func (c *Client) DeleteBranch(branch string) error {
+	c.log.Debug("Deleting branch: " + branch)
+
+	ctx, cancel := c.ctx()
+	defer cancel()
+	if _, err := c.client.Branches.DeleteBranch(c.projectID, branch, gitlab.WithContext(ctx)); err != nil {
+		return fmt.Errorf("failed to delete branch: %w", err)
+	}
+	return nil
+}
+
+// CurrentUsername returns the username of the authenticated user.
+func (c *Client) CurrentUsername() (string, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+	user, _, err := c.client.Users.CurrentUser(gitlab.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed to get current user: %w", err)
+	}
+	return user.Username, nil
+}
+
+// isAuthor reports whether the authenticated user authored the merge request.
+// Lookup failures return false so that approval is still attempted.
+func (c *Client) isAuthor(mrIID int64) bool {
+	ctx, cancel := c.ctx()
+	user, _, err := c.client.Users.CurrentUser(gitlab.WithContext(ctx))
+	cancel()
+	if err != nil {
+		c.log.Debug(fmt.Sprintf("Failed to get current user, attempting approval: %v", err))
+		return false
+	}
+
+	ctx, cancel = c.ctx()
+	defer cancel()
+	mr, _, err := c.client.MergeRequests.GetMergeRequest(c.projectID, mrIID, nil, gitlab.WithContext(ctx))
+	if err != nil {
+		c.log.Debug(fmt.Sprintf("Failed to get merge request author, attempting approval: %v", err))
+		return false
+	}
+
+	return mr.Author != nil && mr.Author.ID == user.ID
+}
+
+// SquashOption returns the project setting deciding whether merge requests are
+// squashed: "never", "always", "default_on" or "default_off" (the last two let
+// the merge request choose). The project merge method (merge commit, semi-linear
+// or fast-forward) applies either way.
+func (c *Client) SquashOption() (string, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+	project, _, err := c.client.Projects.GetProject(c.projectID, nil, gitlab.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed to get project information: %w", err)
+	}
+
+	c.log.Debug(fmt.Sprintf("Project squash option: %s, merge method: %s", project.SquashOption, project.MergeMethod))
+	return string(project.SquashOption), nil
+}
+
 // MergeMergeRequest merges a merge request with optional squash.
 // The source branch is automatically removed after merge.
 //
@@ -312,21 +872,189 @@ func (c *Client) MergeMergeRequest(mrIID int64, squash bool, commitTitle string)
 		mergeOptions.MergeCommitMessage = new(commitTitle)
 	}
 
-	_, _, err := c.client.MergeRequests.AcceptMergeRequest(c.projectID, mrIID, mergeOptions)
+	ctx, cancel := c.ctx()
+	defer cancel()
+	mr, _, err := c.client.MergeRequests.AcceptMergeRequest(c.projectID, mrIID, mergeOptions, gitlab.WithContext(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to merge MR: %w", err)
 	}
 
 	c.log.Debug("Merge request merged successfully")
+	// Without a merge commit (fast-forward merge method), the source branch head lands as is.
+	c.mergeSHA = cmp.Or(mr.MergeCommitSHA, mr.SquashCommitSHA, mr.SHA)
+	return nil
+}
+
+// EnableAutoMerge sets a merge request to be merged, squashed if squash is true, once
+// its pipeline succeeds (auto-merge). The source branch is removed after the merge.
+// GitLab merges it right away if the pipeline already succeeded.
+func (c *Client) EnableAutoMerge(mrIID int64, squash bool) error {
+	c.log.Debug(fmt.Sprintf("Enabling auto-merge of merge request, IID: %d", mrIID))
+
+	ctx, cancel := c.ctx()
+	defer cancel()
+	_, _, err := c.client.MergeRequests.AcceptMergeRequest(c.projectID, mrIID, &gitlab.AcceptMergeRequestOptions{
+		AutoMerge:                new(true),
+		Squash:                   new(squash),
+		ShouldRemoveSourceBranch: new(true),
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to enable auto-merge: %w", err)
+	}
 	return nil
 }
 
+// MergeCommitSHA returns the SHA of the commit the last [Client.MergeMergeRequest]
+// left at the head of the target branch, empty if unknown.
+func (c *Client) MergeCommitSHA() string {
+	return c.mergeSHA
+}
+
+// CheckMergeable verifies that a merge request can be merged without conflicts.
+// GitLab computes mergeability asynchronously, so while the detailed merge status
+// is still being checked the request is polled a few times before giving up.
+//
+// Returns [ErrMRConflict] if the merge request has conflicts or must be rebased.
+// Other blocking statuses (pipeline, approvals, ...) are left to the merge call.
+func (c *Client) CheckMergeable(mrIID int64) error {
+	for attempt := range mergeabilityAttempts {
+		ctx, cancel := c.ctx()
+		mr, _, err := c.client.MergeRequests.GetMergeRequest(c.projectID, mrIID, nil, gitlab.WithContext(ctx))
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to get merge request: %w", err)
+		}
+
+		c.log.Debug(fmt.Sprintf("Merge request !%d detailed merge status: %s (has conflicts: %v)",
+			mrIID, mr.DetailedMergeStatus, mr.HasConflicts))
+
+		switch mr.DetailedMergeStatus {
+		case "checking", "unchecked", "preparing":
+			if attempt < mergeabilityAttempts-1 {
+				c.clock.Sleep(mergeabilityInterval)
+			}
+			continue
+		case "conflict":
+			return fmt.Errorf("%w: !%d (%s into %s)", errMRConflict, mrIID, mr.SourceBranch, mr.TargetBranch)
+		case "need_rebase":
+			return fmt.Errorf("%w: !%d must be rebased onto %s", errMRConflict, mrIID, mr.TargetBranch)
+		}
+
+		if mr.HasConflicts {
+			return fmt.Errorf("%w: !%d (%s into %s)", errMRConflict, mrIID, mr.SourceBranch, mr.TargetBranch)
+		}
+		return nil
+	}
+
+	c.log.Debug("Merge status still being computed, proceeding with merge")
+	return nil
+}
+
+// RebaseMergeRequest rebases the source branch of a merge request onto its target branch,
+// server side, and polls every 2 seconds until GitLab reports the rebase as finished.
+// The new head SHA is stored for [Client.WaitForPipeline].
+//
+// Returns [ErrRebaseFailed] with GitLab's merge error if the rebase fails (e.g. conflicts),
+// or if it is still in progress when timeout expires.
+func (c *Client) RebaseMergeRequest(mrIID int64, timeout time.Duration) error {
+	c.log.Debug(fmt.Sprintf("Rebasing merge request, IID: %d", mrIID))
+
+	ctx, cancel := c.ctx()
+	_, err := c.client.MergeRequests.RebaseMergeRequest(c.projectID, mrIID, nil, gitlab.WithContext(ctx))
+	cancel()
+	if err != nil {
+		return fmt.Errorf("%w: !%d: %w", errRebaseFailed, mrIID, err)
+	}
+
+	start := c.clock.Now()
+	for {
+		ctx, cancel := c.ctx()
+		mr, _, err := c.client.MergeRequests.GetMergeRequest(c.projectID, mrIID,
+			&gitlab.GetMergeRequestsOptions{IncludeRebaseInProgress: new(true)}, gitlab.WithContext(ctx))
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to get merge request: %w", err)
+		}
+
+		if !mr.RebaseInProgress {
+			if mr.MergeError != "" {
+				return fmt.Errorf("%w: !%d: %s", errRebaseFailed, mrIID, mr.MergeError)
+			}
+			c.mrSHA = mr.SHA
+			c.log.Debug(fmt.Sprintf("Merge request rebased, SHA: %s", mr.SHA))
+			return nil
+		}
+
+		if c.clock.Since(start)+rebasePollInterval > timeout {
+			return fmt.Errorf("%w: !%d: rebase still in progress after %s",
+				errRebaseFailed, mrIID, timeutil.FormatDuration(c.clock.Since(start)))
+		}
+		c.clock.Sleep(rebasePollInterval)
+	}
+}
+
+// WaitForApprovals checks that the approval rules of a merge request are satisfied.
+// With a zero timeout the state is checked once; otherwise it is polled every
+// 15 seconds until the rules are satisfied or the timeout expires.
+// If the approval state cannot be read (e.g. unsupported by the instance), the
+// check is skipped and the merge call decides.
+//
+// Returns [ErrApprovalsPending] naming how many approvals are still required.
+func (c *Client) WaitForApprovals(mrIID int64, timeout time.Duration) error {
+	start := c.clock.Now()
+	for {
+		left, rules, err := c.approvalsLeft(mrIID)
+		if err != nil {
+			c.log.Debug(fmt.Sprintf("Failed to get approval state, skipping approval check: %v", err))
+			return nil
+		}
+		if left == 0 {
+			return nil
+		}
+
+		pending := fmt.Errorf("%w: !%d needs %d more approval(s) (rules: %s)",
+			errApprovalsPending, mrIID, left, strings.Join(rules, ", "))
+		if c.clock.Since(start)+approvalPollInterval > timeout {
+			return pending
+		}
+
+		c.log.Info(fmt.Sprintf("Waiting for %d more approval(s) (rules: %s)...", left, strings.Join(rules, ", ")))
+		c.clock.Sleep(approvalPollInterval)
+	}
+}
+
+// approvalsLeft returns how many approvals are still required on a merge request
+// and the names of the unsatisfied rules.
+func (c *Client) approvalsLeft(mrIID int64) (int64, []string, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+	state, _, err := c.client.MergeRequestApprovals.GetApprovalState(c.projectID, mrIID, gitlab.WithContext(ctx))
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get approval state: %w", err)
+	}
+
+	var left int64
+	var rules []string
+	for _, rule := range state.Rules {
+		if rule.Approved {
+			continue
+		}
+		if missing := rule.ApprovalsRequired - int64(len(rule.ApprovedBy)); missing > 0 {
+			left += missing
+			rules = append(rules, rule.Name)
+		}
+	}
+	return left, rules, nil
+}
+
 // GetMergeRequestsByBranch returns all open merge requests for the given source branch.
 func (c *Client) GetMergeRequestsByBranch(sourceBranch string) ([]*gitlab.BasicMergeRequest, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
 	mrs, _, err := c.client.MergeRequests.ListProjectMergeRequests(c.projectID, &gitlab.ListProjectMergeRequestsOptions{
 		SourceBranch: &sourceBranch,
 		State:        new("opened"),
-	})
+	}, gitlab.WithContext(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to list merge requests: %w", err)
 	}
@@ -352,14 +1080,57 @@ func (c *Client) processPipelinesWithJobTracking(
 		allJobs = append(allJobs, fallbackJobs...)
 	}
 
+	played := c.playManual && c.playManualJobs(allJobs)
+
 	// Update job tracker with new jobs (creates/updates handles automatically)
 	transitions := tracker.update(allJobs, c.updatableLog)
-	for _, transition := range transitions {
-		c.log.Debug(transition)
-	}
+	c.reportTransitions(transitions)
 
 	// Analyze job statuses for completion
-	return c.analyzePipelineJobCompletion(allJobs)
+	allCompleted, overallStatus := c.analyzePipelineJobCompletion(allJobs)
+	if !allCompleted && !played && pipelinesSettled(pipelines) {
+		// Jobs of later stages stay "created" behind a manual job that blocks the pipeline
+		allCompleted = true
+	}
+	return allCompleted, overallStatus
+}
+
+// playManualJobs starts the manual jobs not played yet during this wait and marks them
+// pending. Jobs that cannot be played are logged and left manual, so they do not block.
+// Returns true if a job was started.
+func (c *Client) playManualJobs(jobs []*Job) bool {
+	played := false
+	for _, job := range jobs {
+		if job.Status != statusManual || c.playedJobs[job.ID] {
+			continue
+		}
+		c.playedJobs[job.ID] = true
+
+		ctx, cancel := c.ctx()
+		_, _, err := c.client.Jobs.PlayJob(c.projectID, job.ID, nil, gitlab.WithContext(ctx))
+		cancel()
+		if err != nil {
+			c.log.Warn(fmt.Sprintf("Failed to start manual job %s: %v", job.Name, err))
+			continue
+		}
+		c.updatableLog.Info("Started manual job: " + job.Name)
+		job.Status = statusPending
+		played = true
+	}
+	return played
+}
+
+// pipelinesSettled reports whether every pipeline has reached a status it will not
+// leave without user action, including "manual" (blocked on a manual job).
+func pipelinesSettled(pipelines []*gitlab.PipelineInfo) bool {
+	for _, pipeline := range pipelines {
+		switch pipeline.Status {
+		case statusSuccess, statusFailed, statusCanceled, statusSkipped, statusManual:
+		default:
+			return false
+		}
+	}
+	return true
 }
 
 // fetchJobsForPipelines fetches jobs for multiple pipelines concurrently.
@@ -374,12 +1145,15 @@ func (c *Client) fetchJobsForPipelines(
 
 	resultChan := make(chan pipelineJobs, len(pipelines))
 	var wg sync.WaitGroup
+	sem := make(chan struct{}, c.concurrency())
 
-	// Launch goroutines to fetch jobs concurrently
+	// Launch goroutines to fetch jobs concurrently, at most c.concurrency() at a time
 	for _, pipeline := range pipelines {
 		wg.Add(1)
 		go func(p *gitlab.PipelineInfo) {
 			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
 			jobs, err := c.fetchPipelineJobs(p.ID)
 			resultChan <- pipelineJobs{
 				pipelineID: p.ID,
@@ -418,14 +1192,23 @@ func (c *Client) fetchJobsForPipelines(
 }
 
 // analyzePipelineJobCompletion checks if all jobs are completed and determines overall status.
+// Skipped and manual jobs are complete and do not affect the status, so a pipeline made only of
+// them succeeds; delayed jobs and jobs waiting for a runner or resource are still running.
+// Ignored jobs (see [Client.SetIgnoredChecks]) are left out.
 func (c *Client) analyzePipelineJobCompletion(allJobs []*Job) (bool, string) {
 	allCompleted := true
 	overallStatus := statusSuccess
 
 	for _, job := range allJobs {
+		if c.ignoredCheck(job.Name) {
+			continue
+		}
 		switch job.Status {
-		case statusRunning, statusPending, statusCreated:
+		case statusRunning, statusPending, statusCreated, statusPreparing, statusWaitingForResource,
+			statusScheduled:
 			allCompleted = false
+		case statusManual, statusSkipped:
+			// Not run automatically: never blocks the wait
 		case statusFailed:
 			if overallStatus == statusSuccess {
 				overallStatus = statusFailed
@@ -448,30 +1231,10 @@ func (c *Client) processPipelinesFallback(tracker *jobTracker, pipelines []*gitl
 
 	// Update job tracker with converted jobs (creates/updates spinners automatically)
 	transitions := tracker.update(jobs, c.updatableLog)
-	for _, transition := range transitions {
-		c.log.Debug(transition)
-	}
+	c.reportTransitions(transitions)
 
 	// Analyze completion status
-	allCompleted := true
-	overallStatus := statusSuccess
-
-	for _, job := range jobs {
-		switch job.Status {
-		case statusRunning, statusPending, statusCreated:
-			allCompleted = false
-		case statusFailed:
-			if overallStatus == statusSuccess {
-				overallStatus = statusFailed
-			}
-		case statusCanceled:
-			if overallStatus == statusSuccess {
-				overallStatus = statusCanceled
-			}
-		}
-	}
-
-	return allCompleted, overallStatus
+	return c.analyzePipelineJobCompletion(jobs)
 }
 
 // convertPipelinesToJobs converts pipelines to Job format for display with jobTracker.
@@ -511,14 +1274,89 @@ func (c *Client) convertPipelinesToJobs(pipelines []*gitlab.PipelineInfo) []*Job
 	return jobs
 }
 
+// freshPipelines returns the pipelines created at or after the time set with
+// [Client.SetCISince], all of them when it is not set.
+func (c *Client) freshPipelines(pipelines []*gitlab.PipelineInfo) []*gitlab.PipelineInfo {
+	if c.ciSince.IsZero() {
+		return pipelines
+	}
+	fresh := make([]*gitlab.PipelineInfo, 0, len(pipelines))
+	for _, pipeline := range pipelines {
+		if pipeline.CreatedAt != nil && !pipeline.CreatedAt.Before(c.ciSince) {
+			fresh = append(fresh, pipeline)
+		}
+	}
+	return fresh
+}
+
+// refreshHeadSHA re-reads the head commit of the merge request, which moves when the
+// branch is pushed to after the merge request was created or fetched.
+// Returns true if it changed. Errors keep the known commit.
+func (c *Client) refreshHeadSHA() bool {
+	ctx, cancel := c.ctx()
+	defer cancel()
+	mr, _, err := c.client.MergeRequests.GetMergeRequest(c.projectID, c.mrIID, nil, gitlab.WithContext(ctx))
+	if err != nil {
+		c.log.Debug(fmt.Sprintf("Failed to refresh the merge request head commit, keeping %s: %v", c.mrSHA, err))
+		return false
+	}
+	if mr.SHA == "" || mr.SHA == c.mrSHA {
+		return false
+	}
+
+	previous := c.mrSHA
+	c.mrSHA = mr.SHA
+	if previous == "" {
+		return false
+	}
+	c.updatableLog.Info(fmt.Sprintf("Branch updated (%s → %s): waiting for the pipelines of the new commit",
+		shortSHA(previous), shortSHA(mr.SHA)))
+	return true
+}
+
+// supersededPipelineID returns the highest ID of the pipelines not run for headSHA, or
+// previous if higher. Pipelines are numbered in creation order, so those up to this ID
+// were created for an earlier head commit, including their merged results pipelines.
+func supersededPipelineID(pipelines []*gitlab.PipelineInfo, headSHA string, previous int64) int64 {
+	superseded := previous
+	for _, pipeline := range pipelines {
+		if pipeline.SHA != headSHA {
+			superseded = max(superseded, pipeline.ID)
+		}
+	}
+	return superseded
+}
+
+// currentPipelines drops the pipelines up to supersededID (see [supersededPipelineID]).
+func currentPipelines(pipelines []*gitlab.PipelineInfo, supersededID int64) []*gitlab.PipelineInfo {
+	if supersededID == 0 {
+		return pipelines
+	}
+	current := make([]*gitlab.PipelineInfo, 0, len(pipelines))
+	for _, pipeline := range pipelines {
+		if pipeline.ID > supersededID {
+			current = append(current, pipeline)
+		}
+	}
+	return current
+}
+
+// shortSHA abbreviates a commit SHA to 7 characters, like git.
+func shortSHA(sha string) string {
+	return sha[:min(len(sha), 7)]
+}
+
 // hasPipelineRuns checks if there are any pipeline runs (in any state) for this MR.
 func (c *Client) hasPipelineRuns() bool {
 	// Check for pipelines associated with this commit SHA
+	ctx, cancel := c.ctx()
+	defer cancel()
 	pipelines, _, err := c.client.Pipelines.ListProjectPipelines(
 		c.projectID,
 		&gitlab.ListProjectPipelinesOptions{
 			SHA: new(c.mrSHA),
 		},
+		gitlab.WithContext(ctx),
 	)
 	if err != nil {
 		c.log.Debug(fmt.Sprintf("Failed to list project pipelines, assuming pipelines exist - error: %v", err))
@@ -542,6 +1380,7 @@ func (c *Client) fetchPipelineJobs(pipelineID int64) ([]*Job, error) {
 	var perPage int64 = 100
 
 	for {
+		ctx, cancel := c.ctx()
 		jobs, resp, err := c.client.Jobs.ListPipelineJobs(
 			c.projectID,
 			pipelineID,
@@ -551,7 +1390,9 @@ func (c *Client) fetchPipelineJobs(pipelineID int64) ([]*Job, error) {
 					PerPage: perPage,
 				},
 			},
+			gitlab.WithContext(ctx),
 		)
+		cancel()
 		if err != nil {
 			return nil, fmt.Errorf("failed to list pipeline jobs: %w", err)
 		}
@@ -0,0 +1,1019 @@
+package gitlab_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sgaunet/auto-mr/internal/timeutil"
+	"github.com/sgaunet/auto-mr/pkg/gitlab"
+)
+
+// redirectTransport sends every request to the test server, whatever its original host.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (rt redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = rt.target.Scheme
+	req.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(req) //nolint:wrapcheck // test transport
+}
+
+// newServerClient returns a GitLab client backed by mux, with project 42 selected.
+// Projects are served unless mux already serves them.
+func newServerClient(t *testing.T, mux *http.ServeMux) *gitlab.Client {
+	t.Helper()
+
+	projectRequest := httptest.NewRequest(http.MethodGet, "/api/v4/projects/owner%2Fproject", nil)
+	if _, pattern := mux.Handler(projectRequest); pattern == "" {
+		mux.HandleFunc("GET /api/v4/projects/{path}", func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `{"id": 42}`)
+		})
+	}
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("failed to parse server URL: %v", err)
+	}
+
+	t.Setenv("GITLAB_TOKEN", "test-token")
+	client, err := gitlab.NewClient(&http.Client{Transport: redirectTransport{target: target}})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if err := client.SetProjectFromURL("https://gitlab.com/owner/project.git"); err != nil {
+		t.Fatalf("failed to set project: %v", err)
+	}
+	return client
+}
+
+// TestApproveMergeRequestSkipsOwnMR verifies that approval is skipped for the MR author.
+func TestApproveMergeRequestSkipsOwnMR(t *testing.T) {
+	tests := []struct {
+		name        string
+		authorID    int
+		expectApply bool
+	}{
+		{"author is current user", 7, false},
+		{"author is someone else", 8, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			approved := false
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v4/user", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `{"id": 7, "username": "me"}`)
+			})
+			mux.HandleFunc("GET /api/v4/projects/42/merge_requests/5", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprintf(w, `{"iid": 5, "author": {"id": %d}}`, tt.authorID)
+			})
+			mux.HandleFunc("POST /api/v4/projects/42/merge_requests/5/approve", func(w http.ResponseWriter, _ *http.Request) {
+				approved = true
+				fmt.Fprint(w, `{}`)
+			})
+
+			client := newServerClient(t, mux)
+			if err := client.ApproveMergeRequest(5); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if approved != tt.expectApply {
+				t.Errorf("approve endpoint called = %v, want %v", approved, tt.expectApply)
+			}
+		})
+	}
+}
+
+// TestNewClientSudo verifies that GITLAB_SUDO is sent with every request.
+func TestNewClientSudo(t *testing.T) {
+	t.Setenv("GITLAB_SUDO", "jane")
+
+	var sudo []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/{path}", func(w http.ResponseWriter, r *http.Request) {
+		sudo = append(sudo, r.Header.Get("Sudo"))
+		fmt.Fprint(w, `{"id": 42}`)
+	})
+	mux.HandleFunc("GET /api/v4/user", func(w http.ResponseWriter, r *http.Request) {
+		sudo = append(sudo, r.Header.Get("Sudo"))
+		fmt.Fprint(w, `{"id": 7, "username": "jane"}`)
+	})
+
+	client := newServerClient(t, mux)
+	username, err := client.CurrentUsername()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if username != "jane" || !slices.Equal(sudo, []string{"jane", "jane"}) {
+		t.Errorf("expected both requests made as jane, got user %q, Sudo headers %v", username, sudo)
+	}
+}
+
+// TestApproveMergeRequestWithPassword verifies that GITLAB_APPROVAL_PASSWORD is sent with the approval.
+func TestApproveMergeRequestWithPassword(t *testing.T) {
+	t.Setenv("GITLAB_APPROVAL_PASSWORD", "s3cret")
+
+	var got string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/user", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id": 7, "username": "me"}`)
+	})
+	mux.HandleFunc("GET /api/v4/projects/42/merge_requests/5", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"iid": 5, "author": {"id": 8}}`)
+	})
+	mux.HandleFunc("POST /api/v4/projects/42/merge_requests/5/approve", func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			ApprovalPassword string `json:"approval_password"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		got = payload.ApprovalPassword
+		fmt.Fprint(w, `{}`)
+	})
+
+	client := newServerClient(t, mux)
+	if err := client.ApproveMergeRequest(5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "s3cret" {
+		t.Errorf("expected approval_password %q, got %q", "s3cret", got)
+	}
+}
+
+// TestCheckMergeable verifies conflict detection from the detailed merge status.
+func TestCheckMergeable(t *testing.T) {
+	tests := []struct {
+		name      string
+		response  string
+		wantError bool
+	}{
+		{"mergeable", `{"iid": 5, "detailed_merge_status": "mergeable"}`, false},
+		{"waiting for pipeline", `{"iid": 5, "detailed_merge_status": "ci_still_running"}`, false},
+		{"conflict", `{"iid": 5, "detailed_merge_status": "conflict", "has_conflicts": true}`, true},
+		{"needs rebase", `{"iid": 5, "detailed_merge_status": "need_rebase"}`, true},
+		{"has conflicts flag only", `{"iid": 5, "detailed_merge_status": "not_approved", "has_conflicts": true}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v4/projects/42/merge_requests/5", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, tt.response)
+			})
+
+			err := newServerClient(t, mux).CheckMergeable(5)
+			if got := errors.Is(err, gitlab.ErrMRConflict); got != tt.wantError {
+				t.Errorf("CheckMergeable() error = %v, want conflict: %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+// TestCreateMergeRequestUsesUserCache verifies that resolved user IDs are reused across runs.
+func TestCreateMergeRequestUsesUserCache(t *testing.T) {
+	cachePath := t.TempDir() + "/users.json"
+	userLookups := 0
+
+	for range 2 {
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v4/users", func(w http.ResponseWriter, r *http.Request) {
+			userLookups++
+			fmt.Fprintf(w, `[{"id": 10, "username": %q}]`, r.URL.Query().Get("username"))
+		})
+		mux.HandleFunc("POST /api/v4/projects/42/merge_requests", func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `{"iid": 1, "sha": "abc"}`)
+		})
+
+		client := newServerClient(t, mux)
+		client.SetUserCache(cachePath)
+		if _, err := client.CreateMergeRequest("feature", "main", "Title", "", "alice", "bob", nil, false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if userLookups != 2 {
+		t.Errorf("expected 2 user lookups (first run only), got %d", userLookups)
+	}
+}
+
+// TestCreateMergeRequestWithUserIDs verifies that "id:<n>" users are sent as is,
+// without looking them up by username.
+func TestCreateMergeRequestWithUserIDs(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/users", func(w http.ResponseWriter, _ *http.Request) {
+		t.Error("unexpected user lookup")
+		fmt.Fprint(w, `[]`)
+	})
+	var payload struct {
+		AssigneeID  int64   `json:"assignee_id"`
+		ReviewerIDs []int64 `json:"reviewer_ids"`
+	}
+	mux.HandleFunc("POST /api/v4/projects/42/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		fmt.Fprint(w, `{"iid": 1, "sha": "abc"}`)
+	})
+
+	client := newServerClient(t, mux)
+	if _, err := client.CreateMergeRequest("feature", "main", "Title", "", "id:101", "id:202", nil, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if payload.AssigneeID != 101 {
+		t.Errorf("assignee_id = %d, want 101", payload.AssigneeID)
+	}
+	if len(payload.ReviewerIDs) != 1 || payload.ReviewerIDs[0] != 202 {
+		t.Errorf("reviewer_ids = %v, want [202]", payload.ReviewerIDs)
+	}
+}
+
+// TestCreateMergeRequestWithoutUsers verifies that an empty assignee and reviewer are
+// left out of the request instead of failing the lookup.
+func TestCreateMergeRequestWithoutUsers(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/users", func(w http.ResponseWriter, _ *http.Request) {
+		t.Error("unexpected user lookup")
+		fmt.Fprint(w, `[]`)
+	})
+	var payload map[string]any
+	mux.HandleFunc("POST /api/v4/projects/42/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		fmt.Fprint(w, `{"iid": 1, "sha": "abc"}`)
+	})
+
+	client := newServerClient(t, mux)
+	if _, err := client.CreateMergeRequest("feature", "main", "Title", "", "", "", nil, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, found := payload["assignee_id"]; found {
+		t.Errorf("expected no assignee_id, got %v", payload["assignee_id"])
+	}
+	if reviewers, _ := payload["reviewer_ids"].([]any); len(reviewers) != 0 {
+		t.Errorf("expected no reviewer_ids, got %v", reviewers)
+	}
+}
+
+// TestWaitForApprovals verifies the approval check without waiting.
+func TestWaitForApprovals(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		state     string
+		expectErr error
+	}{
+		{
+			name:   "rules satisfied",
+			status: http.StatusOK,
+			state:  `{"rules": [{"name": "Maintainers", "approvals_required": 1, "approved": true}]}`,
+		},
+		{
+			name:   "approvals missing",
+			status: http.StatusOK,
+			state: `{"rules": [{"name": "Maintainers", "approvals_required": 2, "approved": false,
+				"approved_by": [{"id": 1}]}]}`,
+			expectErr: gitlab.ErrApprovalsPending,
+		},
+		{
+			name:   "approval state unavailable",
+			status: http.StatusNotFound,
+			state:  `{"message": "404 Not Found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v4/projects/42/merge_requests/5/approval_state",
+				func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(tt.status)
+					fmt.Fprint(w, tt.state)
+				})
+
+			client := newServerClient(t, mux)
+			err := client.WaitForApprovals(5, 0)
+			if !errors.Is(err, tt.expectErr) {
+				t.Errorf("expected %v, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+// TestRequestTimeout verifies that a stalled API call fails once the request timeout expires.
+func TestRequestTimeout(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/42/labels", func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	client := newServerClient(t, mux)
+	client.SetRequestTimeout(50 * time.Millisecond)
+
+	_, err := client.ListLabels()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+// TestRebaseMergeRequest verifies that a finished rebase succeeds and that GitLab's
+// merge error (e.g. conflicts) is reported as ErrRebaseFailed.
+func TestRebaseMergeRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		mr      string
+		wantErr error
+	}{
+		{name: "rebased", mr: `{"iid": 7, "sha": "abc123", "rebase_in_progress": false}`},
+		{
+			name:    "conflict",
+			mr:      `{"iid": 7, "rebase_in_progress": false, "merge_error": "Rebase failed: conflicts"}`,
+			wantErr: gitlab.ErrRebaseFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT /api/v4/projects/42/merge_requests/7/rebase", func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+				fmt.Fprint(w, `{"rebase_in_progress": true}`)
+			})
+			mux.HandleFunc("GET /api/v4/projects/42/merge_requests/7", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("include_rebase_in_progress") != "true" {
+					t.Errorf("expected include_rebase_in_progress=true, got %q", r.URL.RawQuery)
+				}
+				fmt.Fprint(w, tt.mr)
+			})
+			client := newServerClient(t, mux)
+
+			err := client.RebaseMergeRequest(7, time.Minute)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestCreateMergeRequestNote verifies that the comment body is posted as a merge request note.
+func TestCreateMergeRequestNote(t *testing.T) {
+	var got string
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v4/projects/42/merge_requests/7/notes", func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Body string `json:"body"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		got = payload.Body
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id": 1}`)
+	})
+	client := newServerClient(t, mux)
+
+	if err := client.CreateMergeRequestNote(7, "- abc1234 feat: x"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "- abc1234 feat: x" {
+		t.Errorf("expected note body %q, got %q", "- abc1234 feat: x", got)
+	}
+}
+
+// TestAddReviewers verifies that users and group members are added to the current
+// reviewers, leaving out the author.
+func TestAddReviewers(t *testing.T) {
+	var reviewerIDs []int64
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/42/merge_requests/7", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"iid": 7, "author": {"id": 1}, "reviewers": [{"id": 2}]}`)
+	})
+	mux.HandleFunc("GET /api/v4/users", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("username") == "alice" {
+			fmt.Fprint(w, `[{"id": 3, "username": "alice"}]`)
+			return
+		}
+		fmt.Fprint(w, `[]`)
+	})
+	mux.HandleFunc("GET /api/v4/groups/{group}/members", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("group") != "backend" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `[{"id": 1, "state": "active"}, {"id": 3, "state": "active"},
+			{"id": 4, "state": "active"}, {"id": 5, "state": "blocked"}]`)
+	})
+	mux.HandleFunc("PUT /api/v4/projects/42/merge_requests/7", func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			ReviewerIDs []int64 `json:"reviewer_ids"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		reviewerIDs = payload.ReviewerIDs
+		fmt.Fprint(w, `{"iid": 7}`)
+	})
+	client := newServerClient(t, mux)
+
+	if err := client.AddReviewers(7, []string{"alice", "backend"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(reviewerIDs) != "[2 3 4]" {
+		t.Errorf("expected reviewers [2 3 4], got %v", reviewerIDs)
+	}
+
+	err := client.AddReviewers(7, []string{"nobody"})
+	if !errors.Is(err, gitlab.ErrReviewerNotFound) {
+		t.Errorf("expected ErrReviewerNotFound, got %v", err)
+	}
+}
+
+func TestMarkMergeRequestReady(t *testing.T) {
+	var title string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/42/merge_requests/7", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"iid": 7, "title": "Draft: feat: add x", "draft": true}`)
+	})
+	mux.HandleFunc("GET /api/v4/projects/42/merge_requests/8", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"iid": 8, "title": "feat: add y", "draft": false}`)
+	})
+	mux.HandleFunc("PUT /api/v4/projects/42/merge_requests/7", func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Title string `json:"title"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		title = payload.Title
+		fmt.Fprint(w, `{"iid": 7}`)
+	})
+	client := newServerClient(t, mux)
+
+	changed, err := client.MarkMergeRequestReady(7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !changed || title != "feat: add x" {
+		t.Errorf("expected title %q to be set, got changed=%v title=%q", "feat: add x", changed, title)
+	}
+
+	changed, err = client.MarkMergeRequestReady(8)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if changed {
+		t.Error("expected a non-draft merge request to be left as is")
+	}
+}
+
+func TestEnableAutoMerge(t *testing.T) {
+	var payload struct {
+		AutoMerge                bool `json:"auto_merge"`
+		Squash                   bool `json:"squash"`
+		ShouldRemoveSourceBranch bool `json:"should_remove_source_branch"`
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /api/v4/projects/42/merge_requests/7/merge", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		fmt.Fprint(w, `{"iid": 7, "state": "opened"}`)
+	})
+	client := newServerClient(t, mux)
+
+	if err := client.EnableAutoMerge(7, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !payload.AutoMerge || !payload.Squash || !payload.ShouldRemoveSourceBranch {
+		t.Errorf("expected auto_merge, squash and should_remove_source_branch, got %+v", payload)
+	}
+}
+
+func TestAddMergeRequestLabels(t *testing.T) {
+	var addLabels string
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /api/v4/projects/42/merge_requests/7", func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			AddLabels string  `json:"add_labels"`
+			Labels    *string `json:"labels"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if payload.Labels != nil {
+			t.Errorf("expected the current labels to be kept, got labels=%q", *payload.Labels)
+		}
+		addLabels = payload.AddLabels
+		fmt.Fprint(w, `{"iid": 7}`)
+	})
+	client := newServerClient(t, mux)
+
+	if err := client.AddMergeRequestLabels(7, []string{"merged-by-bot"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if addLabels != "merged-by-bot" {
+		t.Errorf("expected add_labels %q, got %q", "merged-by-bot", addLabels)
+	}
+}
+
+func TestCloseMergeRequest(t *testing.T) {
+	var stateEvent string
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /api/v4/projects/42/merge_requests/7", func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			StateEvent string `json:"state_event"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		stateEvent = payload.StateEvent
+		fmt.Fprint(w, `{"iid": 7, "state": "closed"}`)
+	})
+	client := newServerClient(t, mux)
+
+	if err := client.CloseMergeRequest(7); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stateEvent != "close" {
+		t.Errorf("expected state_event %q, got %q", "close", stateEvent)
+	}
+}
+
+// TestMergeCommitSHA verifies that the commit left at the head of the target branch is
+// recorded by the merge: the merge commit, else the squash commit, else the source head.
+func TestMergeCommitSHA(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		expected string
+	}{
+		{"merge commit", `{"iid": 7, "sha": "head", "squash_commit_sha": "squash", "merge_commit_sha": "merge"}`, "merge"},
+		{"squash fast-forward", `{"iid": 7, "sha": "head", "squash_commit_sha": "squash"}`, "squash"},
+		{"fast-forward", `{"iid": 7, "sha": "head"}`, "head"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT /api/v4/projects/42/merge_requests/7/merge", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, tt.response)
+			})
+			client := newServerClient(t, mux)
+
+			if err := client.MergeMergeRequest(7, true, "Title"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := client.MergeCommitSHA(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestGetMergedMergeRequest verifies that only a merged MR at the given head SHA matches.
+func TestGetMergedMergeRequest(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/42/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("state"); got != "merged" {
+			t.Errorf("expected state=merged, got %q", got)
+		}
+		fmt.Fprint(w, `[{"iid": 3, "sha": "old"}, {"iid": 4, "sha": "abc123", "web_url": "https://gitlab.com/mr/4"}]`)
+	})
+	client := newServerClient(t, mux)
+
+	mr, err := client.GetMergedMergeRequest("feature", "main", "abc123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mr.IID != 4 {
+		t.Errorf("expected MR !4, got !%d", mr.IID)
+	}
+
+	_, err = client.GetMergedMergeRequest("feature", "main", "new")
+	if !errors.Is(err, gitlab.ErrMRNotFound) {
+		t.Errorf("expected %v, got %v", gitlab.ErrMRNotFound, err)
+	}
+}
+
+// newPipelineClient returns a client backed by mux with MR 5 selected, whose pipeline list is
+// served by pipelines and the jobs of pipeline 1 by jobs (none when nil), and a fake clock
+// driving the wait. MR 5 has head commit abc, unless mux already serves it.
+func newPipelineClient(
+	t *testing.T, mux *http.ServeMux, pipelines, jobs http.HandlerFunc,
+) (*gitlab.Client, *timeutil.FakeClock) {
+	t.Helper()
+
+	mux.HandleFunc("GET /api/v4/projects/42/merge_requests", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[{"iid": 5}]`)
+	})
+	mrRequest := httptest.NewRequest(http.MethodGet, "/api/v4/projects/42/merge_requests/5", nil)
+	if _, pattern := mux.Handler(mrRequest); pattern == "" {
+		mux.HandleFunc("GET /api/v4/projects/42/merge_requests/5", func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `{"iid": 5, "sha": "abc"}`)
+		})
+	}
+	mux.HandleFunc("GET /api/v4/projects/42/pipelines", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[{"id": 1}]`)
+	})
+	if jobs == nil {
+		jobs = func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `[]`)
+		}
+	}
+	mux.HandleFunc("GET /api/v4/projects/42/pipelines/1/jobs", jobs)
+	mux.HandleFunc("GET /api/v4/projects/42/merge_requests/5/pipelines", pipelines)
+	client := newServerClient(t, mux)
+	if _, err := client.GetMergeRequestByBranch("feature", "main"); err != nil {
+		t.Fatalf("failed to get merge request: %v", err)
+	}
+
+	clock := timeutil.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	client.SetClock(clock)
+	return client, clock
+}
+
+// TestWaitForPipelineTimeout verifies that the wait polls every 5 seconds until the timeout.
+func TestWaitForPipelineTimeout(t *testing.T) {
+	polls := 0
+	client, clock := newPipelineClient(t, http.NewServeMux(), func(w http.ResponseWriter, _ *http.Request) {
+		polls++
+		fmt.Fprint(w, `[{"id": 1, "status": "running"}]`)
+	}, nil)
+
+	_, err := client.WaitForPipeline(time.Minute)
+	if !errors.Is(err, gitlab.ErrPipelineTimeout) {
+		t.Fatalf("expected ErrPipelineTimeout, got %v", err)
+	}
+	if polls != 12 || clock.Sleeps() != 12 {
+		t.Errorf("expected 12 polls and sleeps in 1m, got %d polls and %d sleeps", polls, clock.Sleeps())
+	}
+}
+
+// TestWaitForPipelineStartTimeout verifies that the wait gives up once no pipeline showed
+// up within the start timeout, well before the pipeline timeout.
+func TestWaitForPipelineStartTimeout(t *testing.T) {
+	client, clock := newPipelineClient(t, http.NewServeMux(), func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[]`)
+	}, nil)
+	client.SetStartTimeout(30 * time.Second)
+
+	_, err := client.WaitForPipeline(time.Hour)
+	if !errors.Is(err, gitlab.ErrNoPipeline) {
+		t.Fatalf("expected ErrNoPipeline, got %v", err)
+	}
+	if clock.Sleeps() != 6 {
+		t.Errorf("expected 6 poll sleeps in 30s, got %d sleeps", clock.Sleeps())
+	}
+}
+
+// TestWaitForPipelineCompletes verifies that the wait returns the pipeline status once it completes.
+func TestWaitForPipelineCompletes(t *testing.T) {
+	tests := []struct {
+		name   string
+		final  string
+		expect string
+	}{
+		{"success", "success", "success"},
+		{"failure", "failed", "failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := 0
+			client, clock := newPipelineClient(t, http.NewServeMux(), func(w http.ResponseWriter, _ *http.Request) {
+				polls++
+				status := "running"
+				if polls == 3 {
+					status = tt.final
+				}
+				fmt.Fprintf(w, `[{"id": 1, "status": %q}]`, status)
+			}, nil)
+
+			status, err := client.WaitForPipeline(time.Hour)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if status != tt.expect {
+				t.Errorf("expected status %q, got %q", tt.expect, status)
+			}
+			if clock.Sleeps() != 2 {
+				t.Errorf("expected 2 sleeps before the third poll, got %d", clock.Sleeps())
+			}
+		})
+	}
+}
+
+// TestWaitForPipelineSkippedAndManualJobs verifies that skipped and manual jobs count as
+// done without failing the pipeline, including jobs left "created" behind a blocking manual job.
+func TestWaitForPipelineSkippedAndManualJobs(t *testing.T) {
+	tests := []struct {
+		name           string
+		pipelineStatus string
+		jobs           string
+	}{
+		{
+			name:           "all skipped",
+			pipelineStatus: "skipped",
+			jobs: `[{"id": 1, "name": "lint", "status": "skipped", "created_at": "2025-01-01T00:00:00Z"},
+				{"id": 2, "name": "test", "status": "skipped", "created_at": "2025-01-01T00:00:00Z"}]`,
+		},
+		{
+			name:           "manual jobs only",
+			pipelineStatus: "manual",
+			jobs:           `[{"id": 1, "name": "deploy", "status": "manual", "created_at": "2025-01-01T00:00:00Z"}]`,
+		},
+		{
+			name:           "blocked on a manual job",
+			pipelineStatus: "manual",
+			jobs: `[{"id": 1, "name": "build", "status": "success", "created_at": "2025-01-01T00:00:00Z"},
+				{"id": 2, "name": "approve", "status": "manual", "created_at": "2025-01-01T00:00:00Z"},
+				{"id": 3, "name": "deploy", "status": "created", "created_at": "2025-01-01T00:00:00Z"}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, clock := newPipelineClient(t, http.NewServeMux(), func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprintf(w, `[{"id": 1, "status": %q}]`, tt.pipelineStatus)
+			}, func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, tt.jobs)
+			})
+
+			status, err := client.WaitForPipeline(time.Hour)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if status != "success" {
+				t.Errorf("expected success, got %q", status)
+			}
+			if clock.Sleeps() != 0 {
+				t.Errorf("expected the first poll to complete the wait, got %d sleeps", clock.Sleeps())
+			}
+		})
+	}
+}
+
+// TestWaitForPipelineWaitsForQueuedJobs verifies that jobs waiting for a resource are still running.
+func TestWaitForPipelineWaitsForQueuedJobs(t *testing.T) {
+	polls := 0
+	client, clock := newPipelineClient(t, http.NewServeMux(), func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[{"id": 1, "status": "running"}]`)
+	}, func(w http.ResponseWriter, _ *http.Request) {
+		polls++
+		status := "waiting_for_resource"
+		if polls > 1 {
+			status = "success"
+		}
+		fmt.Fprintf(w, `[{"id": 1, "name": "deploy", "status": %q, "created_at": "2025-01-01T00:00:00Z"}]`, status)
+	})
+
+	status, err := client.WaitForPipeline(time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != "success" || clock.Sleeps() != 1 {
+		t.Errorf("expected success after one more poll, got %q after %d sleeps", status, clock.Sleeps())
+	}
+}
+
+// TestWaitForPipelineFreshCI verifies that pipelines created before the time set with
+// SetCISince are ignored, so that an earlier green run of the same commit is not accepted.
+func TestWaitForPipelineFreshCI(t *testing.T) {
+	polls := 0
+	client, clock := newPipelineClient(t, http.NewServeMux(), func(w http.ResponseWriter, _ *http.Request) {
+		polls++
+		old := `{"id": 7, "status": "success", "created_at": "2024-12-31T12:00:00Z"}`
+		switch polls {
+		case 1:
+			fmt.Fprintf(w, `[%s]`, old)
+		case 2:
+			fmt.Fprintf(w, `[{"id": 1, "status": "running", "created_at": "2025-01-01T00:00:10Z"}, %s]`, old)
+		default:
+			fmt.Fprintf(w, `[{"id": 1, "status": "failed", "created_at": "2025-01-01T00:00:10Z"}, %s]`, old)
+		}
+	}, nil)
+	client.SetCISince(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	status, err := client.WaitForPipeline(time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != "failed" || clock.Sleeps() != 2 {
+		t.Errorf("expected the new pipeline's failed status after 2 sleeps, got %q after %d sleeps",
+			status, clock.Sleeps())
+	}
+}
+
+// TestWaitForPipelineBranchUpdated verifies that when the branch is pushed to during the
+// wait, the pipeline of the previous head commit is dropped, even once it failed, and the
+// pipeline of the new one is waited for.
+func TestWaitForPipelineBranchUpdated(t *testing.T) {
+	mrFetches, polls := 0, 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/42/merge_requests/5", func(w http.ResponseWriter, _ *http.Request) {
+		mrFetches++
+		sha := "abc"
+		if mrFetches > 2 { // fetched by branch, then when the wait starts
+			sha = "def"
+		}
+		fmt.Fprintf(w, `{"iid": 5, "sha": %q}`, sha)
+	})
+	mux.HandleFunc("GET /api/v4/projects/42/pipelines/2/jobs", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+	client, clock := newPipelineClient(t, mux, func(w http.ResponseWriter, _ *http.Request) {
+		polls++
+		switch polls {
+		case 1:
+			fmt.Fprint(w, `[{"id": 1, "sha": "abc", "status": "running"}]`)
+		case 2:
+			fmt.Fprint(w, `[{"id": 2, "sha": "def", "status": "running"}, {"id": 1, "sha": "abc", "status": "failed"}]`)
+		default:
+			fmt.Fprint(w, `[{"id": 2, "sha": "def", "status": "success"}, {"id": 1, "sha": "abc", "status": "failed"}]`)
+		}
+	}, nil)
+
+	status, err := client.WaitForPipeline(time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != "success" || clock.Sleeps() != 2 {
+		t.Errorf("expected the new commit's pipeline to succeed after 2 sleeps, got %q after %d sleeps",
+			status, clock.Sleeps())
+	}
+}
+
+// TestWaitForPipelineManualJobs verifies that manual jobs are not waited for by default,
+// and are started once then waited for when playing them is enabled.
+func TestWaitForPipelineManualJobs(t *testing.T) {
+	tests := []struct {
+		name        string
+		play        bool
+		expectPlays int
+		expectSleep int
+	}{
+		{"ignored", false, 0, 0},
+		{"played", true, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plays := 0
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /api/v4/projects/42/jobs/2/play", func(w http.ResponseWriter, _ *http.Request) {
+				plays++
+				fmt.Fprint(w, `{"id": 2, "status": "pending"}`)
+			})
+			client, clock := newPipelineClient(t, mux, func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `[{"id": 1, "status": "success"}]`)
+			}, func(w http.ResponseWriter, _ *http.Request) {
+				deploy := "manual"
+				if plays > 0 {
+					deploy = "success"
+				}
+				fmt.Fprintf(w, `[{"id": 1, "name": "test", "status": "success", "created_at": "2025-01-01T00:00:00Z"},
+					{"id": 2, "name": "deploy", "status": %q, "created_at": "2025-01-01T00:00:00Z"}]`, deploy)
+			})
+			client.SetPlayManualJobs(tt.play)
+
+			status, err := client.WaitForPipeline(time.Hour)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if status != "success" {
+				t.Errorf("expected success, got %q", status)
+			}
+			if plays != tt.expectPlays || clock.Sleeps() != tt.expectSleep {
+				t.Errorf("expected %d plays and %d sleeps, got %d and %d",
+					tt.expectPlays, tt.expectSleep, plays, clock.Sleeps())
+			}
+		})
+	}
+}
+
+// TestWaitForDeployments verifies that only the deployments of the ref created since
+// the merge are followed, until they finish or the timeout.
+func TestWaitForDeployments(t *testing.T) {
+	tests := []struct {
+		name      string
+		statuses  []string // status of the new deployment at each poll
+		expect    string
+		wantError error
+	}{
+		{"success", []string{"created", "running", "success"}, "success", nil},
+		{"failure", []string{"running", "failed"}, "failed", nil},
+		{"timeout", []string{"blocked"}, "", gitlab.ErrDeploymentTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := 0
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v4/projects/42/deployments", func(w http.ResponseWriter, _ *http.Request) {
+				status := tt.statuses[min(polls, len(tt.statuses)-1)]
+				polls++
+				fmt.Fprintf(w, `[
+					{"id": 3, "iid": 3, "ref": "feature", "status": "running", "created_at": "2025-01-01T00:00:20Z"},
+					{"id": 2, "iid": 2, "ref": "main", "status": %q, "created_at": "2025-01-01T00:00:10Z",
+					 "environment": {"name": "production"}},
+					{"id": 1, "iid": 1, "ref": "main", "status": "running", "created_at": "2024-12-31T00:00:00Z"}]`,
+					status)
+			})
+			client := newServerClient(t, mux)
+			since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			client.SetClock(timeutil.NewFakeClock(since))
+
+			status, err := client.WaitForDeployments("main", since, time.Minute)
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Fatalf("expected %v, got %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if status != tt.expect {
+				t.Errorf("expected %q, got %q", tt.expect, status)
+			}
+			if polls != len(tt.statuses) {
+				t.Errorf("expected %d polls, got %d", len(tt.statuses), polls)
+			}
+		})
+	}
+}
+
+// TestGetMergeRequestByBranchDuplicates verifies that the newest of several open MRs is used
+// and the others are reported as duplicates.
+func TestGetMergeRequestByBranchDuplicates(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/42/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+		if query := r.URL.Query(); query.Get("order_by") != "created_at" || query.Get("sort") != "desc" {
+			t.Errorf("expected newest first, got %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `[{"iid": 9}, {"iid": 4}, {"iid": 2}]`)
+	})
+	mux.HandleFunc("GET /api/v4/projects/42/merge_requests/9", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"iid": 9, "sha": "abc"}`)
+	})
+	client := newServerClient(t, mux)
+
+	mr, err := client.GetMergeRequestByBranch("feature", "main")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mr.IID != 9 {
+		t.Errorf("expected MR 9, got %d", mr.IID)
+	}
+	if duplicates := client.DuplicateMergeRequests(); !slices.Equal(duplicates, []int64{4, 2}) {
+		t.Errorf("expected duplicates [4 2], got %v", duplicates)
+	}
+}
+
+// TestSetUserAgent verifies that API requests carry the configured User-Agent.
+func TestSetUserAgent(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/42/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.UserAgent(); got != "auto-mr/1.2.3" {
+			t.Errorf("expected User-Agent auto-mr/1.2.3, got %q", got)
+		}
+		fmt.Fprint(w, `[]`)
+	})
+	client := newServerClient(t, mux)
+	client.SetUserAgent("auto-mr/1.2.3")
+
+	if _, err := client.GetMergeRequestsByBranch("feature"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// TestWaitForPipelineIgnoredChecks verifies that ignored jobs neither fail nor hold up the wait.
+func TestWaitForPipelineIgnoredChecks(t *testing.T) {
+	client, clock := newPipelineClient(t, http.NewServeMux(), func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[{"id": 1, "status": "running"}]`)
+	}, func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[{"id": 1, "name": "build", "status": "success", "created_at": "2025-01-01T00:00:00Z"},
+			{"id": 2, "name": "flaky-e2e", "status": "failed", "created_at": "2025-01-01T00:00:00Z"},
+			{"id": 3, "name": "flaky-perf", "status": "running", "created_at": "2025-01-01T00:00:00Z"}]`)
+	})
+	client.SetIgnoredChecks([]string{"flaky-*"})
+
+	status, err := client.WaitForPipeline(time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != "success" || clock.Sleeps() != 0 {
+		t.Errorf("expected success without waiting, got %q after %d sleeps", status, clock.Sleeps())
+	}
+}
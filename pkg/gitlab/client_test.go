@@ -40,7 +40,7 @@ func TestNewClientWhitespaceTokenTrimmed(t *testing.T) {
 		}
 	}()
 
-	_, err := gitlab.NewClient()
+	_, err := gitlab.NewClient(nil)
 	if !errors.Is(err, gitlab.ErrTokenRequired) {
 		t.Errorf("expected ErrTokenRequired for whitespace-only token, got: %v", err)
 	}
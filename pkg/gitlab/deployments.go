@@ -0,0 +1,100 @@
+package gitlab
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sgaunet/auto-mr/internal/timeutil"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+// WaitForDeployments waits for the deployments of ref created at or after since,
+// typically the ones started to the project environments by the pipeline of a merge
+// into ref, to finish.
+//
+// Returns "success" once every deployment succeeded or was skipped, or the status
+// of the first one that failed ("failed" or "canceled").
+// Returns [ErrDeploymentTimeout] if no deployment appeared, or one was still
+// running or blocked, when the timeout is exceeded.
+func (c *Client) WaitForDeployments(ref string, since time.Time, timeout time.Duration) (string, error) {
+	c.log.Debug(fmt.Sprintf("Waiting for deployments of %s since %s, timeout: %v",
+		ref, since.Format(time.RFC3339), timeout))
+	c.display.Info("Waiting for deployments of " + ref + "...")
+	c.display.IncreasePadding()
+	defer c.display.DecreasePadding()
+
+	start := c.clock.Now()
+	reported := make(map[int64]string)
+	for {
+		deployments, err := c.listDeploymentsSince(ref, since)
+		if err != nil {
+			return "", err
+		}
+
+		done := len(deployments) > 0
+		for _, deployment := range deployments {
+			environment := deploymentEnvironment(deployment)
+			if reported[deployment.ID] != deployment.Status {
+				reported[deployment.ID] = deployment.Status
+				c.display.Info(fmt.Sprintf("Deployment to %s: %s", environment, deployment.Status))
+			}
+
+			switch deployment.Status {
+			case statusSuccess, statusSkipped:
+			case statusFailed, statusCanceled:
+				c.display.Error(fmt.Sprintf("Deployment to %s failed - total time: %s",
+					environment, timeutil.FormatDuration(c.clock.Since(start))))
+				return deployment.Status, nil
+			default:
+				done = false
+			}
+		}
+
+		if done {
+			c.display.Success("Deployments completed successfully - total time: " +
+				timeutil.FormatDuration(c.clock.Since(start)))
+			return statusSuccess, nil
+		}
+
+		if c.clock.Since(start)+pipelinePollInterval > timeout {
+			c.display.Error("Timeout after " + timeutil.FormatDuration(c.clock.Since(start)))
+			if len(deployments) == 0 {
+				return "", fmt.Errorf("%w: no deployment of %s started", errDeploymentTimeout, ref)
+			}
+			return "", fmt.Errorf("%w: deployment of %s still in progress", errDeploymentTimeout, ref)
+		}
+		c.clock.Sleep(pipelinePollInterval)
+	}
+}
+
+// listDeploymentsSince returns the deployments of ref created at or after since.
+// Only the most recent page is fetched, newest first.
+func (c *Client) listDeploymentsSince(ref string, since time.Time) ([]*gitlab.Deployment, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+	deployments, _, err := c.client.Deployments.ListProjectDeployments(c.projectID,
+		&gitlab.ListProjectDeploymentsOptions{
+			ListOptions: gitlab.ListOptions{PerPage: deploymentsPageSize},
+			OrderBy:     new("created_at"),
+			Sort:        new("desc"),
+		}, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+
+	recent := make([]*gitlab.Deployment, 0, len(deployments))
+	for _, deployment := range deployments {
+		if deployment.Ref == ref && deployment.CreatedAt != nil && !deployment.CreatedAt.Before(since) {
+			recent = append(recent, deployment)
+		}
+	}
+	return recent, nil
+}
+
+// deploymentEnvironment returns the name of the environment a deployment targets.
+func deploymentEnvironment(deployment *gitlab.Deployment) string {
+	if deployment.Environment == nil || deployment.Environment.Name == "" {
+		return fmt.Sprintf("#%d", deployment.IID)
+	}
+	return deployment.Environment.Name
+}
@@ -1,6 +1,9 @@
 package gitlab_test
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/sgaunet/auto-mr/pkg/gitlab"
@@ -80,33 +83,52 @@ func TestEdgeCaseLongStrings(t *testing.T) {
 	}
 }
 
-// TestEdgeCaseURLVariations tests various URL format variations.
+// TestEdgeCaseURLVariations verifies the project path extracted from various remote
+// URL formats by SetProjectFromURL.
 func TestEdgeCaseURLVariations(t *testing.T) {
 	urls := []struct {
 		url       string
+		wantPath  string
 		shouldErr bool
 	}{
-		{"https://gitlab.com/owner/project.git", false},
-		{"https://gitlab.com/owner/project", false},
-		{"git@gitlab.com:owner/project.git", false},
-		{"ssh://git@gitlab.com/owner/project.git", false},
-		{"https://gitlab.example.com/owner/project.git", false},
-		{"not-a-url", true},
-		{"", true},
+		{"https://gitlab.com/owner/project.git", "owner/project", false},
+		{"https://gitlab.com/owner/project", "owner/project", false},
+		{"https://gitlab.com/owner/project/", "owner/project", false},
+		{"https://gitlab.com/owner/project.name.git", "owner/project.name", false},
+		{"https://gitlab.com/group/subgroup/project.git", "group/subgroup/project", false},
+		{"git@gitlab.com:owner/project.git", "owner/project", false},
+		{"git@gitlab.com:group/sub1/sub2/project.git", "group/sub1/sub2/project", false},
+		{"ssh://git@gitlab.com/owner/project.git", "owner/project", false},
+		{"https://gitlab.example.com/owner/project.git", "owner/project", false},
+		{"https://gitlab.com/owner/.git", "", true},
+		{"gitlab.com/owner/project.git", "", true},
+		{"not-a-url", "", true},
+		{"", "", true},
 	}
 
+	var gotPath string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/{path}", func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.PathValue("path")
+		fmt.Fprint(w, `{"id": 42}`)
+	})
+	client := newServerClient(t, mux)
+
 	for _, test := range urls {
 		t.Run("URL: "+test.url, func(t *testing.T) {
-			mockAPI := mocks.NewGitLabAPIClient()
+			gotPath = ""
+			err := client.SetProjectFromURL(test.url)
 			if test.shouldErr {
-				mockAPI.SetProjectFromURLError = gitlab.ErrInvalidURLFormat
+				if !errors.Is(err, gitlab.ErrInvalidURLFormat) {
+					t.Errorf("Expected ErrInvalidURLFormat, got %v", err)
+				}
+				return
 			}
-			err := mockAPI.SetProjectFromURL(test.url)
-			if test.shouldErr && err == nil {
-				t.Error("Expected error for invalid URL")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
 			}
-			if !test.shouldErr && err != nil {
-				t.Errorf("Unexpected error: %v", err)
+			if gotPath != test.wantPath {
+				t.Errorf("Expected project %q, got %q", test.wantPath, gotPath)
 			}
 		})
 	}
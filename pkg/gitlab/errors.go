@@ -9,8 +9,15 @@ var (
 	errAssigneeNotFound = errors.New("failed to find assignee user")
 	errReviewerNotFound = errors.New("failed to find reviewer user")
 	errPipelineTimeout  = errors.New("timeout waiting for pipeline completion")
+	errNoPipeline       = errors.New("no pipeline started")
 	errMRNotFound       = errors.New("no merge request found for branch")
 	errMRAlreadyExists  = errors.New("merge request already exists for this branch")
+	errMRConflict       = errors.New("merge request has conflicts with the target branch")
+	errUserNotFound     = errors.New("no GitLab user with this username")
+	errApprovalsPending = errors.New("merge request still requires approvals")
+	errRebaseFailed     = errors.New("failed to rebase merge request")
+
+	errDeploymentTimeout = errors.New("timeout waiting for deployment completion")
 
 	// ErrTokenRequired is returned when GITLAB_TOKEN environment variable is missing.
 	ErrTokenRequired = errTokenRequired
@@ -22,8 +29,18 @@ var (
 	ErrReviewerNotFound = errReviewerNotFound
 	// ErrPipelineTimeout is returned when waiting for pipeline completion times out.
 	ErrPipelineTimeout = errPipelineTimeout
+	// ErrNoPipeline is returned when no pipeline started within the start timeout.
+	ErrNoPipeline = errNoPipeline
 	// ErrMRNotFound is returned when no merge request is found for the branch.
 	ErrMRNotFound = errMRNotFound
 	// ErrMRAlreadyExists is returned when a merge request already exists for the branch.
 	ErrMRAlreadyExists = errMRAlreadyExists
+	// ErrMRConflict is returned when a merge request cannot be merged because of conflicts.
+	ErrMRConflict = errMRConflict
+	// ErrApprovalsPending is returned when approval rules are not satisfied yet.
+	ErrApprovalsPending = errApprovalsPending
+	// ErrRebaseFailed is returned when GitLab cannot rebase the source branch (e.g. conflicts).
+	ErrRebaseFailed = errRebaseFailed
+	// ErrDeploymentTimeout is returned when waiting for deployments times out.
+	ErrDeploymentTimeout = errDeploymentTimeout
 )
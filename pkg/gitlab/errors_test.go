@@ -68,6 +68,34 @@ func TestErrorMRNotFound(t *testing.T) {
 	})
 }
 
+// TestErrorMRConflict tests conflict detection before merging.
+func TestErrorMRConflict(t *testing.T) {
+	t.Run("conflicting MR is not merged", func(t *testing.T) {
+		mockAPI := mocks.NewGitLabAPIClient()
+		mockAPI.CheckMergeableError = fmt.Errorf("%w: !123", gitlab.ErrMRConflict)
+
+		err := mockAPI.CheckMergeable(123)
+		if !errors.Is(err, gitlab.ErrMRConflict) {
+			t.Errorf("Expected ErrMRConflict, got: %v", err)
+		}
+		if mockAPI.GetCallCount("MergeMergeRequest") != 0 {
+			t.Error("MergeMergeRequest should not be called")
+		}
+	})
+
+	t.Run("mergeable MR", func(t *testing.T) {
+		mockAPI := mocks.NewGitLabAPIClient()
+
+		if err := mockAPI.CheckMergeable(123); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		lastCall := mockAPI.GetLastCall("CheckMergeable")
+		if lastCall == nil || lastCall.Args["mrIID"] != int64(123) {
+			t.Errorf("Expected CheckMergeable call with mrIID 123, got %v", lastCall)
+		}
+	})
+}
+
 // TestErrorMRAlreadyExists tests MR already exists error detection.
 func TestErrorMRAlreadyExists(t *testing.T) {
 	scenarios := []struct {
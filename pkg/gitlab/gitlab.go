@@ -7,11 +7,13 @@
 //   - Label retrieval for interactive selection
 //
 // Authentication requires a GITLAB_TOKEN environment variable containing a
-// personal access token with api scope.
+// personal access token with api scope. Instances requiring a password to approve
+// merge requests also need GITLAB_APPROVAL_PASSWORD. GITLAB_SUDO impersonates a user
+// with an administrator token.
 //
 // Usage:
 //
-//	client, err := gitlab.NewClient()
+//	client, err := gitlab.NewClient(nil)
 //	client.SetLogger(logger)
 //	client.SetProjectFromURL("https://gitlab.com/org/repo.git")
 //	labels, _ := client.ListLabels()
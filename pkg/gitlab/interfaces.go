@@ -38,10 +38,22 @@ type APIClient interface {
 	// Returns an error if the approval fails.
 	ApproveMergeRequest(mrIID int64) error
 
+	// CheckMergeable verifies that a merge request has no conflicts with its target branch.
+	// Returns ErrMRConflict if GitLab reports conflicts or a required rebase.
+	CheckMergeable(mrIID int64) error
+
+	// WaitForApprovals waits up to timeout for the approval rules to be satisfied.
+	// Returns ErrApprovalsPending if approvals are still required.
+	WaitForApprovals(mrIID int64, timeout time.Duration) error
+
 	// MergeMergeRequest merges a merge request with optional squash.
 	// Returns an error if the merge fails.
 	MergeMergeRequest(mrIID int64, squash bool, commitTitle string) error
 
+	// CurrentUsername returns the username of the authenticated user.
+	// It is used to resolve the "@me" assignee.
+	CurrentUsername() (string, error)
+
 	// GetMergeRequestsByBranch returns all open merge requests for the given source branch.
 	GetMergeRequestsByBranch(sourceBranch string) ([]*gitlab.BasicMergeRequest, error)
 }
@@ -51,8 +63,8 @@ type APIClient interface {
 // of state transitions and display handle management without real API calls.
 type StateTracker interface {
 	// update processes new jobs, detects state transitions, and updates handles.
-	// Returns a list of state transition descriptions for logging/debugging.
-	update(newJobs []*Job, logger *bullets.UpdatableLogger) []string
+	// Returns the state transitions detected, for logging and the transition hook.
+	update(newJobs []*Job, logger *bullets.UpdatableLogger) []Transition
 
 	// getJob retrieves a job by ID with read lock.
 	// Returns the Job and a boolean indicating if the job exists.
@@ -2,18 +2,21 @@ package gitlab
 
 import (
 	"context"
-	"fmt"
 	"time"
 
+	"github.com/sgaunet/auto-mr/internal/logger"
 	"github.com/sgaunet/bullets"
 )
 
 // newJobTracker creates a new job tracker with initialized maps.
+// With plain progress ([logger.PlainProgress]), running jobs get static handles
+// reprinted at every poll instead of spinners.
 func newJobTracker() *jobTracker {
 	return &jobTracker{
 		jobs:     make(map[int64]*Job),
 		handles:  make(map[int64]*bullets.BulletHandle),
 		spinners: make(map[int64]*bullets.Spinner),
+		plain:    logger.PlainProgress(),
 	}
 }
 
@@ -73,9 +76,9 @@ func (jt *jobTracker) deleteSpinner(id int64) {
 }
 
 // update processes new jobs, detects state transitions, and updates handles.
-// Returns a list of state transition descriptions.
-func (jt *jobTracker) update(newJobs []*Job, logger *bullets.UpdatableLogger) []string {
-	var transitions []string
+// Returns the state transitions detected.
+func (jt *jobTracker) update(newJobs []*Job, logger *bullets.UpdatableLogger) []Transition {
+	var transitions []Transition
 	newJobIDs := make(map[int64]bool)
 
 	for _, newJob := range newJobs {
@@ -84,9 +87,8 @@ func (jt *jobTracker) update(newJobs []*Job, logger *bullets.UpdatableLogger) []
 		}
 
 		newJobIDs[newJob.ID] = true
-		transition := jt.processJobUpdate(newJob, logger)
-		if transition != "" {
-			transitions = append(transitions, transition)
+		if transition := jt.processJobUpdate(newJob, logger); transition != nil {
+			transitions = append(transitions, *transition)
 		}
 	}
 
@@ -97,7 +99,8 @@ func (jt *jobTracker) update(newJobs []*Job, logger *bullets.UpdatableLogger) []
 }
 
 // processJobUpdate handles the update logic for a single job.
-func (jt *jobTracker) processJobUpdate(newJob *Job, logger *bullets.UpdatableLogger) string {
+// Returns nil when the job's status did not change.
+func (jt *jobTracker) processJobUpdate(newJob *Job, logger *bullets.UpdatableLogger) *Transition {
 	oldJob, exists := jt.getJob(newJob.ID)
 
 	switch {
@@ -111,11 +114,11 @@ func (jt *jobTracker) processJobUpdate(newJob *Job, logger *bullets.UpdatableLog
 }
 
 // handleNewJob processes a newly detected job.
-func (jt *jobTracker) handleNewJob(newJob *Job, logger *bullets.UpdatableLogger) string {
+func (jt *jobTracker) handleNewJob(newJob *Job, logger *bullets.UpdatableLogger) *Transition {
 	jt.setJob(newJob.ID, newJob)
 	statusText := formatJobStatus(newJob)
 
-	if newJob.Status == statusRunning || newJob.Status == statusPending {
+	if !jt.plain && (newJob.Status == statusRunning || newJob.Status == statusPending) {
 		spinner := logger.SpinnerCircle(context.Background(), statusText)
 		jt.setSpinner(newJob.ID, spinner)
 		// Start time update loop for any job with spinner that has started timing
@@ -127,22 +130,29 @@ func (jt *jobTracker) handleNewJob(newJob *Job, logger *bullets.UpdatableLogger)
 		jt.setHandle(newJob.ID, handle)
 	}
 
-	return fmt.Sprintf("Job %d started: %s/%s", newJob.ID, newJob.Stage, newJob.Name)
+	return newTransition("", newJob)
 }
 
 // handleJobStatusChange processes a job with changed status.
-func (jt *jobTracker) handleJobStatusChange(oldJob, newJob *Job, logger *bullets.UpdatableLogger) string {
-	wasPulsing := oldJob.Status == statusRunning
-	isPulsing := newJob.Status == statusRunning
+func (jt *jobTracker) handleJobStatusChange(oldJob, newJob *Job, logger *bullets.UpdatableLogger) *Transition {
+	wasPulsing := !jt.plain && oldJob.Status == statusRunning
+	isPulsing := !jt.plain && newJob.Status == statusRunning
 
 	jt.updateHandleForJob(logger, newJob, wasPulsing, isPulsing)
 	jt.setJob(newJob.ID, newJob)
-	return fmt.Sprintf("Job %d: %s -> %s", newJob.ID, oldJob.Status, newJob.Status)
+	return newTransition(oldJob.Status, newJob)
 }
 
 // handleJobDataUpdate updates job data without status change.
-func (jt *jobTracker) handleJobDataUpdate(newJob *Job) string {
+func (jt *jobTracker) handleJobDataUpdate(newJob *Job) *Transition {
 	jt.setJob(newJob.ID, newJob)
+	if jt.plain {
+		// Static handles print a new line on update: report running jobs at every poll only
+		if handle, exists := jt.getHandle(newJob.ID); exists && newJob.Status == statusRunning {
+			handle.Update(bullets.InfoLevel, formatJobStatus(newJob))
+		}
+		return nil
+	}
 	// Update text only for non-running jobs (spinners display automatically)
 	if newJob.Status != statusRunning {
 		if handle, exists := jt.getHandle(newJob.ID); exists {
@@ -150,23 +160,39 @@ func (jt *jobTracker) handleJobDataUpdate(newJob *Job) string {
 			handle.Update(bullets.InfoLevel, statusText)
 		}
 	}
-	return ""
+	return nil
 }
 
 // detectRemovedJobs detects jobs that have been removed.
-func (jt *jobTracker) detectRemovedJobs(newJobIDs map[int64]bool) []string {
-	var transitions []string
+func (jt *jobTracker) detectRemovedJobs(newJobIDs map[int64]bool) []Transition {
+	var transitions []Transition
 	jt.mu.RLock()
 	defer jt.mu.RUnlock()
 
-	for id := range jt.jobs {
+	for id, job := range jt.jobs {
 		if !newJobIDs[id] {
-			transitions = append(transitions, fmt.Sprintf("Job %d removed", id))
+			removed := newTransition(job.Status, job)
+			removed.NewStatus = ""
+			transitions = append(transitions, *removed)
 		}
 	}
 	return transitions
 }
 
+// newTransition describes job reaching its current status from oldStatus.
+func newTransition(oldStatus string, job *Job) *Transition {
+	return &Transition{
+		JobID:      job.ID,
+		Name:       job.Name,
+		Stage:      job.Stage,
+		OldStatus:  oldStatus,
+		NewStatus:  job.Status,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+		ObservedAt: time.Now(),
+	}
+}
+
 // updateHandleForJob updates the display for a job when status changes.
 // wasPulsing and isPulsing control whether to start or stop the spinner animation.
 func (jt *jobTracker) updateHandleForJob(logger *bullets.UpdatableLogger, job *Job, wasPulsing, isPulsing bool) {
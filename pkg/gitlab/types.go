@@ -1,9 +1,11 @@
 package gitlab
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
+	"github.com/sgaunet/auto-mr/internal/timeutil"
 	"github.com/sgaunet/bullets"
 	gitlab "gitlab.com/gitlab-org/api/client-go"
 )
@@ -13,6 +15,15 @@ const (
 	minURLParts            = 2
 	pipelinePollInterval   = 5 * time.Second
 	spinnerUpdateInterval  = 1 * time.Second
+	statusLineInterval     = 30 * time.Second // periodic progress line when spinners are disabled
+	mergeabilityAttempts   = 5
+	mergeabilityInterval   = 2 * time.Second
+	approvalPollInterval   = 15 * time.Second
+	rebasePollInterval     = 2 * time.Second
+	membersPageSize        = 100
+	deploymentsPageSize    = 50
+	defaultAPIConcurrency  = 4 // concurrent pipeline job fetches
+	defaultRequestTimeout  = 30 * time.Second
 	maxJobDetailsToDisplay = 3
 	statusSuccess          = "success"
 	statusRunning          = "running"
@@ -21,6 +32,11 @@ const (
 	statusFailed           = "failed"
 	statusCanceled         = "canceled"
 	statusSkipped          = "skipped"
+	statusManual           = "manual"
+	statusScheduled        = "scheduled"
+	statusPreparing        = "preparing"
+
+	statusWaitingForResource = "waiting_for_resource"
 )
 
 // Client represents a GitLab API client wrapper that manages merge request
@@ -29,22 +45,39 @@ const (
 //
 // Not safe for concurrent use.
 type Client struct {
-	client       *gitlab.Client
-	projectID    string
-	mrIID        int64
-	mrSHA        string
-	log          *bullets.Logger
-	updatableLog *bullets.UpdatableLogger
-	display      *displayRenderer // Display renderer for UI output
+	client         *gitlab.Client
+	projectID      string
+	mrIID          int64
+	mrSHA          string
+	duplicates     []int64 // Other open MRs found by the last GetMergeRequestByBranch
+	log            *bullets.Logger
+	updatableLog   *bullets.UpdatableLogger
+	display        *displayRenderer // Display renderer for UI output
+	users          *userCache       // Optional username→ID cache (nil disables caching)
+	apiConcurrency int              // Max concurrent pipeline job fetches (<1: default)
+	requestTimeout time.Duration    // Per API call timeout (<=0: default)
+	approvalPass   string           // GITLAB_APPROVAL_PASSWORD, sent with approvals (never logged)
+	onTransition   func(Transition) // Optional job transition hook (nil disables it)
+	clock          timeutil.Clock   // Time source of the polling loops
+	playManual     bool             // Play manual jobs while waiting for the pipeline
+	playedJobs     map[int64]bool   // Manual jobs played during the current wait
+	ciSince        time.Time        // Pipelines created before are ignored while waiting (zero: none)
+	startTimeout   time.Duration    // Longest wait for a pipeline to start (0: the whole timeout)
+	requireChecks  bool             // Wait for a pipeline to start even when the commit has none yet
+	ignoredChecks  []string         // Job name patterns left out of the pipeline status
+	mergeSHA       string           // Commit created by the last merge (see Client.MergeCommitSHA)
 }
 
 // Label represents a GitLab label.
 type Label struct {
-	Name string
+	Name        string
+	Color       string // Hex color with a leading '#' (e.g. "#d73a4a"), empty if unknown
+	Description string
 }
 
 // Job represents a GitLab pipeline job with detailed status information.
-// Status values are: "created", "pending", "running", "success", "failed", "canceled", "skipped".
+// Status values are: "created", "waiting_for_resource", "preparing", "pending", "running",
+// "success", "failed", "canceled", "skipped", "manual", "scheduled".
 type Job struct {
 	ID         int64      // Unique job ID
 	Name       string     // Job name as defined in .gitlab-ci.yml
@@ -57,10 +90,36 @@ type Job struct {
 	WebURL     string     // Browser URL for the job
 }
 
+// Transition describes a job state change observed while waiting for a pipeline.
+// It is passed to the hook registered with [Client.SetTransitionHook].
+type Transition struct {
+	JobID      int64      // Job ID
+	Name       string     // Job name
+	Stage      string     // Pipeline stage
+	OldStatus  string     // Previous status (empty when the job was first seen)
+	NewStatus  string     // Current status (empty when the job left the pipeline)
+	StartedAt  *time.Time // When the job started running (nil if not started)
+	FinishedAt *time.Time // When the job finished (nil if still running)
+	ObservedAt time.Time  // When the change was detected
+}
+
+// String returns a one-line description, as logged at debug level.
+func (t Transition) String() string {
+	switch {
+	case t.OldStatus == "":
+		return fmt.Sprintf("Job %d started: %s/%s", t.JobID, t.Stage, t.Name)
+	case t.NewStatus == "":
+		return fmt.Sprintf("Job %d removed", t.JobID)
+	default:
+		return fmt.Sprintf("Job %d: %s -> %s", t.JobID, t.OldStatus, t.NewStatus)
+	}
+}
+
 // jobTracker tracks jobs and their display handles/spinners with thread-safe access.
 type jobTracker struct {
 	mu       sync.RWMutex
 	jobs     map[int64]*Job
 	handles  map[int64]*bullets.BulletHandle
 	spinners map[int64]*bullets.Spinner
+	plain    bool // --progress=plain: handles printed at every poll instead of spinners
 }
@@ -0,0 +1,116 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// userCacheTTL is how long a resolved username→ID mapping is trusted.
+const userCacheTTL = 24 * time.Hour
+
+// userCacheEntry is a single cached username resolution.
+type userCacheEntry struct {
+	ID         int64     `json:"id"`
+	ResolvedAt time.Time `json:"resolved_at"`
+}
+
+// userCache is a small file-backed cache of GitLab username→ID lookups.
+// It is best-effort: read and write failures are treated as cache misses.
+type userCache struct {
+	mu   sync.Mutex
+	path string
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+// newUserCache creates a cache stored at path.
+func newUserCache(path string) *userCache {
+	return &userCache{path: path, ttl: userCacheTTL, now: time.Now}
+}
+
+// lookup returns the cached ID for key if present and not expired.
+func (uc *userCache) lookup(key string) (int64, bool) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	entries, err := uc.load()
+	if err != nil {
+		return 0, false
+	}
+
+	entry, ok := entries[key]
+	if !ok || uc.now().Sub(entry.ResolvedAt) > uc.ttl {
+		return 0, false
+	}
+	return entry.ID, true
+}
+
+// store records the ID for key.
+func (uc *userCache) store(key string, id int64) error {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	entries, err := uc.load()
+	if err != nil {
+		entries = make(map[string]userCacheEntry)
+	}
+	entries[key] = userCacheEntry{ID: id, ResolvedAt: uc.now()}
+	return uc.save(entries)
+}
+
+// invalidate removes key from the cache.
+func (uc *userCache) invalidate(key string) error {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	entries, err := uc.load()
+	if err != nil {
+		return nil //nolint:nilerr // Nothing cached, nothing to invalidate
+	}
+	if _, ok := entries[key]; !ok {
+		return nil
+	}
+	delete(entries, key)
+	return uc.save(entries)
+}
+
+// load reads all entries from disk. A missing file yields an empty map.
+func (uc *userCache) load() (map[string]userCacheEntry, error) {
+	entries := make(map[string]userCacheEntry)
+
+	// #nosec G304 - Cache path is derived from the user's config directory
+	data, err := os.ReadFile(uc.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read user cache: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse user cache: %w", err)
+	}
+	return entries, nil
+}
+
+// save writes all entries to disk, creating the parent directory if needed.
+func (uc *userCache) save(entries map[string]userCacheEntry) error {
+	if err := os.MkdirAll(filepath.Dir(uc.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create user cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode user cache: %w", err)
+	}
+
+	if err := os.WriteFile(uc.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write user cache: %w", err)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/sgaunet/auto-mr/pkg/gitlab"
 	"github.com/sgaunet/auto-mr/testing/fixtures"
 	"github.com/sgaunet/auto-mr/testing/mocks"
 )
@@ -266,3 +267,36 @@ func TestWorkflowApprovalScenarios(t *testing.T) {
 		}
 	})
 }
+
+// TestTransitionString verifies the debug descriptions of job transitions.
+func TestTransitionString(t *testing.T) {
+	tests := []struct {
+		name       string
+		transition gitlab.Transition
+		expected   string
+	}{
+		{
+			name:       "new job",
+			transition: gitlab.Transition{JobID: 7, Name: "unit", Stage: "test", NewStatus: "running"},
+			expected:   "Job 7 started: test/unit",
+		},
+		{
+			name:       "status change",
+			transition: gitlab.Transition{JobID: 7, Name: "unit", OldStatus: "running", NewStatus: "success"},
+			expected:   "Job 7: running -> success",
+		},
+		{
+			name:       "removed job",
+			transition: gitlab.Transition{JobID: 7, Name: "unit", OldStatus: "pending"},
+			expected:   "Job 7 removed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.transition.String(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
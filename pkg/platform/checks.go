@@ -0,0 +1,21 @@
+package platform
+
+import "strings"
+
+// ChecksURL returns the page listing the pipelines/workflows of mr on p's platform:
+// the Pipelines tab on GitLab, the Checks tab on GitHub and the pull request itself
+// on Forgejo, which shows the commit statuses there.
+func ChecksURL(p Provider, mr *MergeRequest) string {
+	base := strings.TrimSuffix(mr.WebURL, "/")
+	if base == "" {
+		return ""
+	}
+	switch p.(type) {
+	case *GitLabAdapter:
+		return base + "/pipelines"
+	case *GitHubAdapter:
+		return base + "/checks"
+	default:
+		return base
+	}
+}
@@ -0,0 +1,33 @@
+package platform_test
+
+import (
+	"testing"
+
+	"github.com/sgaunet/auto-mr/pkg/config"
+	"github.com/sgaunet/auto-mr/pkg/platform"
+	"github.com/sgaunet/auto-mr/testing/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecksURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider platform.Provider
+		webURL   string
+		want     string
+	}{
+		{"gitlab", platform.NewGitLabAdapter(nil, &config.GitLabConfig{}, nil),
+			"https://gitlab.com/o/r/-/merge_requests/7", "https://gitlab.com/o/r/-/merge_requests/7/pipelines"},
+		{"github", platform.NewGitHubAdapter(nil, &config.GitHubConfig{}, nil),
+			"https://github.com/o/r/pull/7", "https://github.com/o/r/pull/7/checks"},
+		{"other", mocks.NewPlatformProvider(), "https://codeberg.org/o/r/pulls/7", "https://codeberg.org/o/r/pulls/7"},
+		{"unknown URL", platform.NewGitHubAdapter(nil, &config.GitHubConfig{}, nil), "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := platform.ChecksURL(tt.provider, &platform.MergeRequest{WebURL: tt.webURL})
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -7,6 +7,61 @@ var (
 	// ErrAlreadyExists is returned when a merge/pull request already exists for the branch.
 	ErrAlreadyExists = errors.New("merge/pull request already exists for this branch")
 
+	// ErrIncomplete is returned by Create when the merge/pull request was created but
+	// setting its assignees, labels or reviewers failed afterwards (GitHub).
+	ErrIncomplete = errors.New("merge/pull request created but not fully configured")
+
 	// ErrNotFound is returned when no merge/pull request is found for the branch.
 	ErrNotFound = errors.New("no merge/pull request found for branch")
+
+	// ErrMergeConflict is returned by Merge when the merge/pull request conflicts with its target branch.
+	ErrMergeConflict = errors.New("merge/pull request has conflicts with the target branch")
+
+	// ErrPipelineTimeout is returned by WaitForPipeline when the pipeline/workflows
+	// do not complete within the timeout.
+	ErrPipelineTimeout = errors.New("timeout waiting for pipeline completion")
+
+	// ErrNoChecks is returned by WaitForPipeline when no pipeline/workflow started
+	// within [Options.ChecksStartTimeout].
+	ErrNoChecks = errors.New("no pipeline/workflow started")
+
+	// ErrApprovalsPending is returned by Merge when required approvals are still missing.
+	ErrApprovalsPending = errors.New("merge/pull request still requires approvals")
+
+	// ErrForkUnsupported is returned by [NewProvider] when a fork workflow is requested
+	// on a platform that does not support it.
+	ErrForkUnsupported = errors.New("opening merge requests from a fork is not supported on this platform")
+
+	// ErrDeploymentTimeout is returned by WaitForDeployment when no deployment started,
+	// or one is still running, when the timeout is exceeded.
+	ErrDeploymentTimeout = errors.New("timeout waiting for deployment completion")
+
+	// ErrDeploymentsUnsupported is returned by WaitForDeployment on platforms without deployments.
+	ErrDeploymentsUnsupported = errors.New("waiting for deployments is not supported on this platform")
+
+	// ErrFreshCIUnsupported is returned by RequireFreshCI on platforms that cannot tell CI runs apart by age.
+	ErrFreshCIUnsupported = errors.New("requiring fresh CI runs is not supported on this platform")
+
+	// ErrRebaseFailed is returned by Rebase when the source branch cannot be rebased
+	// onto the target branch, typically because of conflicts.
+	ErrRebaseFailed = errors.New("merge/pull request could not be rebased onto the target branch")
+
+	// ErrRebaseUnsupported is returned by Rebase on platforms without server-side rebase.
+	ErrRebaseUnsupported = errors.New("rebasing merge requests is not supported on this platform")
+
+	// ErrProjectsUnsupported is returned by AddToProject on platforms without GitHub Projects.
+	ErrProjectsUnsupported = errors.New("adding merge requests to projects is not supported on this platform")
+
+	// ErrProjectAccess is returned by AddToProject when the token is not allowed to use projects.
+	ErrProjectAccess = errors.New("token cannot access projects")
+
+	// ErrNotDraft is returned by MarkReady when the merge/pull request is not a draft.
+	ErrNotDraft = errors.New("merge/pull request is not a draft")
+
+	// ErrMergeMethodDisabled is returned by [MergeMethods.ChooseSquash] when the merge
+	// method asked for is disabled in the repository/project settings.
+	ErrMergeMethodDisabled = errors.New("merge method is disabled in the repository settings")
+
+	// ErrTokenMissing is returned by [CheckToken] when the API token of the detected platform is not set.
+	ErrTokenMissing = errors.New("API token environment variable is not set")
 )
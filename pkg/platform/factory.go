@@ -20,37 +20,74 @@ var errUnsupportedPlatform = errors.New("unsupported platform")
 //
 // Parameters:
 //   - p: the detected platform ([git.PlatformGitLab], [git.PlatformGitHub], or [git.PlatformForgejo])
-//   - cfg: the loaded configuration (must not be nil)
+//   - cfg: the loaded configuration (must not be nil), read by the provider at each call
 //   - logger: the logger instance for debug output
+//   - opts: runtime settings for the underlying API client (the zero value uses library defaults)
 //
 // Returns errUnsupportedPlatform if the platform is not GitLab, GitHub, or Forgejo.
+// Returns [ErrForkUnsupported] if opts.HeadRemoteURL is set for GitLab.
 //
 //nolint:ireturn // Factory function must return interface to enable platform abstraction.
-func NewProvider(p git.Platform, cfg *config.Config, logger *bullets.Logger) (Provider, error) {
+func NewProvider(
+	p git.Platform, cfg *config.Config, logger *bullets.Logger, opts Options,
+) (Provider, error) {
 	switch p {
 	case git.PlatformGitLab:
-		client, err := gitlab.NewClient()
+		if opts.HeadRemoteURL != "" {
+			return nil, fmt.Errorf("%w: GitLab", ErrForkUnsupported)
+		}
+		client, err := gitlab.NewClient(opts.HTTPClient)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create GitLab client: %w", err)
 		}
 		client.SetLogger(logger)
-		return NewGitLabAdapter(client, cfg.GitLab, logger), nil
+		client.SetUserCache(opts.UserCachePath)
+		client.SetAPIConcurrency(opts.APIConcurrency)
+		client.SetRequestTimeout(opts.RequestTimeout)
+		client.SetPlayManualJobs(opts.PlayManualJobs)
+		client.SetStartTimeout(opts.ChecksStartTimeout)
+		client.SetRequireChecks(opts.RequireChecks)
+		client.SetIgnoredChecks(opts.IgnoredChecks)
+		client.SetUserAgent(opts.UserAgent)
+		return NewGitLabAdapter(client, &cfg.GitLab, logger), nil
 
 	case git.PlatformGitHub:
-		client, err := ghclient.NewClient()
+		client, err := ghclient.NewClient(opts.HTTPClient)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create GitHub client: %w", err)
 		}
 		client.SetLogger(logger)
-		return NewGitHubAdapter(client, cfg.GitHub, logger), nil
+		client.SetKeepAuthorReviewers(cfg.GitHub.KeepAuthorReviewer != nil && *cfg.GitHub.KeepAuthorReviewer)
+		client.SetAPIConcurrency(opts.APIConcurrency)
+		client.SetRequestTimeout(opts.RequestTimeout)
+		client.SetStartTimeout(opts.ChecksStartTimeout)
+		client.SetRequireChecks(opts.RequireChecks)
+		client.SetIgnoredChecks(opts.IgnoredChecks)
+		client.SetUserAgent(opts.UserAgent)
+		if opts.StartupDelay != nil {
+			client.SetStartupDelay(*opts.StartupDelay)
+		}
+		if opts.HeadRemoteURL != "" {
+			if err := client.SetHeadRepositoryFromURL(opts.HeadRemoteURL); err != nil {
+				return nil, fmt.Errorf("failed to set GitHub head repository: %w", err)
+			}
+		}
+		return NewGitHubAdapter(client, &cfg.GitHub, logger), nil
 
 	case git.PlatformForgejo:
-		client, err := forgejo.NewClient(cfg.Forgejo.URL)
+		client, err := forgejo.NewClient(cfg.Forgejo.URL, opts.HTTPClient)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create Forgejo client: %w", err)
 		}
 		client.SetLogger(logger)
-		return NewForgejoAdapter(client, cfg.Forgejo, logger), nil
+		client.SetRequestTimeout(opts.RequestTimeout)
+		client.SetUserAgent(opts.UserAgent)
+		if opts.HeadRemoteURL != "" {
+			if err := client.SetHeadRepositoryFromURL(opts.HeadRemoteURL); err != nil {
+				return nil, fmt.Errorf("failed to set Forgejo head repository: %w", err)
+			}
+		}
+		return NewForgejoAdapter(client, &cfg.Forgejo, logger), nil
 
 	default:
 		return nil, fmt.Errorf("%w: %s", errUnsupportedPlatform, p)
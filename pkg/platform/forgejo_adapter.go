@@ -3,6 +3,7 @@ package platform
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/sgaunet/auto-mr/pkg/config"
@@ -14,12 +15,13 @@ import (
 // It translates between the platform-agnostic types and the Forgejo-specific API.
 type ForgejoAdapter struct {
 	client *forgejo.Client
-	cfg    config.ForgejoConfig
+	cfg    *config.ForgejoConfig
 	log    *bullets.Logger
 }
 
-// NewForgejoAdapter creates a new Forgejo adapter.
-func NewForgejoAdapter(client *forgejo.Client, cfg config.ForgejoConfig, log *bullets.Logger) *ForgejoAdapter {
+// NewForgejoAdapter creates a new Forgejo adapter. cfg is read at each call, so
+// changes made after creation (e.g. a reviewer picked from the pool) apply.
+func NewForgejoAdapter(client *forgejo.Client, cfg *config.ForgejoConfig, log *bullets.Logger) *ForgejoAdapter {
 	return &ForgejoAdapter{
 		client: client,
 		cfg:    cfg,
@@ -44,17 +46,32 @@ func (a *ForgejoAdapter) ListLabels() ([]Label, error) {
 
 	labels := make([]Label, len(fjLabels))
 	for i, l := range fjLabels {
-		labels[i] = Label{Name: l.Name}
+		labels[i] = Label{Name: l.Name, Color: l.Color, Description: l.Description}
 	}
 	return labels, nil
 }
 
+// ListMembers returns the usernames of the Forgejo repository collaborators.
+func (a *ForgejoAdapter) ListMembers() ([]string, error) {
+	members, err := a.client.ListCollaboratorLogins()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+	return members, nil
+}
+
 // Create creates a new pull request on Forgejo.
+// An "@me" assignee is resolved to the authenticated user.
 func (a *ForgejoAdapter) Create(params CreateParams) (*MergeRequest, error) {
+	assignee, reviewer, err := resolveParticipants(a.cfg.Assignee, a.cfg.Reviewer, a.client.CurrentUsername)
+	if err != nil {
+		return nil, err
+	}
+
 	pr, err := a.client.CreatePullRequest(
 		params.SourceBranch, params.TargetBranch,
 		params.Title, params.Body,
-		a.cfg.Assignee, a.cfg.Reviewer,
+		assignee, reviewer,
 		params.Labels,
 	)
 	if err != nil {
@@ -72,12 +89,35 @@ func (a *ForgejoAdapter) Create(params CreateParams) (*MergeRequest, error) {
 }
 
 // GetByBranch fetches an existing pull request by source and target branches.
+// Returns [ErrNotFound] if no open one matches the given branches.
 func (a *ForgejoAdapter) GetByBranch(sourceBranch, targetBranch string) (*MergeRequest, error) {
 	pr, err := a.client.GetPullRequestByBranch(sourceBranch, targetBranch)
+	if errors.Is(err, forgejo.ErrPRNotFound) {
+		return nil, fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pull request by branch: %w", err)
 	}
 
+	return &MergeRequest{
+		ID:           pr.Index,
+		WebURL:       pr.HTMLURL,
+		SourceBranch: pr.Head.Ref,
+		Duplicates:   a.client.DuplicatePullRequests(),
+	}, nil
+}
+
+// FindMerged fetches the merged Forgejo pull request of sourceBranch at headSHA.
+// Returns [ErrNotFound] if there is none.
+func (a *ForgejoAdapter) FindMerged(sourceBranch, targetBranch, headSHA string) (*MergeRequest, error) {
+	pr, err := a.client.GetMergedPullRequest(sourceBranch, targetBranch, headSHA)
+	if errors.Is(err, forgejo.ErrPRNotFound) {
+		return nil, fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merged pull request: %w", err)
+	}
+
 	return &MergeRequest{
 		ID:           pr.Index,
 		WebURL:       pr.HTMLURL,
@@ -86,14 +126,103 @@ func (a *ForgejoAdapter) GetByBranch(sourceBranch, targetBranch string) (*MergeR
 }
 
 // WaitForPipeline waits for Forgejo Actions / commit-status CI completion.
+// Returns [ErrPipelineTimeout] if the timeout is exceeded.
 func (a *ForgejoAdapter) WaitForPipeline(timeout time.Duration) (string, error) {
 	status, err := a.client.WaitForPipeline(timeout)
 	if err != nil {
+		if errors.Is(err, forgejo.ErrWorkflowTimeout) {
+			return "", fmt.Errorf("%w: %w", ErrPipelineTimeout, err)
+		}
 		return "", fmt.Errorf("failed to wait for Forgejo pipeline: %w", err)
 	}
 	return status, nil
 }
 
+// Rebase returns [ErrRebaseUnsupported]: auto-mr only rebases merge requests on GitLab.
+func (a *ForgejoAdapter) Rebase(_ int64, _ time.Duration) error {
+	return ErrRebaseUnsupported
+}
+
+// Comment posts a comment on a Forgejo pull request.
+func (a *ForgejoAdapter) Comment(mrID int64, body string) error {
+	if err := a.client.CreatePullRequestComment(mrID, body); err != nil {
+		return fmt.Errorf("failed to comment on pull request: %w", err)
+	}
+	return nil
+}
+
+// AddLabels adds labels to a Forgejo pull request.
+func (a *ForgejoAdapter) AddLabels(mrID int64, labels []string) error {
+	if err := a.client.AddPullRequestLabels(mrID, labels); err != nil {
+		return fmt.Errorf("failed to label pull request: %w", err)
+	}
+	return nil
+}
+
+// RequestReviewers requests review of a Forgejo pull request from users and
+// "org/team" teams.
+func (a *ForgejoAdapter) RequestReviewers(mrID int64, owners []string) error {
+	users, teams := splitTeams(owners)
+	if err := a.client.RequestReviewers(mrID, users, teams); err != nil {
+		return fmt.Errorf("failed to request pull request reviewers: %w", err)
+	}
+	return nil
+}
+
+// MarkReady marks a draft Forgejo pull request as ready for review.
+// Returns [ErrNotDraft] if it is not a draft.
+func (a *ForgejoAdapter) MarkReady(mrID int64) error {
+	changed, err := a.client.MarkPullRequestReady(mrID)
+	if err != nil {
+		return fmt.Errorf("failed to mark pull request as ready: %w", err)
+	}
+	if !changed {
+		return ErrNotDraft
+	}
+	return nil
+}
+
+// EnableAutoMerge enables auto-merge of a Forgejo pull request: it is merged, squashed if
+// squash is true, once its status checks succeed.
+func (a *ForgejoAdapter) EnableAutoMerge(mrID int64, squash bool) error {
+	if err := a.client.EnableAutoMerge(mrID, squash); err != nil {
+		return fmt.Errorf("failed to enable auto-merge of pull request: %w", err)
+	}
+	return nil
+}
+
+// RequireFreshCI returns [ErrFreshCIUnsupported]: Forgejo commit statuses are
+// updated in place, so an earlier run cannot be told apart from a new one.
+func (a *ForgejoAdapter) RequireFreshCI(_ time.Time) error {
+	return ErrFreshCIUnsupported
+}
+
+// WaitForDeployment returns [ErrDeploymentsUnsupported]: Forgejo has no deployments API.
+func (a *ForgejoAdapter) WaitForDeployment(_ string, _ time.Time, _ time.Duration) (string, error) {
+	return "", ErrDeploymentsUnsupported
+}
+
+// AddToProject returns [ErrProjectsUnsupported]: projects are a GitHub feature.
+func (a *ForgejoAdapter) AddToProject(_ int64, _ int) error {
+	return ErrProjectsUnsupported
+}
+
+// Close closes a Forgejo pull request without merging it.
+func (a *ForgejoAdapter) Close(mrID int64) error {
+	if err := a.client.ClosePullRequest(mrID); err != nil {
+		return fmt.Errorf("failed to close pull request: %w", err)
+	}
+	return nil
+}
+
+// DeleteBranch deletes a branch from the Forgejo repository.
+func (a *ForgejoAdapter) DeleteBranch(branch string) error {
+	if err := a.client.DeleteBranch(branch); err != nil {
+		return fmt.Errorf("failed to delete remote branch: %w", err)
+	}
+	return nil
+}
+
 // Approve is a no-op for Forgejo (Forgejo doesn't gate merges on approval).
 func (a *ForgejoAdapter) Approve(_ int64) error {
 	return nil
@@ -108,6 +237,25 @@ func (a *ForgejoAdapter) Merge(params MergeParams) error {
 	return nil
 }
 
+// MergeMethods returns the merge styles allowed in the Forgejo repository settings,
+// preferring squash when it is the default style.
+func (a *ForgejoAdapter) MergeMethods() (*MergeMethods, error) {
+	styles, defaultStyle, err := a.client.AllowedMergeStyles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allowed merge styles: %w", err)
+	}
+	return &MergeMethods{
+		Squash:        slices.Contains(styles, "squash"),
+		Merge:         slices.Contains(styles, "merge"),
+		SquashDefault: defaultStyle == "squash",
+	}, nil
+}
+
+// MergeCommitSHA returns the SHA of the commit created by the last merge.
+func (a *ForgejoAdapter) MergeCommitSHA() string {
+	return a.client.MergeCommitSHA()
+}
+
 // PlatformName returns "Forgejo".
 func (a *ForgejoAdapter) PlatformName() string {
 	return "Forgejo"
@@ -118,5 +266,20 @@ func (a *ForgejoAdapter) PipelineTimeout() string {
 	return a.cfg.PipelineTimeout
 }
 
+// DefaultLabels returns the configured default labels.
+func (a *ForgejoAdapter) DefaultLabels() []string {
+	return a.cfg.DefaultLabels
+}
+
+// Squash returns the configured squash default (nil if unset).
+func (a *ForgejoAdapter) Squash() *bool {
+	return a.cfg.Squash
+}
+
+// ApproveTiming returns [config.ApproveAfterWait]: approval is a no-op on Forgejo.
+func (a *ForgejoAdapter) ApproveTiming() string {
+	return config.ApproveAfterWait
+}
+
 // Compile-time interface check.
 var _ Provider = (*ForgejoAdapter)(nil)
@@ -3,6 +3,7 @@ package platform
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/sgaunet/auto-mr/pkg/config"
@@ -14,12 +15,13 @@ import (
 // It translates between the platform-agnostic types and the GitHub-specific API.
 type GitHubAdapter struct {
 	client *ghclient.Client
-	cfg    config.GitHubConfig
+	cfg    *config.GitHubConfig
 	log    *bullets.Logger
 }
 
-// NewGitHubAdapter creates a new GitHub adapter.
-func NewGitHubAdapter(client *ghclient.Client, cfg config.GitHubConfig, log *bullets.Logger) *GitHubAdapter {
+// NewGitHubAdapter creates a new GitHub adapter. cfg is read at each call, so
+// changes made after creation (e.g. a reviewer picked from the pool) apply.
+func NewGitHubAdapter(client *ghclient.Client, cfg *config.GitHubConfig, log *bullets.Logger) *GitHubAdapter {
 	return &GitHubAdapter{
 		client: client,
 		cfg:    cfg,
@@ -44,24 +46,49 @@ func (a *GitHubAdapter) ListLabels() ([]Label, error) {
 
 	labels := make([]Label, len(ghLabels))
 	for i, l := range ghLabels {
-		labels[i] = Label{Name: l.Name}
+		labels[i] = Label{Name: l.Name, Color: l.Color, Description: l.Description}
 	}
 	return labels, nil
 }
 
+// ListMembers returns the usernames of the GitHub repository collaborators.
+func (a *GitHubAdapter) ListMembers() ([]string, error) {
+	members, err := a.client.ListCollaboratorLogins()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+	return members, nil
+}
+
 // Create creates a new pull request on GitHub.
+// An "@me" assignee is resolved to the authenticated user.
 func (a *GitHubAdapter) Create(params CreateParams) (*MergeRequest, error) {
+	assignee, reviewer, err := resolveParticipants(a.cfg.Assignee, a.cfg.Reviewer, a.client.CurrentUsername)
+	if err != nil {
+		return nil, err
+	}
+	var assignees, reviewers []string
+	if assignee != "" {
+		assignees = []string{assignee}
+	}
+	if reviewer != "" {
+		reviewers = []string{reviewer}
+	}
+
 	pr, err := a.client.CreatePullRequest(
 		params.SourceBranch, params.TargetBranch,
 		params.Title, params.Body,
-		[]string{a.cfg.Assignee},
-		[]string{a.cfg.Reviewer},
+		assignees,
+		reviewers,
 		params.Labels,
 	)
 	if err != nil {
 		if errors.Is(err, ghclient.ErrPRAlreadyExists) {
 			return nil, fmt.Errorf("%w: %w", ErrAlreadyExists, err)
 		}
+		if errors.Is(err, ghclient.ErrPRIncomplete) {
+			return nil, fmt.Errorf("%w: %w", ErrIncomplete, err)
+		}
 		return nil, fmt.Errorf("failed to create pull request: %w", err)
 	}
 
@@ -73,37 +100,179 @@ func (a *GitHubAdapter) Create(params CreateParams) (*MergeRequest, error) {
 }
 
 // GetByBranch fetches an existing pull request by source and target branches.
+// Returns [ErrNotFound] if no open one matches the given branches.
 func (a *GitHubAdapter) GetByBranch(sourceBranch, targetBranch string) (*MergeRequest, error) {
 	pr, err := a.client.GetPullRequestByBranch(sourceBranch, targetBranch)
+	if errors.Is(err, ghclient.ErrPRNotFound) {
+		return nil, fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pull request by branch: %w", err)
 	}
 
+	var duplicates []int64
+	for _, number := range a.client.DuplicatePullRequests() {
+		duplicates = append(duplicates, int64(number))
+	}
 	return &MergeRequest{
 		ID:           int64(*pr.Number),
 		WebURL:       *pr.HTMLURL,
 		SourceBranch: *pr.Head.Ref,
+		Duplicates:   duplicates,
+	}, nil
+}
+
+// FindMerged fetches the merged GitHub pull request of sourceBranch at headSHA.
+// Returns [ErrNotFound] if there is none.
+func (a *GitHubAdapter) FindMerged(sourceBranch, targetBranch, headSHA string) (*MergeRequest, error) {
+	pr, err := a.client.GetMergedPullRequest(sourceBranch, targetBranch, headSHA)
+	if errors.Is(err, ghclient.ErrPRNotFound) {
+		return nil, fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merged pull request: %w", err)
+	}
+
+	return &MergeRequest{
+		ID:           int64(pr.GetNumber()),
+		WebURL:       pr.GetHTMLURL(),
+		SourceBranch: pr.GetHead().GetRef(),
 	}, nil
 }
 
 // WaitForPipeline waits for GitHub workflow completion.
+// Returns [ErrPipelineTimeout] if the timeout is exceeded.
 func (a *GitHubAdapter) WaitForPipeline(timeout time.Duration) (string, error) {
 	conclusion, err := a.client.WaitForWorkflows(timeout)
 	if err != nil {
+		if errors.Is(err, ghclient.ErrWorkflowTimeout) {
+			return "", fmt.Errorf("%w: %w", ErrPipelineTimeout, err)
+		}
+		if errors.Is(err, ghclient.ErrNoWorkflow) {
+			return "", fmt.Errorf("%w: %w", ErrNoChecks, err)
+		}
 		return "", fmt.Errorf("failed to wait for GitHub workflows: %w", err)
 	}
 	return conclusion, nil
 }
 
+// RequireFreshCI makes WaitForPipeline ignore the workflow runs created before since.
+func (a *GitHubAdapter) RequireFreshCI(since time.Time) error {
+	a.client.SetCISince(since)
+	return nil
+}
+
+// WaitForDeployment waits for the GitHub deployments of ref.
+// Returns [ErrDeploymentTimeout] if the timeout is exceeded.
+func (a *GitHubAdapter) WaitForDeployment(ref string, since time.Time, timeout time.Duration) (string, error) {
+	state, err := a.client.WaitForDeployments(ref, since, timeout)
+	if err != nil {
+		if errors.Is(err, ghclient.ErrDeploymentTimeout) {
+			return "", fmt.Errorf("%w: %w", ErrDeploymentTimeout, err)
+		}
+		return "", fmt.Errorf("failed to wait for GitHub deployments: %w", err)
+	}
+	return state, nil
+}
+
+// Rebase returns [ErrRebaseUnsupported]: auto-mr only rebases merge requests on GitLab.
+func (a *GitHubAdapter) Rebase(_ int64, _ time.Duration) error {
+	return ErrRebaseUnsupported
+}
+
+// Comment posts a comment on a GitHub pull request.
+func (a *GitHubAdapter) Comment(mrID int64, body string) error {
+	if err := a.client.CreatePullRequestComment(int(mrID), body); err != nil {
+		return fmt.Errorf("failed to comment on pull request: %w", err)
+	}
+	return nil
+}
+
+// AddLabels adds labels to a GitHub pull request.
+func (a *GitHubAdapter) AddLabels(mrID int64, labels []string) error {
+	if err := a.client.AddPullRequestLabels(int(mrID), labels); err != nil {
+		return fmt.Errorf("failed to label pull request: %w", err)
+	}
+	return nil
+}
+
+// RequestReviewers requests review of a GitHub pull request from users and
+// "org/team" teams.
+func (a *GitHubAdapter) RequestReviewers(mrID int64, owners []string) error {
+	users, teams := splitTeams(owners)
+	if err := a.client.RequestReviewers(int(mrID), users, teams); err != nil {
+		return fmt.Errorf("failed to request pull request reviewers: %w", err)
+	}
+	return nil
+}
+
+// MarkReady marks a draft GitHub pull request as ready for review.
+// Returns [ErrNotDraft] if it is not a draft.
+func (a *GitHubAdapter) MarkReady(mrID int64) error {
+	changed, err := a.client.MarkPullRequestReady(int(mrID))
+	if err != nil {
+		return fmt.Errorf("failed to mark pull request as ready: %w", err)
+	}
+	if !changed {
+		return ErrNotDraft
+	}
+	return nil
+}
+
+// EnableAutoMerge enables auto-merge of a GitHub pull request: it is merged, squashed if
+// squash is true, once its required checks succeed.
+func (a *GitHubAdapter) EnableAutoMerge(mrID int64, squash bool) error {
+	if err := a.client.EnablePullRequestAutoMerge(int(mrID), ghclient.GetMergeMethod(squash)); err != nil {
+		return fmt.Errorf("failed to enable auto-merge of pull request: %w", err)
+	}
+	return nil
+}
+
+// AddToProject adds a GitHub pull request to a Projects (v2) board of the repository owner.
+// Returns [ErrProjectAccess] if the token is not allowed to use projects.
+func (a *GitHubAdapter) AddToProject(mrID int64, project int) error {
+	if err := a.client.AddPullRequestToProject(int(mrID), project); err != nil {
+		if errors.Is(err, ghclient.ErrProjectAccess) {
+			return fmt.Errorf("%w: %w", ErrProjectAccess, err)
+		}
+		return fmt.Errorf("failed to add pull request to project: %w", err)
+	}
+	return nil
+}
+
+// Close closes a GitHub pull request without merging it.
+func (a *GitHubAdapter) Close(mrID int64) error {
+	if err := a.client.ClosePullRequest(int(mrID)); err != nil {
+		return fmt.Errorf("failed to close pull request: %w", err)
+	}
+	return nil
+}
+
+// DeleteBranch deletes a branch from the GitHub repository.
+func (a *GitHubAdapter) DeleteBranch(branch string) error {
+	if err := a.client.DeleteBranch(branch); err != nil {
+		return fmt.Errorf("failed to delete remote branch: %w", err)
+	}
+	return nil
+}
+
 // Approve is a no-op for GitHub (GitHub doesn't require self-approval).
 func (a *GitHubAdapter) Approve(_ int64) error {
 	return nil
 }
 
 // Merge merges a GitHub pull request and deletes the remote branch.
+// Returns [ErrMergeConflict] without attempting the merge if GitHub reports conflicts.
 func (a *GitHubAdapter) Merge(params MergeParams) error {
+	if err := a.client.CheckMergeable(int(params.MRID)); err != nil {
+		if errors.Is(err, ghclient.ErrPRConflict) {
+			return fmt.Errorf("%w: %w", ErrMergeConflict, err)
+		}
+		return fmt.Errorf("failed to check pull request mergeability: %w", err)
+	}
+
 	mergeMethod := ghclient.GetMergeMethod(params.Squash)
-	if err := a.client.MergePullRequest(int(params.MRID), mergeMethod, params.CommitTitle); err != nil {
+	if err := a.client.MergePullRequest(int(params.MRID), mergeMethod, params.CommitTitle, params.CommitMessage); err != nil {
 		return fmt.Errorf("failed to merge pull request: %w", err)
 	}
 
@@ -117,6 +286,26 @@ func (a *GitHubAdapter) Merge(params MergeParams) error {
 	return nil
 }
 
+// MergeMethods returns the merge methods allowed in the GitHub repository settings.
+// Squashing is preferred when allowed, as GitHub has no default method.
+func (a *GitHubAdapter) MergeMethods() (*MergeMethods, error) {
+	methods, err := a.client.AllowedMergeMethods()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allowed merge methods: %w", err)
+	}
+	squash := slices.Contains(methods, ghclient.GetMergeMethod(true))
+	return &MergeMethods{
+		Squash:        squash,
+		Merge:         slices.Contains(methods, ghclient.GetMergeMethod(false)),
+		SquashDefault: squash,
+	}, nil
+}
+
+// MergeCommitSHA returns the SHA of the commit created by the last merge.
+func (a *GitHubAdapter) MergeCommitSHA() string {
+	return a.client.MergeCommitSHA()
+}
+
 // PlatformName returns "GitHub".
 func (a *GitHubAdapter) PlatformName() string {
 	return "GitHub"
@@ -127,5 +316,20 @@ func (a *GitHubAdapter) PipelineTimeout() string {
 	return a.cfg.PipelineTimeout
 }
 
+// DefaultLabels returns the configured default labels.
+func (a *GitHubAdapter) DefaultLabels() []string {
+	return a.cfg.DefaultLabels
+}
+
+// Squash returns the configured squash default (nil if unset).
+func (a *GitHubAdapter) Squash() *bool {
+	return a.cfg.Squash
+}
+
+// ApproveTiming returns [config.ApproveAfterWait]: approval is a no-op on GitHub.
+func (a *GitHubAdapter) ApproveTiming() string {
+	return config.ApproveAfterWait
+}
+
 // Compile-time interface check.
 var _ Provider = (*GitHubAdapter)(nil)
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	autolabels "github.com/sgaunet/auto-mr/internal/labels"
 	"github.com/sgaunet/auto-mr/pkg/config"
 	"github.com/sgaunet/auto-mr/pkg/gitlab"
 	"github.com/sgaunet/bullets"
@@ -14,11 +15,12 @@ import (
 // It translates between the platform-agnostic types and the GitLab-specific API.
 type GitLabAdapter struct {
 	client *gitlab.Client
-	cfg    config.GitLabConfig
+	cfg    *config.GitLabConfig
 }
 
-// NewGitLabAdapter creates a new GitLab adapter.
-func NewGitLabAdapter(client *gitlab.Client, cfg config.GitLabConfig, _ *bullets.Logger) *GitLabAdapter {
+// NewGitLabAdapter creates a new GitLab adapter. cfg is read at each call, so
+// changes made after creation (e.g. a reviewer picked from the pool) apply.
+func NewGitLabAdapter(client *gitlab.Client, cfg *config.GitLabConfig, _ *bullets.Logger) *GitLabAdapter {
 	return &GitLabAdapter{
 		client: client,
 		cfg:    cfg,
@@ -42,17 +44,37 @@ func (a *GitLabAdapter) ListLabels() ([]Label, error) {
 
 	labels := make([]Label, len(glLabels))
 	for i, l := range glLabels {
-		labels[i] = Label{Name: l.Name}
+		labels[i] = Label{
+			Name:        l.Name,
+			Color:       l.Color,
+			Description: l.Description,
+			Scope:       autolabels.Scope(l.Name),
+		}
 	}
 	return labels, nil
 }
 
+// ListMembers returns the usernames of the GitLab project members.
+func (a *GitLabAdapter) ListMembers() ([]string, error) {
+	members, err := a.client.ListMemberUsernames()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+	return members, nil
+}
+
 // Create creates a new merge request on GitLab.
+// An "@me" assignee is resolved to the authenticated user.
 func (a *GitLabAdapter) Create(params CreateParams) (*MergeRequest, error) {
+	assignee, reviewer, err := resolveParticipants(a.cfg.Assignee, a.cfg.Reviewer, a.client.CurrentUsername)
+	if err != nil {
+		return nil, err
+	}
+
 	mr, err := a.client.CreateMergeRequest(
 		params.SourceBranch, params.TargetBranch,
 		params.Title, params.Body,
-		a.cfg.Assignee, a.cfg.Reviewer,
+		assignee, reviewer,
 		params.Labels, params.Squash,
 	)
 	if err != nil {
@@ -70,12 +92,35 @@ func (a *GitLabAdapter) Create(params CreateParams) (*MergeRequest, error) {
 }
 
 // GetByBranch fetches an existing merge request by source and target branches.
+// Returns [ErrNotFound] if no open one matches the given branches.
 func (a *GitLabAdapter) GetByBranch(sourceBranch, targetBranch string) (*MergeRequest, error) {
 	mr, err := a.client.GetMergeRequestByBranch(sourceBranch, targetBranch)
+	if errors.Is(err, gitlab.ErrMRNotFound) {
+		return nil, fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get merge request by branch: %w", err)
 	}
 
+	return &MergeRequest{
+		ID:           mr.IID,
+		WebURL:       mr.WebURL,
+		SourceBranch: mr.SourceBranch,
+		Duplicates:   a.client.DuplicateMergeRequests(),
+	}, nil
+}
+
+// FindMerged fetches the merged GitLab merge request of sourceBranch at headSHA.
+// Returns [ErrNotFound] if there is none.
+func (a *GitLabAdapter) FindMerged(sourceBranch, targetBranch, headSHA string) (*MergeRequest, error) {
+	mr, err := a.client.GetMergedMergeRequest(sourceBranch, targetBranch, headSHA)
+	if errors.Is(err, gitlab.ErrMRNotFound) {
+		return nil, fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merged merge request: %w", err)
+	}
+
 	return &MergeRequest{
 		ID:           mr.IID,
 		WebURL:       mr.WebURL,
@@ -84,14 +129,120 @@ func (a *GitLabAdapter) GetByBranch(sourceBranch, targetBranch string) (*MergeRe
 }
 
 // WaitForPipeline waits for GitLab pipeline completion.
+// Returns [ErrPipelineTimeout] if the timeout is exceeded.
 func (a *GitLabAdapter) WaitForPipeline(timeout time.Duration) (string, error) {
 	status, err := a.client.WaitForPipeline(timeout)
 	if err != nil {
+		if errors.Is(err, gitlab.ErrPipelineTimeout) {
+			return "", fmt.Errorf("%w: %w", ErrPipelineTimeout, err)
+		}
+		if errors.Is(err, gitlab.ErrNoPipeline) {
+			return "", fmt.Errorf("%w: %w", ErrNoChecks, err)
+		}
 		return "", fmt.Errorf("failed to wait for GitLab pipeline: %w", err)
 	}
 	return status, nil
 }
 
+// RequireFreshCI makes WaitForPipeline ignore the merge request pipelines created before since.
+func (a *GitLabAdapter) RequireFreshCI(since time.Time) error {
+	a.client.SetCISince(since)
+	return nil
+}
+
+// WaitForDeployment waits for the deployments of ref to the GitLab environments.
+// Returns [ErrDeploymentTimeout] if the timeout is exceeded.
+func (a *GitLabAdapter) WaitForDeployment(ref string, since time.Time, timeout time.Duration) (string, error) {
+	status, err := a.client.WaitForDeployments(ref, since, timeout)
+	if err != nil {
+		if errors.Is(err, gitlab.ErrDeploymentTimeout) {
+			return "", fmt.Errorf("%w: %w", ErrDeploymentTimeout, err)
+		}
+		return "", fmt.Errorf("failed to wait for GitLab deployments: %w", err)
+	}
+	return status, nil
+}
+
+// Rebase rebases the source branch of a GitLab merge request onto its target branch.
+// Returns [ErrRebaseFailed] if GitLab cannot rebase it (e.g. conflicts).
+func (a *GitLabAdapter) Rebase(mrID int64, timeout time.Duration) error {
+	if err := a.client.RebaseMergeRequest(mrID, timeout); err != nil {
+		if errors.Is(err, gitlab.ErrRebaseFailed) {
+			return fmt.Errorf("%w: %w", ErrRebaseFailed, err)
+		}
+		return fmt.Errorf("failed to rebase merge request: %w", err)
+	}
+	return nil
+}
+
+// RequestReviewers adds users, and the members of groups, as reviewers of a GitLab
+// merge request.
+func (a *GitLabAdapter) RequestReviewers(mrID int64, owners []string) error {
+	if err := a.client.AddReviewers(mrID, owners); err != nil {
+		return fmt.Errorf("failed to add merge request reviewers: %w", err)
+	}
+	return nil
+}
+
+// Comment posts a note on a GitLab merge request.
+func (a *GitLabAdapter) Comment(mrID int64, body string) error {
+	if err := a.client.CreateMergeRequestNote(mrID, body); err != nil {
+		return fmt.Errorf("failed to comment on merge request: %w", err)
+	}
+	return nil
+}
+
+// AddLabels adds labels to a GitLab merge request.
+func (a *GitLabAdapter) AddLabels(mrID int64, labels []string) error {
+	if err := a.client.AddMergeRequestLabels(mrID, labels); err != nil {
+		return fmt.Errorf("failed to label merge request: %w", err)
+	}
+	return nil
+}
+
+// MarkReady marks a draft GitLab merge request as ready for review.
+// Returns [ErrNotDraft] if it is not a draft.
+func (a *GitLabAdapter) MarkReady(mrID int64) error {
+	changed, err := a.client.MarkMergeRequestReady(mrID)
+	if err != nil {
+		return fmt.Errorf("failed to mark merge request as ready: %w", err)
+	}
+	if !changed {
+		return ErrNotDraft
+	}
+	return nil
+}
+
+// EnableAutoMerge enables auto-merge of a GitLab merge request: it is merged, squashed if
+// squash is true, once its pipeline succeeds.
+func (a *GitLabAdapter) EnableAutoMerge(mrID int64, squash bool) error {
+	if err := a.client.EnableAutoMerge(mrID, squash); err != nil {
+		return fmt.Errorf("failed to enable auto-merge of merge request: %w", err)
+	}
+	return nil
+}
+
+// AddToProject returns [ErrProjectsUnsupported]: projects are a GitHub feature.
+func (a *GitLabAdapter) AddToProject(_ int64, _ int) error {
+	return ErrProjectsUnsupported
+}
+
+// Close closes a GitLab merge request without merging it.
+func (a *GitLabAdapter) Close(mrID int64) error {
+	if err := a.client.CloseMergeRequest(mrID); err != nil {
+		return fmt.Errorf("failed to close merge request: %w", err)
+	}
+	return nil
+}
+
+// DeleteBranch deletes a branch from the GitLab repository.
+func (a *GitLabAdapter) DeleteBranch(branch string) error {
+	if err := a.client.DeleteBranch(branch); err != nil {
+		return fmt.Errorf("failed to delete remote branch: %w", err)
+	}
+	return nil
+}
+
 // Approve approves a GitLab merge request.
 func (a *GitLabAdapter) Approve(mrID int64) error {
 	if err := a.client.ApproveMergeRequest(mrID); err != nil {
@@ -102,13 +253,49 @@ func (a *GitLabAdapter) Approve(mrID int64) error {
 
 // Merge merges a GitLab merge request.
 // Branch deletion is handled by GitLab's RemoveSourceBranch flag set during creation.
+// Returns [ErrMergeConflict] without attempting the merge if GitLab reports conflicts,
+// and [ErrApprovalsPending] if approval rules are still unsatisfied after params.ApprovalTimeout.
 func (a *GitLabAdapter) Merge(params MergeParams) error {
+	if err := a.client.CheckMergeable(params.MRID); err != nil {
+		if errors.Is(err, gitlab.ErrMRConflict) {
+			return fmt.Errorf("%w: %w", ErrMergeConflict, err)
+		}
+		return fmt.Errorf("failed to check merge request mergeability: %w", err)
+	}
+
+	if err := a.client.WaitForApprovals(params.MRID, params.ApprovalTimeout); err != nil {
+		if errors.Is(err, gitlab.ErrApprovalsPending) {
+			return fmt.Errorf("%w: %w", ErrApprovalsPending, err)
+		}
+		return fmt.Errorf("failed to check merge request approvals: %w", err)
+	}
+
 	if err := a.client.MergeMergeRequest(params.MRID, params.Squash, params.CommitTitle); err != nil {
 		return fmt.Errorf("failed to merge MR: %w", err)
 	}
 	return nil
 }
 
+// MergeMethods returns the merge methods allowed by the squash option of the GitLab
+// project: "always" and "never" leave a single one, "default_on" and "default_off"
+// allow both and tell the preferred one.
+func (a *GitLabAdapter) MergeMethods() (*MergeMethods, error) {
+	option, err := a.client.SquashOption()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get squash option: %w", err)
+	}
+	return &MergeMethods{
+		Squash:        option != "never",
+		Merge:         option != "always",
+		SquashDefault: option == "always" || option == "default_on",
+	}, nil
+}
+
+// MergeCommitSHA returns the SHA of the commit created by the last merge.
+func (a *GitLabAdapter) MergeCommitSHA() string {
+	return a.client.MergeCommitSHA()
+}
+
 // PlatformName returns "GitLab".
 func (a *GitLabAdapter) PlatformName() string {
 	return "GitLab"
@@ -119,5 +306,23 @@ func (a *GitLabAdapter) PipelineTimeout() string {
 	return a.cfg.PipelineTimeout
 }
 
+// DefaultLabels returns the configured default labels.
+func (a *GitLabAdapter) DefaultLabels() []string {
+	return a.cfg.DefaultLabels
+}
+
+// Squash returns the configured squash default (nil if unset).
+func (a *GitLabAdapter) Squash() *bool {
+	return a.cfg.Squash
+}
+
+// ApproveTiming returns the configured approve_timing, [config.ApproveAfterWait] if unset.
+func (a *GitLabAdapter) ApproveTiming() string {
+	if a.cfg.ApproveTiming == "" {
+		return config.ApproveAfterWait
+	}
+	return a.cfg.ApproveTiming
+}
+
 // Compile-time interface check.
 var _ Provider = (*GitLabAdapter)(nil)
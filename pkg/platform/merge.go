@@ -0,0 +1,35 @@
+package platform
+
+import "fmt"
+
+// MergeMethods are the ways a repository/project lets its merge/pull requests be
+// merged, see [Provider.MergeMethods].
+type MergeMethods struct {
+	Squash        bool // Squash merges are enabled
+	Merge         bool // Merges without squash are enabled
+	SquashDefault bool // Squashing is preferred when both are enabled
+}
+
+// ChooseSquash returns whether to squash given the methods m enables: as requested
+// when requested is set, otherwise with the preferred method.
+//
+// Returns [ErrMergeMethodDisabled] if the requested method is disabled, or both are.
+func (m MergeMethods) ChooseSquash(requested *bool) (bool, error) {
+	if requested != nil {
+		if *requested && !m.Squash {
+			return false, fmt.Errorf("%w: squash merges", ErrMergeMethodDisabled)
+		}
+		if !*requested && !m.Merge {
+			return false, fmt.Errorf("%w: merges without squash", ErrMergeMethodDisabled)
+		}
+		return *requested, nil
+	}
+
+	switch {
+	case m.Squash && (m.SquashDefault || !m.Merge):
+		return true, nil
+	case m.Merge:
+		return false, nil
+	}
+	return false, fmt.Errorf("%w: both squash merges and merges without squash", ErrMergeMethodDisabled)
+}
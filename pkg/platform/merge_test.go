@@ -0,0 +1,42 @@
+package platform_test
+
+import (
+	"testing"
+
+	"github.com/sgaunet/auto-mr/pkg/platform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChooseSquash(t *testing.T) {
+	both := platform.MergeMethods{Squash: true, Merge: true}
+	tests := []struct {
+		name      string
+		methods   platform.MergeMethods
+		requested *bool
+		want      bool
+		wantErr   bool
+	}{
+		{"both, merge preferred", both, nil, false, false},
+		{"both, squash preferred", platform.MergeMethods{Squash: true, Merge: true, SquashDefault: true}, nil, true, false},
+		{"squash only", platform.MergeMethods{Squash: true}, nil, true, false},
+		{"merge only", platform.MergeMethods{Merge: true, SquashDefault: true}, nil, false, false},
+		{"neither", platform.MergeMethods{}, nil, false, true},
+		{"squash requested", both, new(true), true, false},
+		{"merge requested", platform.MergeMethods{Squash: true, Merge: true, SquashDefault: true}, new(false), false, false},
+		{"squash requested, disabled", platform.MergeMethods{Merge: true}, new(true), false, true},
+		{"merge requested, disabled", platform.MergeMethods{Squash: true}, new(false), false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.methods.ChooseSquash(tt.requested)
+			if tt.wantErr {
+				require.ErrorIs(t, err, platform.ErrMergeMethodDisabled)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -11,16 +11,76 @@ type Provider interface {
 	// ListLabels returns all available labels.
 	ListLabels() ([]Label, error)
 
+	// ListMembers returns the usernames that can be assigned or asked for review:
+	// GitLab project members, GitHub and Forgejo repository collaborators.
+	ListMembers() ([]string, error)
+
 	// Create creates a new merge/pull request.
+	// Returns [ErrIncomplete] if it was created but setting its assignees, labels or
+	// reviewers failed: it is then left open as is.
 	Create(params CreateParams) (*MergeRequest, error)
 
 	// GetByBranch fetches an existing merge/pull request by source and target branches.
 	GetByBranch(sourceBranch, targetBranch string) (*MergeRequest, error)
 
+	// FindMerged fetches a merged merge/pull request of sourceBranch into targetBranch
+	// whose head commit is headSHA, e.g. merged by a previous run interrupted before cleanup.
+	// Returns [ErrNotFound] if there is none.
+	FindMerged(sourceBranch, targetBranch, headSHA string) (*MergeRequest, error)
+
 	// WaitForPipeline waits for CI/CD pipeline or workflow completion.
 	// Returns the overall status/conclusion or an error on timeout.
 	WaitForPipeline(timeout time.Duration) (string, error)
 
+	// RequireFreshCI makes WaitForPipeline ignore the pipelines/workflow runs created
+	// before since, e.g. an earlier green run of the same commit, and wait for newer ones.
+	// GitLab and GitHub only: Forgejo returns [ErrFreshCIUnsupported].
+	RequireFreshCI(since time.Time) error
+
+	// WaitForDeployment waits for the deployments of ref created at or after since,
+	// e.g. those started by a merge into ref, and returns "success" or the status of
+	// the one that failed. Returns [ErrDeploymentTimeout] on timeout.
+	// GitLab and GitHub only: Forgejo returns [ErrDeploymentsUnsupported].
+	WaitForDeployment(ref string, since time.Time, timeout time.Duration) (string, error)
+
+	// Rebase rebases the source branch onto the target branch on the server,
+	// waiting at most timeout for the rebase to finish.
+	// GitLab only: GitHub and Forgejo return [ErrRebaseUnsupported].
+	Rebase(mrID int64, timeout time.Duration) error
+
+	// RequestReviewers requests review of a merge/pull request from owners, e.g. the
+	// code owners of its changes, on top of its current reviewers. An owner is a
+	// username or a team: "org/team" on GitHub and Forgejo, a group path standing for
+	// its members on GitLab. The author is left out.
+	RequestReviewers(mrID int64, owners []string) error
+
+	// Comment posts a comment (Markdown) on a merge/pull request.
+	Comment(mrID int64, body string) error
+
+	// AddLabels adds existing labels to a merge/pull request, keeping its current ones.
+	AddLabels(mrID int64, labels []string) error
+
+	// MarkReady turns a draft merge/pull request into one ready for review.
+	// Returns [ErrNotDraft] if it is not a draft.
+	MarkReady(mrID int64) error
+
+	// EnableAutoMerge makes the platform merge a merge/pull request, squashed if
+	// squash is true, once its pipeline/checks succeed, and delete its source branch.
+	// GitHub: auto-merge must be allowed in the repository settings.
+	EnableAutoMerge(mrID int64, squash bool) error
+
+	// AddToProject adds a merge/pull request to the Projects (v2) board numbered
+	// project of the repository owner.
+	// GitHub only: GitLab and Forgejo return [ErrProjectsUnsupported].
+	AddToProject(mrID int64, project int) error
+
+	// Close closes a merge/pull request without merging it.
+	Close(mrID int64) error
+
+	// DeleteBranch deletes a branch from the remote repository the merge/pull
+	// request is opened from.
+	DeleteBranch(branch string) error
+
 	// Approve approves a merge/pull request.
 	// No-op for GitHub (returns nil).
 	Approve(mrID int64) error
@@ -29,9 +89,27 @@ type Provider interface {
 	// GitHub: also deletes the remote branch internally.
 	Merge(params MergeParams) error
 
+	// MergeMethods returns the merge methods enabled in the repository/project
+	// settings, so that merging does not fail with a disabled one.
+	MergeMethods() (*MergeMethods, error)
+
+	// MergeCommitSHA returns the SHA of the commit the last Merge left at the head of
+	// the target branch (merge or squash commit), empty if unknown.
+	MergeCommitSHA() string
+
 	// PlatformName returns "GitLab", "GitHub", or "Forgejo".
 	PlatformName() string
 
 	// PipelineTimeout returns the config value for timeout resolution.
 	PipelineTimeout() string
+
+	// DefaultLabels returns the configured labels for unattended (--yes) runs.
+	DefaultLabels() []string
+
+	// Squash returns the platform's configured squash default (nil if unset).
+	Squash() *bool
+
+	// ApproveTiming returns when to approve: "before-wait" or "after-wait"
+	// (also returned when unset), see config.ApproveBeforeWait.
+	ApproveTiming() string
 }
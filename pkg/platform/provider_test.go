@@ -2,12 +2,23 @@ package platform_test
 
 import (
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
+	"github.com/sgaunet/auto-mr/internal/timeutil"
+	"github.com/sgaunet/auto-mr/pkg/config"
+	"github.com/sgaunet/auto-mr/pkg/git"
+	ghclient "github.com/sgaunet/auto-mr/pkg/github"
+	"github.com/sgaunet/auto-mr/pkg/gitlab"
 	"github.com/sgaunet/auto-mr/pkg/platform"
 	"github.com/sgaunet/auto-mr/testing/fixtures"
 	"github.com/sgaunet/auto-mr/testing/mocks"
+	"github.com/sgaunet/bullets"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -442,3 +453,149 @@ func TestForgejoAdapter_MergeWithBranchDeletion(t *testing.T) {
 	assert.Equal(t, "forgejo-feature", lastCall.Args["sourceBranch"])
 	assert.Equal(t, int64(77), lastCall.Args["mrID"])
 }
+
+func TestWaitUntilVisible(t *testing.T) {
+	t.Run("visible immediately", func(t *testing.T) {
+		mock := mocks.NewPlatformProvider()
+		mock.GetByBranchResponse = fixtures.ValidPlatformMergeRequest()
+
+		err := platform.WaitUntilVisible(mock, "feature", "main", time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, 1, mock.GetCallCount("GetByBranch"))
+	})
+
+	t.Run("never visible gives up after max wait", func(t *testing.T) {
+		mock := mocks.NewPlatformProvider()
+		mock.GetByBranchError = platform.ErrNotFound
+
+		start := time.Now()
+		err := platform.WaitUntilVisible(mock, "feature", "main", 600*time.Millisecond)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, platform.ErrNotFound))
+		assert.Less(t, time.Since(start), 2*time.Second)
+		assert.Greater(t, mock.GetCallCount("GetByBranch"), 1)
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		mock := mocks.NewPlatformProvider()
+		mock.GetByBranchError = errors.New("unauthorized")
+
+		err := platform.WaitUntilVisible(mock, "feature", "main", time.Second)
+		require.Error(t, err)
+		assert.Equal(t, 1, mock.GetCallCount("GetByBranch"))
+	})
+}
+
+// TestNewProvider_ReadsConfigAtEachCall verifies that configuration changes made after
+// the provider is created apply, so that a single provider serves the whole run.
+func TestNewProvider_ReadsConfigAtEachCall(t *testing.T) {
+	t.Setenv("GITLAB_TOKEN", "test-token")
+	cfg := &config.Config{}
+
+	provider, err := platform.NewProvider(git.PlatformGitLab, cfg, bullets.New(io.Discard), platform.Options{})
+	require.NoError(t, err)
+
+	cfg.GitLab.PipelineTimeout = "45m"
+	cfg.GitLab.DefaultLabels = []string{"ci"}
+	assert.Equal(t, "45m", provider.PipelineTimeout())
+	assert.Equal(t, []string{"ci"}, provider.DefaultLabels())
+}
+
+// --- Checks start timeout ---
+
+// redirectTransport sends every request to the test server, whatever the API host.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (rt redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = rt.target.Scheme
+	req.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func newRedirectClient(t *testing.T, mux *http.ServeMux) *http.Client {
+	t.Helper()
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	target, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	return &http.Client{Transport: redirectTransport{target: target}}
+}
+
+// TestGitLabAdapter_NoPipelineStarted verifies that with the checks required, a merge
+// request whose pipeline never starts fails with ErrNoChecks instead of being taken
+// for one without CI.
+func TestGitLabAdapter_NoPipelineStarted(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v4/projects/{path}", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id": 42}`)
+	})
+	mux.HandleFunc("GET /api/v4/projects/42/merge_requests", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[{"iid": 5}]`)
+	})
+	mux.HandleFunc("GET /api/v4/projects/42/merge_requests/5", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"iid": 5, "sha": "abc"}`)
+	})
+	mux.HandleFunc("GET /api/v4/projects/42/merge_requests/5/pipelines", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+	mux.HandleFunc("GET /api/v4/projects/42/pipelines", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+
+	t.Setenv("GITLAB_TOKEN", "test-token")
+	client, err := gitlab.NewClient(newRedirectClient(t, mux))
+	require.NoError(t, err)
+	require.NoError(t, client.SetProjectFromURL("https://gitlab.com/owner/project.git"))
+	client.SetClock(timeutil.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)))
+	client.SetStartTimeout(30 * time.Second)
+	client.SetRequireChecks(true)
+
+	adapter := platform.NewGitLabAdapter(client, &config.GitLabConfig{}, bullets.New(io.Discard))
+	_, err = adapter.GetByBranch("feature", "main")
+	require.NoError(t, err)
+
+	_, err = adapter.WaitForPipeline(time.Hour)
+	require.ErrorIs(t, err, platform.ErrNoChecks)
+}
+
+// TestGitHubAdapter_NoWorkflowStarted verifies that with the checks required, a pull
+// request whose workflows never start fails with ErrNoChecks instead of being taken
+// for one without CI.
+func TestGitHubAdapter_NoWorkflowStarted(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/repo", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"name": "repo"}`)
+	})
+	mux.HandleFunc("GET /repos/owner/repo/pulls", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `[{"number": 5, "html_url": "https://github.com/owner/repo/pull/5",
+			"head": {"ref": "feature", "sha": "abc"}}]`)
+	})
+	mux.HandleFunc("GET /repos/owner/repo/actions/runs", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"total_count": 0, "workflow_runs": []}`)
+	})
+	mux.HandleFunc("GET /repos/owner/repo/commits/abc/check-suites", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"total_count": 0, "check_suites": []}`)
+	})
+	mux.HandleFunc("GET /repos/owner/repo/commits/abc/check-runs", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"total_count": 0, "check_runs": []}`)
+	})
+
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	client, err := ghclient.NewClient(newRedirectClient(t, mux))
+	require.NoError(t, err)
+	require.NoError(t, client.SetRepositoryFromURL("https://github.com/owner/repo.git"))
+	client.SetClock(timeutil.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)))
+	client.SetStartTimeout(30 * time.Second)
+	client.SetRequireChecks(true)
+
+	adapter := platform.NewGitHubAdapter(client, &config.GitHubConfig{}, bullets.New(io.Discard))
+	_, err = adapter.GetByBranch("feature", "main")
+	require.NoError(t, err)
+
+	_, err = adapter.WaitForPipeline(time.Hour)
+	require.ErrorIs(t, err, platform.ErrNoChecks)
+}
@@ -0,0 +1,46 @@
+package platform
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sgaunet/auto-mr/pkg/git"
+)
+
+// tokenEnvVars lists the environment variable holding the API token of each platform.
+var tokenEnvVars = map[git.Platform]string{
+	git.PlatformGitLab:  "GITLAB_TOKEN",
+	git.PlatformGitHub:  "GITHUB_TOKEN",
+	git.PlatformForgejo: "FORGEJO_TOKEN",
+}
+
+// TokenEnv returns the environment variable holding the API token for p,
+// or an empty string for an unknown platform.
+func TokenEnv(p git.Platform) string {
+	return tokenEnvVars[p]
+}
+
+// CheckToken verifies that the token of platform p is set, before any client is created.
+// When it is missing but the token of another platform is set, the error says so,
+// since that token cannot be used for p.
+//
+// Returns [ErrTokenMissing] naming the variable to set.
+func CheckToken(p git.Platform) error {
+	name := TokenEnv(p)
+	if name == "" || strings.TrimSpace(os.Getenv(name)) != "" {
+		return nil
+	}
+
+	var others []string
+	for _, other := range []git.Platform{git.PlatformGitLab, git.PlatformGitHub, git.PlatformForgejo} {
+		if other != p && strings.TrimSpace(os.Getenv(tokenEnvVars[other])) != "" {
+			others = append(others, tokenEnvVars[other])
+		}
+	}
+	if len(others) > 0 {
+		return fmt.Errorf("%w: set %s for this %s repository (%s is set, but only applies to another platform)",
+			ErrTokenMissing, name, p, strings.Join(others, ", "))
+	}
+	return fmt.Errorf("%w: set %s for this %s repository", ErrTokenMissing, name, p)
+}
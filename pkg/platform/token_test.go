@@ -0,0 +1,42 @@
+package platform_test
+
+import (
+	"testing"
+
+	"github.com/sgaunet/auto-mr/pkg/git"
+	"github.com/sgaunet/auto-mr/pkg/platform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform git.Platform
+		env      map[string]string
+		wantErr  bool
+		contains string
+	}{
+		{"token set", git.PlatformGitHub, map[string]string{"GITHUB_TOKEN": "t"}, false, ""},
+		{"no token", git.PlatformGitHub, nil, true, "set GITHUB_TOKEN"},
+		{"only other platform token", git.PlatformGitHub, map[string]string{"GITLAB_TOKEN": "t"}, true,
+			"GITLAB_TOKEN is set, but only applies to another platform"},
+		{"blank token", git.PlatformForgejo, map[string]string{"FORGEJO_TOKEN": "  "}, true, "set FORGEJO_TOKEN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"GITLAB_TOKEN", "GITHUB_TOKEN", "FORGEJO_TOKEN"} {
+				t.Setenv(name, tt.env[name])
+			}
+
+			err := platform.CheckToken(tt.platform)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, platform.ErrTokenMissing)
+			assert.Contains(t, err.Error(), tt.contains)
+		})
+	}
+}
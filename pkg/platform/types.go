@@ -6,23 +6,32 @@
 //
 // Use [NewProvider] to create the appropriate adapter based on the detected platform:
 //
-//	provider, err := platform.NewProvider(git.PlatformGitHub, cfg, logger)
+//	provider, err := platform.NewProvider(git.PlatformGitHub, cfg, logger, platform.Options{})
 //	provider.Initialize(remoteURL)
 //	mr, _ := provider.Create(platform.CreateParams{...})
 //	status, _ := provider.WaitForPipeline(30 * time.Minute)
 //	provider.Merge(platform.MergeParams{MRID: mr.ID, ...})
 package platform
 
+import (
+	"net/http"
+	"time"
+)
+
 // Label represents a platform-agnostic label.
 type Label struct {
-	Name string
+	Name        string
+	Color       string // Hex color with a leading '#' (e.g. "#d73a4a"), empty if unknown
+	Description string
+	Scope       string // GitLab scoped label scope ("priority" for "priority::high"), empty if not scoped
 }
 
 // MergeRequest represents a platform-agnostic merge/pull request.
 type MergeRequest struct {
-	ID           int64  // GitLab: MR IID; GitHub: PR Number
-	WebURL       string // Browser URL
-	SourceBranch string // Needed for GitHub post-merge branch deletion
+	ID           int64   // GitLab: MR IID; GitHub: PR Number
+	WebURL       string  // Browser URL
+	SourceBranch string  // Needed for GitHub post-merge branch deletion
+	Duplicates   []int64 // GetByBranch: IDs of the other open ones for the same branches, newest first
 }
 
 // CreateParams holds parameters for creating a merge/pull request.
@@ -37,10 +46,51 @@ type CreateParams struct {
 	Squash       bool
 }
 
+// Options holds runtime settings applied to the underlying API client by [NewProvider].
+type Options struct {
+	// HTTPClient is used for API requests, e.g. one trusting a custom CA (nil uses library defaults).
+	HTTPClient *http.Client
+	// UserCachePath is the file caching GitLab username→ID lookups (empty disables caching).
+	UserCachePath string
+	// HeadRemoteURL is the remote the source branch is pushed to, when it differs from
+	// the remote passed to [Provider.Initialize] (fork workflow). Empty means the same remote.
+	HeadRemoteURL string
+	// APIConcurrency limits concurrent job fetches while waiting for pipelines (<1: client default).
+	APIConcurrency int
+	// PlayManualJobs starts the manual jobs of GitLab pipelines while waiting, instead of
+	// ignoring them (other platforms have no manual jobs).
+	PlayManualJobs bool
+	// RequestTimeout bounds each API call (<=0: client default of 30s). The pipeline wait
+	// as a whole is bounded by its own timeout.
+	RequestTimeout time.Duration
+	// StartupDelay bounds the wait for GitHub workflows to be created after a push,
+	// before concluding there are none (nil: client default of 5s, zero: check once).
+	// The wait for the pipeline starts as soon as they show up.
+	StartupDelay *time.Duration
+	// ChecksStartTimeout bounds the wait for the first GitLab pipeline or GitHub check run,
+	// after which WaitForPipeline returns [ErrNoChecks] (0: the whole pipeline timeout).
+	// Forgejo already treats a commit without statuses as having no CI.
+	ChecksStartTimeout time.Duration
+	// RequireChecks makes GitLab and GitHub wait for a pipeline/check run to start, within
+	// ChecksStartTimeout, when there is none yet instead of proceeding without checks.
+	RequireChecks bool
+	// IgnoredChecks are job/check name patterns (path.Match syntax, e.g. "lint-*") whose
+	// result does not gate the merge: the jobs are displayed but not waited for.
+	// GitLab and GitHub only: Forgejo gates on the combined commit status.
+	IgnoredChecks []string
+	// UserAgent is sent as the User-Agent header of API requests, e.g. "auto-mr/1.2.0"
+	// (empty: library default).
+	UserAgent string
+}
+
 // MergeParams holds parameters for merging a merge/pull request.
 type MergeParams struct {
-	MRID         int64
-	Squash       bool
-	CommitTitle  string
-	SourceBranch string // GitHub: for branch deletion; GitLab: unused
+	MRID          int64
+	Squash        bool
+	CommitTitle   string
+	CommitMessage string // GitHub: merge commit message (empty: CommitTitle); others: unused
+	SourceBranch  string // GitHub: for branch deletion; GitLab: unused
+	// ApprovalTimeout bounds the wait for required approvals (GitLab only).
+	// Zero fails immediately with [ErrApprovalsPending] when approvals are missing.
+	ApprovalTimeout time.Duration
 }
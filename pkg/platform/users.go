@@ -0,0 +1,47 @@
+package platform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sgaunet/auto-mr/pkg/config"
+)
+
+// resolveParticipants replaces the [config.CurrentUser] sentinel in assignee and
+// reviewer with the authenticated user's name, looked up via currentUser only when
+// needed. A reviewer resolving to that user is dropped (returned empty) since
+// authors cannot review their own changes.
+func resolveParticipants(
+	assignee, reviewer string, currentUser func() (string, error),
+) (string, string, error) {
+	if !config.IsCurrentUser(assignee) && !config.IsCurrentUser(reviewer) {
+		return assignee, reviewer, nil
+	}
+
+	me, err := currentUser()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve %s: %w", config.CurrentUser, err)
+	}
+
+	if config.IsCurrentUser(assignee) {
+		assignee = me
+	}
+	if config.IsCurrentUser(reviewer) || reviewer == me {
+		reviewer = ""
+	}
+	return assignee, reviewer, nil
+}
+
+// splitTeams separates the users from the "org/team" teams among owners, returning
+// the teams by name, without their organization.
+func splitTeams(owners []string) ([]string, []string) {
+	var users, teams []string
+	for _, owner := range owners {
+		if _, team, ok := strings.Cut(owner, "/"); ok {
+			teams = append(teams, team)
+		} else {
+			users = append(users, owner)
+		}
+	}
+	return users, teams
+}
@@ -0,0 +1,52 @@
+package platform
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveParticipants(t *testing.T) {
+	tests := []struct {
+		name             string
+		assignee         string
+		reviewer         string
+		expectedAssignee string
+		expectedReviewer string
+		expectLookup     bool
+	}{
+		{"plain usernames", "alice", "bob", "alice", "bob", false},
+		{"assignee @me", "@me", "bob", "me", "bob", true},
+		{"assignee @self", "@self", "bob", "me", "bob", true},
+		{"reviewer is the author", "@me", "me", "me", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			looked := false
+			assignee, reviewer, err := resolveParticipants(tt.assignee, tt.reviewer, func() (string, error) {
+				looked = true
+				return "me", nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedAssignee, assignee)
+			assert.Equal(t, tt.expectedReviewer, reviewer)
+			assert.Equal(t, tt.expectLookup, looked)
+		})
+	}
+
+	t.Run("lookup failure", func(t *testing.T) {
+		_, _, err := resolveParticipants("@me", "bob", func() (string, error) {
+			return "", errors.New("unauthorized")
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestSplitTeams(t *testing.T) {
+	users, teams := splitTeams([]string{"alice", "org/backend", "bob", "org/docs"})
+	assert.Equal(t, []string{"alice", "bob"}, users)
+	assert.Equal(t, []string{"backend", "docs"}, teams)
+}
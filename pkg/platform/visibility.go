@@ -0,0 +1,42 @@
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Backoff bounds used by [WaitUntilVisible].
+const (
+	visibilityInitialDelay = 250 * time.Millisecond
+	visibilityMaxDelay     = 2 * time.Second
+)
+
+// WaitUntilVisible polls p until the merge/pull request for sourceBranch→targetBranch
+// can be fetched, doubling the delay between attempts (up to visibilityMaxDelay).
+// APIs are eventually consistent, so a request created or a branch pushed a moment
+// ago may not be listed yet.
+//
+// Only [ErrNotFound] is retried; other errors are returned immediately.
+// Returns an error wrapping [ErrNotFound] if the request is still not visible after maxWait.
+func WaitUntilVisible(p Provider, sourceBranch, targetBranch string, maxWait time.Duration) error {
+	deadline := time.Now().Add(maxWait)
+	delay := visibilityInitialDelay
+
+	for {
+		_, err := p.GetByBranch(sourceBranch, targetBranch)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return err
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Errorf("still not visible after %s: %w", maxWait, err)
+		}
+		time.Sleep(min(delay, remaining))
+		delay = min(2*delay, visibilityMaxDelay)
+	}
+}
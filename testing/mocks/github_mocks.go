@@ -20,6 +20,7 @@ type GitHubAPIClient struct {
 
 	// Configurable responses
 	SetRepositoryFromURLError      error
+	SetHeadRepositoryFromURLError  error
 	ListLabelsResponse             []*ghpkg.Label
 	ListLabelsError                error
 	CreatePullRequestResponse      *github.PullRequest
@@ -28,10 +29,13 @@ type GitHubAPIClient struct {
 	GetPullRequestByBranchError    error
 	WaitForWorkflowsConclusion     string
 	WaitForWorkflowsError          error
+	CheckMergeableError            error
 	MergePullRequestError          error
 	GetPullRequestsByHeadResponse  []*github.PullRequest
 	GetPullRequestsByHeadError     error
 	DeleteBranchError              error
+	CurrentUsernameResponse        string
+	CurrentUsernameError           error
 }
 
 // MethodCall represents a tracked method call with its parameters.
@@ -107,12 +111,21 @@ func (m *GitHubAPIClient) WaitForWorkflows(timeout time.Duration) (string, error
 	return m.WaitForWorkflowsConclusion, m.WaitForWorkflowsError
 }
 
+// CheckMergeable implements github.APIClient.
+func (m *GitHubAPIClient) CheckMergeable(prNumber int) error {
+	m.trackCall("CheckMergeable", map[string]any{
+		"prNumber": prNumber,
+	})
+	return m.CheckMergeableError
+}
+
 // MergePullRequest implements github.APIClient.
-func (m *GitHubAPIClient) MergePullRequest(prNumber int, mergeMethod, commitTitle string) error {
+func (m *GitHubAPIClient) MergePullRequest(prNumber int, mergeMethod, commitTitle, commitMessage string) error {
 	m.trackCall("MergePullRequest", map[string]any{
-		"prNumber":     prNumber,
-		"mergeMethod":  mergeMethod,
-		argCommitTitle: commitTitle,
+		"prNumber":      prNumber,
+		"mergeMethod":   mergeMethod,
+		argCommitTitle:  commitTitle,
+		"commitMessage": commitMessage,
 	})
 	return m.MergePullRequestError
 }
@@ -133,6 +146,20 @@ func (m *GitHubAPIClient) DeleteBranch(branch string) error {
 	return m.DeleteBranchError
 }
 
+// SetHeadRepositoryFromURL implements github.APIClient.
+func (m *GitHubAPIClient) SetHeadRepositoryFromURL(url string) error {
+	m.trackCall("SetHeadRepositoryFromURL", map[string]any{
+		"url": url,
+	})
+	return m.SetHeadRepositoryFromURLError
+}
+
+// CurrentUsername implements github.APIClient.
+func (m *GitHubAPIClient) CurrentUsername() (string, error) {
+	m.trackCall("CurrentUsername", nil)
+	return m.CurrentUsernameResponse, m.CurrentUsernameError
+}
+
 // GetCalls returns all tracked method calls.
 func (m *GitHubAPIClient) GetCalls() []MethodCall {
 	m.mu.Lock()
@@ -25,9 +25,13 @@ type GitLabAPIClient struct {
 	WaitForPipelineStatus            string
 	WaitForPipelineError             error
 	ApproveMergeRequestError         error
+	CheckMergeableError              error
+	WaitForApprovalsError            error
 	MergeMergeRequestError           error
 	GetMergeRequestsByBranchResponse []*gitlab.BasicMergeRequest
 	GetMergeRequestsByBranchError    error
+	CurrentUsernameResponse          string
+	CurrentUsernameError             error
 }
 
 // NewGitLabAPIClient creates a new mock GitLab API client.
@@ -94,6 +98,23 @@ func (m *GitLabAPIClient) ApproveMergeRequest(mrIID int64) error {
 	return m.ApproveMergeRequestError
 }
 
+// CheckMergeable implements gitlab.APIClient.
+func (m *GitLabAPIClient) CheckMergeable(mrIID int64) error {
+	m.trackCall("CheckMergeable", map[string]any{
+		"mrIID": mrIID,
+	})
+	return m.CheckMergeableError
+}
+
+// WaitForApprovals implements gitlab.APIClient.
+func (m *GitLabAPIClient) WaitForApprovals(mrIID int64, timeout time.Duration) error {
+	m.trackCall("WaitForApprovals", map[string]any{
+		"mrIID":   mrIID,
+		"timeout": timeout,
+	})
+	return m.WaitForApprovalsError
+}
+
 // MergeMergeRequest implements gitlab.APIClient.
 func (m *GitLabAPIClient) MergeMergeRequest(mrIID int64, squash bool, commitTitle string) error {
 	m.trackCall("MergeMergeRequest", map[string]any{
@@ -112,6 +133,12 @@ func (m *GitLabAPIClient) GetMergeRequestsByBranch(sourceBranch string) ([]*gitl
 	return m.GetMergeRequestsByBranchResponse, m.GetMergeRequestsByBranchError
 }
 
+// CurrentUsername implements gitlab.APIClient.
+func (m *GitLabAPIClient) CurrentUsername() (string, error) {
+	m.trackCall("CurrentUsername", nil)
+	return m.CurrentUsernameResponse, m.CurrentUsernameError
+}
+
 // GetCalls returns all tracked method calls.
 func (m *GitLabAPIClient) GetCalls() []MethodCall {
 	m.mu.Lock()
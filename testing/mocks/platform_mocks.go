@@ -14,19 +14,41 @@ type PlatformProvider struct {
 	calls []MethodCall
 
 	// Configurable responses
-	InitializeError       error
-	ListLabelsResponse    []platform.Label
-	ListLabelsError       error
-	CreateResponse        *platform.MergeRequest
-	CreateError           error
-	GetByBranchResponse   *platform.MergeRequest
-	GetByBranchError      error
-	WaitForPipelineStatus string
-	WaitForPipelineError  error
-	ApproveError          error
-	MergeError            error
-	PlatformNameValue     string
-	PipelineTimeoutValue  string
+	InitializeError         error
+	ListLabelsResponse      []platform.Label
+	ListLabelsError         error
+	ListMembersResponse     []string
+	ListMembersError        error
+	CreateResponse          *platform.MergeRequest
+	CreateError             error
+	GetByBranchResponse     *platform.MergeRequest
+	GetByBranchError        error
+	FindMergedResponse      *platform.MergeRequest
+	FindMergedError         error
+	WaitForPipelineStatus   string
+	WaitForPipelineError    error
+	RequireFreshCIError     error
+	WaitForDeploymentStatus string
+	WaitForDeploymentError  error
+	RebaseError             error
+	RequestReviewersError   error
+	CommentError            error
+	AddLabelsError          error
+	MarkReadyError          error
+	EnableAutoMergeError    error
+	AddToProjectError       error
+	CloseError              error
+	DeleteBranchError       error
+	ApproveError            error
+	MergeError              error
+	MergeMethodsResponse    *platform.MergeMethods
+	MergeMethodsError       error
+	MergeCommitSHAValue     string
+	PlatformNameValue       string
+	PipelineTimeoutValue    string
+	DefaultLabelsValue      []string
+	SquashValue             *bool
+	ApproveTimingValue      string
 }
 
 // NewPlatformProvider creates a new mock platform provider.
@@ -51,6 +73,12 @@ func (m *PlatformProvider) ListLabels() ([]platform.Label, error) {
 	return m.ListLabelsResponse, m.ListLabelsError
 }
 
+// ListMembers implements platform.Provider.
+func (m *PlatformProvider) ListMembers() ([]string, error) {
+	m.trackCall("ListMembers", map[string]any{})
+	return m.ListMembersResponse, m.ListMembersError
+}
+
 // Create implements platform.Provider.
 func (m *PlatformProvider) Create(params platform.CreateParams) (*platform.MergeRequest, error) {
 	m.trackCall("Create", map[string]any{
@@ -73,6 +101,16 @@ func (m *PlatformProvider) GetByBranch(sourceBranch, targetBranch string) (*plat
 	return m.GetByBranchResponse, m.GetByBranchError
 }
 
+// FindMerged implements platform.Provider.
+func (m *PlatformProvider) FindMerged(sourceBranch, targetBranch, headSHA string) (*platform.MergeRequest, error) {
+	m.trackCall("FindMerged", map[string]any{
+		argSourceBranch: sourceBranch,
+		argTargetBranch: targetBranch,
+		"headSHA":       headSHA,
+	})
+	return m.FindMergedResponse, m.FindMergedError
+}
+
 // WaitForPipeline implements platform.Provider.
 func (m *PlatformProvider) WaitForPipeline(timeout time.Duration) (string, error) {
 	m.trackCall("WaitForPipeline", map[string]any{
@@ -81,6 +119,102 @@ func (m *PlatformProvider) WaitForPipeline(timeout time.Duration) (string, error
 	return m.WaitForPipelineStatus, m.WaitForPipelineError
 }
 
+// RequireFreshCI implements platform.Provider.
+func (m *PlatformProvider) RequireFreshCI(since time.Time) error {
+	m.trackCall("RequireFreshCI", map[string]any{
+		"since": since,
+	})
+	return m.RequireFreshCIError
+}
+
+// WaitForDeployment implements platform.Provider.
+func (m *PlatformProvider) WaitForDeployment(ref string, since time.Time, timeout time.Duration) (string, error) {
+	m.trackCall("WaitForDeployment", map[string]any{
+		"ref":      ref,
+		"since":    since,
+		argTimeout: timeout,
+	})
+	return m.WaitForDeploymentStatus, m.WaitForDeploymentError
+}
+
+// Rebase implements platform.Provider.
+func (m *PlatformProvider) Rebase(mrID int64, timeout time.Duration) error {
+	m.trackCall("Rebase", map[string]any{
+		"mrID":    mrID,
+		"timeout": timeout,
+	})
+	return m.RebaseError
+}
+
+// RequestReviewers implements platform.Provider.
+func (m *PlatformProvider) RequestReviewers(mrID int64, owners []string) error {
+	m.trackCall("RequestReviewers", map[string]any{
+		"mrID":   mrID,
+		"owners": owners,
+	})
+	return m.RequestReviewersError
+}
+
+// Comment implements platform.Provider.
+func (m *PlatformProvider) Comment(mrID int64, body string) error {
+	m.trackCall("Comment", map[string]any{
+		"mrID": mrID,
+		"body": body,
+	})
+	return m.CommentError
+}
+
+// AddLabels implements platform.Provider.
+func (m *PlatformProvider) AddLabels(mrID int64, labels []string) error {
+	m.trackCall("AddLabels", map[string]any{
+		"mrID":   mrID,
+		"labels": labels,
+	})
+	return m.AddLabelsError
+}
+
+// MarkReady implements platform.Provider.
+func (m *PlatformProvider) MarkReady(mrID int64) error {
+	m.trackCall("MarkReady", map[string]any{
+		"mrID": mrID,
+	})
+	return m.MarkReadyError
+}
+
+// EnableAutoMerge implements platform.Provider.
+func (m *PlatformProvider) EnableAutoMerge(mrID int64, squash bool) error {
+	m.trackCall("EnableAutoMerge", map[string]any{
+		"mrID":   mrID,
+		"squash": squash,
+	})
+	return m.EnableAutoMergeError
+}
+
+// AddToProject implements platform.Provider.
+func (m *PlatformProvider) AddToProject(mrID int64, project int) error {
+	m.trackCall("AddToProject", map[string]any{
+		"mrID":    mrID,
+		"project": project,
+	})
+	return m.AddToProjectError
+}
+
+// Close implements platform.Provider.
+func (m *PlatformProvider) Close(mrID int64) error {
+	m.trackCall("Close", map[string]any{
+		"mrID": mrID,
+	})
+	return m.CloseError
+}
+
+// DeleteBranch implements platform.Provider.
+func (m *PlatformProvider) DeleteBranch(branch string) error {
+	m.trackCall("DeleteBranch", map[string]any{
+		"branch": branch,
+	})
+	return m.DeleteBranchError
+}
+
 // Approve implements platform.Provider.
 func (m *PlatformProvider) Approve(mrID int64) error {
 	m.trackCall("Approve", map[string]any{
@@ -100,6 +234,17 @@ func (m *PlatformProvider) Merge(params platform.MergeParams) error {
 	return m.MergeError
 }
 
+// MergeMethods implements platform.Provider.
+func (m *PlatformProvider) MergeMethods() (*platform.MergeMethods, error) {
+	m.trackCall("MergeMethods", map[string]any{})
+	return m.MergeMethodsResponse, m.MergeMethodsError
+}
+
+// MergeCommitSHA implements platform.Provider.
+func (m *PlatformProvider) MergeCommitSHA() string {
+	return m.MergeCommitSHAValue
+}
+
 // PlatformName implements platform.Provider.
 func (m *PlatformProvider) PlatformName() string {
 	return m.PlatformNameValue
@@ -110,6 +255,21 @@ func (m *PlatformProvider) PipelineTimeout() string {
 	return m.PipelineTimeoutValue
 }
 
+// DefaultLabels implements platform.Provider.
+func (m *PlatformProvider) DefaultLabels() []string {
+	return m.DefaultLabelsValue
+}
+
+// Squash implements platform.Provider.
+func (m *PlatformProvider) Squash() *bool {
+	return m.SquashValue
+}
+
+// ApproveTiming implements platform.Provider.
+func (m *PlatformProvider) ApproveTiming() string {
+	return m.ApproveTimingValue
+}
+
 // GetCalls returns all tracked method calls.
 func (m *PlatformProvider) GetCalls() []MethodCall {
 	m.mu.Lock()